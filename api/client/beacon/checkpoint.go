@@ -3,6 +3,7 @@ package beacon
 import (
 	"context"
 	"fmt"
+	"os"
 	"path"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -21,7 +22,10 @@ import (
 	"golang.org/x/mod/semver"
 )
 
-var errCheckpointBlockMismatch = errors.New("mismatch between checkpoint sync state and block")
+var (
+	errCheckpointBlockMismatch = errors.New("mismatch between checkpoint sync state and block")
+	errCheckpointStateMismatch = errors.New("downloaded checkpoint sync state does not match the requested state root")
+)
 
 // OriginData represents the BeaconState and ReadOnlySignedBeaconBlock necessary to start an empty Beacon Node
 // using Checkpoint Sync.
@@ -59,6 +63,16 @@ func (o *OriginData) BlockBytes() []byte {
 	return o.bb
 }
 
+// BlockRoot returns the hash_tree_root of the downloaded block.
+func (o *OriginData) BlockRoot() [32]byte {
+	return o.br
+}
+
+// StateRoot returns the hash_tree_root of the downloaded state.
+func (o *OriginData) StateRoot() [32]byte {
+	return o.sr
+}
+
 func fname(prefix string, vu *detect.VersionedUnmarshaler, slot primitives.Slot, root [32]byte) string {
 	return fmt.Sprintf("%s_%s_%s_%d-%#x.ssz", prefix, vu.Config.ConfigName, version.String(vu.Fork), slot, root)
 }
@@ -66,23 +80,72 @@ func fname(prefix string, vu *detect.VersionedUnmarshaler, slot primitives.Slot,
 // DownloadFinalizedData downloads the most recently finalized state, and the block most recently applied to that state.
 // This pair can be used to initialize a new beacon node via checkpoint sync.
 func DownloadFinalizedData(ctx context.Context, client *Client) (*OriginData, error) {
-	sb, err := client.GetState(ctx, IdFinalized)
+	return DownloadOriginData(ctx, client, IdFinalized)
+}
+
+// DownloadOriginData downloads the state with the given id, and the block most recently applied to that state.
+// This pair can be used to initialize a new beacon node via checkpoint sync. The state is held in memory, use
+// DownloadOriginDataToDir to stage a resumable download on disk.
+func DownloadOriginData(ctx context.Context, client *Client, stateId StateOrBlockId) (*OriginData, error) {
+	sb, err := client.GetState(ctx, stateId)
+	if err != nil {
+		return nil, err
+	}
+	return originDataFromState(ctx, client, sb)
+}
+
+// DownloadOriginDataToDir downloads the state with the given id, and the block most recently applied to that state.
+// The state id is first resolved to a state root, and the state is streamed to a partial file in dir named after
+// that root, so that an interrupted download of the same state is resumed by a later call. The partial file is
+// removed once the downloaded state has been checked against the state root.
+func DownloadOriginDataToDir(ctx context.Context, client *Client, stateId StateOrBlockId, dir string) (*OriginData, error) {
+	expectedRoot, ok := stateId.root()
+	if !ok {
+		var err error
+		expectedRoot, err = client.GetStateRoot(ctx, stateId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error resolving state root for state id = %s", stateId)
+		}
+	}
+	partialPath := PartialStatePath(dir, expectedRoot)
+	if _, err := client.GetToFile(ctx, renderGetStatePath(IdFromRoot(expectedRoot)), partialPath,
+		downloadProgressLogger(expectedRoot), base.WithSSZEncoding()); err != nil {
+		return nil, errors.Wrapf(err, "error downloading state with root %#x, partial download kept at %s", expectedRoot, partialPath)
+	}
+	sb, err := os.ReadFile(partialPath) // #nosec G304
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading downloaded state")
+	}
+	od, err := originDataFromState(ctx, client, sb)
 	if err != nil {
 		return nil, err
 	}
+	if od.sr != expectedRoot {
+		if err := os.Remove(partialPath); err != nil {
+			log.WithError(err).Error("Could not remove corrupt state download")
+		}
+		return nil, errors.Wrapf(errCheckpointStateMismatch, "downloaded state root = %#x, expected %#x", od.sr, expectedRoot)
+	}
+	if err := os.Remove(partialPath); err != nil {
+		log.WithError(err).Warn("Could not remove state download")
+	}
+	return od, nil
+}
+
+func originDataFromState(ctx context.Context, client *Client, sb []byte) (*OriginData, error) {
 	vu, err := detect.FromState(sb)
 	if err != nil {
-		return nil, errors.Wrap(err, "error detecting chain config for finalized state")
+		return nil, errors.Wrap(err, "error detecting chain config for checkpoint state")
 	}
 
 	log.WithFields(logrus.Fields{
 		"name": vu.Config.ConfigName,
 		"fork": version.String(vu.Fork),
-	}).Info("Detected supported config in remote finalized state")
+	}).Info("Detected supported config in remote checkpoint state")
 
 	s, err := vu.UnmarshalBeaconState(sb)
 	if err != nil {
-		return nil, errors.Wrap(err, "error unmarshaling finalized state to correct version")
+		return nil, errors.Wrap(err, "error unmarshaling checkpoint state to correct version")
 	}
 
 	slot := s.LatestBlockHeader().Slot
@@ -109,7 +172,7 @@ func DownloadFinalizedData(ctx context.Context, client *Client) (*OriginData, er
 	}
 	sr, err := s.HashTreeRoot(ctx)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to compute htr for finalized state at slot=%d", s.Slot())
+		return nil, errors.Wrapf(err, "failed to compute htr for checkpoint state at slot=%d", s.Slot())
 	}
 
 	log.
@@ -438,9 +438,13 @@ func TestDownloadFinalizedData(t *testing.T) {
 	trans := &testRT{rt: func(req *http.Request) (*http.Response, error) {
 		res := &http.Response{Request: req}
 		switch req.URL.Path {
-		case renderGetStatePath(IdFinalized):
+		case renderGetStatePath(IdFinalized), renderGetStatePath(IdFromRoot(sr)):
 			res.StatusCode = http.StatusOK
+			res.ContentLength = int64(len(ms))
 			res.Body = io.NopCloser(bytes.NewBuffer(ms))
+		case getStateRootTpl(IdFinalized):
+			res.StatusCode = http.StatusOK
+			res.Body = io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"data":{"root":"%#x"}}`, sr)))
 		case renderGetBlockPath(IdFromSlot(b.Block().Slot())):
 			res.StatusCode = http.StatusOK
 			res.Body = io.NopCloser(bytes.NewBuffer(mb))
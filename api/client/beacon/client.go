@@ -28,6 +28,8 @@ import (
 const (
 	getSignedBlockPath       = "/eth/v2/beacon/blocks"
 	getBlockRootPath         = "/eth/v1/beacon/blocks/{{.Id}}/root"
+	getBlockHeaderPath       = "/eth/v1/beacon/headers/{{.Id}}"
+	getStateRootPath         = "/eth/v1/beacon/states/{{.Id}}/root"
 	getForkForStatePath      = "/eth/v1/beacon/states/{{.Id}}/fork"
 	getWeakSubjectivityPath  = "/prysm/v1/beacon/weak_subjectivity"
 	getForkSchedulePath      = "/eth/v1/config/fork_schedule"
@@ -35,6 +37,7 @@ const (
 	getStatePath             = "/eth/v2/debug/beacon/states"
 	getNodeVersionPath       = "/eth/v1/node/version"
 	changeBLStoExecutionPath = "/eth/v1/beacon/pool/bls_to_execution_changes"
+	getProposerDutiesPath    = "/eth/v1/validator/duties/proposer"
 )
 
 // StateOrBlockId represents the block_id / state_id parameters that several of the Eth Beacon API methods accept.
@@ -136,6 +139,51 @@ func (c *Client) GetBlockRoot(ctx context.Context, blockId StateOrBlockId) ([32]
 	return bytesutil.ToBytes32(rs), nil
 }
 
+var getBlockHeaderTpl = idTemplate(getBlockHeaderPath)
+
+// GetBlockHeader retrieves the signed BeaconBlockHeader of the block for the given block id.
+// Block identifier can be one of: "head" (canonical head in node's view), "genesis", "finalized",
+// <slot>, <hex encoded blockRoot with 0x prefix>. Variables of type StateOrBlockId are exported by this package
+// for the named identifiers.
+func (c *Client) GetBlockHeader(ctx context.Context, blockId StateOrBlockId) (*ethpb.SignedBeaconBlockHeader, error) {
+	b, err := c.Get(ctx, getBlockHeaderTpl(blockId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting block header by id = %s", blockId)
+	}
+	resp := &structs.GetBlockHeaderResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json data from get block header response")
+	}
+	if resp.Data == nil || resp.Data.Header == nil {
+		return nil, errors.New("empty block header response")
+	}
+	return resp.Data.Header.ToConsensus()
+}
+
+var getStateRootTpl = idTemplate(getStateRootPath)
+
+// GetStateRoot retrieves the hash_tree_root of the BeaconState for the given state id.
+// State identifier can be one of: "head" (canonical head in node's view), "genesis", "finalized",
+// <slot>, <hex encoded stateRoot with 0x prefix>. Variables of type StateOrBlockId are exported by this package
+// for the named identifiers.
+func (c *Client) GetStateRoot(ctx context.Context, stateId StateOrBlockId) ([32]byte, error) {
+	rootPath := getStateRootTpl(stateId)
+	b, err := c.Get(ctx, rootPath)
+	if err != nil {
+		return [32]byte{}, errors.Wrapf(err, "error requesting state root by id = %s", stateId)
+	}
+	jsonr := &struct{ Data struct{ Root string } }{}
+	err = json.Unmarshal(b, jsonr)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "error decoding json data from get state root response")
+	}
+	rs, err := hexutil.Decode(jsonr.Data.Root)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, fmt.Sprintf("error decoding hex-encoded value %s", jsonr.Data.Root))
+	}
+	return bytesutil.ToBytes32(rs), nil
+}
+
 var getForkTpl = idTemplate(getForkForStatePath)
 
 // GetFork queries the Beacon Node API for the Fork from the state identified by stateId.
@@ -157,6 +205,20 @@ func (c *Client) GetFork(ctx context.Context, stateId StateOrBlockId) (*ethpb.Fo
 	return fr.ToConsensus()
 }
 
+// GetProposerDuties retrieves the block proposers of every slot of the given epoch, along with the root of the
+// block the duties depend on.
+func (c *Client) GetProposerDuties(ctx context.Context, epoch primitives.Epoch) (*structs.GetProposerDutiesResponse, error) {
+	b, err := c.Get(ctx, path.Join(getProposerDutiesPath, strconv.FormatUint(uint64(epoch), 10)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting proposer duties of epoch %d", epoch)
+	}
+	resp := &structs.GetProposerDutiesResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrap(err, "error decoding json data from get proposer duties response")
+	}
+	return resp, nil
+}
+
 // GetForkSchedule retrieve all forks, past present and future, of which this node is aware.
 func (c *Client) GetForkSchedule(ctx context.Context) (forks.OrderedSchedule, error) {
 	body, err := c.Get(ctx, getForkSchedulePath)
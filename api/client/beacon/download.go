@@ -0,0 +1,74 @@
+package beacon
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	base "github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	partialStatePrefix = "state_"
+	partialStateSuffix = ".ssz.part"
+	// progressLogInterval is the minimum time between two state download progress logs.
+	progressLogInterval = 10 * time.Second
+)
+
+// PartialStatePath returns the path of the partial download of the state with the given root in dir.
+func PartialStatePath(dir string, root [32]byte) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%#x%s", partialStatePrefix, root, partialStateSuffix))
+}
+
+// PartialStateRoots returns the roots of the states with a partial download in dir, which can be resumed by
+// downloading the state by its root.
+func PartialStateRoots(dir string) ([][32]byte, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, partialStatePrefix+"0x*"+partialStateSuffix))
+	if err != nil {
+		return nil, err
+	}
+	roots := make([][32]byte, 0, len(matches))
+	for _, m := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), partialStatePrefix), partialStateSuffix)
+		r, err := hexutil.Decode(name)
+		if err != nil || len(r) != 32 {
+			continue
+		}
+		roots = append(roots, bytesutil.ToBytes32(r))
+	}
+	return roots, nil
+}
+
+// root returns the root encoded in the id, if it identifies a state or block by root.
+func (id StateOrBlockId) root() ([32]byte, bool) {
+	r, err := hexutil.Decode(string(id))
+	if err != nil || len(r) != 32 {
+		return [32]byte{}, false
+	}
+	return bytesutil.ToBytes32(r), true
+}
+
+func downloadProgressLogger(root [32]byte) base.ProgressFunc {
+	start := time.Now()
+	var last time.Time
+	return func(downloaded, total int64) {
+		if time.Since(last) < progressLogInterval && downloaded != total {
+			return
+		}
+		last = time.Now()
+		fields := logrus.Fields{
+			"stateRoot":  fmt.Sprintf("%#x", root),
+			"downloaded": fmt.Sprintf("%.1fMiB", float64(downloaded)/(1<<20)),
+			"elapsed":    time.Since(start).Round(time.Second),
+		}
+		if total > 0 {
+			fields["total"] = fmt.Sprintf("%.1fMiB", float64(total)/(1<<20))
+			fields["percent"] = fmt.Sprintf("%.1f", float64(downloaded)*100/float64(total))
+		}
+		log.WithFields(fields).Info("Downloading checkpoint state")
+	}
+}
@@ -0,0 +1,107 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+)
+
+const (
+	getGenesisPath                     = "/eth/v1/beacon/genesis"
+	getLightClientBootstrapPath        = "/eth/v1/beacon/light_client/bootstrap"
+	getLightClientUpdatesPath          = "/eth/v1/beacon/light_client/updates"
+	getLightClientFinalityUpdatePath   = "/eth/v1/beacon/light_client/finality_update"
+	getLightClientOptimisticUpdatePath = "/eth/v1/beacon/light_client/optimistic_update"
+)
+
+// GetGenesis retrieves the genesis time, genesis validators root and genesis fork version of the chain.
+func (c *Client) GetGenesis(ctx context.Context) (*structs.Genesis, error) {
+	b, err := c.Get(ctx, getGenesisPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting genesis")
+	}
+	resp := &structs.GetGenesisResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling response body: %s", string(b))
+	}
+	if resp.Data == nil {
+		return nil, errors.New("empty genesis response")
+	}
+	return resp.Data, nil
+}
+
+// GetLightClientBootstrap retrieves the light client bootstrap for the given block root.
+func (c *Client) GetLightClientBootstrap(ctx context.Context, blockRoot [32]byte) (*structs.LightClientBootstrapResponse, error) {
+	b, err := c.Get(ctx, path.Join(getLightClientBootstrapPath, fmt.Sprintf("%#x", blockRoot)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting light client bootstrap for root %#x", blockRoot)
+	}
+	resp := &structs.LightClientBootstrapResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling light client bootstrap response")
+	}
+	if resp.Data == nil {
+		return nil, errors.New("empty light client bootstrap response")
+	}
+	return resp, nil
+}
+
+// GetLightClientUpdatesByRange retrieves up to count light client updates, starting at the given sync committee period.
+func (c *Client) GetLightClientUpdatesByRange(ctx context.Context, startPeriod, count uint64) ([]*structs.LightClientUpdateResponse, error) {
+	q := url.Values{}
+	q.Set("start_period", strconv.FormatUint(startPeriod, 10))
+	q.Set("count", strconv.FormatUint(count, 10))
+	b, err := c.Get(ctx, getLightClientUpdatesPath, client.WithQueryParams(q))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting light client updates from period %d", startPeriod)
+	}
+	// The Beacon API specifies a list of updates, some servers wrap the list in an object.
+	var updates []*structs.LightClientUpdateResponse
+	if err := json.Unmarshal(b, &updates); err == nil {
+		return updates, nil
+	}
+	resp := &structs.LightClientUpdatesByRangeResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling light client updates response")
+	}
+	return resp.Updates, nil
+}
+
+// GetLightClientFinalityUpdate retrieves the latest light client finality update known to the node.
+func (c *Client) GetLightClientFinalityUpdate(ctx context.Context) (*structs.LightClientFinalityUpdateResponse, error) {
+	b, err := c.Get(ctx, getLightClientFinalityUpdatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting light client finality update")
+	}
+	resp := &structs.LightClientFinalityUpdateResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling light client finality update response")
+	}
+	if resp.Data == nil {
+		return nil, errors.New("empty light client finality update response")
+	}
+	return resp, nil
+}
+
+// GetLightClientOptimisticUpdate retrieves the latest light client optimistic update known to the node.
+func (c *Client) GetLightClientOptimisticUpdate(ctx context.Context) (*structs.LightClientOptimisticUpdateResponse, error) {
+	b, err := c.Get(ctx, getLightClientOptimisticUpdatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error requesting light client optimistic update")
+	}
+	resp := &structs.LightClientOptimisticUpdateResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling light client optimistic update response")
+	}
+	if resp.Data == nil {
+		return nil, errors.New("empty light client optimistic update response")
+	}
+	return resp, nil
+}
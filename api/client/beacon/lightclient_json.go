@@ -0,0 +1,156 @@
+package beacon
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+// LightClientStoreFromBootstrap initializes a light client store from a bootstrap for the trusted block root,
+// in the form served by the Beacon API.
+func LightClientStoreFromBootstrap(trustedRoot [32]byte, b *structs.LightClientBootstrap) (*lightclient.Store, error) {
+	if b == nil {
+		return nil, errors.Wrap(lightclient.ErrInvalidBootstrap, "empty bootstrap")
+	}
+	header, err := lightClientHeaderFromJSON(b.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode bootstrap header")
+	}
+	if b.CurrentSyncCommittee == nil {
+		return nil, errors.Wrap(lightclient.ErrInvalidBootstrap, "bootstrap has no current sync committee")
+	}
+	committee, err := b.CurrentSyncCommittee.ToConsensus()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode bootstrap sync committee")
+	}
+	branch, err := lightClientBranchFromJSON(b.CurrentSyncCommitteeBranch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode bootstrap sync committee branch")
+	}
+	return lightclient.NewStore(trustedRoot, header, committee, branch)
+}
+
+// lightClientHeaderFromJSON decodes the beacon block header of a light client header of any fork. It returns nil for
+// an empty header.
+func lightClientHeaderFromJSON(raw json.RawMessage) (*ethpb.BeaconBlockHeader, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	h := &struct {
+		Beacon *structs.BeaconBlockHeader `json:"beacon"`
+	}{}
+	if err := json.Unmarshal(raw, h); err != nil {
+		return nil, err
+	}
+	if h.Beacon == nil {
+		return nil, errors.New("light client header has no beacon header")
+	}
+	return h.Beacon.ToConsensus()
+}
+
+// LightClientUpdateFromJSON decodes a light client update served by the Beacon API.
+func LightClientUpdateFromJSON(u *structs.LightClientUpdate) (*lightclient.Update, error) {
+	if u == nil {
+		return nil, errors.New("empty light client update")
+	}
+	fu, err := LightClientFinalityUpdateFromJSON(&structs.LightClientFinalityUpdate{
+		AttestedHeader:  u.AttestedHeader,
+		FinalizedHeader: u.FinalizedHeader,
+		FinalityBranch:  u.FinalityBranch,
+		SyncAggregate:   u.SyncAggregate,
+		SignatureSlot:   u.SignatureSlot,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if u.NextSyncCommittee != nil {
+		if fu.NextSyncCommittee, err = u.NextSyncCommittee.ToConsensus(); err != nil {
+			return nil, errors.Wrap(err, "could not decode next sync committee")
+		}
+		if fu.NextSyncCommitteeBranch, err = lightClientBranchFromJSON(u.NextSyncCommitteeBranch); err != nil {
+			return nil, errors.Wrap(err, "could not decode next sync committee branch")
+		}
+	}
+	return fu, nil
+}
+
+// LightClientFinalityUpdateFromJSON decodes a light client finality update served by the Beacon API.
+func LightClientFinalityUpdateFromJSON(u *structs.LightClientFinalityUpdate) (*lightclient.Update, error) {
+	if u == nil {
+		return nil, errors.New("empty light client update")
+	}
+	ou, err := LightClientOptimisticUpdateFromJSON(&structs.LightClientOptimisticUpdate{
+		AttestedHeader: u.AttestedHeader,
+		SyncAggregate:  u.SyncAggregate,
+		SignatureSlot:  u.SignatureSlot,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ou.FinalizedHeader, err = lightClientHeaderFromJSON(u.FinalizedHeader); err != nil {
+		return nil, errors.Wrap(err, "could not decode finalized header")
+	}
+	if ou.FinalityBranch, err = lightClientBranchFromJSON(u.FinalityBranch); err != nil {
+		return nil, errors.Wrap(err, "could not decode finality branch")
+	}
+	return ou, nil
+}
+
+// LightClientOptimisticUpdateFromJSON decodes a light client optimistic update served by the Beacon API.
+func LightClientOptimisticUpdateFromJSON(u *structs.LightClientOptimisticUpdate) (*lightclient.Update, error) {
+	if u == nil {
+		return nil, errors.New("empty light client update")
+	}
+	attested, err := lightClientHeaderFromJSON(u.AttestedHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode attested header")
+	}
+	if attested == nil {
+		return nil, errors.New("light client update has no attested header")
+	}
+	sa, err := lightClientSyncAggregateFromJSON(u.SyncAggregate)
+	if err != nil {
+		return nil, err
+	}
+	signatureSlot, err := strconv.ParseUint(u.SignatureSlot, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature slot")
+	}
+	return &lightclient.Update{
+		AttestedHeader: attested,
+		SyncAggregate:  sa,
+		SignatureSlot:  primitives.Slot(signatureSlot),
+	}, nil
+}
+
+func lightClientBranchFromJSON(branch []string) ([][]byte, error) {
+	b := make([][]byte, len(branch))
+	for i, s := range branch {
+		var err error
+		if b[i], err = hexutil.Decode(s); err != nil {
+			return nil, errors.Wrapf(err, "could not decode branch element %d", i)
+		}
+	}
+	return b, nil
+}
+
+func lightClientSyncAggregateFromJSON(sa *structs.SyncAggregate) (*ethpb.SyncAggregate, error) {
+	if sa == nil {
+		return nil, errors.New("missing sync aggregate")
+	}
+	bits, err := hexutil.Decode(sa.SyncCommitteeBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode sync committee bits")
+	}
+	sig, err := hexutil.Decode(sa.SyncCommitteeSignature)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode sync committee signature")
+	}
+	return &ethpb.SyncAggregate{SyncCommitteeBits: bits, SyncCommitteeSignature: sig}, nil
+}
@@ -0,0 +1,75 @@
+package beacon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestUpdateFromJSON(t *testing.T) {
+	root := "0x" + "11111111111111111111111111111111" + "11111111111111111111111111111111"
+	header := &structs.BeaconBlockHeader{
+		Slot:          "100",
+		ProposerIndex: "1",
+		ParentRoot:    root,
+		StateRoot:     root,
+		BodyRoot:      root,
+	}
+	raw, err := json.Marshal(&structs.LightClientHeaderCapella{Beacon: header})
+	require.NoError(t, err)
+	finalizedHeader := *header
+	finalizedHeader.Slot = "64"
+	finalizedRaw, err := json.Marshal(&structs.LightClientHeader{Beacon: &finalizedHeader})
+	require.NoError(t, err)
+
+	u, err := LightClientUpdateFromJSON(&structs.LightClientUpdate{
+		AttestedHeader:  raw,
+		FinalizedHeader: finalizedRaw,
+		FinalityBranch:  []string{root, root},
+		SyncAggregate: &structs.SyncAggregate{
+			SyncCommitteeBits:      "0x0f",
+			SyncCommitteeSignature: "0xc0",
+		},
+		SignatureSlot: "101",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(100), u.AttestedHeader.Slot)
+	assert.Equal(t, primitives.Slot(64), u.FinalizedHeader.Slot)
+	assert.Equal(t, 2, len(u.FinalityBranch))
+	assert.Equal(t, primitives.Slot(101), u.SignatureSlot)
+	assert.DeepEqual(t, []byte{0x0f}, []byte(u.SyncAggregate.SyncCommitteeBits))
+	assert.Equal(t, true, u.NextSyncCommittee == nil)
+
+	_, err = LightClientUpdateFromJSON(&structs.LightClientUpdate{
+		AttestedHeader: json.RawMessage(`{}`),
+		SyncAggregate:  &structs.SyncAggregate{SyncCommitteeBits: "0x00", SyncCommitteeSignature: "0x00"},
+		SignatureSlot:  "1",
+	})
+	require.ErrorContains(t, "no beacon header", err)
+}
+
+func TestOptimisticUpdateFromJSON(t *testing.T) {
+	root := "0x" + "11111111111111111111111111111111" + "11111111111111111111111111111111"
+	raw, err := json.Marshal(&structs.LightClientHeader{Beacon: &structs.BeaconBlockHeader{
+		Slot:          "100",
+		ProposerIndex: "1",
+		ParentRoot:    root,
+		StateRoot:     root,
+		BodyRoot:      root,
+	}})
+	require.NoError(t, err)
+
+	u, err := LightClientOptimisticUpdateFromJSON(&structs.LightClientOptimisticUpdate{
+		AttestedHeader: raw,
+		SyncAggregate:  &structs.SyncAggregate{SyncCommitteeBits: "0x0f", SyncCommitteeSignature: "0xc0"},
+		SignatureSlot:  "101",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, primitives.Slot(100), u.AttestedHeader.Slot)
+	assert.Equal(t, true, u.FinalizedHeader == nil)
+	assert.Equal(t, primitives.Slot(101), u.SignatureSlot)
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DownloadChunkSize is the size of the ranges requested by GetToFile from servers supporting range requests.
+	DownloadChunkSize = 64 << 20
+	// downloadMaxRetries is the number of consecutive failed attempts after which GetToFile gives up.
+	downloadMaxRetries = 5
+)
+
+// errRangeMismatch indicates the server answered a range request with content at an unexpected offset.
+var errRangeMismatch = errors.New("server returned an unexpected content range")
+
+// ProgressFunc is called by GetToFile after every write, with the bytes downloaded so far and the total size,
+// which is -1 if the server did not announce it.
+type ProgressFunc func(downloaded, total int64)
+
+// GetToFile downloads the response body of a GET request for path to the file at dst. When the server supports
+// range requests the body is fetched in chunks of DownloadChunkSize and appended to the content already in dst,
+// so that an interrupted download resumes where it stopped, both within this call and across calls. Otherwise,
+// the whole body is downloaded again. Failed chunks are retried. It returns the size of the complete file,
+// after checking it against the size announced by the server.
+func (c *Client) GetToFile(ctx context.Context, path, dst string, progress ProgressFunc, opts ...ReqOption) (offset int64, err error) {
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not open %s", dst)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	offset, err = f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	total := int64(-1)
+	failures := 0
+	for total < 0 || offset < total {
+		var next int64
+		next, total, err = c.getChunk(ctx, path, f, offset, progress, opts...)
+		if next > offset {
+			failures = 0
+		}
+		offset = next
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil || errors.Is(err, ErrNotOK) {
+			return offset, err
+		}
+		failures++
+		if failures >= downloadMaxRetries {
+			return offset, errors.Wrapf(err, "download failed after %d attempts", failures)
+		}
+		select {
+		case <-ctx.Done():
+			return offset, ctx.Err()
+		case <-time.After(time.Duration(failures) * time.Second):
+		}
+	}
+	if offset != total {
+		return offset, fmt.Errorf("downloaded %d bytes, expected %d", offset, total)
+	}
+	return offset, nil
+}
+
+// getChunk requests the chunk starting at offset and writes it to f. It returns the offset following the written
+// content and the total size of the resource, or -1 if it is not known yet.
+func (c *Client) getChunk(ctx context.Context, path string, f *os.File, offset int64, progress ProgressFunc, opts ...ReqOption) (int64, int64, error) {
+	u := c.baseURL.ResolveReference(&url.URL{Path: path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return offset, -1, err
+	}
+	for _, o := range opts {
+		o(req)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+DownloadChunkSize-1))
+	r, err := c.hc.Do(req)
+	if err != nil {
+		return offset, -1, err
+	}
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	switch r.StatusCode {
+	case http.StatusPartialContent:
+		start, total, err := parseContentRange(r.Header.Get("Content-Range"))
+		if err != nil {
+			return offset, -1, err
+		}
+		if start != offset {
+			return offset, total, errors.Wrapf(errRangeMismatch, "requested offset %d, got %d", offset, start)
+		}
+		return copyAt(f, r.Body, offset, total, progress)
+	case http.StatusOK:
+		// The server does not support range requests, the full body has to be downloaded again.
+		if err := f.Truncate(0); err != nil {
+			return offset, -1, err
+		}
+		next, _, err := copyAt(f, r.Body, 0, r.ContentLength, progress)
+		if err != nil {
+			return next, -1, err
+		}
+		if r.ContentLength >= 0 && next != r.ContentLength {
+			return next, -1, io.ErrUnexpectedEOF
+		}
+		return next, next, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial content is at least as large as the resource, check if it was already complete.
+		_, total, err := parseContentRange(r.Header.Get("Content-Range"))
+		if err == nil && total == offset {
+			return offset, total, nil
+		}
+		if err := f.Truncate(0); err != nil {
+			return offset, -1, err
+		}
+		return 0, -1, errors.Wrapf(errRangeMismatch, "partial content of %d bytes is larger than the resource", offset)
+	default:
+		return offset, -1, Non200Err(r)
+	}
+}
+
+func copyAt(f *os.File, body io.Reader, offset, total int64, progress ProgressFunc) (int64, int64, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, total, err
+	}
+	buf := make([]byte, 1<<20)
+	for {
+		n, rerr := body.Read(buf)
+		if n > 0 {
+			if _, err := f.Write(buf[:n]); err != nil {
+				return offset, total, err
+			}
+			offset += int64(n)
+			if progress != nil {
+				progress(offset, total)
+			}
+		}
+		if rerr == io.EOF {
+			return offset, total, nil
+		}
+		if rerr != nil {
+			return offset, total, rerr
+		}
+	}
+}
+
+// parseContentRange parses the start offset and the total size out of a Content-Range header of the form
+// "bytes start-end/total" or "bytes */total".
+func parseContentRange(h string) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(h, "bytes ")
+	if !ok {
+		return 0, -1, fmt.Errorf("invalid content range %q", h)
+	}
+	rng, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, -1, fmt.Errorf("invalid content range %q", h)
+	}
+	total := int64(-1)
+	if size != "*" {
+		t, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, -1, errors.Wrapf(err, "invalid content range %q", h)
+		}
+		total = t
+	}
+	if rng == "*" {
+		return 0, total, nil
+	}
+	start, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, -1, fmt.Errorf("invalid content range %q", h)
+	}
+	s, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, -1, errors.Wrapf(err, "invalid content range %q", h)
+	}
+	return s, total, nil
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func testContent() []byte {
+	content := make([]byte, 3<<20)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	return content
+}
+
+func TestGetToFile(t *testing.T) {
+	ctx := context.Background()
+	content := testContent()
+	serveContent := func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "state.ssz", time.Time{}, bytes.NewReader(content))
+	}
+
+	t.Run("full download", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(serveContent))
+		defer srv.Close()
+		c, err := NewClient(srv.URL)
+		require.NoError(t, err)
+		dst := filepath.Join(t.TempDir(), "state.part")
+		var progressed int64
+		n, err := c.GetToFile(ctx, "/state", dst, func(downloaded, total int64) {
+			require.Equal(t, int64(len(content)), total)
+			progressed = downloaded
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), n)
+		require.Equal(t, n, progressed)
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.DeepEqual(t, content, b)
+	})
+	t.Run("resume partial file", func(t *testing.T) {
+		var ranges []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ranges = append(ranges, r.Header.Get("Range"))
+			serveContent(w, r)
+		}))
+		defer srv.Close()
+		c, err := NewClient(srv.URL)
+		require.NoError(t, err)
+		dst := filepath.Join(t.TempDir(), "state.part")
+		require.NoError(t, os.WriteFile(dst, content[:1000], 0600))
+		n, err := c.GetToFile(ctx, "/state", dst, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), n)
+		require.DeepEqual(t, []string{"bytes=1000-" + strconv.Itoa(1000+DownloadChunkSize-1)}, ranges)
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.DeepEqual(t, content, b)
+	})
+	t.Run("complete partial file", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(serveContent))
+		defer srv.Close()
+		c, err := NewClient(srv.URL)
+		require.NoError(t, err)
+		dst := filepath.Join(t.TempDir(), "state.part")
+		require.NoError(t, os.WriteFile(dst, content, 0600))
+		n, err := c.GetToFile(ctx, "/state", dst, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), n)
+	})
+	t.Run("interrupted download is resumed", func(t *testing.T) {
+		failed := false
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !failed {
+				failed = true
+				w.Header().Set("Content-Range", "bytes 0-"+strconv.Itoa(len(content)-1)+"/"+strconv.Itoa(len(content)))
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write(content[:len(content)/2])
+				panic(http.ErrAbortHandler)
+			}
+			serveContent(w, r)
+		}))
+		defer srv.Close()
+		c, err := NewClient(srv.URL)
+		require.NoError(t, err)
+		dst := filepath.Join(t.TempDir(), "state.part")
+		n, err := c.GetToFile(ctx, "/state", dst, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), n)
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.DeepEqual(t, content, b)
+	})
+	t.Run("range requests not supported", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(content)
+		}))
+		defer srv.Close()
+		c, err := NewClient(srv.URL)
+		require.NoError(t, err)
+		dst := filepath.Join(t.TempDir(), "state.part")
+		require.NoError(t, os.WriteFile(dst, []byte("stale"), 0600))
+		n, err := c.GetToFile(ctx, "/state", dst, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), n)
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.DeepEqual(t, content, b)
+	})
+	t.Run("not found", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+		c, err := NewClient(srv.URL)
+		require.NoError(t, err)
+		_, err = c.GetToFile(ctx, "/state", filepath.Join(t.TempDir(), "state.part"), nil)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, total, err := parseContentRange("bytes 100-199/1000")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), start)
+	require.Equal(t, int64(1000), total)
+
+	_, total, err = parseContentRange("bytes */1000")
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), total)
+
+	_, total, err = parseContentRange("bytes 0-99/*")
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), total)
+
+	_, _, err = parseContentRange("items 0-99/1000")
+	require.ErrorContains(t, "invalid content range", err)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strings"
@@ -21,6 +22,20 @@ func NormalizeQueryValuesHandler(next http.Handler) http.Handler {
 	})
 }
 
+// AuthorizationHandler rejects requests which do not carry the bearer token in their Authorization header.
+func AuthorizationHandler(token string) Middleware {
+	want := []byte("Bearer " + token)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // CorsHandler sets the cors settings on api endpoints
 func CorsHandler(allowOrigins []string) Middleware {
 	c := cors.New(cors.Options{
@@ -202,3 +202,55 @@ func TestAcceptHeaderHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorizationHandler(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("next handler"))
+		require.NoError(t, err)
+	})
+
+	handler := AuthorizationHandler("secret")(nextHandler)
+
+	tests := []struct {
+		name               string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Valid token",
+			authorization:      "Bearer secret",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Invalid token",
+			authorization:      "Bearer other",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "Token without scheme",
+			authorization:      "secret",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "Missing Authorization",
+			authorization:      "",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatusCode {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatusCode)
+			}
+		})
+	}
+}
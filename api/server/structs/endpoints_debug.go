@@ -55,3 +55,35 @@ type ForkChoiceNodeExtraData struct {
 	ExecutionOptimistic      bool   `json:"execution_optimistic"`
 	TimeStamp                string `json:"timestamp"`
 }
+
+type GetRateLimitsResponse struct {
+	Data []*PeerRateLimits `json:"data"`
+}
+
+type PeerRateLimits struct {
+	PeerId      string             `json:"peer_id"`
+	QuotaFactor string             `json:"quota_factor"`
+	Topics      []*TopicRateLimits `json:"topics"`
+}
+
+type TopicRateLimits struct {
+	Topic         string `json:"topic"`
+	Used          string `json:"used"`
+	Capacity      string `json:"capacity"`
+	BytesUsed     string `json:"bytes_used"`
+	BytesCapacity string `json:"bytes_capacity"`
+}
+
+type GetPendingBlocksResponse struct {
+	Data []*PendingChain `json:"data"`
+}
+
+type PendingChain struct {
+	MissingRoot string   `json:"missing_root"`
+	BaseSlot    string   `json:"base_slot"`
+	HeadRoot    string   `json:"head_root"`
+	HeadSlot    string   `json:"head_slot"`
+	Blocks      string   `json:"blocks"`
+	Bytes       string   `json:"bytes"`
+	Peers       []string `json:"peers"`
+}
@@ -71,3 +71,94 @@ type AddrRequest struct {
 type PeersResponse struct {
 	Peers []*Peer `json:"peers"`
 }
+
+type GetPeerScoreResponse struct {
+	Data *PeerScore `json:"data"`
+}
+
+type PeerScore struct {
+	PeerId        string `json:"peer_id"`
+	Score         string `json:"score"`
+	BadResponses  string `json:"bad_responses"`
+	BlockProvider string `json:"block_provider"`
+	PeerStatus    string `json:"peer_status"`
+	Gossip        string `json:"gossip"`
+	IsBad         bool   `json:"is_bad"`
+	IsBanned      bool   `json:"is_banned"`
+}
+
+type BanPeerRequest struct {
+	PeerId string `json:"peer_id"`
+	// DurationSeconds of the ban, an empty or zero value bans the peer until it is explicitly unbanned.
+	DurationSeconds string `json:"duration_seconds"`
+}
+
+type BannedPeer struct {
+	PeerId string `json:"peer_id"`
+	// Expiry is the unix timestamp at which the ban is lifted, "0" for bans without expiry.
+	Expiry string `json:"expiry"`
+}
+
+type BannedPeersResponse struct {
+	Peers []*BannedPeer `json:"peers"`
+}
+
+type GetBackfillStatusResponse struct {
+	Data *BackfillStatus `json:"data"`
+}
+
+type BackfillStatus struct {
+	Running    bool   `json:"running"`
+	Paused     bool   `json:"paused"`
+	Throttled  bool   `json:"throttled"`
+	LowSlot    string `json:"low_slot"`
+	TargetSlot string `json:"target_slot"`
+	OriginSlot string `json:"origin_slot"`
+	// SlotsPerSecond is the rate at which backfill moved down the chain since it started.
+	SlotsPerSecond string `json:"slots_per_second"`
+	// EtaSeconds is the estimated time to reach the target slot, "0" when it can not be estimated.
+	EtaSeconds string `json:"eta_seconds"`
+	// BandwidthLimit is in bytes per second, "0" when unlimited.
+	BandwidthLimit string `json:"bandwidth_limit"`
+	// ActiveHours is the daily window of UTC hours in which backfill downloads blocks, e.g. "22-6", empty when unrestricted.
+	ActiveHours string                    `json:"active_hours"`
+	Peers       []*BackfillPeerThroughput `json:"peers"`
+}
+
+type BackfillPeerThroughput struct {
+	PeerId         string `json:"peer_id"`
+	Batches        string `json:"batches"`
+	Blocks         string `json:"blocks"`
+	Bytes          string `json:"bytes"`
+	BytesPerSecond string `json:"bytes_per_second"`
+}
+
+type SetBackfillConfigRequest struct {
+	// BandwidthLimit in bytes per second, "0" removes the limit. Left unchanged when omitted.
+	BandwidthLimit *string `json:"bandwidth_limit"`
+	// ActiveHours as "start-end" UTC hours, an empty value removes the restriction. Left unchanged when omitted.
+	ActiveHours *string `json:"active_hours"`
+}
+
+type GetHistoryResponse struct {
+	Data *History `json:"data"`
+}
+
+type History struct {
+	// EarliestAvailableSlot is the lowest slot from which the node stores blocks and states, apart from genesis.
+	EarliestAvailableSlot string `json:"earliest_available_slot"`
+}
+
+type WriteSnapshotResponse struct {
+	Data *Snapshot `json:"data"`
+}
+
+type Snapshot struct {
+	// Path is the path of the snapshot archive on the beacon node host.
+	Path           string `json:"path"`
+	HeadSlot       string `json:"head_slot"`
+	HeadRoot       string `json:"head_root"`
+	FinalizedEpoch string `json:"finalized_epoch"`
+	FinalizedRoot  string `json:"finalized_root"`
+	Blobs          string `json:"blobs"`
+}
@@ -106,3 +106,13 @@ func TestError(t *testing.T) {
 		t.Fatalf("Missing expected error")
 	}
 }
+
+func TestError_AllWorkers(t *testing.T) {
+	// Every worker failing must not send to a closed channel after the first error is returned.
+	for i := 0; i < 100; i++ {
+		_, err := async.Scatter(1024, func(offset int, entries int, _ *sync.RWMutex) (interface{}, error) {
+			return nil, errors.New("bad chunk")
+		})
+		require.ErrorContains(t, "bad chunk", err)
+	}
+}
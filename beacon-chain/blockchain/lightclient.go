@@ -0,0 +1,329 @@
+package blockchain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ssz "github.com/prysmaticlabs/fastssz"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+// lightClientUpdate builds the light client update signed by the sync aggregate of the block, given the block's
+// post state. It returns nil when the block does not carry enough sync committee signatures to make an update.
+func (s *Service) lightClientUpdate(
+	ctx context.Context,
+	signed interfaces.ReadOnlySignedBeaconBlock,
+	postState state.BeaconState,
+) (*ethpbv2.LightClientUpdateWithVersion, error) {
+	if signed.Version() < version.Altair {
+		return nil, nil
+	}
+	syncAggregate, err := signed.Block().Body().SyncAggregate()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get sync aggregate")
+	}
+	if syncAggregate.SyncCommitteeBits.Count() < params.BeaconConfig().MinSyncCommitteeParticipants {
+		return nil, nil
+	}
+
+	attestedRoot := signed.Block().ParentRoot()
+	attestedBlock, err := s.cfg.BeaconDB.Block(ctx, attestedRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get attested block")
+	}
+	if attestedBlock == nil || attestedBlock.IsNil() || attestedBlock.Version() < version.Altair {
+		return nil, nil
+	}
+	attestedState, err := s.cfg.StateGen.StateByRoot(ctx, attestedRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get attested state")
+	}
+
+	var finalizedBlock interfaces.ReadOnlySignedBeaconBlock
+	finalizedCheckPoint := attestedState.FinalizedCheckpoint()
+	if finalizedCheckPoint != nil {
+		finalizedRoot := bytesutil.ToBytes32(finalizedCheckPoint.Root)
+		finalizedBlock, err = s.cfg.BeaconDB.Block(ctx, finalizedRoot)
+		if err != nil {
+			finalizedBlock = nil
+		}
+	}
+
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, postState, signed, attestedState, attestedBlock, finalizedBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create light client update")
+	}
+	return &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(attestedBlock.Version()),
+		Data:    update,
+	}, nil
+}
+
+// saveBestLightClientUpdate stores the update for the sync committee period of its attested header, unless the
+// update already stored for that period is better. It returns whether the update was stored.
+func (s *Service) saveBestLightClientUpdate(ctx context.Context, update *ethpbv2.LightClientUpdateWithVersion) (bool, error) {
+	attestedHeader, err := update.Data.AttestedHeader.GetBeacon()
+	if err != nil {
+		return false, errors.Wrap(err, "could not get attested header")
+	}
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+
+	s.lightClientUpdateLock.Lock()
+	defer s.lightClientUpdateLock.Unlock()
+
+	old, err := s.cfg.BeaconDB.LightClientUpdate(ctx, period)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get stored light client update")
+	}
+	if old != nil && old.Data != nil {
+		better, err := lightclient.IsBetterUpdate(update.Data, old.Data)
+		if err != nil {
+			return false, errors.Wrap(err, "could not compare light client updates")
+		}
+		if !better {
+			return false, nil
+		}
+	}
+	if err := s.cfg.BeaconDB.SaveLightClientUpdate(ctx, period, update); err != nil {
+		return false, errors.Wrap(err, "could not save light client update")
+	}
+	return true, nil
+}
+
+// saveLightClientUpdate keeps the best light client update of the period up to date as blocks are imported.
+func (s *Service) saveLightClientUpdate(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock, postState state.BeaconState) error {
+	update, err := s.lightClientUpdate(ctx, signed, postState)
+	if err != nil || update == nil {
+		return err
+	}
+	_, err = s.saveBestLightClientUpdate(ctx, update)
+	return err
+}
+
+// broadcastLightClientUpdate gossips a light client finality or optimistic update once a third of its signature slot
+// has passed, which gives the block at the signature slot the time to propagate first.
+func (s *Service) broadcastLightClientUpdate(signatureSlot primitives.Slot, msg ssz.Marshaler) {
+	if s.cfg.P2p == nil {
+		return
+	}
+	m, ok := msg.(proto.Message)
+	if !ok {
+		log.Errorf("Light client update of type %T is not a protobuf message", msg)
+		return
+	}
+	cfg := params.BeaconConfig()
+	due := slots.BeginsAt(signatureSlot, s.genesisTime).Add(time.Duration(cfg.SecondsPerSlot) * time.Second / time.Duration(cfg.IntervalsPerSlot))
+	go func() {
+		if wait := time.Until(due); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-s.ctx.Done():
+				return
+			}
+		}
+		if err := s.cfg.P2p.Broadcast(s.ctx, m); err != nil {
+			log.WithError(err).Debug("Could not broadcast light client update")
+		}
+	}()
+}
+
+// runLightClientBackfill stores the best light client update of the finalized sync committee periods which were
+// not seen by the node while it was following the chain: the history before the node started, and the blocks
+// imported by initial sync.
+func (s *Service) runLightClientBackfill() {
+	if err := s.waitForSync(); err != nil {
+		log.WithError(err).Error("Failed to wait for initial sync")
+		return
+	}
+
+	interval := time.Duration(params.BeaconConfig().SlotsPerEpoch.Mul(params.BeaconConfig().SecondsPerSlot)) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.backfillLightClientUpdates(s.ctx); err != nil {
+			log.WithError(err).Error("Could not backfill light client updates")
+		}
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting routine")
+			return
+		}
+	}
+}
+
+// lightClientCandidate is the block signing the best update known so far for a period during backfill.
+type lightClientCandidate struct {
+	block        interfaces.ReadOnlySignedBeaconBlock
+	participants uint64
+}
+
+// lightClientBackfillGap is where the light client backfill stopped because the history before it is not available
+// yet, typically because the node started from a checkpoint and is still backfilling blocks.
+type lightClientBackfillGap struct {
+	// root of the block the walk resumes from, the first block walked for period.
+	root [32]byte
+	// period is the lowest sync committee period that was walked, possibly only partially.
+	period uint64
+}
+
+// backfillLightClientUpdates walks the canonical chain back from the finalized checkpoint and stores an update for
+// every finalized sync committee period that has none. Periods already covered by a previous run are not walked
+// again. If the history of a period is missing, the walk is resumed from that period by later runs once the
+// missing blocks are available.
+func (s *Service) backfillLightClientUpdates(ctx context.Context) error {
+	cp := s.FinalizedCheckpt()
+	finalizedPeriod := slots.SyncCommitteePeriod(cp.Epoch)
+	gap := s.lightClientBackfillGap
+	saved := 0
+
+	if finalizedPeriod > s.lightClientBackfilledPeriod {
+		missing, n, err := s.walkLightClientBackfill(ctx, bytesutil.ToBytes32(cp.Root), s.lightClientBackfilledPeriod, finalizedPeriod, false)
+		if err != nil {
+			return err
+		}
+		saved += n
+		switch {
+		case missing == nil:
+			s.lightClientBackfilledPeriod = finalizedPeriod
+		case gap == nil:
+			log.WithField("period", missing.period).Info("Light client update history is not available yet, backfill will resume once the blocks are available")
+			s.lightClientBackfillGap = missing
+			s.lightClientBackfilledPeriod = finalizedPeriod
+		default:
+			// Only a single gap is tracked, the periods since the previous run are walked again by the next run.
+			log.WithField("period", missing.period).Debug("Light client update history is not available yet")
+		}
+	}
+
+	if gap != nil {
+		missing, n, err := s.walkLightClientBackfill(ctx, gap.root, 0, gap.period+1, true)
+		if err != nil {
+			return err
+		}
+		saved += n
+		s.lightClientBackfillGap = missing
+	}
+
+	if saved > 0 {
+		log.WithFields(logrus.Fields{
+			"updates":         saved,
+			"finalizedPeriod": finalizedPeriod,
+		}).Info("Backfilled light client updates")
+	}
+	return nil
+}
+
+// walkLightClientBackfill walks the canonical chain back from the block with the given root, and stores an update for
+// every sync committee period from minPeriod up to, but excluding, maxPeriod that has none. Within a period, the block
+// with the most sync committee participants signing a header of the same period is used, preferring older blocks on
+// ties. When resuming from a gap, the stored update of the first period walked is only replaced by a better one.
+// The returned gap is nil if the walk reached minPeriod or the Altair fork.
+func (s *Service) walkLightClientBackfill(ctx context.Context, root [32]byte, minPeriod, maxPeriod uint64, resume bool) (*lightClientBackfillGap, int, error) {
+	child, err := s.cfg.BeaconDB.Block(ctx, root)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "could not get block")
+	}
+
+	var (
+		childRoot   = root
+		periodStart [32]byte
+		current     uint64
+		tracking    bool
+		skip        bool
+		candidate   *lightClientCandidate
+		saved       int
+	)
+	flush := func() {
+		if !tracking || skip || candidate == nil {
+			return
+		}
+		if err := s.backfillLightClientUpdate(ctx, candidate.block); err != nil {
+			log.WithError(err).WithField("period", current).Debug("Could not backfill light client update")
+			return
+		}
+		saved++
+	}
+
+	for child != nil && !child.IsNil() && child.Version() >= version.Altair {
+		if ctx.Err() != nil {
+			return nil, saved, ctx.Err()
+		}
+		parentRoot := child.Block().ParentRoot()
+		parent, err := s.cfg.BeaconDB.Block(ctx, parentRoot)
+		if err != nil {
+			return nil, saved, errors.Wrap(err, "could not get parent block")
+		}
+		if parent == nil || parent.IsNil() {
+			// The history before this block is not available yet. The best update seen so far is stored, and the
+			// period is walked again from its start once the history is available.
+			flush()
+			if tracking {
+				return &lightClientBackfillGap{root: periodStart, period: current}, saved, nil
+			}
+			return &lightClientBackfillGap{root: childRoot, period: slots.SyncCommitteePeriod(slots.ToEpoch(child.Block().Slot()))}, saved, nil
+		}
+		attestedPeriod := slots.SyncCommitteePeriod(slots.ToEpoch(parent.Block().Slot()))
+		if attestedPeriod < minPeriod {
+			break
+		}
+		if attestedPeriod < maxPeriod {
+			if !tracking || attestedPeriod != current {
+				flush()
+				first := !tracking
+				current, tracking, candidate, periodStart = attestedPeriod, true, nil, childRoot
+				stored, err := s.cfg.BeaconDB.LightClientUpdate(ctx, current)
+				if err != nil {
+					return nil, saved, errors.Wrap(err, "could not get stored light client update")
+				}
+				skip = stored != nil && !(resume && first)
+			}
+			signaturePeriod := slots.SyncCommitteePeriod(slots.ToEpoch(child.Block().Slot()))
+			if !skip && signaturePeriod == attestedPeriod && parent.Version() >= version.Altair {
+				syncAggregate, err := child.Block().Body().SyncAggregate()
+				if err != nil {
+					return nil, saved, errors.Wrap(err, "could not get sync aggregate")
+				}
+				participants := syncAggregate.SyncCommitteeBits.Count()
+				if participants >= params.BeaconConfig().MinSyncCommitteeParticipants &&
+					(candidate == nil || participants >= candidate.participants) {
+					candidate = &lightClientCandidate{block: child, participants: participants}
+				}
+			}
+		}
+		child, childRoot = parent, parentRoot
+	}
+	flush()
+	return nil, saved, nil
+}
+
+func (s *Service) backfillLightClientUpdate(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock) error {
+	root, err := signed.Block().HashTreeRoot()
+	if err != nil {
+		return errors.Wrap(err, "could not get block root")
+	}
+	postState, err := s.cfg.StateGen.StateByRoot(ctx, root)
+	if err != nil {
+		return errors.Wrap(err, "could not get post state")
+	}
+	update, err := s.lightClientUpdate(ctx, signed, postState)
+	if err != nil {
+		return err
+	}
+	if update == nil {
+		return errors.New("block does not make a light client update")
+	}
+	_, err = s.saveBestLightClientUpdate(ctx, update)
+	return err
+}
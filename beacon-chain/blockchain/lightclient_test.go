@@ -0,0 +1,184 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	forkchoicetypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/types"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"google.golang.org/protobuf/proto"
+)
+
+func setupLightClientTestService(t *testing.T) (*Service, *util.TestLightClient) {
+	s, tr := minimalTestService(t)
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	for _, b := range []interfaces.ReadOnlySignedBeaconBlock{l.FinalizedBlock, l.AttestedBlock, l.Block} {
+		require.NoError(t, tr.db.SaveBlock(tr.ctx, b))
+	}
+	attestedRoot, err := l.AttestedBlock.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, tr.sg.SaveState(tr.ctx, attestedRoot, l.AttestedState))
+	return s, l
+}
+
+func TestSaveLightClientUpdate(t *testing.T) {
+	s, l := setupLightClientTestService(t)
+	ctx := l.Ctx
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(l.AttestedBlock.Block().Slot()))
+
+	require.NoError(t, s.saveLightClientUpdate(ctx, l.Block, l.State))
+	stored, err := s.cfg.BeaconDB.LightClientUpdate(ctx, period)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.Equal(t, l.Block.Block().Slot(), stored.Data.SignatureSlot)
+	l.CheckAttestedHeader(stored.Data.AttestedHeader)
+
+	// The same update is not better than itself.
+	update, err := s.lightClientUpdate(ctx, l.Block, l.State)
+	require.NoError(t, err)
+	saved, err := s.saveBestLightClientUpdate(ctx, update)
+	require.NoError(t, err)
+	require.Equal(t, false, saved)
+
+	// An update with more participants replaces the stored one.
+	update.Data.SyncAggregate.SyncCommitteeBits.SetBitAt(params.BeaconConfig().MinSyncCommitteeParticipants, true)
+	saved, err = s.saveBestLightClientUpdate(ctx, update)
+	require.NoError(t, err)
+	require.Equal(t, true, saved)
+}
+
+func TestSaveLightClientUpdate_NotEnoughParticipants(t *testing.T) {
+	s, l := setupLightClientTestService(t)
+	pb, err := l.Block.Proto()
+	require.NoError(t, err)
+	b, ok := pb.(*ethpb.SignedBeaconBlockAltair)
+	require.Equal(t, true, ok)
+	b.Block.Body.SyncAggregate.SyncCommitteeBits.SetBitAt(0, false)
+	signed, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+
+	update, err := s.lightClientUpdate(l.Ctx, signed, l.State)
+	require.NoError(t, err)
+	require.Equal(t, true, update == nil)
+}
+
+func TestBackfillLightClientUpdates(t *testing.T) {
+	s, l := setupLightClientTestService(t)
+	ctx := l.Ctx
+	blockRoot, err := l.Block.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.cfg.StateGen.SaveState(ctx, blockRoot, l.State))
+
+	// The finalized block is the first block of the next period, built on top of the light client blocks.
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(l.AttestedBlock.Block().Slot()))
+	nextPeriodSlot := primitives.Slot(uint64(period+1) * uint64(params.BeaconConfig().EpochsPerSyncCommitteePeriod) * uint64(params.BeaconConfig().SlotsPerEpoch))
+	finalized := util.NewBeaconBlockAltair()
+	finalized.Block.Slot = nextPeriodSlot
+	finalized.Block.ParentRoot = blockRoot[:]
+	util.SaveBlock(t, ctx, s.cfg.BeaconDB, finalized)
+	finalizedRoot, err := finalized.Block.HashTreeRoot()
+	require.NoError(t, err)
+	s.cfg.ForkChoiceStore.Lock()
+	require.NoError(t, s.cfg.ForkChoiceStore.UpdateFinalizedCheckpoint(&forkchoicetypes.Checkpoint{
+		Epoch: slots.ToEpoch(nextPeriodSlot),
+		Root:  finalizedRoot,
+	}))
+	s.cfg.ForkChoiceStore.Unlock()
+
+	require.NoError(t, s.backfillLightClientUpdates(ctx))
+	stored, err := s.cfg.BeaconDB.LightClientUpdate(ctx, period)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.Equal(t, l.Block.Block().Slot(), stored.Data.SignatureSlot)
+	require.Equal(t, period+1, s.lightClientBackfilledPeriod)
+}
+
+func TestBackfillLightClientUpdates_ResumesAfterMissingHistory(t *testing.T) {
+	s, l := setupLightClientTestService(t)
+	ctx := l.Ctx
+	blockRoot, err := l.Block.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.cfg.StateGen.SaveState(ctx, blockRoot, l.State))
+
+	// The block between the light client blocks and the finalized block is not available yet.
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(l.AttestedBlock.Block().Slot()))
+	nextPeriodSlot := primitives.Slot(uint64(period+1) * uint64(params.BeaconConfig().EpochsPerSyncCommitteePeriod) * uint64(params.BeaconConfig().SlotsPerEpoch))
+	missing := util.NewBeaconBlockAltair()
+	missing.Block.Slot = l.Block.Block().Slot() + 1
+	missing.Block.ParentRoot = blockRoot[:]
+	missingRoot, err := missing.Block.HashTreeRoot()
+	require.NoError(t, err)
+	finalized := util.NewBeaconBlockAltair()
+	finalized.Block.Slot = nextPeriodSlot
+	finalized.Block.ParentRoot = missingRoot[:]
+	util.SaveBlock(t, ctx, s.cfg.BeaconDB, finalized)
+	finalizedRoot, err := finalized.Block.HashTreeRoot()
+	require.NoError(t, err)
+	s.cfg.ForkChoiceStore.Lock()
+	require.NoError(t, s.cfg.ForkChoiceStore.UpdateFinalizedCheckpoint(&forkchoicetypes.Checkpoint{
+		Epoch: slots.ToEpoch(nextPeriodSlot),
+		Root:  finalizedRoot,
+	}))
+	s.cfg.ForkChoiceStore.Unlock()
+
+	require.NoError(t, s.backfillLightClientUpdates(ctx))
+	stored, err := s.cfg.BeaconDB.LightClientUpdate(ctx, period)
+	require.NoError(t, err)
+	require.Equal(t, true, stored == nil)
+	require.NotNil(t, s.lightClientBackfillGap)
+	require.Equal(t, finalizedRoot, s.lightClientBackfillGap.root)
+
+	// Once the missing block is available, the walk resumes from the gap.
+	util.SaveBlock(t, ctx, s.cfg.BeaconDB, missing)
+	require.NoError(t, s.backfillLightClientUpdates(ctx))
+	stored, err = s.cfg.BeaconDB.LightClientUpdate(ctx, period)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.Equal(t, l.Block.Block().Slot(), stored.Data.SignatureSlot)
+	require.Equal(t, period+1, s.lightClientBackfilledPeriod)
+	// The history before the light client blocks is still missing.
+	require.NotNil(t, s.lightClientBackfillGap)
+	require.Equal(t, period, s.lightClientBackfillGap.period)
+}
+
+type lightClientBroadcaster struct {
+	mockBroadcaster
+	msgs chan proto.Message
+}
+
+func (b *lightClientBroadcaster) Broadcast(_ context.Context, msg proto.Message) error {
+	b.msgs <- msg
+	return nil
+}
+
+func TestBroadcastLightClientUpdate(t *testing.T) {
+	s, _ := minimalTestService(t)
+	b := &lightClientBroadcaster{msgs: make(chan proto.Message, 1)}
+	s.cfg.P2p = b
+	msg := &ethpb.LightClientOptimisticUpdateAltair{SignatureSlot: 1}
+
+	s.SetGenesisTime(time.Now().Add(-time.Duration(2*params.BeaconConfig().SecondsPerSlot) * time.Second))
+	s.broadcastLightClientUpdate(1, msg)
+	select {
+	case got := <-b.msgs:
+		require.DeepEqual(t, msg, got)
+	case <-time.After(time.Second):
+		t.Fatal("Update was not broadcast")
+	}
+
+	// The update is held back until a third of the signature slot has passed.
+	s.broadcastLightClientUpdate(100, msg)
+	select {
+	case <-b.msgs:
+		t.Fatal("Update was broadcast before the signature slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
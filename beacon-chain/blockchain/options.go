@@ -1,6 +1,8 @@
 package blockchain
 
 import (
+	"time"
+
 	"github.com/prysmaticlabs/prysm/v5/async/event"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
 	statefeed "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/feed/state"
@@ -16,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 )
 
@@ -199,6 +202,24 @@ func WithBlobStorage(b *filesystem.BlobStorage) Option {
 	}
 }
 
+// WithHistoryRetention prunes finalized blocks and states older than the given number of epochs from the database.
+func WithHistoryRetention(epochs primitives.Epoch) Option {
+	return func(s *Service) error {
+		s.cfg.HistoryRetentionEpochs = epochs
+		return nil
+	}
+}
+
+// WithReadOnlyReplica makes the service follow the head saved to the database by another node, moving to newer
+// checkpoints of the database through the refresher every interval, instead of processing blocks and attestations.
+func WithReadOnlyReplica(r DatabaseRefresher, interval time.Duration) Option {
+	return func(s *Service) error {
+		s.cfg.DatabaseRefresher = r
+		s.cfg.ReplicaRefreshInterval = interval
+		return nil
+	}
+}
+
 func WithSyncChecker(checker Checker) Option {
 	return func(s *Service) error {
 		s.cfg.SyncChecker = checker
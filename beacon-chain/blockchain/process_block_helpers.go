@@ -25,6 +25,7 @@ import (
 	mathutil "github.com/prysmaticlabs/prysm/v5/math"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
 	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
+	"github.com/prysmaticlabs/prysm/v5/proto/migration"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/time/slots"
 	"github.com/sirupsen/logrus"
@@ -115,10 +116,12 @@ func (s *Service) sendStateFeedOnBlock(cfg *postBlockProcessConfig) {
 	})
 }
 
-// sendLightClientFeeds sends the light client feeds when feature flag is enabled.
+// sendLightClientFeeds sends the light client feeds and saves the best light client update of the period when
+// feature flag is enabled. The updates of the new head are also gossiped once the node is synced.
 func (s *Service) sendLightClientFeeds(cfg *postBlockProcessConfig) {
 	if features.Get().EnableLightClient {
-		if _, err := s.sendLightClientOptimisticUpdate(cfg.ctx, cfg.roblock, cfg.postState); err != nil {
+		broadcast := cfg.headRoot == cfg.roblock.Root() && s.inRegularSync()
+		if _, err := s.sendLightClientOptimisticUpdate(cfg.ctx, cfg.roblock, cfg.postState, broadcast); err != nil {
 			log.WithError(err).Error("Failed to send light client optimistic update")
 		}
 
@@ -126,11 +129,15 @@ func (s *Service) sendLightClientFeeds(cfg *postBlockProcessConfig) {
 		finalized := s.ForkChoicer().FinalizedCheckpoint()
 
 		// LightClientFinalityUpdate needs super majority
-		s.tryPublishLightClientFinalityUpdate(cfg.ctx, cfg.roblock, finalized, cfg.postState)
+		s.tryPublishLightClientFinalityUpdate(cfg.ctx, cfg.roblock, finalized, cfg.postState, broadcast)
+
+		if err := s.saveLightClientUpdate(cfg.ctx, cfg.roblock, cfg.postState); err != nil {
+			log.WithError(err).Error("Failed to save light client update")
+		}
 	}
 }
 
-func (s *Service) tryPublishLightClientFinalityUpdate(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock, finalized *forkchoicetypes.Checkpoint, postState state.BeaconState) {
+func (s *Service) tryPublishLightClientFinalityUpdate(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock, finalized *forkchoicetypes.Checkpoint, postState state.BeaconState, broadcast bool) {
 	if finalized.Epoch <= s.lastPublishedLightClientEpoch {
 		return
 	}
@@ -150,7 +157,7 @@ func (s *Service) tryPublishLightClientFinalityUpdate(ctx context.Context, signe
 		return
 	}
 
-	_, err = s.sendLightClientFinalityUpdate(ctx, signed, postState)
+	_, err = s.sendLightClientFinalityUpdate(ctx, signed, postState, broadcast)
 	if err != nil {
 		log.WithError(err).Error("Failed to send light client finality update")
 	} else {
@@ -158,9 +165,10 @@ func (s *Service) tryPublishLightClientFinalityUpdate(ctx context.Context, signe
 	}
 }
 
-// sendLightClientFinalityUpdate sends a light client finality update notification to the state feed.
+// sendLightClientFinalityUpdate sends a light client finality update notification to the state feed, and gossips
+// it when broadcast is set.
 func (s *Service) sendLightClientFinalityUpdate(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock,
-	postState state.BeaconState) (int, error) {
+	postState state.BeaconState, broadcast bool) (int, error) {
 	// Get attested state
 	attestedRoot := signed.Block().ParentRoot()
 	attestedBlock, err := s.cfg.BeaconDB.Block(ctx, attestedRoot)
@@ -202,6 +210,14 @@ func (s *Service) sendLightClientFinalityUpdate(ctx context.Context, signed inte
 		Data:    update,
 	}
 
+	if broadcast {
+		msg, err := migration.V2LightClientFinalityUpdateToV1Alpha1(update)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not convert light client finality update")
+		}
+		s.broadcastLightClientUpdate(update.SignatureSlot, msg)
+	}
+
 	// Send event
 	return s.cfg.StateNotifier.StateFeed().Send(&feed.Event{
 		Type: statefeed.LightClientFinalityUpdate,
@@ -209,9 +225,10 @@ func (s *Service) sendLightClientFinalityUpdate(ctx context.Context, signed inte
 	}), nil
 }
 
-// sendLightClientOptimisticUpdate sends a light client optimistic update notification to the state feed.
+// sendLightClientOptimisticUpdate sends a light client optimistic update notification to the state feed, and
+// gossips it when broadcast is set.
 func (s *Service) sendLightClientOptimisticUpdate(ctx context.Context, signed interfaces.ReadOnlySignedBeaconBlock,
-	postState state.BeaconState) (int, error) {
+	postState state.BeaconState, broadcast bool) (int, error) {
 	// Get attested state
 	attestedRoot := signed.Block().ParentRoot()
 	attestedBlock, err := s.cfg.BeaconDB.Block(ctx, attestedRoot)
@@ -241,6 +258,14 @@ func (s *Service) sendLightClientOptimisticUpdate(ctx context.Context, signed in
 		Data:    update,
 	}
 
+	if broadcast {
+		msg, err := migration.V2LightClientOptimisticUpdateToV1Alpha1(update)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not convert light client optimistic update")
+		}
+		s.broadcastLightClientUpdate(update.SignatureSlot, msg)
+	}
+
 	return s.cfg.StateNotifier.StateFeed().Send(&feed.Event{
 		Type: statefeed.LightClientOptimisticUpdate,
 		Data: result,
@@ -411,6 +436,9 @@ func (s *Service) updateFinalized(ctx context.Context, cp *ethpb.Checkpoint) err
 			log.WithError(err).Error("could not migrate to cold")
 		}
 	}()
+	if s.cfg.HistoryRetentionEpochs > 0 {
+		go s.pruneHistoryInBackground(cp.Epoch)
+	}
 	return nil
 }
 
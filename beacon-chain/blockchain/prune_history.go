@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+// historyRetention returns the number of epochs of history kept before the finalized epoch. It is the configured
+// retention, but never less than the weak subjectivity period of the head state, nor than the
+// MIN_EPOCHS_FOR_BLOCK_REQUESTS epochs of blocks peers may request.
+func (s *Service) historyRetention(ctx context.Context) (primitives.Epoch, error) {
+	st, err := s.HeadStateReadOnly(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get head state")
+	}
+	wsPeriod, err := helpers.ComputeWeakSubjectivityPeriod(ctx, st, params.BeaconConfig())
+	if err != nil {
+		return 0, errors.Wrap(err, "could not compute weak subjectivity period")
+	}
+	return max(s.cfg.HistoryRetentionEpochs, wsPeriod, helpers.MinEpochsForBlockRequests()), nil
+}
+
+// pruneHistoryInBackground prunes history unless a previous prune is still running, in which case the next
+// finalized checkpoint prunes what this one would have. It uses the service context, since pruning is not tied to
+// the block which finalized the epoch.
+func (s *Service) pruneHistoryInBackground(finalized primitives.Epoch) {
+	if !s.pruneHistoryLock.TryLock() {
+		log.WithField("finalizedEpoch", finalized).Debug("Skipping history pruning, a previous prune is still running")
+		return
+	}
+	defer s.pruneHistoryLock.Unlock()
+	if err := s.pruneHistory(s.ctx, finalized); err != nil {
+		log.WithError(err).Error("Could not prune history")
+	}
+}
+
+// pruneHistory deletes the blocks and states which are older than the history retention before the finalized epoch.
+func (s *Service) pruneHistory(ctx context.Context, finalized primitives.Epoch) error {
+	retention, err := s.historyRetention(ctx)
+	if err != nil {
+		return err
+	}
+	if finalized <= retention {
+		return nil
+	}
+	before, err := slots.EpochStart(finalized - retention)
+	if err != nil {
+		return err
+	}
+	pruned, err := s.cfg.BeaconDB.PruneHistory(ctx, before)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		log.WithFields(logrus.Fields{
+			"blocks":         pruned,
+			"retainedEpochs": retention,
+			"earliestSlot":   before,
+		}).Info("Pruned history")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	testDB "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestService_PruneHistory(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+	st, _ := util.DeterministicGenesisState(t, 64)
+	s := &Service{
+		cfg:  &config{BeaconDB: beaconDB, HistoryRetentionEpochs: 1},
+		head: &head{state: st},
+	}
+
+	// The retention never goes below the epochs peers may request blocks for.
+	retention, err := s.historyRetention(ctx)
+	require.NoError(t, err)
+	require.Equal(t, helpers.MinEpochsForBlockRequests(), retention)
+	s.cfg.HistoryRetentionEpochs = helpers.MinEpochsForBlockRequests() + 10
+	retention, err = s.historyRetention(ctx)
+	require.NoError(t, err)
+	require.Equal(t, s.cfg.HistoryRetentionEpochs, retention)
+
+	// Genesis <- a (epoch 1) <- b (epoch 6), finalized well after the retention of b.
+	genesis := util.NewBeaconBlock()
+	util.SaveBlock(t, ctx, beaconDB, genesis)
+	genesisRoot, err := genesis.Block.HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, beaconDB.SaveGenesisBlockRoot(ctx, genesisRoot))
+	a := util.NewBeaconBlock()
+	a.Block.Slot = 32
+	a.Block.ParentRoot = genesisRoot[:]
+	util.SaveBlock(t, ctx, beaconDB, a)
+	aRoot, err := a.Block.HashTreeRoot()
+	require.NoError(t, err)
+	b := util.NewBeaconBlock()
+	b.Block.Slot = 200
+	b.Block.ParentRoot = aRoot[:]
+	util.SaveBlock(t, ctx, beaconDB, b)
+	bRoot, err := b.Block.HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, beaconDB.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: b.Block.Slot, Root: bRoot[:]}))
+	finalized := retention + 5
+	require.NoError(t, beaconDB.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: finalized, Root: bRoot[:]}))
+
+	require.NoError(t, s.pruneHistory(ctx, finalized))
+	require.Equal(t, true, beaconDB.HasBlock(ctx, genesisRoot))
+	require.Equal(t, false, beaconDB.HasBlock(ctx, aRoot))
+	require.Equal(t, true, beaconDB.HasBlock(ctx, bRoot))
+	earliest, err := beaconDB.EarliestAvailableSlot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(160), earliest)
+}
+
+func TestService_PruneHistoryInBackground(t *testing.T) {
+	hook := logTest.NewGlobal()
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	s := &Service{
+		ctx:  context.Background(),
+		cfg:  &config{BeaconDB: testDB.SetupDB(t), HistoryRetentionEpochs: 1},
+		head: &head{state: st},
+	}
+
+	// A prune is skipped while another one runs.
+	s.pruneHistoryLock.Lock()
+	s.pruneHistoryInBackground(1)
+	require.LogsContain(t, hook, "Skipping history pruning")
+	require.LogsDoNotContain(t, hook, "Could not prune history")
+	require.Equal(t, false, s.pruneHistoryLock.TryLock())
+	s.pruneHistoryLock.Unlock()
+
+	// The lock is released once a prune is done, even if it failed.
+	s.pruneHistoryInBackground(1)
+	require.LogsContain(t, hook, "Could not prune history")
+	require.Equal(t, true, s.pruneHistoryLock.TryLock())
+	s.pruneHistoryLock.Unlock()
+}
@@ -0,0 +1,183 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	forkchoicetypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/types"
+	consensus_blocks "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// DatabaseRefresher moves a database to the newest checkpoint written by another process.
+type DatabaseRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// followHead runs on a read-only replica instead of block processing. It refreshes the database every interval and
+// follows the head the node writing to it chose.
+func (s *Service) followHead(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refreshHead(s.ctx); err != nil {
+				log.WithError(err).Error("Could not follow the head of the database")
+			}
+		}
+	}
+}
+
+// refreshHead refreshes the database, inserts the blocks saved since the last refresh into forkchoice and makes the
+// head block of the database the head of the chain. The replica has no execution client of its own, so the blocks
+// the writer saved are taken as valid.
+func (s *Service) refreshHead(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "blockChain.refreshHead")
+	defer span.End()
+
+	if err := s.cfg.DatabaseRefresher.Refresh(ctx); err != nil {
+		return errors.Wrap(err, "could not refresh database")
+	}
+	headBlock, err := s.cfg.BeaconDB.HeadBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get head block")
+	}
+	if err := consensus_blocks.BeaconBlockIsNil(headBlock); err != nil {
+		return err
+	}
+	headRoot, err := headBlock.Block().HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if !s.isNewHead(headRoot) {
+		return nil
+	}
+
+	s.cfg.ForkChoiceStore.Lock()
+	defer s.cfg.ForkChoiceStore.Unlock()
+	finalizedEpoch := s.cfg.ForkChoiceStore.FinalizedCheckpoint().Epoch
+	if err := s.insertSavedBlocks(ctx, headRoot, headBlock); err != nil {
+		return err
+	}
+	if err := s.updateSavedCheckpoints(ctx, finalizedEpoch); err != nil {
+		return err
+	}
+
+	headState, err := s.cfg.StateGen.StateByRoot(ctx, headRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not get head state")
+	}
+	if err := s.setHead(&head{
+		root:  headRoot,
+		block: headBlock,
+		state: headState,
+		slot:  headBlock.Block().Slot(),
+	}); err != nil {
+		return errors.Wrap(err, "could not set head")
+	}
+	log.WithFields(logrus.Fields{
+		"slot": headBlock.Block().Slot(),
+		"root": fmt.Sprintf("%#x", bytesutil.Trunc(headRoot[:])),
+	}).Debug("Followed head of the database")
+
+	stateRoot := headBlock.Block().StateRoot()
+	finalized := s.cfg.ForkChoiceStore.FinalizedCheckpoint()
+	go func() {
+		if err := s.notifyNewHeadEvent(s.ctx, headBlock.Block().Slot(), headState, stateRoot[:], headRoot[:]); err != nil {
+			log.WithError(err).Error("Could not notify event feed of new chain head")
+		}
+		if finalized.Epoch > finalizedEpoch {
+			s.sendNewFinalizedEvent(s.ctx, headState)
+		}
+	}()
+	return nil
+}
+
+// insertSavedBlocks inserts the block and its ancestors missing from forkchoice, oldest first. Their states are
+// regenerated from the parent states, which are kept in the hot state cache. Caller must hold the forkchoice lock.
+func (s *Service) insertSavedBlocks(ctx context.Context, root [32]byte, blk interfaces.ReadOnlySignedBeaconBlock) error {
+	var missing []consensus_blocks.ROBlock
+	for !s.cfg.ForkChoiceStore.HasNode(root) {
+		roblock, err := consensus_blocks.NewROBlockWithRoot(blk, root)
+		if err != nil {
+			return err
+		}
+		missing = append(missing, roblock)
+		root = blk.Block().ParentRoot()
+		blk, err = s.cfg.BeaconDB.Block(ctx, root)
+		if err != nil {
+			return errors.Wrapf(err, "could not get block %#x", root)
+		}
+		if err := consensus_blocks.BeaconBlockIsNil(blk); err != nil {
+			return errors.Wrapf(err, "block %#x is not in the database", root)
+		}
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		r := missing[i].Root()
+		st, err := s.cfg.StateGen.StateByRoot(ctx, r)
+		if err != nil {
+			return errors.Wrapf(err, "could not get state of block %#x", r)
+		}
+		if err := s.cfg.StateGen.SaveState(ctx, r, st); err != nil {
+			return errors.Wrapf(err, "could not cache state of block %#x", r)
+		}
+		if err := s.cfg.ForkChoiceStore.InsertNode(ctx, st, missing[i]); err != nil {
+			return errors.Wrapf(err, "could not insert block %#x to forkchoice", r)
+		}
+		if err := s.cfg.ForkChoiceStore.SetOptimisticToValid(ctx, r); err != nil {
+			return errors.Wrapf(err, "could not set block %#x as valid", r)
+		}
+	}
+	return nil
+}
+
+// updateSavedCheckpoints moves the forkchoice checkpoints forward to the ones saved in the database, and keeps the
+// finalized state in memory for state regeneration when it is newer than the previous finalized epoch. Caller must
+// hold the forkchoice lock.
+func (s *Service) updateSavedCheckpoints(ctx context.Context, prevFinalized primitives.Epoch) error {
+	justified, err := s.cfg.BeaconDB.JustifiedCheckpoint(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get justified checkpoint")
+	}
+	if justified == nil {
+		return errNilJustifiedCheckpoint
+	}
+	if justified.Epoch > s.cfg.ForkChoiceStore.JustifiedCheckpoint().Epoch {
+		if err := s.cfg.ForkChoiceStore.UpdateJustifiedCheckpoint(ctx, &forkchoicetypes.Checkpoint{
+			Epoch: justified.Epoch, Root: bytesutil.ToBytes32(justified.Root)}); err != nil {
+			return errors.Wrap(err, "could not update forkchoice's justified checkpoint")
+		}
+	}
+	finalized, err := s.cfg.BeaconDB.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get finalized checkpoint")
+	}
+	if finalized == nil {
+		return errNilFinalizedCheckpoint
+	}
+	if finalized.Epoch > s.cfg.ForkChoiceStore.FinalizedCheckpoint().Epoch {
+		if err := s.cfg.ForkChoiceStore.UpdateFinalizedCheckpoint(&forkchoicetypes.Checkpoint{
+			Epoch: finalized.Epoch, Root: bytesutil.ToBytes32(finalized.Root)}); err != nil {
+			return errors.Wrap(err, "could not update forkchoice's finalized checkpoint")
+		}
+	}
+	if finalized.Epoch <= prevFinalized {
+		return nil
+	}
+	fRoot := s.ensureRootNotZeros(bytesutil.ToBytes32(finalized.Root))
+	fState, err := s.cfg.StateGen.StateByRoot(ctx, fRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not get finalized state")
+	}
+	s.cfg.StateGen.SaveFinalizedState(fState.Slot(), fRoot, fState)
+	return nil
+}
@@ -68,6 +68,10 @@ type Service struct {
 	blockBeingSynced              *currentlySyncingBlock
 	blobStorage                   *filesystem.BlobStorage
 	lastPublishedLightClientEpoch primitives.Epoch
+	lightClientUpdateLock         sync.Mutex
+	lightClientBackfilledPeriod   uint64
+	lightClientBackfillGap        *lightClientBackfillGap
+	pruneHistoryLock              sync.Mutex
 }
 
 // config options for the service.
@@ -94,6 +98,9 @@ type config struct {
 	FinalizedStateAtStartUp state.BeaconState
 	ExecutionEngineCaller   execution.EngineCaller
 	SyncChecker             Checker
+	HistoryRetentionEpochs  primitives.Epoch
+	DatabaseRefresher       DatabaseRefresher
+	ReplicaRefreshInterval  time.Duration
 }
 
 // Checker is an interface used to determine if a node is in initial sync
@@ -201,6 +208,16 @@ func (s *Service) Start() {
 	saved := s.cfg.FinalizedStateAtStartUp
 	defer s.removeStartupState()
 
+	if s.cfg.DatabaseRefresher != nil {
+		if saved == nil || saved.IsNil() {
+			log.Fatal("A read-only replica needs a database with a finalized state")
+		}
+		if err := s.StartFromSavedState(saved); err != nil {
+			log.Fatal(err)
+		}
+		go s.followHead(s.cfg.ReplicaRefreshInterval)
+		return
+	}
 	if saved != nil && !saved.IsNil() {
 		if err := s.StartFromSavedState(saved); err != nil {
 			log.Fatal(err)
@@ -212,12 +229,20 @@ func (s *Service) Start() {
 	}
 	s.spawnProcessAttestationsRoutine()
 	go s.runLateBlockTasks()
+	if features.Get().EnableLightClient {
+		go s.runLightClientBackfill()
+	}
 }
 
 // Stop the blockchain service's main event loop and associated goroutines.
 func (s *Service) Stop() error {
 	defer s.cancel()
 
+	// A read-only replica does not write to the database.
+	if s.cfg.DatabaseRefresher != nil {
+		return nil
+	}
+
 	// lock before accessing s.head, s.head.state, s.head.state.FinalizedCheckpoint().Root
 	s.headLock.RLock()
 	if s.cfg.StateGen != nil && s.head != nil && s.head.state != nil {
@@ -0,0 +1,33 @@
+package altair_test
+
+import (
+	"context"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/altair"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/testing/epochprocessing"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestFuzzProcessEpoch_1000(t *testing.T) {
+	resetCfg := features.InitWithReset(&features.Flags{})
+	defer resetCfg()
+	base := util.RandomEpochStateAltair(t, 64, 8)
+	fuzzer := fuzz.NewWithSeed(0)
+	for i := 0; i < 1000; i++ {
+		st := epochprocessing.FuzzRegistry(t, fuzzer, base, params.BeaconConfig().MaxEffectiveBalance, false)
+		var processed []state.BeaconState
+		for _, parallel := range []bool{false, true} {
+			features.Init(&features.Flags{EnableParallelEpochProcessing: parallel})
+			copied := st.Copy()
+			require.NoError(t, altair.ProcessEpoch(context.Background(), copied))
+			processed = append(processed, copied)
+		}
+		epochprocessing.RequireSameRegistry(t, processed[0], processed[1])
+	}
+}
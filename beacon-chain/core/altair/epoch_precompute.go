@@ -2,13 +2,16 @@ package altair
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/epoch/precompute"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/time"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/prysmaticlabs/prysm/v5/math"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
 )
@@ -41,38 +44,83 @@ func InitializePrecomputeValidators(ctx context.Context, beaconState state.Beaco
 	if beaconState.NumValidators() != len(inactivityScores) {
 		return nil, nil, errors.New("num of validators is different than num of inactivity scores")
 	}
-	if err := beaconState.ReadFromEveryValidator(func(idx int, val state.ReadOnlyValidator) error {
-		// Set validator's balance, inactivity score and slashed/withdrawable status.
-		v := &precompute.Validator{
-			CurrentEpochEffectiveBalance: val.EffectiveBalance(),
-			InactivityScore:              inactivityScores[idx],
-			IsSlashed:                    val.Slashed(),
-			IsWithdrawableCurrentEpoch:   currentEpoch >= val.WithdrawableEpoch(),
-		}
-		// Set validator's active status for current epoch.
-		if helpers.IsActiveValidatorUsingTrie(val, currentEpoch) {
-			v.IsActiveCurrentEpoch = true
-			bal.ActiveCurrentEpoch, err = math.Add64(bal.ActiveCurrentEpoch, val.EffectiveBalance())
-			if err != nil {
-				return err
-			}
-		}
-		// Set validator's active status for previous epoch.
-		if helpers.IsActiveValidatorUsingTrie(val, prevEpoch) {
-			v.IsActivePrevEpoch = true
-			bal.ActivePrevEpoch, err = math.Add64(bal.ActivePrevEpoch, val.EffectiveBalance())
-			if err != nil {
-				return err
+	if features.Get().EnableParallelEpochProcessing {
+		var mu sync.Mutex
+		if err := helpers.ForEachValidatorChunk(len(vals), func(start, end int) error {
+			chunkBal := &precompute.Balance{}
+			for idx := start; idx < end; idx++ {
+				val, err := beaconState.ValidatorAtIndexReadOnly(primitives.ValidatorIndex(idx))
+				if err != nil {
+					return err
+				}
+				vals[idx], err = precomputeValidator(val, inactivityScores[idx], prevEpoch, currentEpoch, chunkBal)
+				if err != nil {
+					return err
+				}
 			}
+			mu.Lock()
+			defer mu.Unlock()
+			return addActiveBalances(bal, chunkBal)
+		}); err != nil {
+			return nil, nil, errors.Wrap(err, "could not read every validator")
 		}
-		vals[idx] = v
-		return nil
+		return vals, bal, nil
+	}
+	if err := beaconState.ReadFromEveryValidator(func(idx int, val state.ReadOnlyValidator) error {
+		vals[idx], err = precomputeValidator(val, inactivityScores[idx], prevEpoch, currentEpoch, bal)
+		return err
 	}); err != nil {
 		return nil, nil, errors.Wrap(err, "could not read every validator")
 	}
 	return vals, bal, nil
 }
 
+// precomputeValidator returns the precomputed record of the validator, and adds its effective balance to the
+// active balances of bal.
+func precomputeValidator(
+	val state.ReadOnlyValidator,
+	inactivityScore uint64,
+	prevEpoch, currentEpoch primitives.Epoch,
+	bal *precompute.Balance,
+) (*precompute.Validator, error) {
+	var err error
+	// Set validator's balance, inactivity score and slashed/withdrawable status.
+	v := &precompute.Validator{
+		CurrentEpochEffectiveBalance: val.EffectiveBalance(),
+		InactivityScore:              inactivityScore,
+		IsSlashed:                    val.Slashed(),
+		IsWithdrawableCurrentEpoch:   currentEpoch >= val.WithdrawableEpoch(),
+	}
+	// Set validator's active status for current epoch.
+	if helpers.IsActiveValidatorUsingTrie(val, currentEpoch) {
+		v.IsActiveCurrentEpoch = true
+		bal.ActiveCurrentEpoch, err = math.Add64(bal.ActiveCurrentEpoch, val.EffectiveBalance())
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Set validator's active status for previous epoch.
+	if helpers.IsActiveValidatorUsingTrie(val, prevEpoch) {
+		v.IsActivePrevEpoch = true
+		bal.ActivePrevEpoch, err = math.Add64(bal.ActivePrevEpoch, val.EffectiveBalance())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// addActiveBalances adds the active balances of the current and previous epochs of src to dst.
+func addActiveBalances(dst, src *precompute.Balance) error {
+	var err error
+	dst.ActiveCurrentEpoch, err = math.Add64(dst.ActiveCurrentEpoch, src.ActiveCurrentEpoch)
+	if err != nil {
+		return err
+	}
+	dst.ActivePrevEpoch, err = math.Add64(dst.ActivePrevEpoch, src.ActivePrevEpoch)
+	return err
+}
+
 // ProcessInactivityScores of beacon chain. This updates inactivity scores of beacon chain and
 // updates the precompute validator struct for later processing. The inactivity scores work as following:
 // For fully inactive validators and perfect active validators, the effect is the same as before Altair.
@@ -104,32 +152,40 @@ func ProcessInactivityScores(
 	recoveryRate := cfg.InactivityScoreRecoveryRate
 	prevEpoch := time.PrevEpoch(beaconState)
 	finalizedEpoch := beaconState.FinalizedCheckpointEpoch()
-	for i, v := range vals {
-		if !precompute.EligibleForRewards(v) {
-			continue
-		}
-
-		if v.IsPrevEpochTargetAttester && !v.IsSlashed {
-			// Decrease inactivity score when validator gets target correct.
-			if v.InactivityScore > 0 {
-				v.InactivityScore -= 1
+	inactivityLeak := helpers.IsInInactivityLeak(prevEpoch, finalizedEpoch)
+	if err := helpers.ForEachValidatorChunk(len(vals), func(start, end int) error {
+		for i := start; i < end; i++ {
+			v := vals[i]
+			if !precompute.EligibleForRewards(v) {
+				continue
 			}
-		} else {
-			v.InactivityScore, err = math.Add64(v.InactivityScore, bias)
-			if err != nil {
-				return nil, nil, err
+
+			if v.IsPrevEpochTargetAttester && !v.IsSlashed {
+				// Decrease inactivity score when validator gets target correct.
+				if v.InactivityScore > 0 {
+					v.InactivityScore -= 1
+				}
+			} else {
+				var err error
+				v.InactivityScore, err = math.Add64(v.InactivityScore, bias)
+				if err != nil {
+					return err
+				}
 			}
-		}
 
-		if !helpers.IsInInactivityLeak(prevEpoch, finalizedEpoch) {
-			score := recoveryRate
-			// Prevents underflow below 0.
-			if score > v.InactivityScore {
-				score = v.InactivityScore
+			if !inactivityLeak {
+				score := recoveryRate
+				// Prevents underflow below 0.
+				if score > v.InactivityScore {
+					score = v.InactivityScore
+				}
+				v.InactivityScore -= score
 			}
-			v.InactivityScore -= score
+			inactivityScores[i] = v.InactivityScore
 		}
-		inactivityScores[i] = v.InactivityScore
+		return nil
+	}); err != nil {
+		return nil, nil, err
 	}
 
 	if err := beaconState.SetInactivityScores(inactivityScores); err != nil {
@@ -167,51 +223,61 @@ func ProcessEpochParticipation(
 	targetIdx := cfg.TimelyTargetFlagIndex
 	sourceIdx := cfg.TimelySourceFlagIndex
 	headIdx := cfg.TimelyHeadFlagIndex
-	for i, b := range cp {
-		has, err := HasValidatorFlag(b, sourceIdx)
-		if err != nil {
-			return nil, nil, err
-		}
-		if has && vals[i].IsActiveCurrentEpoch {
-			vals[i].IsCurrentEpochAttester = true
-		}
-		has, err = HasValidatorFlag(b, targetIdx)
-		if err != nil {
-			return nil, nil, err
-		}
-		if has && vals[i].IsActiveCurrentEpoch {
-			vals[i].IsCurrentEpochAttester = true
-			vals[i].IsCurrentEpochTargetAttester = true
+	if err := helpers.ForEachValidatorChunk(len(cp), func(start, end int) error {
+		for i := start; i < end; i++ {
+			has, err := HasValidatorFlag(cp[i], sourceIdx)
+			if err != nil {
+				return err
+			}
+			if has && vals[i].IsActiveCurrentEpoch {
+				vals[i].IsCurrentEpochAttester = true
+			}
+			has, err = HasValidatorFlag(cp[i], targetIdx)
+			if err != nil {
+				return err
+			}
+			if has && vals[i].IsActiveCurrentEpoch {
+				vals[i].IsCurrentEpochAttester = true
+				vals[i].IsCurrentEpochTargetAttester = true
+			}
 		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
 	}
 	pp, err := beaconState.PreviousEpochParticipation()
 	if err != nil {
 		return nil, nil, err
 	}
-	for i, b := range pp {
-		has, err := HasValidatorFlag(b, sourceIdx)
-		if err != nil {
-			return nil, nil, err
-		}
-		if has && vals[i].IsActivePrevEpoch {
-			vals[i].IsPrevEpochAttester = true
-			vals[i].IsPrevEpochSourceAttester = true
-		}
-		has, err = HasValidatorFlag(b, targetIdx)
-		if err != nil {
-			return nil, nil, err
-		}
-		if has && vals[i].IsActivePrevEpoch {
-			vals[i].IsPrevEpochAttester = true
-			vals[i].IsPrevEpochTargetAttester = true
-		}
-		has, err = HasValidatorFlag(b, headIdx)
-		if err != nil {
-			return nil, nil, err
-		}
-		if has && vals[i].IsActivePrevEpoch {
-			vals[i].IsPrevEpochHeadAttester = true
+	if err := helpers.ForEachValidatorChunk(len(pp), func(start, end int) error {
+		for i := start; i < end; i++ {
+			has, err := HasValidatorFlag(pp[i], sourceIdx)
+			if err != nil {
+				return err
+			}
+			if has && vals[i].IsActivePrevEpoch {
+				vals[i].IsPrevEpochAttester = true
+				vals[i].IsPrevEpochSourceAttester = true
+			}
+			has, err = HasValidatorFlag(pp[i], targetIdx)
+			if err != nil {
+				return err
+			}
+			if has && vals[i].IsActivePrevEpoch {
+				vals[i].IsPrevEpochAttester = true
+				vals[i].IsPrevEpochTargetAttester = true
+			}
+			has, err = HasValidatorFlag(pp[i], headIdx)
+			if err != nil {
+				return err
+			}
+			if has && vals[i].IsActivePrevEpoch {
+				vals[i].IsPrevEpochHeadAttester = true
+			}
 		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
 	}
 	bal = precompute.UpdateBalance(vals, bal, beaconState.Version())
 	return vals, bal, nil
@@ -242,19 +308,25 @@ func ProcessRewardsAndPenaltiesPrecompute(
 	}
 
 	balances := beaconState.Balances()
-	for i := 0; i < numOfVals; i++ {
-		vals[i].BeforeEpochTransitionBalance = balances[i]
+	if err := helpers.ForEachValidatorChunk(numOfVals, func(start, end int) error {
+		for i := start; i < end; i++ {
+			vals[i].BeforeEpochTransitionBalance = balances[i]
+
+			// Compute the post balance of the validator after accounting for the
+			// attester and proposer rewards and penalties.
+			delta := attDeltas[i]
+			var err error
+			balances[i], err = helpers.IncreaseBalanceWithVal(balances[i], delta.HeadReward+delta.SourceReward+delta.TargetReward)
+			if err != nil {
+				return err
+			}
+			balances[i] = helpers.DecreaseBalanceWithVal(balances[i], delta.SourcePenalty+delta.TargetPenalty+delta.InactivityPenalty)
 
-		// Compute the post balance of the validator after accounting for the
-		// attester and proposer rewards and penalties.
-		delta := attDeltas[i]
-		balances[i], err = helpers.IncreaseBalanceWithVal(balances[i], delta.HeadReward+delta.SourceReward+delta.TargetReward)
-		if err != nil {
-			return nil, err
+			vals[i].AfterEpochTransitionBalance = balances[i]
 		}
-		balances[i] = helpers.DecreaseBalanceWithVal(balances[i], delta.SourcePenalty+delta.TargetPenalty+delta.InactivityPenalty)
-
-		vals[i].AfterEpochTransitionBalance = balances[i]
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	if err := beaconState.SetBalances(balances); err != nil {
@@ -285,11 +357,17 @@ func AttestationsDelta(beaconState state.BeaconState, bal *precompute.Balance, v
 	}
 	inactivityDenominator := bias * inactivityPenaltyQuotient
 
-	for i, v := range vals {
-		attDeltas[i], err = attestationDelta(bal, v, baseRewardMultiplier, inactivityDenominator, leak)
-		if err != nil {
-			return nil, err
+	if err := helpers.ForEachValidatorChunk(len(vals), func(start, end int) error {
+		for i := start; i < end; i++ {
+			var err error
+			attDeltas[i], err = attestationDelta(bal, vals[i], baseRewardMultiplier, inactivityDenominator, leak)
+			if err != nil {
+				return err
+			}
 		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	return attDeltas, nil
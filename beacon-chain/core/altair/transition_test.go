@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/altair"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"github.com/prysmaticlabs/prysm/v5/testing/util"
 )
@@ -73,3 +75,23 @@ func TestProcessEpoch_CanProcessBellatrix(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, params.BeaconConfig().SyncCommitteeSize, uint64(len(sc.Pubkeys)))
 }
+
+func TestProcessEpoch_ParallelMatchesSequential(t *testing.T) {
+	for _, experimentalState := range []bool{false, true} {
+		for _, finalizedEpoch := range []primitives.Epoch{8, 0} {
+			resetCfg := features.InitWithReset(&features.Flags{EnableExperimentalState: experimentalState})
+			st := util.RandomEpochStateAltair(t, 5000, finalizedEpoch)
+			var roots [][32]byte
+			for _, parallel := range []bool{false, true} {
+				features.Init(&features.Flags{EnableExperimentalState: experimentalState, EnableParallelEpochProcessing: parallel})
+				copied := st.Copy()
+				require.NoError(t, altair.ProcessEpoch(context.Background(), copied))
+				root, err := copied.HashTreeRoot(context.Background())
+				require.NoError(t, err)
+				roots = append(roots, root)
+			}
+			resetCfg()
+			require.Equal(t, roots[0], roots[1], "Parallel epoch processing differs from sequential, finalized epoch %d", finalizedEpoch)
+		}
+	}
+}
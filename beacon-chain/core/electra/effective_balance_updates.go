@@ -61,5 +61,5 @@ func ProcessEffectiveBalanceUpdates(st state.BeaconState) error {
 		return newVal, nil
 	}
 
-	return st.ApplyToEveryValidator(validatorFunc)
+	return helpers.ApplyToEveryValidator(st, validatorFunc)
 }
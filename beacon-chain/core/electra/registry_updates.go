@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/time"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 )
@@ -43,33 +46,54 @@ func ProcessRegistryUpdates(ctx context.Context, st state.BeaconState) error {
 	// To avoid copying the state validator set via st.Validators(), we will perform a read only pass
 	// over the validator set while collecting validator indices where the validator copy is actually
 	// necessary, then we will process these operations.
-	eligibleForActivationQ := make([]primitives.ValidatorIndex, 0)
-	eligibleForEjection := make([]primitives.ValidatorIndex, 0)
-	eligibleForActivation := make([]primitives.ValidatorIndex, 0)
-
-	if err := st.ReadFromEveryValidator(func(idx int, val state.ReadOnlyValidator) error {
+	eligible := &registryUpdateIndices{}
+	collect := func(indices *registryUpdateIndices, idx int, val state.ReadOnlyValidator) {
 		// Collect validators eligible to enter the activation queue.
 		if helpers.IsEligibleForActivationQueue(val, currentEpoch) {
-			eligibleForActivationQ = append(eligibleForActivationQ, primitives.ValidatorIndex(idx))
+			indices.activationQueue = append(indices.activationQueue, primitives.ValidatorIndex(idx))
 		}
 
 		// Collect validators to eject.
 		if val.EffectiveBalance() <= ejectionBal && helpers.IsActiveValidatorUsingTrie(val, currentEpoch) {
-			eligibleForEjection = append(eligibleForEjection, primitives.ValidatorIndex(idx))
+			indices.ejection = append(indices.ejection, primitives.ValidatorIndex(idx))
 		}
 
 		// Collect validators eligible for activation and not yet dequeued for activation.
 		if helpers.IsEligibleForActivationUsingROVal(st, val) {
-			eligibleForActivation = append(eligibleForActivation, primitives.ValidatorIndex(idx))
+			indices.activation = append(indices.activation, primitives.ValidatorIndex(idx))
 		}
+	}
 
+	if features.Get().EnableParallelEpochProcessing {
+		var mu sync.Mutex
+		if err := helpers.ForEachValidatorChunk(st.NumValidators(), func(start, end int) error {
+			chunk := &registryUpdateIndices{}
+			for idx := start; idx < end; idx++ {
+				val, err := st.ValidatorAtIndexReadOnly(primitives.ValidatorIndex(idx))
+				if err != nil {
+					return err
+				}
+				collect(chunk, idx, val)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			eligible.append(chunk)
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to read validators: %w", err)
+		}
+		// The chunks finish in any order, while the ejections must be processed in the order of the
+		// sequential pass.
+		eligible.sort()
+	} else if err := st.ReadFromEveryValidator(func(idx int, val state.ReadOnlyValidator) error {
+		collect(eligible, idx, val)
 		return nil
 	}); err != nil {
 		return fmt.Errorf("failed to read validators: %w", err)
 	}
 
 	// Handle validators eligible to join the activation queue.
-	for _, idx := range eligibleForActivationQ {
+	for _, idx := range eligible.activationQueue {
 		v, err := st.ValidatorAtIndex(idx)
 		if err != nil {
 			return err
@@ -81,7 +105,7 @@ func ProcessRegistryUpdates(ctx context.Context, st state.BeaconState) error {
 	}
 
 	// Handle validator ejections.
-	for _, idx := range eligibleForEjection {
+	for _, idx := range eligible.ejection {
 		var err error
 		// exitQueueEpoch and churn arguments are not used in electra.
 		st, _, err = validators.InitiateValidatorExit(ctx, st, idx, 0 /*exitQueueEpoch*/, 0 /*churn*/)
@@ -90,7 +114,7 @@ func ProcessRegistryUpdates(ctx context.Context, st state.BeaconState) error {
 		}
 	}
 
-	for _, idx := range eligibleForActivation {
+	for _, idx := range eligible.activation {
 		// Activate all eligible validators.
 		v, err := st.ValidatorAtIndex(idx)
 		if err != nil {
@@ -104,3 +128,22 @@ func ProcessRegistryUpdates(ctx context.Context, st state.BeaconState) error {
 
 	return nil
 }
+
+// registryUpdateIndices are the indices of the validators to update during registry updates.
+type registryUpdateIndices struct {
+	activationQueue []primitives.ValidatorIndex
+	ejection        []primitives.ValidatorIndex
+	activation      []primitives.ValidatorIndex
+}
+
+func (r *registryUpdateIndices) append(other *registryUpdateIndices) {
+	r.activationQueue = append(r.activationQueue, other.activationQueue...)
+	r.ejection = append(r.ejection, other.ejection...)
+	r.activation = append(r.activation, other.activation...)
+}
+
+func (r *registryUpdateIndices) sort() {
+	slices.Sort(r.activationQueue)
+	slices.Sort(r.ejection)
+	slices.Sort(r.activation)
+}
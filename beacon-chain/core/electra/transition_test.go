@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/electra"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	consensusblocks "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
@@ -119,3 +120,21 @@ func TestProcessEpoch_CanProcessElectra(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(2), num)
 }
+
+func TestProcessEpoch_ParallelMatchesSequentialElectra(t *testing.T) {
+	for _, experimentalState := range []bool{false, true} {
+		resetCfg := features.InitWithReset(&features.Flags{EnableExperimentalState: experimentalState})
+		st := util.RandomEpochStateElectra(t, 5000, 8)
+		var roots [][32]byte
+		for _, parallel := range []bool{false, true} {
+			features.Init(&features.Flags{EnableExperimentalState: experimentalState, EnableParallelEpochProcessing: parallel})
+			copied := st.Copy()
+			require.NoError(t, electra.ProcessEpoch(context.Background(), copied))
+			root, err := copied.HashTreeRoot(context.Background())
+			require.NoError(t, err)
+			roots = append(roots, root)
+		}
+		resetCfg()
+		require.Equal(t, roots[0], roots[1], "Parallel epoch processing differs from sequential")
+	}
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prysmaticlabs/prysm/v5/async"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+// ForEachValidatorChunk calls f with consecutive chunks [start, end) of the validator indices below n.
+// With parallel epoch processing enabled, the chunks are processed by separate goroutines, so f must only
+// write to the entries of its own chunk and guard anything it shares with the other chunks. Otherwise f is
+// called once with the whole range.
+func ForEachValidatorChunk(n int, f func(start, end int) error) error {
+	if !features.Get().EnableParallelEpochProcessing || n <= 1 {
+		return f(0, n)
+	}
+	_, err := async.Scatter(n, func(offset int, entries int, _ *sync.RWMutex) (interface{}, error) {
+		return nil, f(offset, offset+entries)
+	})
+	return err
+}
+
+// ApplyToEveryValidator replaces every validator of the state for which f returns a new one, like
+// state.ApplyToEveryValidator. With parallel epoch processing enabled, the new validators are computed in chunks
+// processed by separate goroutines from read only validators, and set afterwards.
+func ApplyToEveryValidator(st state.BeaconState, f func(idx int, val state.ReadOnlyValidator) (*ethpb.Validator, error)) error {
+	if !features.Get().EnableParallelEpochProcessing {
+		return st.ApplyToEveryValidator(f)
+	}
+	newVals := make([]*ethpb.Validator, st.NumValidators())
+	if err := ForEachValidatorChunk(len(newVals), func(start, end int) error {
+		for idx := start; idx < end; idx++ {
+			val, err := st.ValidatorAtIndexReadOnly(primitives.ValidatorIndex(idx))
+			if err != nil {
+				return err
+			}
+			newVals[idx], err = f(idx, val)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for idx, newVal := range newVals {
+		if newVal == nil {
+			continue
+		}
+		if err := st.UpdateValidatorAtIndex(primitives.ValidatorIndex(idx), newVal); err != nil {
+			return fmt.Errorf("failed to update validator at index %d: %w", idx, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package helpers_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestForEachValidatorChunk(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		resetCfg := features.InitWithReset(&features.Flags{EnableParallelEpochProcessing: parallel})
+		for _, n := range []int{0, 1, 7, 1000} {
+			visited := make([]int, n)
+			var mu sync.Mutex
+			calls := 0
+			require.NoError(t, helpers.ForEachValidatorChunk(n, func(start, end int) error {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				for i := start; i < end; i++ {
+					visited[i]++
+				}
+				return nil
+			}))
+			for i, v := range visited {
+				assert.Equal(t, 1, v, "Index %d visited %d times", i, v)
+			}
+			if !parallel {
+				assert.Equal(t, 1, calls)
+			}
+		}
+
+		err := helpers.ForEachValidatorChunk(100, func(start, end int) error {
+			if start <= 42 && 42 < end {
+				return errors.New("bad validator")
+			}
+			return nil
+		})
+		require.ErrorContains(t, "bad validator", err)
+		resetCfg()
+	}
+}
+
+func TestApplyToEveryValidator(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		resetCfg := features.InitWithReset(&features.Flags{EnableParallelEpochProcessing: parallel})
+		st := util.RandomEpochStateAltair(t, 100, 0)
+		before := st.Validators()
+		require.NoError(t, helpers.ApplyToEveryValidator(st, func(idx int, val state.ReadOnlyValidator) (*ethpb.Validator, error) {
+			if idx%3 != 0 {
+				return nil, nil
+			}
+			newVal := val.Copy()
+			newVal.EffectiveBalance = uint64(idx)
+			return newVal, nil
+		}))
+		for i, v := range st.Validators() {
+			if i%3 == 0 {
+				assert.Equal(t, uint64(i), v.EffectiveBalance)
+			} else {
+				assert.Equal(t, before[i].EffectiveBalance, v.EffectiveBalance)
+			}
+		}
+
+		err := helpers.ApplyToEveryValidator(st, func(idx int, val state.ReadOnlyValidator) (*ethpb.Validator, error) {
+			if idx == 42 {
+				return nil, errors.New("bad validator")
+			}
+			return nil, nil
+		})
+		require.ErrorContains(t, "bad validator", err)
+		resetCfg()
+	}
+}
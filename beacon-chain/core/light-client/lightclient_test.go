@@ -343,6 +343,35 @@ func TestLightClient_NewLightClientFinalityUpdateFromBeaconState(t *testing.T) {
 	})
 }
 
+func TestLightClient_NewLightClientBootstrapFromBeaconState(t *testing.T) {
+	t.Run("Altair", func(t *testing.T) {
+		l := util.NewTestLightClient(t).SetupTestAltair()
+
+		bootstrap, err := lightClient.NewLightClientBootstrapFromBeaconState(l.Ctx, l.AttestedState, l.AttestedBlock)
+		require.NoError(t, err)
+		l.CheckAttestedHeader(bootstrap.Header)
+		committee, err := l.AttestedState.CurrentSyncCommittee()
+		require.NoError(t, err)
+		require.DeepSSZEqual(t, committee.Pubkeys, bootstrap.CurrentSyncCommittee.Pubkeys)
+		require.Equal(t, fieldparams.SyncCommitteeBranchDepth, len(bootstrap.CurrentSyncCommitteeBranch))
+	})
+
+	t.Run("Deneb", func(t *testing.T) {
+		l := util.NewTestLightClient(t).SetupTestDeneb(false)
+
+		bootstrap, err := lightClient.NewLightClientBootstrapFromBeaconState(l.Ctx, l.AttestedState, l.AttestedBlock)
+		require.NoError(t, err)
+		l.CheckAttestedHeader(bootstrap.Header)
+	})
+
+	t.Run("block does not match state", func(t *testing.T) {
+		l := util.NewTestLightClient(t).SetupTestAltair()
+
+		_, err := lightClient.NewLightClientBootstrapFromBeaconState(l.Ctx, l.AttestedState, l.Block)
+		require.ErrorContains(t, "not equal to block root", err)
+	})
+}
+
 func TestLightClient_BlockToLightClientHeader(t *testing.T) {
 	t.Run("Altair", func(t *testing.T) {
 		l := util.NewTestLightClient(t).SetupTestAltair()
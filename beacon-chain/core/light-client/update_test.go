@@ -1,4 +1,4 @@
-package lightclient
+package light_client_test
 
 import (
 	"testing"
@@ -608,7 +608,7 @@ func TestIsBetterUpdate(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			result, err := IsBetterUpdate(testCase.newUpdate, testCase.oldUpdate)
+			result, err := lightclient.IsBetterUpdate(testCase.newUpdate, testCase.oldUpdate)
 			assert.NoError(t, err)
 			assert.Equal(t, testCase.expectedResult, result)
 		})
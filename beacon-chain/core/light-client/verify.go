@@ -0,0 +1,264 @@
+package light_client
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/container/trie"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/network/forks"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+const (
+	// Subtree indices and depths of CURRENT_SYNC_COMMITTEE_GINDEX (54), NEXT_SYNC_COMMITTEE_GINDEX (55)
+	// and FINALIZED_ROOT_GINDEX (105) in the beacon state.
+	currentSyncCommitteeIndex = 22
+	nextSyncCommitteeIndex    = 23
+	finalizedRootIndex        = 41
+	syncCommitteeBranchDepth  = 5
+)
+
+var (
+	ErrInvalidBootstrap = errors.New("invalid light client bootstrap")
+	ErrInvalidUpdate    = errors.New("invalid light client update")
+)
+
+// Store is the state of a light client following the chain through sync committee signatures,
+// as described in the light client sync protocol. OptimisticHeader is the latest header signed by
+// a majority of the sync committee, it is never older than FinalizedHeader.
+type Store struct {
+	FinalizedHeader      *ethpb.BeaconBlockHeader
+	OptimisticHeader     *ethpb.BeaconBlockHeader
+	CurrentSyncCommittee *ethpb.SyncCommittee
+	NextSyncCommittee    *ethpb.SyncCommittee
+}
+
+// Update is a light client update in the form verified by the Store. NextSyncCommittee and
+// FinalizedHeader are optional, finality and optimistic updates carry no next sync committee.
+type Update struct {
+	AttestedHeader          *ethpb.BeaconBlockHeader
+	NextSyncCommittee       *ethpb.SyncCommittee
+	NextSyncCommitteeBranch [][]byte
+	FinalizedHeader         *ethpb.BeaconBlockHeader
+	FinalityBranch          [][]byte
+	SyncAggregate           *ethpb.SyncAggregate
+	SignatureSlot           primitives.Slot
+}
+
+// NewStore initializes a light client store from a bootstrap for the trusted block root.
+func NewStore(trustedRoot [32]byte, header *ethpb.BeaconBlockHeader, committee *ethpb.SyncCommittee, branch [][]byte) (*Store, error) {
+	if header == nil || committee == nil {
+		return nil, errors.Wrap(ErrInvalidBootstrap, "missing header or sync committee")
+	}
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute header root")
+	}
+	if root != trustedRoot {
+		return nil, errors.Wrapf(ErrInvalidBootstrap, "header root %#x does not match trusted root %#x", root, trustedRoot)
+	}
+	committeeRoot, err := committee.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute sync committee root")
+	}
+	if !isValidBranch(committeeRoot[:], branch, syncCommitteeBranchDepth, currentSyncCommitteeIndex, header.StateRoot) {
+		return nil, errors.Wrap(ErrInvalidBootstrap, "invalid current sync committee branch")
+	}
+	return &Store{
+		FinalizedHeader:      header,
+		OptimisticHeader:     header,
+		CurrentSyncCommittee: committee,
+	}, nil
+}
+
+// Period returns the sync committee period of the store's finalized header.
+func (s *Store) Period() uint64 {
+	return syncCommitteePeriodAtSlot(s.FinalizedHeader.Slot)
+}
+
+// ProcessUpdate validates the update against the store and applies it when it is signed by a supermajority
+// of the sync committee. The optimistic header follows the attested header of every update signed by a
+// majority of the sync committee. It returns whether the store's finalized header or sync committees changed.
+func (s *Store) ProcessUpdate(u *Update, genesisValidatorsRoot []byte) (bool, error) {
+	if err := s.validateUpdate(u, genesisValidatorsRoot); err != nil {
+		return false, err
+	}
+	participants := countParticipants(u.SyncAggregate, len(s.CurrentSyncCommittee.Pubkeys))
+	if participants*2 > uint64(len(s.CurrentSyncCommittee.Pubkeys)) && u.AttestedHeader.Slot > s.optimisticSlot() {
+		s.OptimisticHeader = u.AttestedHeader
+	}
+	if participants*3 < uint64(len(s.CurrentSyncCommittee.Pubkeys))*2 || u.FinalizedHeader == nil {
+		return false, nil
+	}
+	hasFinalizedNextCommittee := s.NextSyncCommittee == nil && u.NextSyncCommittee != nil &&
+		syncCommitteePeriodAtSlot(u.FinalizedHeader.Slot) == syncCommitteePeriodAtSlot(u.AttestedHeader.Slot)
+	if u.FinalizedHeader.Slot <= s.FinalizedHeader.Slot && !hasFinalizedNextCommittee {
+		return false, nil
+	}
+	return s.applyUpdate(u), nil
+}
+
+func (s *Store) validateUpdate(u *Update, genesisValidatorsRoot []byte) error {
+	if u == nil || u.AttestedHeader == nil || u.SyncAggregate == nil {
+		return errors.Wrap(ErrInvalidUpdate, "missing attested header or sync aggregate")
+	}
+	if countParticipants(u.SyncAggregate, len(s.CurrentSyncCommittee.Pubkeys)) < params.BeaconConfig().MinSyncCommitteeParticipants {
+		return errors.Wrap(ErrInvalidUpdate, "not enough sync committee participants")
+	}
+	if u.SignatureSlot <= u.AttestedHeader.Slot {
+		return errors.Wrapf(ErrInvalidUpdate, "signature slot %d not after attested slot %d", u.SignatureSlot, u.AttestedHeader.Slot)
+	}
+	if u.FinalizedHeader != nil && u.AttestedHeader.Slot < u.FinalizedHeader.Slot {
+		return errors.Wrapf(ErrInvalidUpdate, "attested slot %d before finalized slot %d", u.AttestedHeader.Slot, u.FinalizedHeader.Slot)
+	}
+
+	storePeriod := s.Period()
+	signaturePeriod := syncCommitteePeriodAtSlot(u.SignatureSlot)
+	if signaturePeriod != storePeriod && (s.NextSyncCommittee == nil || signaturePeriod != storePeriod+1) {
+		return errors.Wrapf(ErrInvalidUpdate, "signature period %d not applicable to store period %d", signaturePeriod, storePeriod)
+	}
+	attestedPeriod := syncCommitteePeriodAtSlot(u.AttestedHeader.Slot)
+	hasNextCommittee := s.NextSyncCommittee == nil && u.NextSyncCommittee != nil && attestedPeriod == storePeriod
+	if u.AttestedHeader.Slot <= s.FinalizedHeader.Slot && !hasNextCommittee {
+		return errors.Wrap(ErrInvalidUpdate, "update is not relevant to the store")
+	}
+
+	if u.FinalizedHeader != nil {
+		var finalizedRoot [32]byte
+		if u.FinalizedHeader.Slot != params.BeaconConfig().GenesisSlot {
+			r, err := u.FinalizedHeader.HashTreeRoot()
+			if err != nil {
+				return errors.Wrap(err, "could not compute finalized header root")
+			}
+			finalizedRoot = r
+		}
+		if !isValidBranch(finalizedRoot[:], u.FinalityBranch, FinalityBranchNumOfLeaves, finalizedRootIndex, u.AttestedHeader.StateRoot) {
+			return errors.Wrap(ErrInvalidUpdate, "invalid finality branch")
+		}
+	}
+	if u.NextSyncCommittee != nil {
+		committeeRoot, err := u.NextSyncCommittee.HashTreeRoot()
+		if err != nil {
+			return errors.Wrap(err, "could not compute next sync committee root")
+		}
+		if attestedPeriod == storePeriod && s.NextSyncCommittee != nil {
+			knownRoot, err := s.NextSyncCommittee.HashTreeRoot()
+			if err != nil {
+				return errors.Wrap(err, "could not compute known next sync committee root")
+			}
+			if knownRoot != committeeRoot {
+				return errors.Wrap(ErrInvalidUpdate, "next sync committee does not match the known one")
+			}
+		}
+		if !isValidBranch(committeeRoot[:], u.NextSyncCommitteeBranch, syncCommitteeBranchDepth, nextSyncCommitteeIndex, u.AttestedHeader.StateRoot) {
+			return errors.Wrap(ErrInvalidUpdate, "invalid next sync committee branch")
+		}
+	}
+
+	committee := s.CurrentSyncCommittee
+	if signaturePeriod != storePeriod {
+		committee = s.NextSyncCommittee
+	}
+	return verifySyncAggregate(u, committee, genesisValidatorsRoot)
+}
+
+func (s *Store) applyUpdate(u *Update) bool {
+	storePeriod := s.Period()
+	finalizedPeriod := syncCommitteePeriodAtSlot(u.FinalizedHeader.Slot)
+	if s.NextSyncCommittee == nil {
+		if finalizedPeriod != storePeriod {
+			return false
+		}
+		s.NextSyncCommittee = u.NextSyncCommittee
+	} else if finalizedPeriod == storePeriod+1 {
+		s.CurrentSyncCommittee = s.NextSyncCommittee
+		s.NextSyncCommittee = u.NextSyncCommittee
+	}
+	if u.FinalizedHeader.Slot > s.FinalizedHeader.Slot {
+		s.FinalizedHeader = u.FinalizedHeader
+	}
+	if s.FinalizedHeader.Slot > s.optimisticSlot() {
+		s.OptimisticHeader = s.FinalizedHeader
+	}
+	return true
+}
+
+func (s *Store) optimisticSlot() primitives.Slot {
+	if s.OptimisticHeader == nil {
+		return s.FinalizedHeader.Slot
+	}
+	return s.OptimisticHeader.Slot
+}
+
+// verifySyncAggregate checks the sync committee signature over the attested header.
+func verifySyncAggregate(u *Update, committee *ethpb.SyncCommittee, genesisValidatorsRoot []byte) error {
+	pubkeys := make([]bls.PublicKey, 0, len(committee.Pubkeys))
+	for i, pk := range committee.Pubkeys {
+		if !participated(u.SyncAggregate, i) {
+			continue
+		}
+		p, err := bls.PublicKeyFromBytes(pk)
+		if err != nil {
+			return errors.Wrapf(err, "could not decode sync committee public key %d", i)
+		}
+		pubkeys = append(pubkeys, p)
+	}
+	sig, err := bls.SignatureFromBytes(u.SyncAggregate.SyncCommitteeSignature)
+	if err != nil {
+		return errors.Wrap(err, "could not decode sync committee signature")
+	}
+	prevSlot := u.SignatureSlot
+	if prevSlot > 0 {
+		prevSlot--
+	}
+	forkVersion, err := forks.NewOrderedSchedule(params.BeaconConfig()).VersionForEpoch(slots.ToEpoch(prevSlot))
+	if err != nil {
+		return errors.Wrap(err, "could not determine fork version of signature slot")
+	}
+	domain, err := signing.ComputeDomain(params.BeaconConfig().DomainSyncCommittee, forkVersion[:], genesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not compute sync committee domain")
+	}
+	signingRoot, err := signing.ComputeSigningRoot(u.AttestedHeader, domain)
+	if err != nil {
+		return errors.Wrap(err, "could not compute signing root")
+	}
+	if !sig.FastAggregateVerify(pubkeys, signingRoot) {
+		return errors.Wrap(ErrInvalidUpdate, "invalid sync committee signature")
+	}
+	return nil
+}
+
+func participated(sa *ethpb.SyncAggregate, i int) bool {
+	bits := []byte(sa.SyncCommitteeBits)
+	return i/8 < len(bits) && bits[i/8]&(1<<(uint(i)%8)) != 0
+}
+
+func countParticipants(sa *ethpb.SyncAggregate, committeeSize int) uint64 {
+	var count uint64
+	for i := 0; i < committeeSize; i++ {
+		if participated(sa, i) {
+			count++
+		}
+	}
+	return count
+}
+
+func isValidBranch(leaf []byte, branch [][]byte, depth, index uint64, root []byte) bool {
+	if uint64(len(branch)) != depth {
+		return false
+	}
+	for _, b := range branch {
+		if len(b) != 32 {
+			return false
+		}
+	}
+	return trie.VerifyMerkleProof(root, leaf, index, branch)
+}
+
+func syncCommitteePeriodAtSlot(slot primitives.Slot) uint64 {
+	return slots.SyncCommitteePeriod(slots.ToEpoch(slot))
+}
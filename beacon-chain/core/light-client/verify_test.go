@@ -0,0 +1,214 @@
+package light_client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/go-bitfield"
+	lightClient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/network/forks"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+var testGenesisValidatorsRoot = bytesutil.PadTo([]byte("genesis validators root"), 32)
+
+func headerForState(t *testing.T, st state.BeaconState, slot primitives.Slot) *ethpb.BeaconBlockHeader {
+	require.NoError(t, st.SetSlot(slot))
+	sr, err := st.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	return &ethpb.BeaconBlockHeader{
+		Slot:       slot,
+		ParentRoot: bytesutil.PadTo([]byte("parent"), 32),
+		StateRoot:  sr[:],
+		BodyRoot:   bytesutil.PadTo([]byte("body"), 32),
+	}
+}
+
+func signUpdate(t *testing.T, u *lightClient.Update, committee *ethpb.SyncCommittee, keys []bls.SecretKey, participants int) {
+	keyByPubkey := make(map[[48]byte]bls.SecretKey, len(keys))
+	for _, k := range keys {
+		keyByPubkey[bytesutil.ToBytes48(k.PublicKey().Marshal())] = k
+	}
+	forkVersion, err := forks.NewOrderedSchedule(params.BeaconConfig()).VersionForEpoch(slots.ToEpoch(u.SignatureSlot - 1))
+	require.NoError(t, err)
+	domain, err := signing.ComputeDomain(params.BeaconConfig().DomainSyncCommittee, forkVersion[:], testGenesisValidatorsRoot)
+	require.NoError(t, err)
+	signingRoot, err := signing.ComputeSigningRoot(u.AttestedHeader, domain)
+	require.NoError(t, err)
+
+	bits := bitfield.NewBitvector512()
+	sigs := make([]bls.Signature, 0, participants)
+	for i := 0; i < participants; i++ {
+		bits.SetBitAt(uint64(i), true)
+		sigs = append(sigs, keyByPubkey[bytesutil.ToBytes48(committee.Pubkeys[i])].Sign(signingRoot[:]))
+	}
+	u.SyncAggregate = &ethpb.SyncAggregate{
+		SyncCommitteeBits:      bits,
+		SyncCommitteeSignature: bls.AggregateSignatures(sigs).Marshal(),
+	}
+}
+
+// testState returns an altair state whose sync committees are made of the deterministic validator keys.
+func testState(t *testing.T) (state.BeaconState, []bls.SecretKey) {
+	st, keys := util.DeterministicGenesisStateAltair(t, 64)
+	committee := func(offset int) *ethpb.SyncCommittee {
+		c := &ethpb.SyncCommittee{AggregatePubkey: keys[offset].PublicKey().Marshal()}
+		for i := uint64(0); i < params.BeaconConfig().SyncCommitteeSize; i++ {
+			c.Pubkeys = append(c.Pubkeys, keys[(int(i)+offset)%len(keys)].PublicKey().Marshal())
+		}
+		return c
+	}
+	require.NoError(t, st.SetCurrentSyncCommittee(committee(0)))
+	require.NoError(t, st.SetNextSyncCommittee(committee(1)))
+	return st, keys
+}
+
+type testChain struct {
+	store     *lightClient.Store
+	keys      []bls.SecretKey
+	committee *ethpb.SyncCommittee
+	update    *lightClient.Update
+}
+
+func setupTestChain(t *testing.T) *testChain {
+	ctx := context.Background()
+	st, keys := testState(t)
+	committee, err := st.CurrentSyncCommittee()
+	require.NoError(t, err)
+
+	bootstrapHeader := headerForState(t, st, 8)
+	bootstrapRoot, err := bootstrapHeader.HashTreeRoot()
+	require.NoError(t, err)
+	branch, err := st.CurrentSyncCommitteeProof(ctx)
+	require.NoError(t, err)
+	store, err := lightClient.NewStore(bootstrapRoot, bootstrapHeader, committee, branch)
+	require.NoError(t, err)
+
+	finalizedHeader := &ethpb.BeaconBlockHeader{
+		Slot:       32,
+		ParentRoot: bytesutil.PadTo([]byte("finalized parent"), 32),
+		StateRoot:  bytesutil.PadTo([]byte("finalized state"), 32),
+		BodyRoot:   bytesutil.PadTo([]byte("finalized body"), 32),
+	}
+	finalizedRoot, err := finalizedHeader.HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, st.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}))
+	attestedHeader := headerForState(t, st, 100)
+	finalityBranch, err := st.FinalizedRootProof(ctx)
+	require.NoError(t, err)
+	nextCommittee, err := st.NextSyncCommittee()
+	require.NoError(t, err)
+	nextBranch, err := st.NextSyncCommitteeProof(ctx)
+	require.NoError(t, err)
+
+	u := &lightClient.Update{
+		AttestedHeader:          attestedHeader,
+		NextSyncCommittee:       nextCommittee,
+		NextSyncCommitteeBranch: nextBranch,
+		FinalizedHeader:         finalizedHeader,
+		FinalityBranch:          finalityBranch,
+		SignatureSlot:           101,
+	}
+	signUpdate(t, u, committee, keys, len(committee.Pubkeys))
+	return &testChain{store: store, keys: keys, committee: committee, update: u}
+}
+
+func TestNewStore(t *testing.T) {
+	ctx := context.Background()
+	st, _ := testState(t)
+	committee, err := st.CurrentSyncCommittee()
+	require.NoError(t, err)
+	header := headerForState(t, st, 8)
+	root, err := header.HashTreeRoot()
+	require.NoError(t, err)
+	branch, err := st.CurrentSyncCommitteeProof(ctx)
+	require.NoError(t, err)
+
+	store, err := lightClient.NewStore(root, header, committee, branch)
+	require.NoError(t, err)
+	assert.DeepEqual(t, header, store.FinalizedHeader)
+	assert.Equal(t, uint64(0), store.Period())
+
+	_, err = lightClient.NewStore([32]byte{'a'}, header, committee, branch)
+	require.ErrorIs(t, err, lightClient.ErrInvalidBootstrap)
+
+	badBranch := make([][]byte, len(branch))
+	copy(badBranch, branch)
+	badBranch[0] = make([]byte, 32)
+	_, err = lightClient.NewStore(root, header, committee, badBranch)
+	require.ErrorIs(t, err, lightClient.ErrInvalidBootstrap)
+}
+
+func TestStore_ProcessUpdate(t *testing.T) {
+	t.Run("valid update", func(t *testing.T) {
+		c := setupTestChain(t)
+		changed, err := c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		assert.Equal(t, true, changed)
+		assert.DeepEqual(t, c.update.FinalizedHeader, c.store.FinalizedHeader)
+		assert.DeepEqual(t, c.update.NextSyncCommittee, c.store.NextSyncCommittee)
+		assert.DeepEqual(t, c.update.AttestedHeader, c.store.OptimisticHeader)
+
+		// Applying the same update again changes nothing.
+		changed, err = c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		assert.Equal(t, false, changed)
+	})
+	t.Run("wrong domain", func(t *testing.T) {
+		c := setupTestChain(t)
+		_, err := c.store.ProcessUpdate(c.update, make([]byte, 32))
+		require.ErrorContains(t, "invalid sync committee signature", err)
+		assert.Equal(t, primitives.Slot(8), c.store.FinalizedHeader.Slot)
+	})
+	t.Run("invalid finality branch", func(t *testing.T) {
+		c := setupTestChain(t)
+		c.update.FinalizedHeader.Slot = 33
+		_, err := c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.ErrorContains(t, "invalid finality branch", err)
+	})
+	t.Run("invalid next sync committee branch", func(t *testing.T) {
+		c := setupTestChain(t)
+		c.update.NextSyncCommitteeBranch[0] = make([]byte, 32)
+		_, err := c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.ErrorContains(t, "invalid next sync committee branch", err)
+	})
+	t.Run("no supermajority", func(t *testing.T) {
+		c := setupTestChain(t)
+		signUpdate(t, c.update, c.committee, c.keys, len(c.committee.Pubkeys)/2)
+		changed, err := c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		assert.Equal(t, false, changed)
+		assert.Equal(t, primitives.Slot(8), c.store.FinalizedHeader.Slot)
+		// Half of the committee is not a majority either.
+		assert.Equal(t, primitives.Slot(8), c.store.OptimisticHeader.Slot)
+	})
+	t.Run("optimistic update", func(t *testing.T) {
+		c := setupTestChain(t)
+		c.update.FinalizedHeader = nil
+		c.update.FinalityBranch = nil
+		c.update.NextSyncCommittee = nil
+		c.update.NextSyncCommitteeBranch = nil
+		changed, err := c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.NoError(t, err)
+		assert.Equal(t, false, changed)
+		assert.Equal(t, primitives.Slot(8), c.store.FinalizedHeader.Slot)
+		assert.DeepEqual(t, c.update.AttestedHeader, c.store.OptimisticHeader)
+	})
+	t.Run("signature period too far ahead", func(t *testing.T) {
+		c := setupTestChain(t)
+		slotsPerPeriod := primitives.Slot(params.BeaconConfig().EpochsPerSyncCommitteePeriod) * params.BeaconConfig().SlotsPerEpoch
+		c.update.SignatureSlot = slotsPerPeriod + 1
+		_, err := c.store.ProcessUpdate(c.update, testGenesisValidatorsRoot)
+		require.ErrorContains(t, "not applicable to store period", err)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/testing/benchmark"
+	"github.com/prysmaticlabs/prysm/v5/testing/epochprocessing"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"google.golang.org/protobuf/proto"
 )
@@ -25,17 +26,19 @@ func BenchmarkExecuteStateTransition_FullBlock(b *testing.B) {
 	defer undo()
 	beaconState, err := benchmark.PreGenState1Epoch()
 	require.NoError(b, err)
-	cleanStates := clonedStates(beaconState)
 	block, err := benchmark.PreGenFullBlock()
 	require.NoError(b, err)
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		wsb, err := blocks.NewSignedBeaconBlock(block)
-		require.NoError(b, err)
-		_, err = coreState.ExecuteStateTransition(context.Background(), cleanStates[i], wsb)
-		require.NoError(b, err)
-	}
+	epochprocessing.BenchmarkInModes(b, func(b *testing.B) {
+		cleanStates := clonedStates(beaconState)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			wsb, err := blocks.NewSignedBeaconBlock(block)
+			require.NoError(b, err)
+			_, err = coreState.ExecuteStateTransition(context.Background(), cleanStates[i], wsb)
+			require.NoError(b, err)
+		}
+	})
 }
 
 func BenchmarkExecuteStateTransition_WithCache(b *testing.B) {
@@ -45,7 +48,7 @@ func BenchmarkExecuteStateTransition_WithCache(b *testing.B) {
 
 	beaconState, err := benchmark.PreGenState1Epoch()
 	require.NoError(b, err)
-	cleanStates := clonedStates(beaconState)
+	cleanState := beaconState.Copy()
 	block, err := benchmark.PreGenFullBlock()
 	require.NoError(b, err)
 
@@ -61,13 +64,16 @@ func BenchmarkExecuteStateTransition_WithCache(b *testing.B) {
 	_, err = coreState.ExecuteStateTransition(context.Background(), beaconState, wsb)
 	require.NoError(b, err, "Failed to process block, benchmarks will fail")
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		wsb, err := blocks.NewSignedBeaconBlock(block)
-		require.NoError(b, err)
-		_, err = coreState.ExecuteStateTransition(context.Background(), cleanStates[i], wsb)
-		require.NoError(b, err, "Failed to process block, benchmarks will fail")
-	}
+	epochprocessing.BenchmarkInModes(b, func(b *testing.B) {
+		cleanStates := clonedStates(cleanState)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			wsb, err := blocks.NewSignedBeaconBlock(block)
+			require.NoError(b, err)
+			_, err = coreState.ExecuteStateTransition(context.Background(), cleanStates[i], wsb)
+			require.NoError(b, err, "Failed to process block, benchmarks will fail")
+		}
+	})
 }
 
 func BenchmarkProcessEpoch_2FullEpochs(b *testing.B) {
@@ -84,13 +90,15 @@ func BenchmarkProcessEpoch_2FullEpochs(b *testing.B) {
 	require.NoError(b, helpers.UpdateCommitteeCache(context.Background(), beaconState, time.CurrentEpoch(beaconState)))
 	require.NoError(b, beaconState.SetSlot(currentSlot))
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		// ProcessEpochPrecompute is the optimized version of process epoch. It's enabled by default
-		// at run time.
-		_, err := coreState.ProcessEpochPrecompute(context.Background(), beaconState.Copy())
-		require.NoError(b, err)
-	}
+	epochprocessing.BenchmarkInModes(b, func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// ProcessEpochPrecompute is the optimized version of process epoch. It's enabled by default
+			// at run time.
+			_, err := coreState.ProcessEpochPrecompute(context.Background(), beaconState.Copy())
+			require.NoError(b, err)
+		}
+	})
 }
 
 func BenchmarkHashTreeRoot_FullState(b *testing.B) {
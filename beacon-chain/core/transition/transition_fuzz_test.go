@@ -10,31 +10,34 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/epochprocessing"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 )
 
 func TestFuzzExecuteStateTransition_1000(t *testing.T) {
-	SkipSlotCache.Disable()
-	defer SkipSlotCache.Enable()
-	ctx := context.Background()
-	state, err := state_native.InitializeFromProtoUnsafePhase0(&ethpb.BeaconState{})
-	require.NoError(t, err)
-	sb := &ethpb.SignedBeaconBlock{}
-	fuzzer := fuzz.NewWithSeed(0)
-	fuzzer.NilChance(0.1)
-	for i := 0; i < 1000; i++ {
-		fuzzer.Fuzz(state)
-		fuzzer.Fuzz(sb)
-		if sb.Block == nil || sb.Block.Body == nil {
-			continue
-		}
-		wsb, err := blocks.NewSignedBeaconBlock(sb)
+	epochprocessing.RunInModes(t, func(t *testing.T) {
+		SkipSlotCache.Disable()
+		defer SkipSlotCache.Enable()
+		ctx := context.Background()
+		state, err := state_native.InitializeFromProtoUnsafePhase0(&ethpb.BeaconState{})
 		require.NoError(t, err)
-		s, err := ExecuteStateTransition(ctx, state, wsb)
-		if err != nil && s != nil {
-			t.Fatalf("state should be nil on err. found: %v on error: %v for state: %v and signed block: %v", s, err, state, sb)
+		sb := &ethpb.SignedBeaconBlock{}
+		fuzzer := fuzz.NewWithSeed(0)
+		fuzzer.NilChance(0.1)
+		for i := 0; i < 1000; i++ {
+			fuzzer.Fuzz(state)
+			fuzzer.Fuzz(sb)
+			if sb.Block == nil || sb.Block.Body == nil {
+				continue
+			}
+			wsb, err := blocks.NewSignedBeaconBlock(sb)
+			require.NoError(t, err)
+			s, err := ExecuteStateTransition(ctx, state, wsb)
+			if err != nil && s != nil {
+				t.Fatalf("state should be nil on err. found: %v on error: %v for state: %v and signed block: %v", s, err, state, sb)
+			}
 		}
-	}
+	})
 }
 
 func TestFuzzCalculateStateRoot_1000(t *testing.T) {
@@ -79,22 +82,24 @@ func TestFuzzProcessSlot_1000(t *testing.T) {
 }
 
 func TestFuzzProcessSlots_1000(t *testing.T) {
-	SkipSlotCache.Disable()
-	defer SkipSlotCache.Enable()
-	ctx := context.Background()
-	state, err := state_native.InitializeFromProtoUnsafePhase0(&ethpb.BeaconState{})
-	require.NoError(t, err)
-	slot := primitives.Slot(0)
-	fuzzer := fuzz.NewWithSeed(0)
-	fuzzer.NilChance(0.1)
-	for i := 0; i < 1000; i++ {
-		fuzzer.Fuzz(state)
-		fuzzer.Fuzz(&slot)
-		s, err := ProcessSlots(ctx, state, slot)
-		if err != nil && s != nil {
-			t.Fatalf("state should be nil on err. found: %v on error: %v for state: %v", s, err, state)
+	epochprocessing.RunInModes(t, func(t *testing.T) {
+		SkipSlotCache.Disable()
+		defer SkipSlotCache.Enable()
+		ctx := context.Background()
+		state, err := state_native.InitializeFromProtoUnsafePhase0(&ethpb.BeaconState{})
+		require.NoError(t, err)
+		slot := primitives.Slot(0)
+		fuzzer := fuzz.NewWithSeed(0)
+		fuzzer.NilChance(0.1)
+		for i := 0; i < 1000; i++ {
+			fuzzer.Fuzz(state)
+			fuzzer.Fuzz(&slot)
+			s, err := ProcessSlots(ctx, state, slot)
+			if err != nil && s != nil {
+				t.Fatalf("state should be nil on err. found: %v on error: %v for state: %v", s, err, state)
+			}
 		}
-	}
+	})
 }
 
 func TestFuzzprocessOperationsNoVerify_1000(t *testing.T) {
@@ -156,20 +161,22 @@ func TestFuzzCanProcessEpoch_10000(t *testing.T) {
 }
 
 func TestFuzzProcessEpochPrecompute_1000(t *testing.T) {
-	SkipSlotCache.Disable()
-	defer SkipSlotCache.Enable()
-	ctx := context.Background()
-	state, err := state_native.InitializeFromProtoUnsafePhase0(&ethpb.BeaconState{})
-	require.NoError(t, err)
-	fuzzer := fuzz.NewWithSeed(0)
-	fuzzer.NilChance(0.1)
-	for i := 0; i < 1000; i++ {
-		fuzzer.Fuzz(state)
-		s, err := ProcessEpochPrecompute(ctx, state)
-		if err != nil && s != nil {
-			t.Fatalf("state should be nil on err. found: %v on error: %v for state: %v", s, err, state)
+	epochprocessing.RunInModes(t, func(t *testing.T) {
+		SkipSlotCache.Disable()
+		defer SkipSlotCache.Enable()
+		ctx := context.Background()
+		state, err := state_native.InitializeFromProtoUnsafePhase0(&ethpb.BeaconState{})
+		require.NoError(t, err)
+		fuzzer := fuzz.NewWithSeed(0)
+		fuzzer.NilChance(0.1)
+		for i := 0; i < 1000; i++ {
+			fuzzer.Fuzz(state)
+			s, err := ProcessEpochPrecompute(ctx, state)
+			if err != nil && s != nil {
+				t.Fatalf("state should be nil on err. found: %v on error: %v for state: %v", s, err, state)
+			}
 		}
-	}
+	})
 }
 
 func TestFuzzProcessBlockForStateRoot_1000(t *testing.T) {
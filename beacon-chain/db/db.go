@@ -8,7 +8,11 @@ import (
 
 // NewFileName uses the KVStoreDatafilePath so that if this layer of
 // indirection between db.NewDB->kv.NewKVStore ever changes, it will be easy to remember
-// to also change this filename indirection at the same time.
+// to also change this filename indirection at the same time. The path is the directory
+// of the database when it uses a backend which stores it in a directory.
 func NewFileName(dirPath string) string {
+	if backend, ok, err := kv.DetectBackend(dirPath); err == nil && ok {
+		return kv.StoreDataPath(dirPath, backend)
+	}
 	return kv.StoreDatafilePath(dirPath)
 }
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltDB is a DB backed by a BoltDB file.
+type BoltDB struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens or creates the BoltDB file at the path.
+func OpenBolt(path string, mode os.FileMode, opts *bolt.Options) (*BoltDB, error) {
+	db, err := bolt.Open(path, mode, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &BoltDB{db: db}, nil
+}
+
+// Bolt returns the underlying BoltDB database.
+func (b *BoltDB) Bolt() *bolt.DB {
+	return b.db
+}
+
+// View runs the function in a read only BoltDB transaction.
+func (b *BoltDB) View(fn func(Tx) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx: tx})
+	})
+}
+
+// Update runs the function in a read-write BoltDB transaction.
+func (b *BoltDB) Update(fn func(Tx) error) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx: tx})
+	})
+}
+
+// Backend returns Bolt.
+func (*BoltDB) Backend() Backend {
+	return Bolt
+}
+
+// Path returns the path of the BoltDB file.
+func (b *BoltDB) Path() string {
+	return b.db.Path()
+}
+
+// Close closes the BoltDB file.
+func (b *BoltDB) Close() error {
+	return b.db.Close()
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t boltTx) Bucket(name []byte) Bucket {
+	bkt := t.tx.Bucket(name)
+	if bkt == nil {
+		return nil
+	}
+	return boltBucket{bkt: bkt}
+}
+
+func (t boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	bkt, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return boltBucket{bkt: bkt}, nil
+}
+
+func (t boltTx) DeleteBucket(name []byte) error {
+	err := t.tx.DeleteBucket(name)
+	if err == bolt.ErrBucketNotFound {
+		return ErrBucketNotFound
+	}
+	return err
+}
+
+func (t boltTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return t.tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+		return fn(name, boltBucket{bkt: bkt})
+	})
+}
+
+func (t boltTx) Writable() bool {
+	return t.tx.Writable()
+}
+
+type boltBucket struct {
+	bkt *bolt.Bucket
+}
+
+func (b boltBucket) Get(key []byte) []byte {
+	return b.bkt.Get(key)
+}
+
+func (b boltBucket) Put(key, value []byte) error {
+	if err := b.bkt.Put(key, value); err != nil {
+		if err == bolt.ErrTxNotWritable {
+			return ErrTxNotWritable
+		}
+		return err
+	}
+	return nil
+}
+
+func (b boltBucket) Delete(key []byte) error {
+	if err := b.bkt.Delete(key); err != nil {
+		if err == bolt.ErrTxNotWritable {
+			return ErrTxNotWritable
+		}
+		return err
+	}
+	return nil
+}
+
+func (b boltBucket) ForEach(fn func(k, v []byte) error) error {
+	return b.bkt.ForEach(fn)
+}
+
+func (b boltBucket) Cursor() Cursor {
+	return b.bkt.Cursor()
+}
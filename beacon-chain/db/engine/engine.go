@@ -0,0 +1,177 @@
+// Package engine defines the key-value storage the beacon node database is built on, as a set of named buckets
+// of sorted keys accessed through transactions, with implementations backed by BoltDB and Pebble.
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Backend identifies a storage engine implementation.
+type Backend string
+
+const (
+	// Bolt stores all buckets in a single memory mapped BoltDB file. It allows a single writer at a time.
+	Bolt Backend = "bolt"
+	// Pebble stores all buckets in a Pebble log-structured merge tree, in a directory.
+	Pebble Backend = "pebble"
+)
+
+// Backends lists the supported storage engines.
+var Backends = []Backend{Bolt, Pebble}
+
+var (
+	// ErrTxNotWritable is returned when a read only transaction is used to write.
+	ErrTxNotWritable = errors.New("transaction is not writable")
+	// ErrBucketNotFound is returned when deleting a bucket which does not exist.
+	ErrBucketNotFound = errors.New("bucket not found")
+	// ErrUnknownBackend is returned for a backend name which is not one of Backends.
+	ErrUnknownBackend = errors.New("unknown database backend")
+)
+
+// ParseBackend returns the backend with the given name.
+func ParseBackend(name string) (Backend, error) {
+	for _, b := range Backends {
+		if string(b) == name {
+			return b, nil
+		}
+	}
+	return "", errors.Wrapf(ErrUnknownBackend, "%q", name)
+}
+
+// DB is a key-value store organized in buckets. Read only transactions run concurrently with each other and with
+// at most one read-write transaction.
+type DB interface {
+	// View runs the function in a read only transaction.
+	View(fn func(Tx) error) error
+	// Update runs the function in a read-write transaction, which is committed if the function returns nil and
+	// rolled back otherwise.
+	Update(fn func(Tx) error) error
+	// Backend returns the storage engine of the database.
+	Backend() Backend
+	// Path returns the file or directory the database is stored in.
+	Path() string
+	Close() error
+}
+
+// Tx is a transaction. Keys and values returned by a transaction are only valid until it ends.
+type Tx interface {
+	// Bucket returns the bucket with the given name, or nil if it does not exist.
+	Bucket(name []byte) Bucket
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+	DeleteBucket(name []byte) error
+	// ForEach calls the function for every bucket, in name order.
+	ForEach(fn func(name []byte, b Bucket) error) error
+	Writable() bool
+}
+
+// Bucket is a set of sorted keys and their values.
+type Bucket interface {
+	// Get returns the value of the key, or nil if the key does not exist.
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	// ForEach calls the function for every key and value in the bucket, in key order. The bucket must not be
+	// modified by the function.
+	ForEach(fn func(k, v []byte) error) error
+	Cursor() Cursor
+}
+
+// Cursor iterates over the sorted keys of a bucket. Every method returns nil keys once the cursor moves out of the
+// bucket.
+type Cursor interface {
+	First() (key, value []byte)
+	Last() (key, value []byte)
+	Next() (key, value []byte)
+	Prev() (key, value []byte)
+	// Seek moves the cursor to the first key at or after the given one.
+	Seek(seek []byte) (key, value []byte)
+	// Delete removes the key the cursor is at.
+	Delete() error
+}
+
+// CopyBatchSize is the number of keys Copy writes in each transaction.
+const CopyBatchSize = 10_000
+
+// Copy copies every bucket of the source database into the destination database. Keys are written in batches of
+// CopyBatchSize, so that neither database holds a long running transaction. The progress function, if any, is
+// called after each batch with the bucket being copied and the number of keys copied from it so far.
+func Copy(ctx context.Context, src, dst DB, progress func(bucket []byte, keys int)) error {
+	var names [][]byte
+	if err := src.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, _ Bucket) error {
+			names = append(names, append([]byte{}, name...))
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := dst.Update(func(tx Tx) error {
+			_, err := tx.CreateBucketIfNotExists(name)
+			return err
+		}); err != nil {
+			return err
+		}
+		var bucketProgress func(int)
+		if progress != nil {
+			bucketProgress = func(keys int) { progress(name, keys) }
+		}
+		if err := copyBucketKeys(ctx, src, dst, name, bucketProgress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyBucketKeys copies the keys of the bucket into the existing bucket of the destination database, in batches.
+func copyBucketKeys(ctx context.Context, src, dst DB, name []byte, progress func(keys int)) error {
+	var next []byte
+	copied := 0
+	for first := true; first || next != nil; first = false {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var keys, values [][]byte
+		if err := src.View(func(tx Tx) error {
+			bkt := tx.Bucket(name)
+			if bkt == nil {
+				return nil
+			}
+			c := bkt.Cursor()
+			k, v := c.First()
+			if !first {
+				k, v = c.Seek(next)
+			}
+			for ; k != nil && len(keys) < CopyBatchSize; k, v = c.Next() {
+				keys = append(keys, append([]byte{}, k...))
+				values = append(values, append([]byte{}, v...))
+			}
+			next = nil
+			if k != nil {
+				next = append([]byte{}, k...)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := dst.Update(func(tx Tx) error {
+			bkt := tx.Bucket(name)
+			for i := range keys {
+				if err := bkt.Put(keys[i], values[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("could not copy bucket %s: %w", name, err)
+		}
+		copied += len(keys)
+		if progress != nil {
+			progress(copied)
+		}
+	}
+	return nil
+}
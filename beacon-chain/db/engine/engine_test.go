@@ -0,0 +1,302 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestDB(t *testing.T, backend Backend) DB {
+	var db DB
+	var err error
+	switch backend {
+	case Bolt:
+		db, err = OpenBolt(filepath.Join(t.TempDir(), "test.db"), 0600, &bolt.Options{})
+	case Pebble:
+		db, err = OpenPebble(t.TempDir(), PebbleOptions{CacheSize: 1 << 20})
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return db
+}
+
+func forEachBackend(t *testing.T, fn func(t *testing.T, db DB)) {
+	for _, backend := range Backends {
+		t.Run(string(backend), func(t *testing.T) {
+			db := openTestDB(t, backend)
+			require.Equal(t, backend, db.Backend())
+			fn(t, db)
+		})
+	}
+}
+
+func TestParseBackend(t *testing.T) {
+	b, err := ParseBackend("pebble")
+	require.NoError(t, err)
+	assert.Equal(t, Pebble, b)
+	_, err = ParseBackend("leveldb")
+	require.ErrorIs(t, err, ErrUnknownBackend)
+}
+
+func TestBuckets(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		require.NoError(t, db.Update(func(tx Tx) error {
+			assert.Equal(t, true, tx.Writable())
+			assert.Equal(t, nil, tx.Bucket([]byte("a")))
+			for _, name := range []string{"b", "a", "ab"} {
+				if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+					return err
+				}
+			}
+			require.NoError(t, tx.Bucket([]byte("a")).Put([]byte("k"), []byte("a")))
+			require.NoError(t, tx.Bucket([]byte("ab")).Put([]byte("k"), []byte("ab")))
+			return nil
+		}))
+		require.NoError(t, db.View(func(tx Tx) error {
+			assert.Equal(t, false, tx.Writable())
+			var names []string
+			require.NoError(t, tx.ForEach(func(name []byte, _ Bucket) error {
+				names = append(names, string(name))
+				return nil
+			}))
+			assert.DeepEqual(t, []string{"a", "ab", "b"}, names)
+			// Buckets whose names share a prefix do not share keys.
+			assert.DeepEqual(t, []byte("a"), tx.Bucket([]byte("a")).Get([]byte("k")))
+			assert.DeepEqual(t, []byte("ab"), tx.Bucket([]byte("ab")).Get([]byte("k")))
+			require.ErrorIs(t, tx.Bucket([]byte("a")).Put([]byte("x"), nil), ErrTxNotWritable)
+			return nil
+		}))
+		require.NoError(t, db.Update(func(tx Tx) error {
+			require.NoError(t, tx.DeleteBucket([]byte("a")))
+			require.ErrorIs(t, tx.DeleteBucket([]byte("c")), ErrBucketNotFound)
+			assert.Equal(t, nil, tx.Bucket([]byte("a")))
+			bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+			require.NoError(t, err)
+			assert.Equal(t, true, bkt.Get([]byte("k")) == nil)
+			return nil
+		}))
+		require.NoError(t, db.View(func(tx Tx) error {
+			assert.Equal(t, true, tx.Bucket([]byte("a")).Get([]byte("k")) == nil)
+			assert.DeepEqual(t, []byte("ab"), tx.Bucket([]byte("ab")).Get([]byte("k")))
+			return nil
+		}))
+	})
+}
+
+func TestUpdate_Rollback(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		require.NoError(t, db.Update(func(tx Tx) error {
+			bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+			require.NoError(t, err)
+			return bkt.Put([]byte("k"), []byte("v"))
+		}))
+		wantErr := errors.New("rollback")
+		require.ErrorIs(t, db.Update(func(tx Tx) error {
+			bkt := tx.Bucket([]byte("a"))
+			require.NoError(t, bkt.Put([]byte("k"), []byte("changed")))
+			require.NoError(t, bkt.Put([]byte("other"), []byte("v")))
+			// Writes are visible within the transaction.
+			assert.DeepEqual(t, []byte("changed"), bkt.Get([]byte("k")))
+			return wantErr
+		}), wantErr)
+		require.NoError(t, db.View(func(tx Tx) error {
+			bkt := tx.Bucket([]byte("a"))
+			assert.DeepEqual(t, []byte("v"), bkt.Get([]byte("k")))
+			assert.Equal(t, true, bkt.Get([]byte("other")) == nil)
+			return nil
+		}))
+	})
+}
+
+// failingReader is a Pebble reader whose reads of existing keys fail.
+type failingReader struct {
+	pebble.Reader
+	err error
+}
+
+func (r failingReader) Get(key []byte) ([]byte, io.Closer, error) {
+	v, closer, err := r.Reader.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := closer.Close(); err != nil {
+		return nil, nil, err
+	}
+	if v == nil {
+		return nil, nil, pebble.ErrNotFound
+	}
+	return nil, nil, r.err
+}
+
+func TestPebbleTx_ReadError(t *testing.T) {
+	db, err := OpenPebble(t.TempDir(), PebbleOptions{CacheSize: 1 << 20})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	require.NoError(t, db.Update(func(tx Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+		require.NoError(t, err)
+		return bkt.Put([]byte("k"), []byte("v"))
+	}))
+
+	snap := db.Pebble().NewSnapshot()
+	defer func() {
+		require.NoError(t, snap.Close())
+	}()
+	wantErr := errors.New("corrupted")
+	tx := &pebbleTx{r: failingReader{Reader: snap, err: wantErr}, buckets: map[string]bool{"a": true}}
+	bkt := tx.Bucket([]byte("a"))
+	require.NotNil(t, bkt)
+	// A missing key is not an error.
+	assert.Equal(t, true, bkt.Get([]byte("missing")) == nil)
+	require.NoError(t, tx.err)
+	assert.Equal(t, true, bkt.Get([]byte("k")) == nil)
+	require.ErrorIs(t, tx.err, wantErr)
+}
+
+func TestCursor(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		require.NoError(t, db.Update(func(tx Tx) error {
+			bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+			require.NoError(t, err)
+			for _, k := range []string{"b", "d", "f"} {
+				require.NoError(t, bkt.Put([]byte(k), []byte("v"+k)))
+			}
+			_, err = tx.CreateBucketIfNotExists([]byte("b"))
+			return err
+		}))
+		require.NoError(t, db.View(func(tx Tx) error {
+			c := tx.Bucket([]byte("a")).Cursor()
+			k, v := c.First()
+			assert.DeepEqual(t, []byte("b"), k)
+			assert.DeepEqual(t, []byte("vb"), v)
+			k, _ = c.Next()
+			assert.DeepEqual(t, []byte("d"), k)
+			k, _ = c.Seek([]byte("c"))
+			assert.DeepEqual(t, []byte("d"), k)
+			k, _ = c.Seek([]byte("f"))
+			assert.DeepEqual(t, []byte("f"), k)
+			k, _ = c.Next()
+			assert.Equal(t, true, k == nil)
+			k, _ = c.Seek([]byte("g"))
+			assert.Equal(t, true, k == nil)
+			k, _ = c.Last()
+			assert.DeepEqual(t, []byte("f"), k)
+			k, _ = c.Prev()
+			assert.DeepEqual(t, []byte("d"), k)
+			k, _ = c.Prev()
+			assert.DeepEqual(t, []byte("b"), k)
+			k, _ = c.Prev()
+			assert.Equal(t, true, k == nil)
+
+			k, _ = tx.Bucket([]byte("b")).Cursor().First()
+			assert.Equal(t, true, k == nil)
+			return nil
+		}))
+		require.NoError(t, db.Update(func(tx Tx) error {
+			c := tx.Bucket([]byte("a")).Cursor()
+			for k, _ := c.First(); k != nil; k, _ = c.Next() {
+				if string(k) == "d" {
+					require.NoError(t, c.Delete())
+				}
+			}
+			return nil
+		}))
+		require.NoError(t, db.View(func(tx Tx) error {
+			var keys []string
+			require.NoError(t, tx.Bucket([]byte("a")).ForEach(func(k, _ []byte) error {
+				keys = append(keys, string(k))
+				return nil
+			}))
+			assert.DeepEqual(t, []string{"b", "f"}, keys)
+			return nil
+		}))
+	})
+}
+
+func TestCopy(t *testing.T) {
+	for _, from := range Backends {
+		for _, to := range Backends {
+			t.Run(fmt.Sprintf("%s to %s", from, to), func(t *testing.T) {
+				src, dst := openTestDB(t, from), openTestDB(t, to)
+				n := CopyBatchSize + 10
+				require.NoError(t, src.Update(func(tx Tx) error {
+					a, err := tx.CreateBucketIfNotExists([]byte("a"))
+					require.NoError(t, err)
+					for i := 0; i < n; i++ {
+						require.NoError(t, a.Put([]byte(fmt.Sprintf("%08d", i)), []byte{byte(i)}))
+					}
+					_, err = tx.CreateBucketIfNotExists([]byte("empty"))
+					return err
+				}))
+				var last int
+				require.NoError(t, Copy(context.Background(), src, dst, func(bucket []byte, keys int) {
+					if string(bucket) == "a" {
+						last = keys
+					}
+				}))
+				assert.Equal(t, n, last)
+				require.NoError(t, dst.View(func(tx Tx) error {
+					require.NotNil(t, tx.Bucket([]byte("empty")))
+					count := 0
+					require.NoError(t, tx.Bucket([]byte("a")).ForEach(func(k, v []byte) error {
+						assert.DeepEqual(t, []byte(fmt.Sprintf("%08d", count)), k)
+						assert.DeepEqual(t, []byte{byte(count)}, v)
+						count++
+						return nil
+					}))
+					assert.Equal(t, n, count)
+					return nil
+				}))
+			})
+		}
+	}
+}
+
+func TestPebbleDB_Checkpoint(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := OpenPebble(filepath.Join(dir, "db"), PebbleOptions{CacheSize: 1 << 20})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, writer.Close())
+	}()
+	put := func(v string) {
+		require.NoError(t, writer.Update(func(tx Tx) error {
+			bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+			require.NoError(t, err)
+			return bkt.Put([]byte("k"), []byte(v))
+		}))
+	}
+	put("v1")
+
+	// The database can not be opened by another reader while it is written to.
+	_, err = OpenPebble(filepath.Join(dir, "db"), PebbleOptions{CacheSize: 1 << 20, ReadOnly: true})
+	require.ErrorContains(t, "lock", err)
+
+	checkpoint := filepath.Join(dir, "checkpoint")
+	require.NoError(t, writer.Checkpoint(checkpoint))
+	require.NotNil(t, writer.Checkpoint(checkpoint), "a checkpoint should not overwrite an existing directory")
+	put("v2")
+
+	// The checkpoint keeps what was written before it, while the database is still in use.
+	reader, err := OpenPebble(checkpoint, PebbleOptions{CacheSize: 1 << 20, ReadOnly: true})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, reader.Close())
+	}()
+	require.NoError(t, reader.View(func(tx Tx) error {
+		assert.DeepEqual(t, []byte("v1"), tx.Bucket([]byte("a")).Get([]byte("k")))
+		return nil
+	}))
+}
@@ -0,0 +1,380 @@
+package engine
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+)
+
+// Keys of a bucket are stored behind a prefix made of the length of the bucket name and the name itself, so that
+// no bucket prefix is a prefix of another. Bucket names are recorded behind the zero byte, which can not start a
+// bucket prefix since names are never empty.
+const (
+	pebbleBucketsPrefix = 0
+	maxBucketNameLength = 255
+)
+
+var errInvalidBucketName = errors.New("bucket names must be between 1 and 255 bytes long")
+
+// PebbleOptions configures a Pebble database.
+type PebbleOptions struct {
+	// CacheSize is the size in bytes of the block cache.
+	CacheSize int64
+	// ReadOnly opens the database without allowing writes.
+	ReadOnly bool
+}
+
+// DefaultPebbleOptions are the options the beacon node database is opened with.
+var DefaultPebbleOptions = PebbleOptions{CacheSize: 256 << 20}
+
+// PebbleDB is a DB backed by a Pebble database. Read-write transactions are serialized, so that like with BoltDB
+// a transaction reading a key and writing it back can not race with another one.
+type PebbleDB struct {
+	db      *pebble.DB
+	path    string
+	writeMu sync.Mutex
+}
+
+// OpenPebble opens or creates the Pebble database in the directory.
+func OpenPebble(dir string, opts PebbleOptions) (*PebbleDB, error) {
+	cache := pebble.NewCache(opts.CacheSize)
+	defer cache.Unref()
+	db, err := pebble.Open(dir, &pebble.Options{
+		Cache:    cache,
+		Logger:   pebbleLogger{},
+		ReadOnly: opts.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleDB{db: db, path: dir}, nil
+}
+
+// Pebble returns the underlying Pebble database.
+func (p *PebbleDB) Pebble() *pebble.DB {
+	return p.db
+}
+
+// View runs the function against a snapshot of the database. It returns the first error a read ran into, if the
+// function itself succeeded.
+func (p *PebbleDB) View(fn func(Tx) error) error {
+	snap := p.db.NewSnapshot()
+	tx := &pebbleTx{r: snap}
+	defer func() {
+		tx.close()
+		if err := snap.Close(); err != nil {
+			log.WithError(err).Error("Could not close Pebble snapshot")
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.err
+}
+
+// Update runs the function against an indexed batch, which is committed if the function returns nil and no read
+// ran into an error.
+func (p *PebbleDB) Update(fn func(Tx) error) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	batch := p.db.NewIndexedBatch()
+	tx := &pebbleTx{r: batch, batch: batch}
+	defer func() {
+		tx.close()
+		if err := batch.Close(); err != nil {
+			log.WithError(err).Error("Could not close Pebble batch")
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if tx.err != nil {
+		return tx.err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// Backend returns Pebble.
+func (*PebbleDB) Backend() Backend {
+	return Pebble
+}
+
+// Path returns the directory of the Pebble database.
+func (p *PebbleDB) Path() string {
+	return p.path
+}
+
+// Checkpoint writes a consistent copy of the database to a new directory, which can be opened as a Pebble database
+// of its own. Immutable files are hard linked when the directory is on the same file system, so checkpoints are
+// cheap to write while the database is in use.
+func (p *PebbleDB) Checkpoint(dir string) error {
+	return p.db.Checkpoint(dir, pebble.WithFlushedWAL())
+}
+
+// Close closes the Pebble database.
+func (p *PebbleDB) Close() error {
+	return p.db.Close()
+}
+
+// pebbleTx reads from either a snapshot or an indexed batch, and writes to the batch if there is one. Reads made
+// through the Tx and Bucket interfaces can not return errors, so the first one is kept in err and returned when
+// the transaction ends.
+type pebbleTx struct {
+	r       pebble.Reader
+	batch   *pebble.Batch
+	iters   []*pebble.Iterator
+	buckets map[string]bool
+	err     error
+}
+
+func (t *pebbleTx) close() {
+	for _, it := range t.iters {
+		if err := it.Close(); err != nil {
+			log.WithError(err).Error("Could not close Pebble iterator")
+		}
+	}
+	t.iters = nil
+}
+
+// fail records the first error the transaction ran into.
+func (t *pebbleTx) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// get returns a copy of the value of the key, or nil if the key does not exist.
+func (t *pebbleTx) get(key []byte) ([]byte, error) {
+	v, closer, err := t.r.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read from Pebble")
+	}
+	defer func() {
+		if err := closer.Close(); err != nil {
+			log.WithError(err).Error("Could not release Pebble value")
+		}
+	}()
+	return append([]byte{}, v...), nil
+}
+
+func (t *pebbleTx) iter(lower, upper []byte) *pebble.Iterator {
+	it, err := t.r.NewIter(&pebble.IterOptions{LowerBound: lower, UpperBound: upper})
+	if err != nil {
+		t.fail(errors.Wrap(err, "could not create Pebble iterator"))
+		return nil
+	}
+	t.iters = append(t.iters, it)
+	return it
+}
+
+func (t *pebbleTx) Bucket(name []byte) Bucket {
+	if len(name) == 0 {
+		return nil
+	}
+	exists, ok := t.buckets[string(name)]
+	if !ok {
+		v, err := t.get(pebbleBucketKey(name))
+		if err != nil {
+			t.fail(err)
+			return nil
+		}
+		exists = v != nil
+		t.setBucket(name, exists)
+	}
+	if !exists {
+		return nil
+	}
+	return &pebbleBucket{tx: t, prefix: pebbleBucketPrefix(name)}
+}
+
+func (t *pebbleTx) setBucket(name []byte, exists bool) {
+	if t.buckets == nil {
+		t.buckets = make(map[string]bool)
+	}
+	t.buckets[string(name)] = exists
+}
+
+func (t *pebbleTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	if len(name) == 0 || len(name) > maxBucketNameLength {
+		return nil, errors.Wrapf(errInvalidBucketName, "%q", name)
+	}
+	if b := t.Bucket(name); b != nil {
+		return b, nil
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	if t.batch == nil {
+		return nil, ErrTxNotWritable
+	}
+	if err := t.batch.Set(pebbleBucketKey(name), []byte{}, nil); err != nil {
+		return nil, err
+	}
+	t.setBucket(name, true)
+	return &pebbleBucket{tx: t, prefix: pebbleBucketPrefix(name)}, nil
+}
+
+func (t *pebbleTx) DeleteBucket(name []byte) error {
+	if t.batch == nil {
+		return ErrTxNotWritable
+	}
+	if t.Bucket(name) == nil {
+		return ErrBucketNotFound
+	}
+	prefix := pebbleBucketPrefix(name)
+	if err := t.batch.DeleteRange(prefix, prefixEnd(prefix), nil); err != nil {
+		return err
+	}
+	if err := t.batch.Delete(pebbleBucketKey(name), nil); err != nil {
+		return err
+	}
+	t.setBucket(name, false)
+	return nil
+}
+
+func (t *pebbleTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	it := t.iter([]byte{pebbleBucketsPrefix}, []byte{pebbleBucketsPrefix + 1})
+	if it == nil {
+		return errors.New("could not iterate over buckets")
+	}
+	for ok := it.First(); ok; ok = it.Next() {
+		name := append([]byte{}, it.Key()[1:]...)
+		if err := fn(name, &pebbleBucket{tx: t, prefix: pebbleBucketPrefix(name)}); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+func (t *pebbleTx) Writable() bool {
+	return t.batch != nil
+}
+
+type pebbleBucket struct {
+	tx     *pebbleTx
+	prefix []byte
+}
+
+func (b *pebbleBucket) key(k []byte) []byte {
+	key := make([]byte, len(b.prefix)+len(k))
+	copy(key, b.prefix)
+	copy(key[len(b.prefix):], k)
+	return key
+}
+
+func (b *pebbleBucket) Get(key []byte) []byte {
+	v, err := b.tx.get(b.key(key))
+	if err != nil {
+		b.tx.fail(err)
+	}
+	return v
+}
+
+func (b *pebbleBucket) Put(key, value []byte) error {
+	if b.tx.batch == nil {
+		return ErrTxNotWritable
+	}
+	return b.tx.batch.Set(b.key(key), value, nil)
+}
+
+func (b *pebbleBucket) Delete(key []byte) error {
+	if b.tx.batch == nil {
+		return ErrTxNotWritable
+	}
+	return b.tx.batch.Delete(b.key(key), nil)
+}
+
+func (b *pebbleBucket) ForEach(fn func(k, v []byte) error) error {
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *pebbleBucket) Cursor() Cursor {
+	return &pebbleCursor{bkt: b, it: b.tx.iter(b.prefix, prefixEnd(b.prefix))}
+}
+
+// pebbleCursor returns copies of the keys and values, which like with BoltDB stay valid after the cursor moves.
+type pebbleCursor struct {
+	bkt *pebbleBucket
+	it  *pebble.Iterator
+}
+
+func (c *pebbleCursor) current(ok bool) ([]byte, []byte) {
+	if !ok {
+		return nil, nil
+	}
+	k := append([]byte{}, c.it.Key()[len(c.bkt.prefix):]...)
+	v := append([]byte{}, c.it.Value()...)
+	return k, v
+}
+
+func (c *pebbleCursor) First() ([]byte, []byte) {
+	return c.current(c.it != nil && c.it.First())
+}
+
+func (c *pebbleCursor) Last() ([]byte, []byte) {
+	return c.current(c.it != nil && c.it.Last())
+}
+
+func (c *pebbleCursor) Next() ([]byte, []byte) {
+	return c.current(c.it != nil && c.it.Next())
+}
+
+func (c *pebbleCursor) Prev() ([]byte, []byte) {
+	return c.current(c.it != nil && c.it.Prev())
+}
+
+func (c *pebbleCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.current(c.it != nil && c.it.SeekGE(c.bkt.key(seek)))
+}
+
+func (c *pebbleCursor) Delete() error {
+	if c.it == nil || !c.it.Valid() {
+		return errors.New("cursor is not at a key")
+	}
+	if c.bkt.tx.batch == nil {
+		return ErrTxNotWritable
+	}
+	return c.bkt.tx.batch.Delete(append([]byte{}, c.it.Key()...), nil)
+}
+
+func pebbleBucketKey(name []byte) []byte {
+	return append([]byte{pebbleBucketsPrefix}, name...)
+}
+
+func pebbleBucketPrefix(name []byte) []byte {
+	return append([]byte{byte(len(name))}, name...)
+}
+
+// prefixEnd returns the smallest key greater than every key starting with the prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// pebbleLogger forwards the logs of Pebble to logrus.
+type pebbleLogger struct{}
+
+func (pebbleLogger) Infof(format string, args ...interface{}) {
+	log.Debugf(format, args...)
+}
+
+func (pebbleLogger) Fatalf(format string, args ...interface{}) {
+	log.Fatalf(format, args...)
+}
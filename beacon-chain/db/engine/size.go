@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BucketSize is the space used by a bucket.
+type BucketSize struct {
+	Name []byte
+	Keys int
+	// Bytes is the size of the keys and values of the bucket, with the storage overhead of the backend if it is
+	// known.
+	Bytes int64
+	// AllocatedBytes is the space the backend reserves for the bucket, which is larger than Bytes when pages are
+	// partially used. It is an estimate for backends which compress data.
+	AllocatedBytes int64
+}
+
+// SizeReport is the space used by a database.
+type SizeReport struct {
+	Backend Backend
+	// DiskBytes is the size of the file or directory of the database.
+	DiskBytes int64
+	// FreePages is the number of pages which are not used by any bucket, and are reused by later writes. Only
+	// databases organized in pages report free pages.
+	FreePages int
+	// FreeBytes is the part of DiskBytes which does not hold live data, and which a compaction would reclaim.
+	FreeBytes int64
+	Buckets   []BucketSize
+}
+
+// Sizer is implemented by databases which can report the space they use.
+type Sizer interface {
+	Sizes() (*SizeReport, error)
+}
+
+// Sizes returns the space used by the database, if it can report it.
+func Sizes(db DB) (*SizeReport, error) {
+	s, ok := db.(Sizer)
+	if !ok {
+		return nil, errors.Errorf("database of the %s backend can not report its size", db.Backend())
+	}
+	return s.Sizes()
+}
+
+// Sizes reads the size of the buckets from the statistics of their pages, and the free pages from the freelist.
+func (b *BoltDB) Sizes() (*SizeReport, error) {
+	r := &SizeReport{Backend: Bolt}
+	if err := b.db.View(func(tx *bolt.Tx) error {
+		r.DiskBytes = tx.Size()
+		return tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+			s := bkt.Stats()
+			r.Buckets = append(r.Buckets, BucketSize{
+				Name:           append([]byte{}, name...),
+				Keys:           s.KeyN,
+				Bytes:          int64(s.BranchInuse + s.LeafInuse),
+				AllocatedBytes: int64(s.BranchAlloc + s.LeafAlloc),
+			})
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(b.db.Path()); err == nil {
+		r.DiskBytes = info.Size()
+	}
+	s := b.db.Stats()
+	r.FreePages = s.FreePageN + s.PendingPageN
+	r.FreeBytes = int64(s.FreeAlloc)
+	return r, nil
+}
+
+// Sizes counts the keys and values of every bucket, and reads the space reclaimable by a compaction from the
+// metrics of Pebble.
+func (p *PebbleDB) Sizes() (*SizeReport, error) {
+	r := &SizeReport{Backend: Pebble}
+	if err := p.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, bkt Bucket) error {
+			s := BucketSize{Name: name}
+			if err := bkt.ForEach(func(k, v []byte) error {
+				s.Keys++
+				s.Bytes += int64(len(k) + len(v))
+				return nil
+			}); err != nil {
+				return err
+			}
+			prefix := pebbleBucketPrefix(name)
+			allocated, err := p.db.EstimateDiskUsage(prefix, prefixEnd(prefix))
+			if err != nil {
+				return err
+			}
+			s.AllocatedBytes = int64(allocated)
+			r.Buckets = append(r.Buckets, s)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	m := p.db.Metrics()
+	r.DiskBytes = int64(m.DiskSpaceUsage())
+	r.FreeBytes = int64(m.Table.ObsoleteSize + m.Table.ZombieSize)
+	return r, nil
+}
@@ -0,0 +1,425 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// maxPause is how long new transactions are held back while Swap waits for the running ones to end.
+	maxPause = 100 * time.Millisecond
+	// swapPollInterval is how often Swap checks whether transactions are running while they are held back.
+	swapPollInterval = time.Millisecond
+	// swapRetryInterval is how long Swap waits before holding back transactions again, when some did not end in
+	// time.
+	swapRetryInterval = time.Second
+)
+
+// SwapDB is a DB whose underlying database can be replaced while it is in use. Writes can be tracked, so that a
+// copy of the database made while it is written to can be brought up to date before replacing it.
+type SwapDB struct {
+	mu      sync.RWMutex
+	db      DB
+	pauseMu sync.Mutex
+	pause   chan struct{}
+	trackMu sync.Mutex
+	changes *Changes
+}
+
+// NewSwapDB wraps the database.
+func NewSwapDB(db DB) *SwapDB {
+	return &SwapDB{db: db}
+}
+
+// Unwrap returns the underlying database.
+func (s *SwapDB) Unwrap() DB {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db
+}
+
+// View runs the function in a read only transaction of the underlying database.
+func (s *SwapDB) View(fn func(Tx) error) error {
+	s.waitPause()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.View(fn)
+}
+
+// Update runs the function in a read-write transaction of the underlying database. The keys it writes are
+// recorded once it commits if changes were tracked when it started.
+func (s *SwapDB) Update(fn func(Tx) error) error {
+	s.waitPause()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.tracking() {
+		return s.db.Update(fn)
+	}
+	var changes *Changes
+	if err := s.db.Update(func(tx Tx) error {
+		// The changes of a failed attempt are discarded.
+		changes = NewChanges()
+		return fn(&trackedTx{Tx: tx, changes: changes})
+	}); err != nil {
+		return err
+	}
+	s.trackMu.Lock()
+	defer s.trackMu.Unlock()
+	if s.changes != nil {
+		s.changes.merge(changes)
+	}
+	return nil
+}
+
+// Backend returns the backend of the underlying database.
+func (s *SwapDB) Backend() Backend {
+	return s.Unwrap().Backend()
+}
+
+// Path returns the path of the underlying database.
+func (s *SwapDB) Path() string {
+	return s.Unwrap().Path()
+}
+
+// Close closes the underlying database.
+func (s *SwapDB) Close() error {
+	return s.Unwrap().Close()
+}
+
+// Sizes returns the space used by the underlying database.
+func (s *SwapDB) Sizes() (*SizeReport, error) {
+	return Sizes(s.Unwrap())
+}
+
+// Track starts recording the keys written by read-write transactions. Like Swap, it waits until no transaction is
+// running, so that every transaction committed afterwards is recorded: a transaction which started before tracking
+// and committed after a copy of the database read its keys would otherwise be missing from the copy.
+func (s *SwapDB) Track(ctx context.Context) error {
+	return s.Swap(ctx, func(current DB) (DB, error) {
+		s.trackMu.Lock()
+		defer s.trackMu.Unlock()
+		s.changes = NewChanges()
+		return current, nil
+	})
+}
+
+// TakeChanges returns the keys written since tracking started or since the last call, and keeps tracking.
+func (s *SwapDB) TakeChanges() *Changes {
+	s.trackMu.Lock()
+	defer s.trackMu.Unlock()
+	changes := s.changes
+	if changes != nil {
+		s.changes = NewChanges()
+	}
+	return changes
+}
+
+// StopTracking stops recording written keys.
+func (s *SwapDB) StopTracking() {
+	s.trackMu.Lock()
+	defer s.trackMu.Unlock()
+	s.changes = nil
+}
+
+func (s *SwapDB) tracking() bool {
+	s.trackMu.Lock()
+	defer s.trackMu.Unlock()
+	return s.changes != nil
+}
+
+// Swap waits until no transaction is running, and then replaces the underlying database by the one returned by
+// the function, which is called with the current database. No transaction starts until the function returns. If
+// the function fails, the database it returns along with the error replaces the current one, or the current one is
+// kept if it returns none.
+//
+// Transactions of the database may run others, so Swap can not wait for running transactions while holding back
+// new ones for good. New transactions are instead held back for at most maxPause, and Swap tries again later if
+// the running ones did not end meanwhile, until the context is done.
+func (s *SwapDB) Swap(ctx context.Context, fn func(current DB) (DB, error)) error {
+	for !s.lockPaused() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(swapRetryInterval):
+		}
+	}
+	defer s.mu.Unlock()
+	db, err := fn(s.db)
+	if db != nil {
+		s.db = db
+	}
+	return err
+}
+
+// lockPaused holds back new transactions and takes the write lock once the running ones end, if they end in time.
+func (s *SwapDB) lockPaused() bool {
+	s.pauseMu.Lock()
+	s.pause = make(chan struct{})
+	s.pauseMu.Unlock()
+	defer func() {
+		s.pauseMu.Lock()
+		close(s.pause)
+		s.pause = nil
+		s.pauseMu.Unlock()
+	}()
+	deadline := time.Now().Add(maxPause)
+	for !s.mu.TryLock() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(swapPollInterval)
+	}
+	return true
+}
+
+// waitPause waits while Swap holds back new transactions.
+func (s *SwapDB) waitPause() {
+	s.pauseMu.Lock()
+	pause := s.pause
+	s.pauseMu.Unlock()
+	if pause == nil {
+		return
+	}
+	timer := time.NewTimer(maxPause)
+	defer timer.Stop()
+	select {
+	case <-pause:
+	case <-timer.C:
+	}
+}
+
+// Changes is a set of keys written to a database. Buckets which were created or deleted are recorded as a whole.
+type Changes struct {
+	keys    map[string]map[string]struct{}
+	buckets map[string]struct{}
+}
+
+// NewChanges returns an empty set of changes.
+func NewChanges() *Changes {
+	return &Changes{keys: make(map[string]map[string]struct{}), buckets: make(map[string]struct{})}
+}
+
+// Len returns the number of changed keys and buckets.
+func (c *Changes) Len() int {
+	n := len(c.buckets)
+	for _, keys := range c.keys {
+		n += len(keys)
+	}
+	return n
+}
+
+func (c *Changes) addKey(bucket, key []byte) {
+	keys, ok := c.keys[string(bucket)]
+	if !ok {
+		keys = make(map[string]struct{})
+		c.keys[string(bucket)] = keys
+	}
+	keys[string(key)] = struct{}{}
+}
+
+func (c *Changes) addBucket(bucket []byte) {
+	c.buckets[string(bucket)] = struct{}{}
+	delete(c.keys, string(bucket))
+}
+
+func (c *Changes) merge(other *Changes) {
+	for b := range other.buckets {
+		c.addBucket([]byte(b))
+	}
+	for b, keys := range other.keys {
+		if _, ok := c.buckets[b]; ok {
+			continue
+		}
+		for k := range keys {
+			c.addKey([]byte(b), []byte(k))
+		}
+	}
+}
+
+// ApplyChanges writes the current value of the changed keys of the source database into the destination
+// database, or deletes them from it when they do not exist anymore. Changed buckets are copied as a whole.
+func ApplyChanges(ctx context.Context, src, dst DB, changes *Changes) error {
+	for b := range changes.buckets {
+		if err := copyBucket(ctx, src, dst, []byte(b)); err != nil {
+			return err
+		}
+	}
+	for b, keys := range changes.keys {
+		name := []byte(b)
+		batch := make([][]byte, 0, CopyBatchSize)
+		for k := range keys {
+			batch = append(batch, []byte(k))
+			if len(batch) == CopyBatchSize {
+				if err := copyKeys(src, dst, name, batch); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		if err := copyKeys(src, dst, name, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyBucket replaces the bucket of the destination database by the one of the source database.
+func copyBucket(ctx context.Context, src, dst DB, name []byte) error {
+	exists := false
+	if err := src.View(func(tx Tx) error {
+		exists = tx.Bucket(name) != nil
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := dst.Update(func(tx Tx) error {
+		if tx.Bucket(name) != nil {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		if exists {
+			_, err := tx.CreateBucketIfNotExists(name)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	return copyBucketKeys(ctx, src, dst, name, nil)
+}
+
+func copyKeys(src, dst DB, name []byte, keys [][]byte) error {
+	values := make([][]byte, len(keys))
+	if err := src.View(func(tx Tx) error {
+		bkt := tx.Bucket(name)
+		if bkt == nil {
+			return nil
+		}
+		for i, k := range keys {
+			if v := bkt.Get(k); v != nil {
+				values[i] = append([]byte{}, v...)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return dst.Update(func(tx Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(name)
+		if err != nil {
+			return err
+		}
+		for i, k := range keys {
+			if values[i] == nil {
+				err = bkt.Delete(k)
+			} else {
+				err = bkt.Put(k, values[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// trackedTx records the keys written through it.
+type trackedTx struct {
+	Tx
+	changes *Changes
+}
+
+func (t *trackedTx) Bucket(name []byte) Bucket {
+	b := t.Tx.Bucket(name)
+	if b == nil {
+		return nil
+	}
+	return &trackedBucket{Bucket: b, name: append([]byte{}, name...), changes: t.changes}
+}
+
+func (t *trackedTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	exists := t.Tx.Bucket(name) != nil
+	b, err := t.Tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		t.changes.addBucket(name)
+	}
+	return &trackedBucket{Bucket: b, name: append([]byte{}, name...), changes: t.changes}, nil
+}
+
+func (t *trackedTx) DeleteBucket(name []byte) error {
+	if err := t.Tx.DeleteBucket(name); err != nil {
+		return err
+	}
+	t.changes.addBucket(name)
+	return nil
+}
+
+func (t *trackedTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return t.Tx.ForEach(func(name []byte, b Bucket) error {
+		return fn(name, &trackedBucket{Bucket: b, name: append([]byte{}, name...), changes: t.changes})
+	})
+}
+
+type trackedBucket struct {
+	Bucket
+	name    []byte
+	changes *Changes
+}
+
+func (b *trackedBucket) Put(key, value []byte) error {
+	if err := b.Bucket.Put(key, value); err != nil {
+		return err
+	}
+	b.changes.addKey(b.name, key)
+	return nil
+}
+
+func (b *trackedBucket) Delete(key []byte) error {
+	if err := b.Bucket.Delete(key); err != nil {
+		return err
+	}
+	b.changes.addKey(b.name, key)
+	return nil
+}
+
+func (b *trackedBucket) Cursor() Cursor {
+	return &trackedCursor{Cursor: b.Bucket.Cursor(), bkt: b}
+}
+
+type trackedCursor struct {
+	Cursor
+	bkt *trackedBucket
+	key []byte
+}
+
+func (c *trackedCursor) at(k, v []byte) ([]byte, []byte) {
+	c.key = append(c.key[:0], k...)
+	return k, v
+}
+
+func (c *trackedCursor) First() ([]byte, []byte) { return c.at(c.Cursor.First()) }
+func (c *trackedCursor) Last() ([]byte, []byte)  { return c.at(c.Cursor.Last()) }
+func (c *trackedCursor) Next() ([]byte, []byte)  { return c.at(c.Cursor.Next()) }
+func (c *trackedCursor) Prev() ([]byte, []byte)  { return c.at(c.Cursor.Prev()) }
+func (c *trackedCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.at(c.Cursor.Seek(seek))
+}
+
+func (c *trackedCursor) Delete() error {
+	if err := c.Cursor.Delete(); err != nil {
+		return err
+	}
+	c.bkt.changes.addKey(c.bkt.name, c.key)
+	return nil
+}
@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func fill(t *testing.T, db DB, bucket string, n int) {
+	require.NoError(t, db.Update(func(tx Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		require.NoError(t, err)
+		for i := 0; i < n; i++ {
+			require.NoError(t, bkt.Put([]byte(fmt.Sprintf("%08d", i)), []byte(bucket)))
+		}
+		return nil
+	}))
+}
+
+func contents(t *testing.T, db DB) map[string]map[string]string {
+	c := make(map[string]map[string]string)
+	require.NoError(t, db.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, b Bucket) error {
+			kvs := make(map[string]string)
+			c[string(name)] = kvs
+			return b.ForEach(func(k, v []byte) error {
+				kvs[string(k)] = string(v)
+				return nil
+			})
+		})
+	}))
+	return c
+}
+
+func TestSwapDB_ApplyChanges(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		swap := NewSwapDB(db)
+		fill(t, swap, "a", 10)
+		fill(t, swap, "b", 10)
+		fill(t, swap, "c", 10)
+		require.NoError(t, swap.Track(ctx))
+		dst := openTestDB(t, db.Backend())
+		require.NoError(t, Copy(ctx, swap, dst, nil))
+
+		require.NoError(t, swap.Update(func(tx Tx) error {
+			a := tx.Bucket([]byte("a"))
+			require.NoError(t, a.Put([]byte("new"), []byte("v")))
+			require.NoError(t, a.Put([]byte("00000001"), []byte("changed")))
+			require.NoError(t, a.Delete([]byte("00000002")))
+			c := a.Cursor()
+			for k, _ := c.First(); k != nil; k, _ = c.Next() {
+				if string(k) == "00000003" {
+					require.NoError(t, c.Delete())
+				}
+			}
+			require.NoError(t, tx.DeleteBucket([]byte("b")))
+			d, err := tx.CreateBucketIfNotExists([]byte("d"))
+			require.NoError(t, err)
+			return d.Put([]byte("k"), []byte("v"))
+		}))
+		// Rolled back writes are not recorded.
+		require.ErrorContains(t, "rollback", swap.Update(func(tx Tx) error {
+			require.NoError(t, tx.Bucket([]byte("c")).Put([]byte("rolled back"), []byte("v")))
+			return fmt.Errorf("rollback")
+		}))
+
+		changes := swap.TakeChanges()
+		assert.Equal(t, 6, changes.Len())
+		assert.Equal(t, 0, swap.TakeChanges().Len())
+		require.NoError(t, ApplyChanges(ctx, swap, dst, changes))
+		assert.DeepEqual(t, contents(t, swap), contents(t, dst))
+
+		swap.StopTracking()
+		fill(t, swap, "e", 1)
+		assert.Equal(t, true, swap.TakeChanges() == nil)
+	})
+}
+
+func TestSwapDB_Track_WaitsForUpdates(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		swap := NewSwapDB(db)
+		fill(t, swap, "a", 1)
+
+		// An update which started before tracking and commits after the copy read its bucket is in the copy.
+		started, release := make(chan struct{}), make(chan struct{})
+		updated := make(chan error)
+		go func() {
+			updated <- swap.Update(func(tx Tx) error {
+				close(started)
+				<-release
+				return tx.Bucket([]byte("a")).Put([]byte("k"), []byte("v"))
+			})
+		}()
+		<-started
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, swap.Track(timeoutCtx), context.DeadlineExceeded)
+		tracked := make(chan error)
+		go func() {
+			tracked <- swap.Track(ctx)
+		}()
+		close(release)
+		require.NoError(t, <-updated)
+		require.NoError(t, <-tracked)
+
+		dst := openTestDB(t, db.Backend())
+		require.NoError(t, Copy(ctx, swap, dst, nil))
+		require.NoError(t, ApplyChanges(ctx, swap, dst, swap.TakeChanges()))
+		assert.DeepEqual(t, contents(t, swap), contents(t, dst))
+	})
+}
+
+func TestSwapDB_Swap(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		swap := NewSwapDB(db)
+		fill(t, swap, "a", 1)
+		other := openTestDB(t, db.Backend())
+		fill(t, other, "b", 1)
+
+		// Swap waits for running transactions.
+		started, release := make(chan struct{}), make(chan struct{})
+		go func() {
+			assert.NoError(t, swap.View(func(tx Tx) error {
+				close(started)
+				<-release
+				return nil
+			}))
+		}()
+		<-started
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, swap.Swap(ctx, func(current DB) (DB, error) {
+			return other, nil
+		}), context.DeadlineExceeded)
+		close(release)
+
+		require.ErrorContains(t, "failed", swap.Swap(context.Background(), func(current DB) (DB, error) {
+			return nil, fmt.Errorf("failed")
+		}))
+		assert.Equal(t, db, swap.Unwrap())
+		require.NoError(t, swap.Swap(context.Background(), func(current DB) (DB, error) {
+			assert.Equal(t, db, current)
+			return other, nil
+		}))
+		assert.Equal(t, other, swap.Unwrap())
+
+		// A database returned along with an error replaces the current one.
+		require.ErrorContains(t, "failed", swap.Swap(context.Background(), func(current DB) (DB, error) {
+			return db, fmt.Errorf("failed")
+		}))
+		assert.Equal(t, db, swap.Unwrap())
+		require.NoError(t, swap.Swap(context.Background(), func(current DB) (DB, error) {
+			return other, nil
+		}))
+		require.NoError(t, swap.View(func(tx Tx) error {
+			assert.Equal(t, nil, tx.Bucket([]byte("a")))
+			require.NotNil(t, tx.Bucket([]byte("b")))
+			return nil
+		}))
+	})
+}
+
+func TestSizes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		fill(t, db, "a", 100)
+		fill(t, db, "b", 10)
+		r, err := Sizes(NewSwapDB(db))
+		require.NoError(t, err)
+		assert.Equal(t, db.Backend(), r.Backend)
+		assert.Equal(t, true, r.DiskBytes > 0)
+		require.Equal(t, 2, len(r.Buckets))
+		assert.DeepEqual(t, []byte("a"), r.Buckets[0].Name)
+		assert.Equal(t, 100, r.Buckets[0].Keys)
+		assert.Equal(t, 10, r.Buckets[1].Keys)
+		assert.Equal(t, true, r.Buckets[0].Bytes >= 100*(8+1))
+	})
+}
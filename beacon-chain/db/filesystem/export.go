@@ -0,0 +1,128 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+)
+
+var errInvalidBlobName = errors.New("invalid blob file name, want 0x<root>/<index>.ssz")
+
+// PausePruning holds back pruning until the returned function is called. Pruning is held back from before a
+// database snapshot is taken until its blobs are exported, so that the blobs of the blocks of the snapshot are not
+// deleted in between.
+func (bs *BlobStorage) PausePruning() func() {
+	if bs.pruner == nil {
+		return func() {}
+	}
+	bs.pruner.Lock()
+	return bs.pruner.Unlock
+}
+
+// Export calls fn with the name, size and content of every blob sidecar file in storage, where the name is the
+// path of the file relative to the base directory. Pruning should be held back with PausePruning while blobs are
+// exported.
+func (bs *BlobStorage) Export(ctx context.Context, fn func(name string, size int64, r io.Reader) error) error {
+	entries, err := listDir(bs.fs, ".")
+	if err != nil {
+		return errors.Wrap(err, "unable to list root blobs directory")
+	}
+	for _, dir := range filter(entries, filterRoot) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		files, err := listDir(bs.fs, dir)
+		if err != nil {
+			return errors.Wrapf(err, "unable to list blobs directory %s", dir)
+		}
+		for _, fname := range filter(files, filterSsz) {
+			if err := bs.exportFile(path.Join(dir, fname), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (bs *BlobStorage) exportFile(name string, fn func(name string, size int64, r io.Reader) error) error {
+	f, err := bs.fs.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Errorf("Could not close blob file")
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return fn(name, info.Size(), f)
+}
+
+// Import writes a blob sidecar file exported by Export to storage. The file is written to a partial file first,
+// which is renamed once complete, like Save does.
+func (bs *BlobStorage) Import(name string, r io.Reader) error {
+	root, idx, err := parseBlobName(name)
+	if err != nil {
+		return err
+	}
+	namer := blobNamer{root: root, index: idx}
+	if err := bs.fs.MkdirAll(namer.dir(), directoryPermissions); err != nil {
+		return err
+	}
+	partPath := namer.partPath("import")
+	partialFile, err := bs.fs.Create(partPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create partial file")
+	}
+	n, err := io.Copy(partialFile, r)
+	if err != nil {
+		if closeErr := partialFile.Close(); closeErr != nil {
+			return closeErr
+		}
+		return errors.Wrap(err, "failed to write to partial file")
+	}
+	if bs.fsync {
+		if err := partialFile.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := partialFile.Close(); err != nil {
+		return err
+	}
+	if n == 0 {
+		return errEmptyBlobWritten
+	}
+	return errors.Wrap(bs.fs.Rename(partPath, namer.path()), "failed to rename partial file to final name")
+}
+
+// parseBlobName returns the block root and blob index of a blob file name relative to the base directory.
+func parseBlobName(name string) ([32]byte, uint64, error) {
+	dir, fname := path.Split(path.Clean(name))
+	dir = strings.TrimSuffix(dir, "/")
+	if !filterRoot(dir) || strings.Contains(dir, "/") {
+		return [32]byte{}, 0, errors.Wrap(errInvalidBlobName, name)
+	}
+	root, err := rootFromDir(dir)
+	if err != nil {
+		return [32]byte{}, 0, errors.Wrap(errInvalidBlobName, name)
+	}
+	idx, err := idxFromPath(fname)
+	if err != nil {
+		return [32]byte{}, 0, errors.Wrap(errInvalidBlobName, name)
+	}
+	if idx >= fieldparams.MaxBlobsPerBlock {
+		return [32]byte{}, 0, errors.Wrap(errIndexOutOfBounds, name)
+	}
+	if rootString(root) != dir || fmt.Sprintf("%d%s", idx, dotSszExt) != fname {
+		return [32]byte{}, 0, errors.Wrap(errInvalidBlobName, name)
+	}
+	return root, idx, nil
+}
@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestBlobStorage_ExportImport(t *testing.T) {
+	_, sidecars := util.GenerateTestDenebBlockWithSidecar(t, [32]byte{}, 1, 2)
+	testSidecars, err := verification.BlobSidecarSliceNoop(sidecars)
+	require.NoError(t, err)
+	src := NewEphemeralBlobStorage(t)
+	for _, sc := range testSidecars {
+		require.NoError(t, src.Save(sc))
+	}
+
+	exported := make(map[string][]byte)
+	require.NoError(t, src.Export(context.Background(), func(name string, size int64, r io.Reader) error {
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, size, int64(len(content)))
+		exported[name] = content
+		return nil
+	}))
+	require.Equal(t, len(testSidecars), len(exported))
+
+	dst := NewEphemeralBlobStorage(t)
+	for name, content := range exported {
+		require.NoError(t, dst.Import(name, bytes.NewReader(content)))
+	}
+	root := testSidecars[0].BlockRoot()
+	indices, err := dst.Indices(root)
+	require.NoError(t, err)
+	require.DeepEqual(t, [fieldparams.MaxBlobsPerBlock]bool{true, true}, indices)
+	for _, sc := range testSidecars {
+		got, err := dst.Get(root, sc.Index)
+		require.NoError(t, err)
+		require.DeepEqual(t, sc.BlobSidecar, got.BlobSidecar)
+	}
+}
+
+func TestBlobStorage_PausePruning(t *testing.T) {
+	bs, err := NewBlobStorage(WithBasePath(t.TempDir()))
+	require.NoError(t, err)
+	resume := bs.PausePruning()
+	require.Equal(t, false, bs.pruner.TryLock())
+	resume()
+	require.Equal(t, true, bs.pruner.TryLock())
+	bs.pruner.Unlock()
+
+	// Storage without a pruner has nothing to hold back.
+	resume = (&BlobStorage{}).PausePruning()
+	resume()
+}
+
+func TestBlobStorage_ImportInvalidName(t *testing.T) {
+	bs := NewEphemeralBlobStorage(t)
+	root := rootString([32]byte{'a'})
+	for _, name := range []string{
+		"",
+		"0.ssz",
+		root,
+		root + "/0.part",
+		root + "/x.ssz",
+		root + "/../0.ssz",
+		"../" + root + "/0.ssz",
+		"0xab/0.ssz",
+	} {
+		require.ErrorIs(t, bs.Import(name, bytes.NewReader([]byte{1})), errInvalidBlobName, name)
+	}
+	require.ErrorIs(t, bs.Import(root+"/6.ssz", bytes.NewReader([]byte{1})), errIndexOutOfBounds)
+	require.NoError(t, bs.Import(root+"/5.ssz", bytes.NewReader([]byte{1})))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
 	slashertypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/slasher/types"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/statediff"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
@@ -43,6 +44,10 @@ type ReadOnlyDatabase interface {
 	StateSummary(ctx context.Context, blockRoot [32]byte) (*ethpb.StateSummary, error)
 	HasStateSummary(ctx context.Context, blockRoot [32]byte) bool
 	HighestSlotStatesBelow(ctx context.Context, slot primitives.Slot) ([]state.ReadOnlyBeaconState, error)
+	StateDiffHierarchy() *statediff.Hierarchy
+	StateDiff(ctx context.Context, slot primitives.Slot) (state.BeaconState, error)
+	HasStateDiff(ctx context.Context, slot primitives.Slot) bool
+	HighestStateDiffSlot(ctx context.Context, slot primitives.Slot) (primitives.Slot, bool, error)
 	// Checkpoint operations.
 	JustifiedCheckpoint(ctx context.Context) (*ethpb.Checkpoint, error)
 	FinalizedCheckpoint(ctx context.Context) (*ethpb.Checkpoint, error)
@@ -65,6 +70,8 @@ type ReadOnlyDatabase interface {
 	// origin checkpoint sync support
 	OriginCheckpointBlockRoot(ctx context.Context) ([32]byte, error)
 	BackfillStatus(context.Context) (*dbval.BackfillStatus, error)
+	// History pruning support.
+	EarliestAvailableSlot(ctx context.Context) (primitives.Slot, error)
 }
 
 // NoHeadAccessDatabase defines a struct without access to chain head data.
@@ -84,6 +91,7 @@ type NoHeadAccessDatabase interface {
 	DeleteStates(ctx context.Context, blockRoots [][32]byte) error
 	SaveStateSummary(ctx context.Context, summary *ethpb.StateSummary) error
 	SaveStateSummaries(ctx context.Context, summaries []*ethpb.StateSummary) error
+	SaveStateDiff(ctx context.Context, state state.ReadOnlyBeaconState) error
 	// Checkpoint operations.
 	SaveJustifiedCheckpoint(ctx context.Context, checkpoint *ethpb.Checkpoint) error
 	SaveFinalizedCheckpoint(ctx context.Context, checkpoint *ethpb.Checkpoint) error
@@ -101,6 +109,7 @@ type NoHeadAccessDatabase interface {
 	SaveLightClientUpdate(ctx context.Context, period uint64, update *ethpbv2.LightClientUpdateWithVersion) error
 
 	CleanUpDirtyStates(ctx context.Context, slotsPerArchivedPoint primitives.Slot) error
+	PruneHistory(ctx context.Context, beforeSlot primitives.Slot) (int, error)
 }
 
 // HeadAccessDatabase defines a struct with access to reading chain head data.
@@ -179,4 +188,12 @@ type Database interface {
 
 	DatabasePath() string
 	ClearDB() error
+	Snapshot(ctx context.Context, path string) (*SnapshotInfo, error)
+}
+
+// SnapshotInfo describes the chain as recorded in a database snapshot.
+type SnapshotInfo struct {
+	HeadSlot  primitives.Slot
+	HeadRoot  [32]byte
+	Finalized *ethpb.Checkpoint
 }
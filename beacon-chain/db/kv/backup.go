@@ -3,16 +3,23 @@ package kv
 import (
 	"context"
 	"fmt"
+	"os"
 	"path"
 
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/iface"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	"github.com/prysmaticlabs/prysm/v5/io/file"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	bolt "go.etcd.io/bbolt"
 )
 
-const backupsDirectoryName = "backups"
+// BackupsDirName is the directory of the database directory which backups are written to by default.
+const BackupsDirName = "backups"
 
 // Backup the database to the datadir backup directory.
 // Example for backup at slot 345: $DATADIR/backups/prysm_beacondb_at_slot_0000345.backup
@@ -28,7 +35,7 @@ func (s *Store) Backup(ctx context.Context, outputDir string, permissionOverride
 			return err
 		}
 	} else {
-		backupsDir = path.Join(s.databasePath, backupsDirectoryName)
+		backupsDir = path.Join(s.databasePath, BackupsDirName)
 	}
 	head, err := s.HeadBlock(ctx)
 	if err != nil {
@@ -43,76 +50,97 @@ func (s *Store) Backup(ctx context.Context, outputDir string, permissionOverride
 	}
 	backupPath := path.Join(backupsDir, fmt.Sprintf("prysm_beacondb_at_slot_%07d.backup", head.Block().Slot()))
 	log.WithField("backup", backupPath).Info("Writing backup database.")
+	_, err = s.Snapshot(ctx, backupPath)
+	return err
+}
+
+// Snapshot writes a copy of the database, as of a single point in time, to a BoltDB file at the given path,
+// replacing any file there. Snapshots are always written as a single BoltDB file, whatever the backend of the
+// database. The database stays in use while it is copied: the keys written meanwhile are copied again, and the
+// last ones are copied while holding back transactions. It returns the head and finalized checkpoint the snapshot
+// records.
+func (s *Store) Snapshot(ctx context.Context, snapshotPath string) (*iface.SnapshotInfo, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Snapshot")
+	defer span.End()
+
+	if !s.copying.CompareAndSwap(false, true) {
+		return nil, errCopyRunning
+	}
+	defer s.copying.Store(false)
 
-	copyDB, err := bolt.Open(
-		backupPath,
+	if err := os.Remove(snapshotPath); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "could not remove previous snapshot")
+	}
+	copyDB, err := engine.OpenBolt(
+		snapshotPath,
 		params.BeaconIoConfig().ReadWritePermissions,
 		&bolt.Options{NoSync: true, Timeout: params.BeaconIoConfig().BoltTimeout, FreelistType: bolt.FreelistMapType},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	copyDB.AllocSize = boltAllocSize
+	copyDB.Bolt().AllocSize = boltAllocSize
 
 	defer func() {
 		if err := copyDB.Close(); err != nil {
-			log.WithError(err).Error("Failed to close backup database")
+			log.WithError(err).Error("Failed to close snapshot database")
 		}
 	}()
-	// Prefetch all keys of buckets, and inner keys in a
-	// bucket to use less memory usage when backing up.
-	var bucketKeys [][]byte
-	bucketMap := make(map[string][][]byte)
-	err = s.db.View(func(tx *bolt.Tx) error {
-		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
-			newName := make([]byte, len(name))
-			copy(newName, name)
-			bucketKeys = append(bucketKeys, newName)
-			var innerKeys [][]byte
-			err := b.ForEach(func(k, v []byte) error {
-				if k == nil {
-					return nil
-				}
-				nKey := make([]byte, len(k))
-				copy(nKey, k)
-				innerKeys = append(innerKeys, nKey)
-				return nil
-			})
-			if err != nil {
-				return err
-			}
-			bucketMap[string(newName)] = innerKeys
-			return nil
-		})
-	})
-	if err != nil {
-		return err
+	if err := s.db.Track(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not track changes during snapshot")
 	}
-	// Utilize much smaller writes, compared to
-	// writing for a whole bucket in a single transaction. Also
-	// prevent long-running read transactions, as Bolt doesn't
-	// handle those well.
-	for _, k := range bucketKeys {
-		log.Debugf("Copying bucket %s\n", k)
-		innerKeys := bucketMap[string(k)]
-		for _, ik := range innerKeys {
-			err = s.db.View(func(tx *bolt.Tx) error {
-				bkt := tx.Bucket(k)
-				return copyDB.Update(func(tx2 *bolt.Tx) error {
-					b2, err := tx2.CreateBucketIfNotExists(k)
-					if err != nil {
-						return err
-					}
-					return b2.Put(ik, bkt.Get(ik))
-				})
-			})
-			if err != nil {
-				return err
-			}
+	defer s.db.StopTracking()
+	// Copy utilizes much smaller writes, compared to writing a whole
+	// bucket in a single transaction, and prevents long-running read
+	// transactions.
+	if err := engine.Copy(ctx, s.db, copyDB, func(bucket []byte, keys int) {
+		log.Debugf("Copied %d keys of bucket %s", keys, bucket)
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.catchUp(ctx, copyDB); err != nil {
+		return nil, errors.Wrap(err, "could not copy keys written during snapshot")
+	}
+	if err := s.db.Swap(ctx, func(current engine.DB) (engine.DB, error) {
+		if err := engine.ApplyChanges(ctx, current, copyDB, s.db.TakeChanges()); err != nil {
+			return nil, errors.Wrap(err, "could not copy keys written during snapshot")
 		}
+		return current, nil
+	}); err != nil {
+		return nil, err
 	}
 	// Re-enable sync to allow bolt to fsync
 	// again.
-	copyDB.NoSync = false
-	return nil
+	copyDB.Bolt().NoSync = false
+	if err := copyDB.Bolt().Sync(); err != nil {
+		return nil, err
+	}
+	return snapshotInfo(ctx, copyDB)
+}
+
+func snapshotInfo(ctx context.Context, db engine.DB) (*iface.SnapshotInfo, error) {
+	info := &iface.SnapshotInfo{Finalized: &ethpb.Checkpoint{Root: params.BeaconConfig().ZeroHash[:]}}
+	err := db.View(func(tx engine.Tx) error {
+		if enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey); enc != nil {
+			if err := decode(ctx, enc, info.Finalized); err != nil {
+				return err
+			}
+		}
+		bkt := tx.Bucket(blocksBucket)
+		headRoot := bkt.Get(headBlockRootKey)
+		if headRoot == nil {
+			return nil
+		}
+		enc := bkt.Get(headRoot)
+		if enc == nil {
+			return nil
+		}
+		head, err := unmarshalBlock(ctx, enc)
+		if err != nil {
+			return err
+		}
+		info.HeadSlot, info.HeadRoot = head.Block().Slot(), bytesutil.ToBytes32(headRoot)
+		return nil
+	})
+	return info, err
 }
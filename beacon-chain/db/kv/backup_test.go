@@ -6,105 +6,186 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"github.com/prysmaticlabs/prysm/v5/testing/util"
 )
 
 func TestStore_Backup(t *testing.T) {
-	db, err := NewKVStore(context.Background(), t.TempDir())
-	require.NoError(t, err, "Failed to instantiate DB")
-	ctx := context.Background()
-
-	head := util.NewBeaconBlock()
-	head.Block.Slot = 5000
-
-	wsb, err := blocks.NewSignedBeaconBlock(head)
-	require.NoError(t, err)
-	require.NoError(t, db.SaveBlock(ctx, wsb))
-	root, err := head.Block.HashTreeRoot()
-	require.NoError(t, err)
-	st, err := util.NewBeaconState()
-	require.NoError(t, err)
-	require.NoError(t, db.SaveState(ctx, st, root))
-	require.NoError(t, db.SaveHeadBlockRoot(ctx, root))
-
-	require.NoError(t, db.Backup(ctx, "", false))
-
-	backupsPath := filepath.Join(db.databasePath, backupsDirectoryName)
-	files, err := os.ReadDir(backupsPath)
-	require.NoError(t, err)
-	require.NotEqual(t, 0, len(files), "No backups created")
-	require.NoError(t, db.Close(), "Failed to close database")
-
-	oldFilePath := filepath.Join(backupsPath, files[0].Name())
-	newFilePath := filepath.Join(backupsPath, DatabaseFileName)
-	// We rename the file to match the database file name
-	// our NewKVStore function expects when opening a database.
-	require.NoError(t, os.Rename(oldFilePath, newFilePath))
-
-	backedDB, err := NewKVStore(ctx, backupsPath)
-	require.NoError(t, err, "Failed to instantiate DB")
-	t.Cleanup(func() {
-		require.NoError(t, backedDB.Close(), "Failed to close database")
-	})
-	require.Equal(t, true, backedDB.HasState(ctx, root))
-}
-
-func TestStore_BackupMultipleBuckets(t *testing.T) {
-	db, err := NewKVStore(context.Background(), t.TempDir())
-	require.NoError(t, err, "Failed to instantiate DB")
-	ctx := context.Background()
-
-	startSlot := primitives.Slot(5000)
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		db, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(backend))
+		require.NoError(t, err, "Failed to instantiate DB")
+		ctx := context.Background()
 
-	for i := startSlot; i < 5200; i++ {
 		head := util.NewBeaconBlock()
-		head.Block.Slot = i
+		head.Block.Slot = 5000
+
 		wsb, err := blocks.NewSignedBeaconBlock(head)
 		require.NoError(t, err)
 		require.NoError(t, db.SaveBlock(ctx, wsb))
 		root, err := head.Block.HashTreeRoot()
 		require.NoError(t, err)
 		st, err := util.NewBeaconState()
-		require.NoError(t, st.SetSlot(i))
 		require.NoError(t, err)
 		require.NoError(t, db.SaveState(ctx, st, root))
 		require.NoError(t, db.SaveHeadBlockRoot(ctx, root))
-	}
-
-	require.NoError(t, db.Backup(ctx, "", false))
-
-	backupsPath := filepath.Join(db.databasePath, backupsDirectoryName)
-	files, err := os.ReadDir(backupsPath)
-	require.NoError(t, err)
-	require.NotEqual(t, 0, len(files), "No backups created")
-	require.NoError(t, db.Close(), "Failed to close database")
-
-	oldFilePath := filepath.Join(backupsPath, files[0].Name())
-	newFilePath := filepath.Join(backupsPath, DatabaseFileName)
-	// We rename the file to match the database file name
-	// our NewKVStore function expects when opening a database.
-	require.NoError(t, os.Rename(oldFilePath, newFilePath))
-
-	backedDB, err := NewKVStore(ctx, backupsPath)
-	require.NoError(t, err, "Failed to instantiate DB")
-	t.Cleanup(func() {
-		require.NoError(t, backedDB.Close(), "Failed to close database")
+
+		require.NoError(t, db.Backup(ctx, "", false))
+
+		backupsPath := filepath.Join(db.databasePath, BackupsDirName)
+		files, err := os.ReadDir(backupsPath)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(files), "No backups created")
+		require.NoError(t, db.Close(), "Failed to close database")
+
+		oldFilePath := filepath.Join(backupsPath, files[0].Name())
+		newFilePath := filepath.Join(backupsPath, DatabaseFileName)
+		// We rename the file to match the database file name
+		// our NewKVStore function expects when opening a database.
+		require.NoError(t, os.Rename(oldFilePath, newFilePath))
+
+		backedDB, err := NewKVStore(ctx, backupsPath)
+		require.NoError(t, err, "Failed to instantiate DB")
+		t.Cleanup(func() {
+			require.NoError(t, backedDB.Close(), "Failed to close database")
+		})
+		require.Equal(t, true, backedDB.HasState(ctx, root))
 	})
-	for i := startSlot; i < 5200; i++ {
-		head := util.NewBeaconBlock()
-		head.Block.Slot = i
-		root, err := head.Block.HashTreeRoot()
+}
+
+func TestStore_BackupMultipleBuckets(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		db, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(backend))
+		require.NoError(t, err, "Failed to instantiate DB")
+		ctx := context.Background()
+
+		startSlot := primitives.Slot(5000)
+
+		for i := startSlot; i < 5200; i++ {
+			head := util.NewBeaconBlock()
+			head.Block.Slot = i
+			wsb, err := blocks.NewSignedBeaconBlock(head)
+			require.NoError(t, err)
+			require.NoError(t, db.SaveBlock(ctx, wsb))
+			root, err := head.Block.HashTreeRoot()
+			require.NoError(t, err)
+			st, err := util.NewBeaconState()
+			require.NoError(t, st.SetSlot(i))
+			require.NoError(t, err)
+			require.NoError(t, db.SaveState(ctx, st, root))
+			require.NoError(t, db.SaveHeadBlockRoot(ctx, root))
+		}
+
+		require.NoError(t, db.Backup(ctx, "", false))
+
+		backupsPath := filepath.Join(db.databasePath, BackupsDirName)
+		files, err := os.ReadDir(backupsPath)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(files), "No backups created")
+		require.NoError(t, db.Close(), "Failed to close database")
+
+		oldFilePath := filepath.Join(backupsPath, files[0].Name())
+		newFilePath := filepath.Join(backupsPath, DatabaseFileName)
+		// We rename the file to match the database file name
+		// our NewKVStore function expects when opening a database.
+		require.NoError(t, os.Rename(oldFilePath, newFilePath))
+
+		backedDB, err := NewKVStore(ctx, backupsPath)
+		require.NoError(t, err, "Failed to instantiate DB")
+		t.Cleanup(func() {
+			require.NoError(t, backedDB.Close(), "Failed to close database")
+		})
+		for i := startSlot; i < 5200; i++ {
+			head := util.NewBeaconBlock()
+			head.Block.Slot = i
+			root, err := head.Block.HashTreeRoot()
+			require.NoError(t, err)
+			nBlock, err := backedDB.Block(ctx, root)
+			require.NoError(t, err)
+			require.NotNil(t, nBlock)
+			require.Equal(t, nBlock.Block().Slot(), i)
+			nState, err := backedDB.State(ctx, root)
+			require.NoError(t, err)
+			require.NotNil(t, nState)
+			require.Equal(t, nState.Slot(), i)
+		}
+	})
+}
+
+func TestStore_Snapshot(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db, err := NewKVStore(ctx, t.TempDir(), WithBackend(backend))
+		require.NoError(t, err, "Failed to instantiate DB")
+		genesis := util.NewBeaconBlock()
+		wsb, err := blocks.NewSignedBeaconBlock(genesis)
 		require.NoError(t, err)
-		nBlock, err := backedDB.Block(ctx, root)
+		require.NoError(t, db.SaveBlock(ctx, wsb))
+		genesisRoot, err := genesis.Block.HashTreeRoot()
 		require.NoError(t, err)
-		require.NotNil(t, nBlock)
-		require.Equal(t, nBlock.Block().Slot(), i)
-		nState, err := backedDB.State(ctx, root)
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, genesisRoot))
+		require.NoError(t, db.SaveStateSummary(ctx, &ethpb.StateSummary{Root: genesisRoot[:]}))
+		cp := &ethpb.Checkpoint{Epoch: 1, Root: genesisRoot[:]}
+		require.NoError(t, db.SaveFinalizedCheckpoint(ctx, cp))
+
+		// Blocks keep being saved while the snapshot is written.
+		saved := make(chan error, 1)
+		go func() {
+			for i := primitives.Slot(1); i <= 200; i++ {
+				b := util.NewBeaconBlock()
+				b.Block.Slot = i
+				blk, err := blocks.NewSignedBeaconBlock(b)
+				if err != nil {
+					saved <- err
+					return
+				}
+				root, err := b.Block.HashTreeRoot()
+				if err != nil {
+					saved <- err
+					return
+				}
+				if err := db.SaveBlock(ctx, blk); err != nil {
+					saved <- err
+					return
+				}
+				if err := db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: i, Root: root[:]}); err != nil {
+					saved <- err
+					return
+				}
+				if err := db.SaveHeadBlockRoot(ctx, root); err != nil {
+					saved <- err
+					return
+				}
+			}
+			saved <- nil
+		}()
+		snapshotDir := t.TempDir()
+		info, err := db.Snapshot(ctx, filepath.Join(snapshotDir, DatabaseFileName))
 		require.NoError(t, err)
-		require.NotNil(t, nState)
-		require.Equal(t, nState.Slot(), i)
-	}
+		require.NoError(t, <-saved)
+		require.NoError(t, db.Close(), "Failed to close database")
+
+		copied, err := NewKVStore(ctx, snapshotDir)
+		require.NoError(t, err, "Failed to instantiate DB")
+		t.Cleanup(func() {
+			require.NoError(t, copied.Close(), "Failed to close database")
+		})
+		assert.DeepEqual(t, cp, info.Finalized)
+		// The snapshot holds the blocks up to its head, all of them in the slot index, and at most the next block,
+		// which was saved before becoming the head.
+		blks, roots, err := copied.Blocks(ctx, filters.NewFilter().SetStartSlot(0).SetEndSlot(200))
+		require.NoError(t, err)
+		require.Equal(t, true, len(blks) == int(info.HeadSlot)+1 || len(blks) == int(info.HeadSlot)+2)
+		for i, b := range blks {
+			require.Equal(t, primitives.Slot(i), b.Block().Slot())
+		}
+		if info.HeadSlot > 0 {
+			assert.Equal(t, info.HeadRoot, roots[info.HeadSlot])
+		}
+	})
 }
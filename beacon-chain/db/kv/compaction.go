@@ -0,0 +1,326 @@
+package kv
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// compactionSuffix is appended to the path of the database for the copy made by a compaction.
+	compactionSuffix = ".compact"
+	// replacedSuffix is appended to the path of a database stored in a directory while the copy replaces it.
+	replacedSuffix = ".old"
+	// maxCatchUpRounds is the number of times the keys written during a compaction or snapshot are copied before
+	// the remaining ones are copied while holding back transactions.
+	maxCatchUpRounds = 10
+	// compactionLogInterval is how often the progress of a compaction is logged.
+	compactionLogInterval = 10 * time.Second
+	// compactionCheckInterval is how often RunCompaction checks the free space of the database.
+	compactionCheckInterval = time.Hour
+)
+
+var (
+	// openCompactedEngine opens the database after a compaction, and is replaced by tests.
+	openCompactedEngine = openEngine
+
+	errCopyRunning = errors.New("database compaction or snapshot already running")
+
+	compactionInProgress = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_compaction_in_progress",
+		Help: "Whether the beacon node database is being compacted.",
+	})
+	compactionCopiedKeys = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_compaction_copied_keys",
+		Help: "The number of keys copied by the running database compaction.",
+	})
+	compactionTotalKeys = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_compaction_total_keys",
+		Help: "The number of keys the running database compaction copies.",
+	})
+	compactionReclaimedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_compaction_reclaimed_bytes",
+		Help: "The disk space reclaimed by the last database compaction.",
+	})
+	compactionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_compactions_total",
+		Help: "The number of database compactions, by result.",
+	}, []string{"result"})
+)
+
+// Sizes returns the space used by the database and each of its buckets.
+func (s *Store) Sizes() (*engine.SizeReport, error) {
+	return s.db.Sizes()
+}
+
+// Compact copies the live data of the database into a new database next to it, and replaces the database with the
+// copy, reclaiming the space left by deleted data. The database stays in use while it is copied: the keys written
+// meanwhile are copied again, and the databases are swapped once no transaction is running.
+func (s *Store) Compact(ctx context.Context) (err error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Compact")
+	defer span.End()
+
+	if !s.copying.CompareAndSwap(false, true) {
+		return errCopyRunning
+	}
+	defer s.copying.Store(false)
+	compactionInProgress.Set(1)
+	defer func() {
+		compactionInProgress.Set(0)
+		compactionCopiedKeys.Set(0)
+		compactionTotalKeys.Set(0)
+		if err != nil {
+			compactionsTotal.WithLabelValues("failure").Inc()
+		} else {
+			compactionsTotal.WithLabelValues("success").Inc()
+		}
+	}()
+
+	before, err := s.Sizes()
+	if err != nil {
+		return err
+	}
+	totalKeys := 0
+	for _, b := range before.Buckets {
+		totalKeys += b.Keys
+	}
+	compactionTotalKeys.Set(float64(totalKeys))
+
+	datapath := StoreDataPath(s.databasePath, s.backend)
+	copyPath := datapath + compactionSuffix
+	if err := os.RemoveAll(copyPath); err != nil {
+		return errors.Wrap(err, "could not remove previous compaction copy")
+	}
+	dst, err := openEngine(copyPath, s.backend, false)
+	if err != nil {
+		return errors.Wrap(err, "could not create compaction copy")
+	}
+	swapped := false
+	defer func() {
+		if swapped {
+			return
+		}
+		if err := dst.Close(); err != nil {
+			log.WithError(err).Error("Could not close compaction copy")
+		}
+		if err := os.RemoveAll(copyPath); err != nil {
+			log.WithError(err).Error("Could not remove compaction copy")
+		}
+	}()
+	// The copy is synced once complete, as it is discarded if the node stops before the swap.
+	if boltDB, ok := dst.(*engine.BoltDB); ok {
+		boltDB.Bolt().NoSync = true
+	}
+
+	log.WithFields(logrus.Fields{
+		"path":      datapath,
+		"diskBytes": before.DiskBytes,
+		"freeBytes": before.FreeBytes,
+		"keys":      totalKeys,
+	}).Info("Compacting database")
+	if err := s.db.Track(ctx); err != nil {
+		return errors.Wrap(err, "could not track changes during compaction")
+	}
+	defer s.db.StopTracking()
+	copied, bucketCopied, lastBucket := 0, 0, ""
+	lastLog := time.Now()
+	if err := engine.Copy(ctx, s.db, dst, func(bucket []byte, keys int) {
+		if string(bucket) != lastBucket {
+			lastBucket, bucketCopied = string(bucket), 0
+		}
+		copied += keys - bucketCopied
+		bucketCopied = keys
+		compactionCopiedKeys.Set(float64(copied))
+		if time.Since(lastLog) < compactionLogInterval {
+			return
+		}
+		lastLog = time.Now()
+		log.WithFields(logrus.Fields{
+			"bucket":  lastBucket,
+			"copied":  copied,
+			"percent": percent(copied, totalKeys),
+		}).Info("Compacting database")
+	}); err != nil {
+		return errors.Wrap(err, "could not copy database")
+	}
+
+	if err := s.catchUp(ctx, dst); err != nil {
+		return errors.Wrap(err, "could not copy keys written during compaction")
+	}
+
+	var swapErr error
+	if err := s.db.Swap(ctx, func(current engine.DB) (engine.DB, error) {
+		if err := engine.ApplyChanges(ctx, current, dst, s.db.TakeChanges()); err != nil {
+			return nil, errors.Wrap(err, "could not copy keys written during compaction")
+		}
+		if boltDB, ok := dst.(*engine.BoltDB); ok {
+			boltDB.Bolt().NoSync = false
+			if err := boltDB.Bolt().Sync(); err != nil {
+				return nil, err
+			}
+		}
+		if err := dst.Close(); err != nil {
+			return nil, err
+		}
+		swapped = true
+		if err := current.Close(); err != nil {
+			return nil, err
+		}
+		// The original database is reopened if the copy could not replace it, so that the store is not left with
+		// the closed database.
+		var db engine.DB
+		var err error
+		if swapErr = replaceDataPath(copyPath, datapath); swapErr != nil {
+			db, err = openCompactedEngine(datapath, s.backend, false)
+			if err != nil {
+				err = errors.Wrap(err, "could not reopen database after compaction")
+			}
+		} else {
+			db, err = reopenAfterCompaction(copyPath, datapath, s.backend)
+		}
+		if db != nil {
+			if err := s.registerCollector(db); err != nil {
+				log.WithError(err).Error("Could not register database collector")
+			}
+		}
+		return db, err
+	}); err != nil {
+		return err
+	}
+	if swapErr != nil {
+		return errors.Wrap(swapErr, "could not replace database with compaction copy")
+	}
+
+	after, err := s.Sizes()
+	if err != nil {
+		return err
+	}
+	compactionReclaimedBytes.Set(float64(before.DiskBytes - after.DiskBytes))
+	log.WithFields(logrus.Fields{
+		"path":           datapath,
+		"diskBytes":      after.DiskBytes,
+		"reclaimedBytes": before.DiskBytes - after.DiskBytes,
+	}).Info("Compacted database")
+	return nil
+}
+
+// catchUp copies the keys written to the database since it started tracking changes to the destination. Keys keep
+// being written while they are copied, so they are copied until few are left.
+func (s *Store) catchUp(ctx context.Context, dst engine.DB) error {
+	for i := 0; i < maxCatchUpRounds; i++ {
+		changes := s.db.TakeChanges()
+		if err := engine.ApplyChanges(ctx, s.db, dst, changes); err != nil {
+			return err
+		}
+		if changes.Len() < engine.CopyBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// RunCompaction compacts the database in the background whenever the share of its disk space which does not hold
+// live data reaches the given ratio, until the context is done.
+func (s *Store) RunCompaction(ctx context.Context, minFreeRatio float64) {
+	ticker := time.NewTicker(compactionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r, err := s.Sizes()
+			if err != nil {
+				log.WithError(err).Error("Could not read database size")
+				continue
+			}
+			if r.DiskBytes == 0 || float64(r.FreeBytes)/float64(r.DiskBytes) < minFreeRatio {
+				continue
+			}
+			if err := s.Compact(ctx); err != nil {
+				log.WithError(err).Error("Could not compact database")
+			}
+		}
+	}
+}
+
+// replaceDataPath moves the compaction copy to the path of the database. The database is first moved aside, which
+// recoverCompaction undoes if the node stops in between, and kept until the copy is opened. It is moved back if the
+// copy can not be moved.
+func replaceDataPath(copyPath, datapath string) error {
+	replaced := datapath + replacedSuffix
+	if err := os.Rename(datapath, replaced); err != nil {
+		return err
+	}
+	if err := os.Rename(copyPath, datapath); err != nil {
+		if err := os.Rename(replaced, datapath); err != nil {
+			log.WithError(err).Error("Could not restore database moved aside by compaction")
+		}
+		return err
+	}
+	return nil
+}
+
+// reopenAfterCompaction opens the database once the copy replaced it, and removes the original database. If the
+// copy can not be opened, it is moved back and the original database is opened instead, and returned along with the
+// error.
+func reopenAfterCompaction(copyPath, datapath string, backend engine.Backend) (engine.DB, error) {
+	replaced := datapath + replacedSuffix
+	db, err := openCompactedEngine(datapath, backend, false)
+	if err == nil {
+		if err := os.RemoveAll(replaced); err != nil {
+			log.WithError(err).Error("Could not remove database replaced by compaction copy")
+		}
+		return db, nil
+	}
+	err = errors.Wrap(err, "could not open compaction copy")
+	log.WithError(err).Error("Restoring database replaced by compaction copy")
+	if err := os.Rename(datapath, copyPath); err != nil {
+		return nil, errors.Wrap(err, "could not move compaction copy back")
+	}
+	if err := os.Rename(replaced, datapath); err != nil {
+		return nil, errors.Wrap(err, "could not restore database replaced by compaction copy")
+	}
+	db, openErr := openCompactedEngine(datapath, backend, false)
+	if openErr != nil {
+		return nil, errors.Wrap(openErr, "could not reopen database after compaction")
+	}
+	return db, err
+}
+
+// recoverCompaction cleans up after a compaction interrupted by the node stopping, keeping the copy only if it had
+// replaced the database.
+func recoverCompaction(dirPath string) error {
+	for _, b := range engine.Backends {
+		datapath := StoreDataPath(dirPath, b)
+		replaced := datapath + replacedSuffix
+		if _, err := os.Stat(replaced); err == nil {
+			if _, err := os.Stat(datapath); os.IsNotExist(err) {
+				log.WithField("path", datapath).Warn("Restoring database moved aside by an interrupted compaction")
+				if err := os.Rename(replaced, datapath); err != nil {
+					return err
+				}
+			}
+		}
+		for _, p := range []string{replaced, datapath + compactionSuffix} {
+			if err := os.RemoveAll(p); err != nil {
+				return errors.Wrap(err, "could not remove leftovers of an interrupted compaction")
+			}
+		}
+	}
+	return nil
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(n*10000/total) / 100
+}
@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestStore_Compact(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupDB(t, backend)
+		value := make([]byte, 4096)
+		want := make(map[string]string)
+		require.NoError(t, db.db.Update(func(tx engine.Tx) error {
+			bkt := tx.Bucket(blocksBucket)
+			for i := 0; i < 2000; i++ {
+				k := fmt.Sprintf("%08d", i)
+				require.NoError(t, bkt.Put([]byte(k), value))
+				want[k] = string(value)
+			}
+			return nil
+		}))
+		require.NoError(t, db.db.Update(func(tx engine.Tx) error {
+			bkt := tx.Bucket(blocksBucket)
+			for i := 0; i < 2000; i++ {
+				if i%10 != 0 {
+					k := fmt.Sprintf("%08d", i)
+					require.NoError(t, bkt.Delete([]byte(k)))
+					delete(want, k)
+				}
+			}
+			return nil
+		}))
+		before, err := db.Sizes()
+		require.NoError(t, err)
+
+		// The database keeps being written to by concurrent transactions while it is compacted.
+		const writers = 4
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		var wantMu sync.Mutex
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; ; i += writers {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					k := fmt.Sprintf("%08d", i%2000)
+					assert.NoError(t, db.db.Update(func(tx engine.Tx) error {
+						bkt := tx.Bucket(blocksBucket)
+						wantMu.Lock()
+						defer wantMu.Unlock()
+						if i%3 == 0 {
+							delete(want, k)
+							return bkt.Delete([]byte(k))
+						}
+						want[k] = fmt.Sprintf("written during compaction %d", i)
+						return bkt.Put([]byte(k), []byte(want[k]))
+					}))
+				}
+			}(w)
+		}
+		require.NoError(t, db.Compact(ctx))
+		close(done)
+		wg.Wait()
+		require.ErrorIs(t, db.Compact(canceledContext()), context.Canceled)
+
+		got := make(map[string]string)
+		require.NoError(t, db.db.View(func(tx engine.Tx) error {
+			return tx.Bucket(blocksBucket).ForEach(func(k, v []byte) error {
+				got[string(k)] = string(v)
+				return nil
+			})
+		}))
+		assert.DeepEqual(t, want, got)
+		after, err := db.Sizes()
+		require.NoError(t, err)
+		if backend == engine.Bolt {
+			assert.Equal(t, true, after.DiskBytes < before.DiskBytes, "compaction did not shrink %d bytes", before.DiskBytes)
+		}
+		_, err = os.Stat(StoreDataPath(db.databasePath, backend) + compactionSuffix)
+		assert.Equal(t, true, os.IsNotExist(err))
+
+		// The database is usable after the swap.
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, [32]byte{'A'}))
+	})
+}
+
+func TestStore_Compact_OpenFailure(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupDB(t, backend)
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, [32]byte{'A'}))
+		opened := 0
+		openCompactedEngine = func(datapath string, backend engine.Backend, readOnly bool) (engine.DB, error) {
+			opened++
+			if opened == 1 {
+				return nil, errors.New("injected open failure")
+			}
+			return openEngine(datapath, backend, readOnly)
+		}
+		defer func() {
+			openCompactedEngine = openEngine
+		}()
+
+		// The copy can not be opened, so the original database is reopened.
+		require.ErrorContains(t, "injected open failure", db.Compact(ctx))
+		root, err := db.GenesisBlockRoot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, [32]byte{'A'}, root)
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, [32]byte{'B'}))
+		datapath := StoreDataPath(db.databasePath, backend)
+		_, err = os.Stat(datapath + replacedSuffix)
+		assert.Equal(t, true, os.IsNotExist(err))
+
+		require.NoError(t, db.Compact(ctx))
+		root, err = db.GenesisBlockRoot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, [32]byte{'B'}, root)
+		_, err = os.Stat(datapath + compactionSuffix)
+		assert.Equal(t, true, os.IsNotExist(err))
+	})
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestRecoverCompaction(t *testing.T) {
+	dir := t.TempDir()
+	datapath := StoreDataPath(dir, engine.Pebble)
+	// The node stopped after moving the database aside.
+	require.NoError(t, os.MkdirAll(datapath+replacedSuffix, 0700))
+	require.NoError(t, os.MkdirAll(datapath+compactionSuffix, 0700))
+	require.NoError(t, recoverCompaction(dir))
+	_, err := os.Stat(datapath)
+	require.NoError(t, err)
+	for _, p := range []string{datapath + replacedSuffix, datapath + compactionSuffix} {
+		_, err = os.Stat(p)
+		assert.Equal(t, true, os.IsNotExist(err))
+	}
+
+	// The node stopped after replacing the database.
+	require.NoError(t, os.MkdirAll(datapath+replacedSuffix, 0700))
+	require.NoError(t, os.WriteFile(StoreDataPath(dir, engine.Bolt)+compactionSuffix, nil, 0600))
+	require.NoError(t, recoverCompaction(dir))
+	_, err = os.Stat(datapath)
+	require.NoError(t, err)
+	_, err = os.Stat(datapath + replacedSuffix)
+	assert.Equal(t, true, os.IsNotExist(err))
+	_, err = os.Stat(StoreDataPath(dir, engine.Bolt) + compactionSuffix)
+	assert.Equal(t, true, os.IsNotExist(err))
+}
@@ -4,32 +4,35 @@ import (
 	"context"
 	"testing"
 
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 	v2 "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 )
 
 func TestStore_SavePowchainData(t *testing.T) {
-	type args struct {
-		data *v2.ETH1ChainData
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
-		{
-			name: "nil data",
-			args: args{
-				data: nil,
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		type args struct {
+			data *v2.ETH1ChainData
+		}
+		tests := []struct {
+			name    string
+			args    args
+			wantErr bool
+		}{
+			{
+				name: "nil data",
+				args: args{
+					data: nil,
+				},
+				wantErr: true,
 			},
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			store := setupDB(t)
-			if err := store.SaveExecutionChainData(context.Background(), tt.args.data); (err != nil) != tt.wantErr {
-				t.Errorf("SaveExecutionChainData() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				store := setupDB(t, backend)
+				if err := store.SaveExecutionChainData(context.Background(), tt.args.data); (err != nil) != tt.wantErr {
+					t.Errorf("SaveExecutionChainData() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
 }
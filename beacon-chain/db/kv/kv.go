@@ -1,5 +1,6 @@
-// Package kv defines a bolt-db, key-value store implementation
-// of the Database interface defined by a Prysm beacon node.
+// Package kv defines a key-value store implementation of the
+// Database interface defined by a Prysm beacon node, on top of
+// one of the storage engines of the engine package.
 package kv
 
 import (
@@ -7,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
@@ -14,7 +17,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	prombolt "github.com/prysmaticlabs/prombbolt"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/statediff"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
@@ -34,6 +39,8 @@ const (
 	BeaconNodeDbDirName = "beaconchaindata"
 	// DatabaseFileName is the name of the beacon node database.
 	DatabaseFileName = "beaconchain.db"
+	// PebbleDirName is the name of the directory of the beacon node database when it uses the Pebble backend.
+	PebbleDirName = "beaconchain.pebble"
 
 	boltAllocSize = 8 * 1024 * 1024
 	// The size of hash length in bytes
@@ -64,6 +71,10 @@ var (
 		Name: "db_beacon_state_saving_milliseconds",
 		Help: "Milliseconds it takes to save a beacon state to the DB",
 	})
+	stateDiffReadingTime = promauto.NewSummary(prometheus.SummaryOpts{
+		Name: "db_beacon_state_diff_reading_milliseconds",
+		Help: "Milliseconds it takes to rebuild a beacon state from its state diffs in the DB",
+	})
 )
 
 // BlockCacheSize specifies 1000 slots worth of blocks cached, which
@@ -82,13 +93,23 @@ var blockedBuckets = [][]byte{
 }
 
 // Store defines an implementation of the Prysm Database interface
-// using BoltDB as the underlying persistent kv-store for Ethereum Beacon Nodes.
+// using BoltDB or Pebble as the underlying persistent kv-store for Ethereum Beacon Nodes.
 type Store struct {
-	db                  *bolt.DB
+	db                  *engine.SwapDB
+	backend             engine.Backend
+	collector           prometheus.Collector
 	databasePath        string
 	blockCache          *ristretto.Cache
 	validatorEntryCache *ristretto.Cache
 	stateSummaryCache   *stateSummaryCache
+	stateDiffExponents  []uint8
+	stateDiffHierarchy  *statediff.Hierarchy
+	stateDiffBases      *stateDiffBases
+	copying             atomic.Bool
+	readOnly            bool
+	replica             bool
+	replicaCheckpoint   string
+	replicaClone        string
 	ctx                 context.Context
 }
 
@@ -99,6 +120,82 @@ func StoreDatafilePath(dirPath string) string {
 	return path.Join(dirPath, DatabaseFileName)
 }
 
+// StoreDataPath returns the file or directory the database of the backend is stored in.
+func StoreDataPath(dirPath string, backend engine.Backend) string {
+	if backend == engine.Pebble {
+		return path.Join(dirPath, PebbleDirName)
+	}
+	return StoreDatafilePath(dirPath)
+}
+
+// DetectBackend returns the backend of the database stored in the directory, and false if there is none.
+func DetectBackend(dirPath string) (engine.Backend, bool, error) {
+	var found []engine.Backend
+	for _, b := range engine.Backends {
+		ok, err := file.Exists(StoreDataPath(dirPath, b), file.Regular)
+		if b == engine.Pebble {
+			ok, err = file.HasDir(StoreDataPath(dirPath, b))
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			found = append(found, b)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return found[0], true, nil
+	default:
+		return "", false, fmt.Errorf("found a database for each of the backends %v in %s", found, dirPath)
+	}
+}
+
+// OpenEngine opens or creates the database of the backend in the directory.
+func OpenEngine(dirPath string, backend engine.Backend, readOnly bool) (engine.DB, error) {
+	return openEngine(StoreDataPath(dirPath, backend), backend, readOnly)
+}
+
+func openEngine(datapath string, backend engine.Backend, readOnly bool) (engine.DB, error) {
+	switch backend {
+	case engine.Bolt:
+		log.WithField("path", datapath).Info("Opening Bolt DB")
+		boltDB, err := engine.OpenBolt(
+			datapath,
+			params.BeaconIoConfig().ReadWritePermissions,
+			&bolt.Options{
+				Timeout:         1 * time.Second,
+				InitialMmapSize: mmapSize,
+				ReadOnly:        readOnly,
+			},
+		)
+		if err != nil {
+			if errors.Is(err, bolt.ErrTimeout) {
+				return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+			}
+			return nil, err
+		}
+		boltDB.Bolt().AllocSize = boltAllocSize
+		return boltDB, nil
+	case engine.Pebble:
+		log.WithField("path", datapath).Info("Opening Pebble DB")
+		opts := engine.DefaultPebbleOptions
+		opts.ReadOnly = readOnly
+		pebbleDB, err := engine.OpenPebble(datapath, opts)
+		if err != nil {
+			if strings.Contains(err.Error(), "lock") {
+				return nil, errors.Wrap(err, "cannot obtain database lock, database may be in use by another process")
+			}
+			return nil, err
+		}
+		return pebbleDB, nil
+	default:
+		return nil, errors.Wrapf(engine.ErrUnknownBackend, "%q", backend)
+	}
+}
+
 var Buckets = [][]byte{
 	blocksBucket,
 	stateBucket,
@@ -108,6 +205,7 @@ var Buckets = [][]byte{
 	stateSummaryBucket,
 	stateValidatorsBucket,
 	lightClientUpdatesBucket,
+	stateDiffBucket,
 	// Indices buckets.
 	blockSlotIndicesBucket,
 	stateSlotIndicesBucket,
@@ -124,6 +222,14 @@ var Buckets = [][]byte{
 // KVStoreOption is a functional option that modifies a kv.Store.
 type KVStoreOption func(*Store)
 
+// WithBackend sets the storage engine of the database. It is only used when creating a new database, opening an
+// existing database with another backend fails.
+func WithBackend(backend engine.Backend) KVStoreOption {
+	return func(s *Store) {
+		s.backend = backend
+	}
+}
+
 // NewKVStore initializes a new boltDB key-value store at the directory
 // path specified, creates the kv-buckets based on the schema, and stores
 // an open connection db object as a property of the Store struct.
@@ -137,23 +243,6 @@ func NewKVStore(ctx context.Context, dirPath string, opts ...KVStoreOption) (*St
 			return nil, err
 		}
 	}
-	datafile := StoreDatafilePath(dirPath)
-	log.WithField("path", datafile).Info("Opening Bolt DB")
-	boltDB, err := bolt.Open(
-		datafile,
-		params.BeaconIoConfig().ReadWritePermissions,
-		&bolt.Options{
-			Timeout:         1 * time.Second,
-			InitialMmapSize: mmapSize,
-		},
-	)
-	if err != nil {
-		if errors.Is(err, bolt.ErrTimeout) {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
-		}
-		return nil, err
-	}
-	boltDB.AllocSize = boltAllocSize
 	blockCache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1000,           // number of keys to track frequency of (1000).
 		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
@@ -173,7 +262,6 @@ func NewKVStore(ctx context.Context, dirPath string, opts ...KVStoreOption) (*St
 	}
 
 	kv := &Store{
-		db:                  boltDB,
 		databasePath:        dirPath,
 		blockCache:          blockCache,
 		validatorEntryCache: validatorCache,
@@ -183,18 +271,57 @@ func NewKVStore(ctx context.Context, dirPath string, opts ...KVStoreOption) (*St
 	for _, o := range opts {
 		o(kv)
 	}
-	if err := kv.db.Update(func(tx *bolt.Tx) error {
+	if kv.readOnly {
+		if err := openReadOnlyStore(kv, dirPath); err != nil {
+			return nil, err
+		}
+		if err := kv.setupStateDiffHierarchy(); err != nil {
+			if closeErr := kv.Close(); closeErr != nil {
+				log.WithError(closeErr).Error("Could not close database")
+			}
+			return nil, err
+		}
+		return kv, nil
+	}
+	existing, ok, err := DetectBackend(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if kv.backend != "" && kv.backend != existing {
+			return nil, fmt.Errorf("database in %s uses the %s backend, not %s", dirPath, existing, kv.backend)
+		}
+		kv.backend = existing
+	}
+	if kv.backend == "" {
+		kv.backend = engine.Bolt
+	}
+	if err := recoverCompaction(dirPath); err != nil {
+		return nil, err
+	}
+	db, err := OpenEngine(dirPath, kv.backend, false)
+	if err != nil {
+		return nil, err
+	}
+	kv.db = engine.NewSwapDB(db)
+	if err := kv.db.Update(func(tx engine.Tx) error {
 		return createBuckets(tx, Buckets...)
 	}); err != nil {
 		return nil, err
 	}
-	if err = prometheus.Register(createBoltCollector(kv.db)); err != nil {
+	if err := kv.registerCollector(db); err != nil {
 		return nil, err
 	}
 	// Setup the type of block storage used depending on whether or not this is a fresh database.
 	if err := kv.setupBlockStorageType(ctx); err != nil {
 		return nil, err
 	}
+	if err := kv.setupStateDiffHierarchy(); err != nil {
+		if closeErr := kv.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close database")
+		}
+		return nil, err
+	}
 
 	return kv, nil
 }
@@ -207,23 +334,41 @@ func (s *Store) ClearDB() error {
 	if _, err := os.Stat(s.databasePath); os.IsNotExist(err) {
 		return nil
 	}
-	prometheus.Unregister(createBoltCollector(s.db))
-	if err := os.Remove(path.Join(s.databasePath, DatabaseFileName)); err != nil {
+	if err := os.RemoveAll(StoreDataPath(s.databasePath, s.backend)); err != nil {
 		return errors.Wrap(err, "could not remove database file")
 	}
 	return nil
 }
 
-// Close closes the underlying BoltDB database.
+// Close closes the underlying database.
 func (s *Store) Close() error {
-	prometheus.Unregister(createBoltCollector(s.db))
+	if s.collector != nil {
+		prometheus.Unregister(s.collector)
+	}
 
 	// Before DB closes, we should dump the cached state summary objects to DB.
-	if err := s.saveCachedStateSummariesDB(s.ctx); err != nil {
+	if !s.readOnly {
+		if err := s.saveCachedStateSummariesDB(s.ctx); err != nil {
+			return err
+		}
+	}
+	// The caches run goroutines which keep them in memory until they are closed.
+	s.blockCache.Close()
+	s.validatorEntryCache.Close()
+
+	if err := s.db.Close(); err != nil {
 		return err
 	}
+	// The links of the replica checkpoint are only needed while it is open.
+	if s.replicaClone != "" {
+		return os.RemoveAll(s.replicaClone)
+	}
+	return nil
+}
 
-	return s.db.Close()
+// Backend returns the storage engine of the database.
+func (s *Store) Backend() engine.Backend {
+	return s.backend
 }
 
 // DatabasePath at which this database writes files.
@@ -243,7 +388,7 @@ func (s *Store) setupBlockStorageType(ctx context.Context) error {
 	saveFull := features.Get().SaveFullExecutionPayloads
 
 	var saveBlinded bool
-	if err := s.db.Update(func(tx *bolt.Tx) error {
+	if err := s.db.Update(func(tx engine.Tx) error {
 		// If we have a key stating we wish to save blinded beacon blocks, then we set saveBlinded to true.
 		metadataBkt := tx.Bucket(chainMetadataBucket)
 		keyExists := len(metadataBkt.Get(saveBlindedBeaconBlocksKey)) > 0
@@ -287,7 +432,7 @@ func (s *Store) setupBlockStorageType(ctx context.Context) error {
 	return nil
 }
 
-func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
+func createBuckets(tx engine.Tx, buckets ...[]byte) error {
 	for _, bucket := range buckets {
 		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
 			return err
@@ -296,6 +441,20 @@ func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
 	return nil
 }
 
+// registerCollector registers the prometheus collector of the database, if its backend has one.
+func (s *Store) registerCollector(db engine.DB) error {
+	if s.collector != nil {
+		prometheus.Unregister(s.collector)
+		s.collector = nil
+	}
+	boltDB, ok := db.(*engine.BoltDB)
+	if !ok {
+		return nil
+	}
+	s.collector = createBoltCollector(boltDB.Bolt())
+	return prometheus.Register(s.collector)
+}
+
 // createBoltCollector returns a prometheus collector specifically configured for boltdb.
 func createBoltCollector(db *bolt.DB) prometheus.Collector {
 	return prombolt.New("boltDB", db, blockedBuckets...)
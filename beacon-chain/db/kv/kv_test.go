@@ -6,17 +6,17 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"github.com/prysmaticlabs/prysm/v5/testing/util"
-	bolt "go.etcd.io/bbolt"
 )
 
-// setupDB instantiates and returns a Store instance.
-func setupDB(t testing.TB) *Store {
-	db, err := NewKVStore(context.Background(), t.TempDir())
+// setupDB instantiates and returns a Store instance using the given backend.
+func setupDB(t testing.TB, backend engine.Backend) *Store {
+	db, err := NewKVStore(context.Background(), t.TempDir(), WithBackend(backend))
 	require.NoError(t, err, "Failed to instantiate DB")
 	t.Cleanup(func() {
 		require.NoError(t, db.Close(), "Failed to close database")
@@ -24,192 +24,253 @@ func setupDB(t testing.TB) *Store {
 	return db
 }
 
+// forEachBackend runs the test as a subtest for every backend.
+func forEachBackend(t *testing.T, fn func(t *testing.T, backend engine.Backend)) {
+	for _, backend := range engine.Backends {
+		t.Run(string(backend), func(t *testing.T) {
+			fn(t, backend)
+		})
+	}
+}
+
+// forEachBackendBenchmark runs the benchmark as a sub-benchmark for every backend.
+func forEachBackendBenchmark(b *testing.B, fn func(b *testing.B, backend engine.Backend)) {
+	for _, backend := range engine.Backends {
+		b.Run(string(backend), func(b *testing.B) {
+			fn(b, backend)
+		})
+	}
+}
+
 func Test_setupBlockStorageType(t *testing.T) {
-	ctx := context.Background()
-	t.Run("fresh database with feature enabled to store full blocks should store full blocks", func(t *testing.T) {
-		resetFn := features.InitWithReset(&features.Flags{
-			SaveFullExecutionPayloads: true,
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		t.Run("fresh database with feature enabled to store full blocks should store full blocks", func(t *testing.T) {
+			resetFn := features.InitWithReset(&features.Flags{
+				SaveFullExecutionPayloads: true,
+			})
+			defer resetFn()
+			store := setupDB(t, backend)
+
+			blk := util.NewBeaconBlockBellatrix()
+			blk.Block.Body.ExecutionPayload.BlockNumber = 1
+			wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
+			require.NoError(t, err)
+			root, err := wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+			require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
+			require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
+			retrievedBlk, err := store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, false, retrievedBlk.IsBlinded())
+			require.DeepEqual(t, wrappedBlock, retrievedBlk)
 		})
-		defer resetFn()
-		store := setupDB(t)
-
-		blk := util.NewBeaconBlockBellatrix()
-		blk.Block.Body.ExecutionPayload.BlockNumber = 1
-		wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
-		require.NoError(t, err)
-		root, err := wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-		require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
-		require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
-		retrievedBlk, err := store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, false, retrievedBlk.IsBlinded())
-		require.DeepEqual(t, wrappedBlock, retrievedBlk)
-	})
-	t.Run("fresh database with default settings should store blinded", func(t *testing.T) {
-		resetFn := features.InitWithReset(&features.Flags{
-			SaveFullExecutionPayloads: false,
+		t.Run("fresh database with default settings should store blinded", func(t *testing.T) {
+			resetFn := features.InitWithReset(&features.Flags{
+				SaveFullExecutionPayloads: false,
+			})
+			defer resetFn()
+			store := setupDB(t, backend)
+
+			blk := util.NewBeaconBlockBellatrix()
+			blk.Block.Body.ExecutionPayload.BlockNumber = 1
+			wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
+			require.NoError(t, err)
+			root, err := wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+			require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
+			require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
+			retrievedBlk, err := store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, true, retrievedBlk.IsBlinded())
+
+			wantedBlk, err := wrappedBlock.ToBlinded()
+			require.NoError(t, err)
+			require.DeepEqual(t, wantedBlk, retrievedBlk)
 		})
-		defer resetFn()
-		store := setupDB(t)
-
-		blk := util.NewBeaconBlockBellatrix()
-		blk.Block.Body.ExecutionPayload.BlockNumber = 1
-		wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
-		require.NoError(t, err)
-		root, err := wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-		require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
-		require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
-		retrievedBlk, err := store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, true, retrievedBlk.IsBlinded())
-
-		wantedBlk, err := wrappedBlock.ToBlinded()
-		require.NoError(t, err)
-		require.DeepEqual(t, wantedBlk, retrievedBlk)
-	})
-	t.Run("existing database with blinded blocks but no key in metadata bucket should continue storing blinded blocks", func(t *testing.T) {
-		store := setupDB(t)
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
-			return tx.Bucket(chainMetadataBucket).Put(saveBlindedBeaconBlocksKey, []byte{1})
-		}))
-
-		blk := util.NewBlindedBeaconBlockBellatrix()
-		blk.Block.Body.ExecutionPayloadHeader.BlockNumber = 1
-		wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
-		require.NoError(t, err)
-		root, err := wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-		require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
-		require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
-		retrievedBlk, err := store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, true, retrievedBlk.IsBlinded())
-		require.DeepEqual(t, wrappedBlock, retrievedBlk)
-
-		// We then delete the key from the bucket.
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
-			return tx.Bucket(chainMetadataBucket).Delete(saveBlindedBeaconBlocksKey)
-		}))
-
-		// Not a fresh database, has blinded blocks already and should continue being that way.
-		err = store.setupBlockStorageType(ctx)
-		require.NoError(t, err)
-
-		var shouldSaveBlinded bool
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
-			bkt := tx.Bucket(chainMetadataBucket)
-			shouldSaveBlinded = len(bkt.Get(saveBlindedBeaconBlocksKey)) > 0
-			return nil
-		}))
-
-		// Should have set the chain metadata bucket to save blinded
-		require.Equal(t, true, shouldSaveBlinded)
-
-		blkFull := util.NewBeaconBlockBellatrix()
-		blkFull.Block.Body.ExecutionPayload.BlockNumber = 2
-		wrappedBlock, err = blocks.NewSignedBeaconBlock(blkFull)
-		require.NoError(t, err)
-		root, err = wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-		wrappedBlinded, err := wrappedBlock.ToBlinded()
-		require.NoError(t, err)
-
-		retrievedBlk, err = store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, true, retrievedBlk.IsBlinded())
-
-		// Compare retrieved value by root, and marshaled bytes.
-		mSrc, err := wrappedBlinded.MarshalSSZ()
-		require.NoError(t, err)
-		mTgt, err := retrievedBlk.MarshalSSZ()
-		require.NoError(t, err)
-		require.Equal(t, true, bytes.Equal(mSrc, mTgt))
-
-		rSrc, err := wrappedBlinded.Block().HashTreeRoot()
-		require.NoError(t, err)
-		rTgt, err := retrievedBlk.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.Equal(t, rSrc, rTgt)
-	})
-	t.Run("existing database with full blocks type should continue storing full blocks", func(t *testing.T) {
-		store := setupDB(t)
-		require.NoError(t, store.db.Update(func(tx *bolt.Tx) error {
-			return tx.Bucket(chainMetadataBucket).Delete(saveBlindedBeaconBlocksKey)
-		}))
-
-		blk := util.NewBeaconBlockBellatrix()
-		blk.Block.Body.ExecutionPayload.BlockNumber = 1
-		wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
-		require.NoError(t, err)
-		root, err := wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-		require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
-		require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
-		retrievedBlk, err := store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, false, retrievedBlk.IsBlinded())
-		require.DeepEqual(t, wrappedBlock, retrievedBlk)
-
-		// Not a fresh database, has full blocks already and should continue being that way.
-		err = store.setupBlockStorageType(ctx)
-		require.NoError(t, err)
-
-		blk = util.NewBeaconBlockBellatrix()
-		blk.Block.Body.ExecutionPayload.BlockNumber = 2
-		wrappedBlock, err = blocks.NewSignedBeaconBlock(blk)
-		require.NoError(t, err)
-		root, err = wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-
-		retrievedBlk, err = store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, false, retrievedBlk.IsBlinded())
-
-		// Compare retrieved value by root, and marshaled bytes.
-		mSrc, err := wrappedBlock.MarshalSSZ()
-		require.NoError(t, err)
-		mTgt, err := retrievedBlk.MarshalSSZ()
-		require.NoError(t, err)
-		require.Equal(t, true, bytes.Equal(mSrc, mTgt))
-
-		rTgt, err := retrievedBlk.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.Equal(t, root, rTgt)
-	})
-	t.Run("existing database with blinded blocks type should error if user enables full blocks feature flag", func(t *testing.T) {
-		store := setupDB(t)
-
-		blk := util.NewBeaconBlockBellatrix()
-		blk.Block.Body.ExecutionPayload.BlockNumber = 1
-		wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
-		require.NoError(t, err)
-		root, err := wrappedBlock.Block().HashTreeRoot()
-		require.NoError(t, err)
-		require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
-		require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
-		require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
-		retrievedBlk, err := store.Block(ctx, root)
-		require.NoError(t, err)
-		require.Equal(t, true, retrievedBlk.IsBlinded())
-		wantedBlk, err := wrappedBlock.ToBlinded()
-		require.NoError(t, err)
-		require.DeepEqual(t, wantedBlk, retrievedBlk)
-
-		// Trying to enable full blocks with a database that is already storing blinded blocks should error.
-		resetFn := features.InitWithReset(&features.Flags{
-			SaveFullExecutionPayloads: true,
+		t.Run("existing database with blinded blocks but no key in metadata bucket should continue storing blinded blocks", func(t *testing.T) {
+			store := setupDB(t, backend)
+			require.NoError(t, store.db.Update(func(tx engine.Tx) error {
+				return tx.Bucket(chainMetadataBucket).Put(saveBlindedBeaconBlocksKey, []byte{1})
+			}))
+
+			blk := util.NewBlindedBeaconBlockBellatrix()
+			blk.Block.Body.ExecutionPayloadHeader.BlockNumber = 1
+			wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
+			require.NoError(t, err)
+			root, err := wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+			require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
+			require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
+			retrievedBlk, err := store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, true, retrievedBlk.IsBlinded())
+			require.DeepEqual(t, wrappedBlock, retrievedBlk)
+
+			// We then delete the key from the bucket.
+			require.NoError(t, store.db.Update(func(tx engine.Tx) error {
+				return tx.Bucket(chainMetadataBucket).Delete(saveBlindedBeaconBlocksKey)
+			}))
+
+			// Not a fresh database, has blinded blocks already and should continue being that way.
+			err = store.setupBlockStorageType(ctx)
+			require.NoError(t, err)
+
+			var shouldSaveBlinded bool
+			require.NoError(t, store.db.Update(func(tx engine.Tx) error {
+				bkt := tx.Bucket(chainMetadataBucket)
+				shouldSaveBlinded = len(bkt.Get(saveBlindedBeaconBlocksKey)) > 0
+				return nil
+			}))
+
+			// Should have set the chain metadata bucket to save blinded
+			require.Equal(t, true, shouldSaveBlinded)
+
+			blkFull := util.NewBeaconBlockBellatrix()
+			blkFull.Block.Body.ExecutionPayload.BlockNumber = 2
+			wrappedBlock, err = blocks.NewSignedBeaconBlock(blkFull)
+			require.NoError(t, err)
+			root, err = wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+			wrappedBlinded, err := wrappedBlock.ToBlinded()
+			require.NoError(t, err)
+
+			retrievedBlk, err = store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, true, retrievedBlk.IsBlinded())
+
+			// Compare retrieved value by root, and marshaled bytes.
+			mSrc, err := wrappedBlinded.MarshalSSZ()
+			require.NoError(t, err)
+			mTgt, err := retrievedBlk.MarshalSSZ()
+			require.NoError(t, err)
+			require.Equal(t, true, bytes.Equal(mSrc, mTgt))
+
+			rSrc, err := wrappedBlinded.Block().HashTreeRoot()
+			require.NoError(t, err)
+			rTgt, err := retrievedBlk.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.Equal(t, rSrc, rTgt)
+		})
+		t.Run("existing database with full blocks type should continue storing full blocks", func(t *testing.T) {
+			store := setupDB(t, backend)
+			require.NoError(t, store.db.Update(func(tx engine.Tx) error {
+				return tx.Bucket(chainMetadataBucket).Delete(saveBlindedBeaconBlocksKey)
+			}))
+
+			blk := util.NewBeaconBlockBellatrix()
+			blk.Block.Body.ExecutionPayload.BlockNumber = 1
+			wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
+			require.NoError(t, err)
+			root, err := wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+			require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
+			require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
+			retrievedBlk, err := store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, false, retrievedBlk.IsBlinded())
+			require.DeepEqual(t, wrappedBlock, retrievedBlk)
+
+			// Not a fresh database, has full blocks already and should continue being that way.
+			err = store.setupBlockStorageType(ctx)
+			require.NoError(t, err)
+
+			blk = util.NewBeaconBlockBellatrix()
+			blk.Block.Body.ExecutionPayload.BlockNumber = 2
+			wrappedBlock, err = blocks.NewSignedBeaconBlock(blk)
+			require.NoError(t, err)
+			root, err = wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+
+			retrievedBlk, err = store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, false, retrievedBlk.IsBlinded())
+
+			// Compare retrieved value by root, and marshaled bytes.
+			mSrc, err := wrappedBlock.MarshalSSZ()
+			require.NoError(t, err)
+			mTgt, err := retrievedBlk.MarshalSSZ()
+			require.NoError(t, err)
+			require.Equal(t, true, bytes.Equal(mSrc, mTgt))
+
+			rTgt, err := retrievedBlk.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.Equal(t, root, rTgt)
+		})
+		t.Run("existing database with blinded blocks type should error if user enables full blocks feature flag", func(t *testing.T) {
+			store := setupDB(t, backend)
+
+			blk := util.NewBeaconBlockBellatrix()
+			blk.Block.Body.ExecutionPayload.BlockNumber = 1
+			wrappedBlock, err := blocks.NewSignedBeaconBlock(blk)
+			require.NoError(t, err)
+			root, err := wrappedBlock.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveBlock(ctx, wrappedBlock))
+			require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: root[:]}))
+			require.NoError(t, store.SaveHeadBlockRoot(ctx, root))
+			retrievedBlk, err := store.Block(ctx, root)
+			require.NoError(t, err)
+			require.Equal(t, true, retrievedBlk.IsBlinded())
+			wantedBlk, err := wrappedBlock.ToBlinded()
+			require.NoError(t, err)
+			require.DeepEqual(t, wantedBlk, retrievedBlk)
+
+			// Trying to enable full blocks with a database that is already storing blinded blocks should error.
+			resetFn := features.InitWithReset(&features.Flags{
+				SaveFullExecutionPayloads: true,
+			})
+			defer resetFn()
+			err = store.setupBlockStorageType(ctx)
+			errMsg := "cannot use the %s flag with this existing database, as it has already been initialized"
+			require.ErrorContains(t, fmt.Sprintf(errMsg, features.SaveFullExecutionPayloads.Name), err)
 		})
-		defer resetFn()
-		err = store.setupBlockStorageType(ctx)
-		errMsg := "cannot use the %s flag with this existing database, as it has already been initialized"
-		require.ErrorContains(t, fmt.Sprintf(errMsg, features.SaveFullExecutionPayloads.Name), err)
 	})
 }
+
+func TestNewKVStore_Backend(t *testing.T) {
+	ctx := context.Background()
+	for _, backend := range engine.Backends {
+		t.Run(string(backend), func(t *testing.T) {
+			dir := t.TempDir()
+			_, ok, err := DetectBackend(dir)
+			require.NoError(t, err)
+			require.Equal(t, false, ok)
+
+			store, err := NewKVStore(ctx, dir, WithBackend(backend))
+			require.NoError(t, err)
+			require.Equal(t, backend, store.Backend())
+			root := [32]byte{'A'}
+			require.NoError(t, store.SaveGenesisBlockRoot(ctx, root))
+			require.NoError(t, store.Close())
+
+			detected, ok, err := DetectBackend(dir)
+			require.NoError(t, err)
+			require.Equal(t, true, ok)
+			require.Equal(t, backend, detected)
+
+			// An existing database is opened with its backend when none is configured.
+			store, err = NewKVStore(ctx, dir)
+			require.NoError(t, err)
+			require.Equal(t, backend, store.Backend())
+			genesisRoot, err := store.GenesisBlockRoot(ctx)
+			require.NoError(t, err)
+			require.Equal(t, root, genesisRoot)
+			require.NoError(t, store.Close())
+
+			for _, other := range engine.Backends {
+				if other == backend {
+					continue
+				}
+				_, err = NewKVStore(ctx, dir, WithBackend(other))
+				require.ErrorContains(t, fmt.Sprintf("uses the %s backend", backend), err)
+			}
+		})
+	}
+}
@@ -3,12 +3,12 @@ package kv
 import (
 	"context"
 
-	bolt "go.etcd.io/bbolt"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 )
 
 var migrationCompleted = []byte("done")
 
-type migration func(context.Context, *bolt.DB) error
+type migration func(context.Context, engine.DB) error
 
 var migrations = []migration{
 	migrateArchivedIndex,
@@ -24,5 +24,6 @@ func (s *Store) RunMigrations(ctx context.Context) error {
 			return err
 		}
 	}
-	return nil
+	// Moving archived states into state diffs depends on how the store was configured.
+	return s.migrateStateDiffs(ctx)
 }
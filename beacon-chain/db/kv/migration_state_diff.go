@@ -0,0 +1,123 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/progress"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+var migrationStateDiffKey = []byte("migration_state_diff")
+
+type archivedState struct {
+	slot primitives.Slot
+	root [32]byte
+}
+
+// migrateStateDiffs moves the finalized states saved at archive points into the state diff hierarchy, then deletes
+// the full copies. Genesis, checkpoint and origin states are kept in full.
+func (s *Store) migrateStateDiffs(ctx context.Context) error {
+	h := s.stateDiffHierarchy
+	if h == nil {
+		return nil
+	}
+	var done bool
+	if err := s.db.View(func(tx engine.Tx) error {
+		done = bytes.Equal(tx.Bucket(migrationsBucket).Get(migrationStateDiffKey), migrationCompleted)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	f, err := s.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	finalizedSlot, err := slots.EpochStart(f.Epoch)
+	if err != nil {
+		return err
+	}
+	oRoot, err := s.OriginCheckpointBlockRoot(ctx)
+	if err != nil && !errors.Is(err, ErrNotFoundOriginBlockRoot) {
+		return err
+	}
+
+	// Archived states are keyed by the root of the last block at or below their slot. Only the states of the
+	// finalized chain can be moved, the slot of the state itself is checked once it is read.
+	var archived []archivedState
+	if err := s.db.View(func(tx engine.Tx) error {
+		genesisRoot := tx.Bucket(blocksBucket).Get(genesisBlockRootKey)
+		finalized := tx.Bucket(finalizedBlockRootsIndexBucket)
+		return tx.Bucket(stateBucket).ForEach(func(k, _ []byte) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !bytes.Equal(k, genesisRoot) && finalized.Get(k) == nil {
+				return nil
+			}
+			slot, err := s.slotByBlockRoot(ctx, tx, k)
+			if err != nil {
+				return err
+			}
+			if slot <= finalizedSlot {
+				archived = append(archived, archivedState{slot: slot, root: bytesutil.ToBytes32(k)})
+			}
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	// Diffs are computed against the states migrated before them.
+	sort.Slice(archived, func(i, j int) bool {
+		return archived[i].slot < archived[j].slot
+	})
+
+	log.WithField("states", len(archived)).Info("Performing a one-time migration of archived states to state diffs")
+	bar := progress.InitializeProgressBar(len(archived), "Migrating archived states to state diffs.")
+	var migrated [][32]byte
+	for _, a := range archived {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := bar.Add(1); err != nil {
+			log.WithError(err).Debug("Could not increase progress bar")
+		}
+		st, err := s.State(ctx, a.root)
+		if err != nil {
+			return errors.Wrapf(err, "could not read archived state %#x", a.root)
+		}
+		if st == nil || st.IsNil() || st.Slot() > finalizedSlot || !h.Stored(st.Slot()) || s.HasStateDiff(ctx, st.Slot()) {
+			continue
+		}
+		if err := s.SaveStateDiff(ctx, st); err != nil {
+			return errors.Wrapf(err, "could not save state diff at slot %d", st.Slot())
+		}
+		migrated = append(migrated, a.root)
+	}
+
+	for _, root := range migrated {
+		if root == oRoot {
+			continue
+		}
+		if err := s.DeleteState(ctx, root); err != nil && !errors.Is(err, ErrDeleteJustifiedAndFinalized) {
+			return errors.Wrapf(err, "could not delete archived state %#x", root)
+		}
+	}
+
+	if err := s.db.Update(func(tx engine.Tx) error {
+		return tx.Bucket(migrationsBucket).Put(migrationStateDiffKey, migrationCompleted)
+	}); err != nil {
+		return err
+	}
+	log.WithField("states", len(migrated)).Info("Migration of archived states to state diffs done")
+	return nil
+}
@@ -0,0 +1,77 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func Test_migrateStateDiffs(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		// Archive states with the default layout first.
+		db, err := NewKVStore(ctx, dir, WithBackend(backend))
+		require.NoError(t, err)
+		genesis, _ := util.DeterministicGenesisStateDeneb(t, 32)
+		gBlk := util.NewBeaconBlock()
+		gRoot, err := gBlk.Block.HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, gRoot))
+		require.NoError(t, db.SaveState(ctx, genesis, gRoot))
+		blks := makeBlocks(t, 0, 40, gRoot)
+		require.NoError(t, db.SaveBlocks(ctx, blks))
+
+		roots := make(map[primitives.Slot][32]byte)
+		for _, b := range blks {
+			roots[b.Block().Slot()], err = b.Block().HashTreeRoot()
+			require.NoError(t, err)
+		}
+		archived := make(map[primitives.Slot]*ethpb.BeaconStateDeneb)
+		// The state at slot 12 is archived by the root of the block at slot 11, as if slot 12 was skipped.
+		for slot, blockSlot := range map[primitives.Slot]primitives.Slot{4: 4, 8: 8, 12: 11, 6: 6, 36: 36} {
+			st := genesis.Copy()
+			require.NoError(t, st.SetSlot(slot))
+			require.NoError(t, st.UpdateBalancesAtIndex(primitives.ValidatorIndex(slot%32), uint64(slot)))
+			require.NoError(t, db.SaveState(ctx, st, roots[blockSlot]))
+			archived[slot] = st.ToProto().(*ethpb.BeaconStateDeneb)
+		}
+		fRoot := roots[32]
+		cp := &ethpb.Checkpoint{Epoch: 1, Root: fRoot[:]}
+		require.NoError(t, db.SaveState(ctx, genesis, fRoot))
+		require.NoError(t, db.SaveFinalizedCheckpoint(ctx, cp))
+		require.NoError(t, db.Close())
+
+		db = setupStateDiffDB(t, backend, dir, []uint8{4, 2})
+		require.NoError(t, db.migrateStateDiffs(ctx))
+
+		for _, slot := range []primitives.Slot{0, 4, 8, 12} {
+			require.Equal(t, true, db.HasStateDiff(ctx, slot), "slot %d", slot)
+		}
+		got, err := db.StateDiff(ctx, 12)
+		require.NoError(t, err)
+		assert.DeepSSZEqual(t, archived[12], got.ToProto())
+
+		// Migrated states are deleted, except the genesis and finalized ones.
+		assert.Equal(t, false, db.HasState(ctx, roots[4]))
+		assert.Equal(t, false, db.HasState(ctx, roots[11]))
+		assert.Equal(t, true, db.HasState(ctx, gRoot))
+		assert.Equal(t, true, db.HasState(ctx, fRoot))
+		// States which are not at a slot of the hierarchy, or not finalized, are left alone.
+		assert.Equal(t, true, db.HasState(ctx, roots[6]))
+		assert.Equal(t, true, db.HasState(ctx, roots[36]))
+		assert.Equal(t, false, db.HasStateDiff(ctx, 36))
+
+		require.NoError(t, db.db.View(func(tx engine.Tx) error {
+			assert.DeepEqual(t, migrationCompleted, tx.Bucket(migrationsBucket).Get(migrationStateDiffKey))
+			return nil
+		}))
+	})
+}
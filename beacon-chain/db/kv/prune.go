@@ -0,0 +1,273 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"google.golang.org/protobuf/proto"
+)
+
+// pruneBatchSlots is the number of slots whose history PruneHistory deletes in a single transaction.
+const pruneBatchSlots = primitives.Slot(256)
+
+// historyPrunedBeforeKey records the slot below which PruneHistory deleted blocks and states.
+var historyPrunedBeforeKey = []byte("history-pruned-before")
+
+var (
+	prunedBlocksTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "db_pruned_blocks_total",
+		Help: "The number of historical blocks pruned from the beacon node database.",
+	})
+	historyPrunedBeforeSlot = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_history_pruned_before_slot",
+		Help: "The slot below which blocks and states were pruned from the beacon node database.",
+	})
+)
+
+type slotRoot struct {
+	slot primitives.Slot
+	root []byte
+}
+
+// EarliestAvailableSlot returns the lowest slot from which blocks and states are available, apart from the genesis
+// and origin checkpoint ones. The history below it was either pruned or has not been backfilled yet.
+func (s *Store) EarliestAvailableSlot(ctx context.Context) (primitives.Slot, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.EarliestAvailableSlot")
+	defer span.End()
+	var earliest primitives.Slot
+	err := s.db.View(func(tx engine.Tx) error {
+		var err error
+		earliest, err = earliestAvailableSlot(tx)
+		return err
+	})
+	return earliest, err
+}
+
+func earliestAvailableSlot(tx engine.Tx) (primitives.Slot, error) {
+	earliest := historyPrunedBefore(tx)
+	enc := tx.Bucket(blocksBucket).Get(backfillStatusKey)
+	if len(enc) == 0 {
+		return earliest, nil
+	}
+	bf := &dbval.BackfillStatus{}
+	if err := proto.Unmarshal(enc, bf); err != nil {
+		return 0, err
+	}
+	if low := primitives.Slot(bf.LowSlot); low > earliest {
+		earliest = low
+	}
+	return earliest, nil
+}
+
+// PruneHistory deletes the blocks and states below the given slot, along with their state summaries, their entries
+// in the slot, parent root and finalized block roots indices, and the state diffs which the states that are kept do
+// not depend on. The genesis and origin checkpoint blocks and states are kept. History is only deleted below the
+// start of the finalized epoch, whatever the given slot.
+//
+// History is deleted in batches of slots, each in its own transaction. The slot below which it is deleted is
+// recorded with each batch, so that pruning resumes where it stopped. It returns the number of deleted blocks.
+func (s *Store) PruneHistory(ctx context.Context, beforeSlot primitives.Slot) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PruneHistory")
+	defer span.End()
+
+	finalized, err := s.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	finalizedSlot, err := slots.EpochStart(finalized.Epoch)
+	if err != nil {
+		return 0, err
+	}
+	if beforeSlot > finalizedSlot {
+		beforeSlot = finalizedSlot
+	}
+
+	pruned := 0
+	for {
+		if ctx.Err() != nil {
+			return pruned, ctx.Err()
+		}
+		done := false
+		n, end := 0, primitives.Slot(0)
+		if err := s.db.Update(func(tx engine.Tx) error {
+			start := historyPrunedBefore(tx)
+			if start >= beforeSlot {
+				done = true
+				return nil
+			}
+			end = pruneBatchEnd(tx, start, beforeSlot)
+			var err error
+			n, err = s.pruneSlots(ctx, tx, start, end)
+			if err != nil {
+				return err
+			}
+			return tx.Bucket(chainMetadataBucket).Put(historyPrunedBeforeKey, bytesutil.SlotToBytesBigEndian(end))
+		}); err != nil {
+			return pruned, errors.Wrap(err, "could not prune history")
+		}
+		if done {
+			break
+		}
+		pruned += n
+		prunedBlocksTotal.Add(float64(n))
+		historyPrunedBeforeSlot.Set(float64(end))
+	}
+	return pruned, nil
+}
+
+// pruneBatchEnd returns the end of the next batch of slots to prune, skipping the slots which hold neither blocks nor
+// states.
+func pruneBatchEnd(tx engine.Tx, start, beforeSlot primitives.Slot) primitives.Slot {
+	first := beforeSlot
+	for _, b := range [][]byte{blockSlotIndicesBucket, stateSlotIndicesBucket} {
+		k, _ := tx.Bucket(b).Cursor().Seek(bytesutil.SlotToBytesBigEndian(start))
+		if k != nil && bytesutil.BytesToSlotBigEndian(k) < first {
+			first = bytesutil.BytesToSlotBigEndian(k)
+		}
+	}
+	if first+pruneBatchSlots < beforeSlot {
+		return first + pruneBatchSlots
+	}
+	return beforeSlot
+}
+
+// pruneSlots deletes the history of the slots from start to end, excluded, and returns the number of deleted blocks.
+func (s *Store) pruneSlots(ctx context.Context, tx engine.Tx, start, end primitives.Slot) (int, error) {
+	blocksBkt := tx.Bucket(blocksBucket)
+	genesisRoot := bytesutil.SafeCopyBytes(blocksBkt.Get(genesisBlockRootKey))
+	originRoot := bytesutil.SafeCopyBytes(blocksBkt.Get(originCheckpointBlockRootKey))
+	keep := func(root []byte) bool {
+		return bytes.Equal(root, genesisRoot) || bytes.Equal(root, originRoot)
+	}
+	summaryBkt := tx.Bucket(stateSummaryBucket)
+	deleteSummary := func(root []byte) error {
+		s.stateSummaryCache.delete(bytesutil.ToBytes32(root))
+		return summaryBkt.Delete(root)
+	}
+
+	// States are deleted first, as the state summary of a deleted block is kept while its state is.
+	for _, sr := range rootsInSlotRange(tx, stateSlotIndicesBucket, start, end) {
+		if keep(sr.root) {
+			continue
+		}
+		if err := s.deleteState(ctx, tx, sr.root); err != nil {
+			return 0, errors.Wrapf(err, "could not delete state of block root %#x", sr.root)
+		}
+		if blocksBkt.Get(sr.root) == nil {
+			if err := deleteSummary(sr.root); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	finalizedBkt := tx.Bucket(finalizedBlockRootsIndexBucket)
+	deleted := 0
+	for _, sr := range rootsInSlotRange(tx, blockSlotIndicesBucket, start, end) {
+		if keep(sr.root) {
+			continue
+		}
+		indices := map[string][]byte{string(blockSlotIndicesBucket): bytesutil.SlotToBytesBigEndian(sr.slot)}
+		if enc := blocksBkt.Get(sr.root); enc != nil {
+			blk, err := unmarshalBlock(ctx, enc)
+			if err != nil {
+				return 0, errors.Wrapf(err, "could not decode block with root %#x", sr.root)
+			}
+			indices = blockIndices(sr.slot, blk.Block().ParentRoot())
+			deleted++
+		}
+		if err := deleteValueForIndices(ctx, indices, sr.root, tx); err != nil {
+			return 0, errors.Wrap(err, "could not delete root for DB indices")
+		}
+		if err := blocksBkt.Delete(sr.root); err != nil {
+			return 0, err
+		}
+		if err := tx.Bucket(blockParentRootIndicesBucket).Delete(sr.root); err != nil {
+			return 0, err
+		}
+		if err := finalizedBkt.Delete(sr.root); err != nil {
+			return 0, err
+		}
+		if tx.Bucket(stateBucket).Get(sr.root) == nil {
+			if err := deleteSummary(sr.root); err != nil {
+				return 0, err
+			}
+		}
+		s.blockCache.Del(string(sr.root))
+	}
+
+	// The finalized blocks which are kept must not point to deleted children.
+	for _, root := range [][]byte{genesisRoot, originRoot} {
+		if len(root) == 0 {
+			continue
+		}
+		enc := finalizedBkt.Get(root)
+		if enc == nil || bytes.Equal(enc, containerFinalizedButNotCanonical) {
+			continue
+		}
+		ctr := &ethpb.FinalizedBlockRootContainer{}
+		if err := decode(ctx, enc, ctr); err != nil {
+			return 0, err
+		}
+		if len(ctr.ChildRoot) == 0 || finalizedBkt.Get(ctr.ChildRoot) != nil {
+			continue
+		}
+		ctr.ChildRoot = nil
+		enc, err := encode(ctx, ctr)
+		if err != nil {
+			return 0, err
+		}
+		if err := finalizedBkt.Put(root, enc); err != nil {
+			return 0, err
+		}
+	}
+
+	if h := s.stateDiffHierarchy; h != nil {
+		floor := bytesutil.SlotToBytesBigEndian(h.SnapshotFloor(end))
+		diffBkt := tx.Bucket(stateDiffBucket)
+		var keys [][]byte
+		c := diffBkt.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, floor) < 0; k, _ = c.Next() {
+			keys = append(keys, bytesutil.SafeCopyBytes(k))
+		}
+		for _, k := range keys {
+			if err := diffBkt.Delete(k); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// rootsInSlotRange returns the roots stored in a slot index bucket for the slots from start to end, excluded.
+func rootsInSlotRange(tx engine.Tx, bucket []byte, start, end primitives.Slot) []slotRoot {
+	var roots []slotRoot
+	c := tx.Bucket(bucket).Cursor()
+	for k, v := c.Seek(bytesutil.SlotToBytesBigEndian(start)); k != nil; k, v = c.Next() {
+		slot := bytesutil.BytesToSlotBigEndian(k)
+		if slot >= end {
+			break
+		}
+		for i := 0; i+32 <= len(v); i += 32 {
+			roots = append(roots, slotRoot{slot: slot, root: bytesutil.SafeCopyBytes(v[i : i+32])})
+		}
+	}
+	return roots
+}
+
+func historyPrunedBefore(tx engine.Tx) primitives.Slot {
+	enc := tx.Bucket(chainMetadataBucket).Get(historyPrunedBeforeKey)
+	if len(enc) == 0 {
+		return 0
+	}
+	return bytesutil.BytesToSlotBigEndian(enc)
+}
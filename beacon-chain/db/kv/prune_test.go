@@ -0,0 +1,107 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	consensusblocks "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestStore_PruneHistory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupDB(t, backend)
+		slotsPerEpoch := uint64(params.BeaconConfig().SlotsPerEpoch)
+
+		genesis := util.NewBeaconBlock()
+		genesisRoot, err := genesis.Block.HashTreeRoot()
+		require.NoError(t, err)
+		wsb, err := consensusblocks.NewSignedBeaconBlock(genesis)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveBlock(ctx, wsb))
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, genesisRoot))
+
+		// Blocks from slot 1 to 4 epochs, with a state every 8 slots.
+		blks := makeBlocks(t, 0, slotsPerEpoch*4, genesisRoot)
+		require.NoError(t, db.SaveBlocks(ctx, blks))
+		roots := make([][32]byte, len(blks))
+		for i, b := range blks {
+			roots[i], err = b.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: b.Block().Slot(), Root: roots[i][:]}))
+			if b.Block().Slot()%8 != 0 {
+				continue
+			}
+			st, err := util.NewBeaconState()
+			require.NoError(t, err)
+			require.NoError(t, st.SetSlot(b.Block().Slot()))
+			require.NoError(t, db.SaveState(ctx, st, roots[i]))
+		}
+		finalizedSlot := primitives.Slot(slotsPerEpoch * 3)
+		finalizedRoot := roots[finalizedSlot-1]
+		require.NoError(t, db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 3, Root: finalizedRoot[:]}))
+
+		earliest, err := db.EarliestAvailableSlot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, primitives.Slot(0), earliest)
+
+		before := primitives.Slot(slotsPerEpoch*2 + 5)
+		pruned, err := db.PruneHistory(ctx, before)
+		require.NoError(t, err)
+		assert.Equal(t, int(before)-1, pruned)
+		for i, r := range roots {
+			slot := primitives.Slot(i + 1)
+			kept := slot >= before
+			assert.Equal(t, kept, db.HasBlock(ctx, r), "block at slot %d", slot)
+			assert.Equal(t, kept, db.HasStateSummary(ctx, r), "state summary at slot %d", slot)
+			if slot%8 == 0 {
+				assert.Equal(t, kept, db.HasState(ctx, r), "state at slot %d", slot)
+			}
+			if slot < finalizedSlot {
+				assert.Equal(t, kept, db.IsFinalizedBlock(ctx, r), "finalized block at slot %d", slot)
+			}
+		}
+		assert.Equal(t, true, db.HasBlock(ctx, genesisRoot))
+		assert.Equal(t, true, db.IsFinalizedBlock(ctx, genesisRoot))
+		child, err := db.FinalizedChildBlock(ctx, genesisRoot)
+		require.NoError(t, err)
+		assert.Equal(t, nil, child)
+
+		// The slot index only holds the blocks which are kept.
+		idxRoots, err := db.BlockRoots(ctx, filters.NewFilter().SetStartSlot(0).SetEndSlot(before))
+		require.NoError(t, err)
+		assert.DeepEqual(t, [][32]byte{genesisRoot, roots[before-1]}, idxRoots)
+
+		earliest, err = db.EarliestAvailableSlot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before, earliest)
+		pruned, err = db.PruneHistory(ctx, before)
+		require.NoError(t, err)
+		assert.Equal(t, 0, pruned)
+
+		// History at and above the finalized checkpoint is kept.
+		pruned, err = db.PruneHistory(ctx, primitives.Slot(slotsPerEpoch*4))
+		require.NoError(t, err)
+		assert.Equal(t, int(finalizedSlot-before), pruned)
+		assert.Equal(t, true, db.HasBlock(ctx, finalizedRoot))
+		assert.Equal(t, true, db.HasState(ctx, finalizedRoot))
+		earliest, err = db.EarliestAvailableSlot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, finalizedSlot, earliest)
+
+		// Blocks which are not backfilled yet are not available either.
+		require.NoError(t, db.SaveBackfillStatus(ctx, &dbval.BackfillStatus{LowSlot: uint64(finalizedSlot) + 1}))
+		earliest, err = db.EarliestAvailableSlot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, finalizedSlot+1, earliest)
+	})
+}
@@ -0,0 +1,52 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/sirupsen/logrus"
+)
+
+// WithReadOnly opens an existing database without writing to it. The database is locked like a writable one, so
+// it can not be opened while a node runs on it. Read-only replicas open checkpoints of the database instead, see
+// WithReplica.
+func WithReadOnly() KVStoreOption {
+	return func(s *Store) {
+		s.readOnly = true
+	}
+}
+
+// ReadOnly returns true if the database was opened without allowing writes.
+func (s *Store) ReadOnly() bool {
+	return s.readOnly
+}
+
+// openReadOnlyStore opens the existing database of the directory for the store, without writing to it.
+func openReadOnlyStore(kv *Store, dirPath string) error {
+	if kv.replica {
+		return openReplicaStore(kv, dirPath)
+	}
+	hasDir, err := file.HasDir(dirPath)
+	if err != nil {
+		return err
+	}
+	backend, ok, err := DetectBackend(dirPath)
+	if err != nil {
+		return err
+	}
+	if !hasDir || !ok {
+		return fmt.Errorf("no database to open read only in %s", dirPath)
+	}
+	if kv.backend != "" && kv.backend != backend {
+		return fmt.Errorf("database in %s uses the %s backend, not %s", dirPath, backend, kv.backend)
+	}
+	kv.backend = backend
+	log.WithFields(logrus.Fields{"path": StoreDataPath(dirPath, backend), "backend": backend}).Info("Opening database read only")
+	db, err := openEngine(StoreDataPath(dirPath, backend), backend, true)
+	if err != nil {
+		return err
+	}
+	kv.db = engine.NewSwapDB(db)
+	return kv.registerCollector(db)
+}
@@ -0,0 +1,44 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestStore_ReadOnly(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		_, err := NewKVStore(ctx, dir, WithReadOnly())
+		require.ErrorContains(t, "no database to open read only", err)
+
+		writer, err := NewKVStore(ctx, dir, WithBackend(backend))
+		require.NoError(t, err)
+		genesis := util.NewBeaconBlock()
+		util.SaveBlock(t, ctx, writer, genesis)
+		root, err := genesis.Block.HashTreeRoot()
+		require.NoError(t, err)
+
+		// The database is locked while it is written to.
+		_, err = NewKVStore(ctx, dir, WithReadOnly())
+		require.ErrorContains(t, "lock", err)
+		require.NoError(t, writer.Close())
+
+		db, err := NewKVStore(ctx, dir, WithReadOnly())
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, db.Close())
+		}()
+		assert.Equal(t, true, db.ReadOnly())
+		assert.Equal(t, backend, db.Backend())
+		assert.Equal(t, true, db.HasBlock(ctx, root))
+		require.NotNil(t, db.SaveGenesisBlockRoot(ctx, root), "a read only database should not be written to")
+		require.ErrorIs(t, db.Refresh(ctx), errNotReplica)
+	})
+}
@@ -0,0 +1,274 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/sirupsen/logrus"
+)
+
+// ReplicaCheckpointsDirName is the directory of the database directory which the checkpoints opened by read-only
+// replicas are written to.
+const ReplicaCheckpointsDirName = "replica-checkpoints"
+
+const (
+	// replicaClonesDirName is the directory of the checkpoints directory which replicas link the checkpoint they
+	// open into, since a Pebble database can only be opened by one process at a time.
+	replicaClonesDirName = "replicas"
+	// replicaCheckpointsKept is the number of checkpoints kept, so that a replica still linking the files of the
+	// previous checkpoint does not see them removed.
+	replicaCheckpointsKept = 3
+	// replicaCheckpointTempSuffix marks a checkpoint being written, which replicas ignore.
+	replicaCheckpointTempSuffix = ".tmp"
+)
+
+var (
+	errNotReplica             = errors.New("only a replica database can be refreshed")
+	errReplicaCheckpointsBolt = errors.New("replica checkpoints need the pebble backend")
+)
+
+// WithReplica opens the newest replica checkpoint of an existing database read only, as a read-only replica of the
+// node writing to the database does. That node writes the checkpoints with RunReplicaCheckpoints, and Refresh moves
+// the replica to the checkpoints written since.
+func WithReplica() KVStoreOption {
+	return func(s *Store) {
+		s.readOnly = true
+		s.replica = true
+	}
+}
+
+// WriteReplicaCheckpoint writes a checkpoint of the database for read-only replicas, and removes the checkpoints
+// no replica opens anymore.
+func (s *Store) WriteReplicaCheckpoint() error {
+	if s.readOnly {
+		return errors.New("a read only database can not write replica checkpoints")
+	}
+	pebbleDB, ok := s.db.Unwrap().(*engine.PebbleDB)
+	if !ok {
+		return errReplicaCheckpointsBolt
+	}
+	dir := filepath.Join(s.databasePath, ReplicaCheckpointsDirName)
+	if err := os.MkdirAll(dir, params.BeaconIoConfig().ReadWriteExecutePermissions); err != nil {
+		return err
+	}
+	// Checkpoints are named after the time they are written at, so that the newest one sorts last.
+	checkpoint := filepath.Join(dir, fmt.Sprintf("%020d", time.Now().UnixNano()))
+	tmp := checkpoint + replicaCheckpointTempSuffix
+	if err := pebbleDB.Checkpoint(tmp); err != nil {
+		return errors.Wrap(err, "could not write checkpoint")
+	}
+	if err := os.Rename(tmp, checkpoint); err != nil {
+		return err
+	}
+	return pruneReplicaCheckpoints(dir)
+}
+
+// RunReplicaCheckpoints writes a replica checkpoint every interval, until the context is done.
+func (s *Store) RunReplicaCheckpoints(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.WriteReplicaCheckpoint(); err != nil {
+				log.WithError(err).Error("Could not write replica checkpoint")
+			}
+		}
+	}
+}
+
+// Refresh moves a replica to the newest replica checkpoint, so that it sees what was written to the database
+// until then. It does nothing when no checkpoint was written since the last one, and keeps the checkpoint in use
+// if the newest one can not be opened.
+func (s *Store) Refresh(ctx context.Context) error {
+	if !s.replica {
+		return errNotReplica
+	}
+	checkpointsDir := filepath.Join(s.databasePath, ReplicaCheckpointsDirName)
+	checkpoint, err := newestReplicaCheckpoint(checkpointsDir)
+	if err != nil {
+		return err
+	}
+	if checkpoint == s.replicaCheckpoint {
+		return nil
+	}
+	return s.db.Swap(ctx, func(current engine.DB) (engine.DB, error) {
+		clone, db, err := openReplicaCheckpoint(checkpointsDir, checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		if err := current.Close(); err != nil {
+			log.WithError(err).Error("Could not close database before reopening it")
+		}
+		if err := os.RemoveAll(s.replicaClone); err != nil {
+			log.WithError(err).Error("Could not remove previous replica checkpoint")
+		}
+		s.replicaCheckpoint, s.replicaClone = checkpoint, clone
+		return db, nil
+	})
+}
+
+// openReplicaStore opens the newest replica checkpoint of the database in the directory for the store.
+func openReplicaStore(kv *Store, dirPath string) error {
+	if kv.backend != "" && kv.backend != engine.Pebble {
+		return errReplicaCheckpointsBolt
+	}
+	// Bolt databases have no replica checkpoints, as a checkpoint would copy the whole database.
+	backend, ok, err := DetectBackend(dirPath)
+	if err != nil {
+		return err
+	}
+	if ok && backend != engine.Pebble {
+		return errors.Wrapf(errReplicaCheckpointsBolt, "database in %s uses the %s backend", dirPath, backend)
+	}
+	kv.backend = engine.Pebble
+	checkpointsDir := filepath.Join(dirPath, ReplicaCheckpointsDirName)
+	checkpoint, err := newestReplicaCheckpoint(checkpointsDir)
+	if err != nil {
+		return err
+	}
+	clone, db, err := openReplicaCheckpoint(checkpointsDir, checkpoint)
+	if err != nil {
+		return err
+	}
+	log.WithFields(logrus.Fields{"path": filepath.Join(checkpointsDir, checkpoint), "backend": kv.backend}).Info("Opening replica checkpoint")
+	kv.db = engine.NewSwapDB(db)
+	kv.replicaCheckpoint, kv.replicaClone = checkpoint, clone
+	return nil
+}
+
+// newestReplicaCheckpoint returns the name of the newest checkpoint of the directory.
+func newestReplicaCheckpoint(dir string) (string, error) {
+	checkpoints, err := replicaCheckpoints(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(checkpoints) == 0 {
+		return "", fmt.Errorf("no replica checkpoint in %s, the node writing to the database must write them", dir)
+	}
+	return checkpoints[len(checkpoints)-1], nil
+}
+
+// replicaCheckpoints returns the names of the complete checkpoints of the directory, oldest first.
+func replicaCheckpoints(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != replicaClonesDirName && !strings.HasSuffix(e.Name(), replicaCheckpointTempSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// openReplicaCheckpoint links the checkpoint into a directory of its own and opens it read only. The directory is
+// returned along with the database, for it to be removed once the database is closed.
+func openReplicaCheckpoint(checkpointsDir, checkpoint string) (string, engine.DB, error) {
+	clonesDir := filepath.Join(checkpointsDir, replicaClonesDirName)
+	if err := os.MkdirAll(clonesDir, params.BeaconIoConfig().ReadWriteExecutePermissions); err != nil {
+		return "", nil, err
+	}
+	clone, err := os.MkdirTemp(clonesDir, checkpoint+"-")
+	if err != nil {
+		return "", nil, err
+	}
+	db, err := cloneReplicaCheckpoint(filepath.Join(checkpointsDir, checkpoint), clone)
+	if err != nil {
+		if rmErr := os.RemoveAll(clone); rmErr != nil {
+			log.WithError(rmErr).Error("Could not remove replica checkpoint")
+		}
+		return "", nil, errors.Wrapf(err, "could not open replica checkpoint %s", checkpoint)
+	}
+	return clone, db, nil
+}
+
+func cloneReplicaCheckpoint(checkpoint, clone string) (engine.DB, error) {
+	entries, err := os.ReadDir(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		src, dst := filepath.Join(checkpoint, e.Name()), filepath.Join(clone, e.Name())
+		// Tables are never modified, so they are linked rather than copied when the file system allows it.
+		if filepath.Ext(e.Name()) == ".sst" && os.Link(src, dst) == nil {
+			continue
+		}
+		if err := file.CopyFile(src, dst); err != nil {
+			return nil, err
+		}
+	}
+	opts := engine.DefaultPebbleOptions
+	opts.ReadOnly = true
+	return engine.OpenPebble(clone, opts)
+}
+
+// pruneReplicaCheckpoints removes all but the newest checkpoints of the directory, the checkpoints which were not
+// completely written, and the links of replicas which stopped without removing them.
+func pruneReplicaCheckpoints(dir string) error {
+	checkpoints, err := replicaCheckpoints(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var remove []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), replicaCheckpointTempSuffix) {
+			remove = append(remove, filepath.Join(dir, e.Name()))
+		}
+	}
+	kept := make(map[string]bool)
+	for i, c := range checkpoints {
+		if i < len(checkpoints)-replicaCheckpointsKept {
+			remove = append(remove, filepath.Join(dir, c))
+		} else {
+			kept[c] = true
+		}
+	}
+	clones, err := os.ReadDir(filepath.Join(dir, replicaClonesDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, e := range clones {
+		// The links of a kept checkpoint may still be written by a replica before it opens them.
+		checkpoint, _, _ := strings.Cut(e.Name(), "-")
+		if kept[checkpoint] {
+			continue
+		}
+		clone := filepath.Join(dir, replicaClonesDirName, e.Name())
+		// A replica holds the lock of the checkpoint it has open.
+		lock, err := pebble.LockDirectory(clone, vfs.Default)
+		if err != nil {
+			continue
+		}
+		if err := lock.Close(); err != nil {
+			return err
+		}
+		remove = append(remove, clone)
+	}
+	for _, p := range remove {
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package kv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestStore_Replica(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	writer, err := NewKVStore(ctx, dir, WithBackend(engine.Pebble))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, writer.Close())
+	}()
+	saveHead := func(slot primitives.Slot) [32]byte {
+		b := util.NewBeaconBlock()
+		b.Block.Slot = slot
+		wsb, err := blocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		root, err := wsb.Block().HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, writer.SaveBlock(ctx, wsb))
+		require.NoError(t, writer.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: slot, Root: root[:]}))
+		require.NoError(t, writer.SaveHeadBlockRoot(ctx, root))
+		return root
+	}
+	headRoot := func(db *Store) [32]byte {
+		head, err := db.HeadBlock(ctx)
+		require.NoError(t, err)
+		root, err := head.Block().HashTreeRoot()
+		require.NoError(t, err)
+		return root
+	}
+	first := saveHead(1)
+
+	_, err = NewKVStore(ctx, dir, WithReplica())
+	require.ErrorContains(t, "no replica checkpoint", err)
+	require.NoError(t, writer.WriteReplicaCheckpoint())
+
+	// Replicas read the checkpoint while the writer keeps the database open.
+	replica, err := NewKVStore(ctx, dir, WithReplica())
+	require.NoError(t, err)
+	other, err := NewKVStore(ctx, dir, WithReplica())
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+	assert.Equal(t, true, replica.ReadOnly())
+	assert.Equal(t, engine.Pebble, replica.Backend())
+	assert.Equal(t, first, headRoot(replica))
+	require.NotNil(t, replica.SaveHeadBlockRoot(ctx, first), "a replica should not be written to")
+
+	// A replica does not see what was written after the checkpoint it opened.
+	second := saveHead(2)
+	require.NoError(t, replica.Refresh(ctx))
+	assert.Equal(t, false, replica.HasBlock(ctx, second))
+	require.NoError(t, writer.WriteReplicaCheckpoint())
+	require.NoError(t, replica.Refresh(ctx))
+	assert.Equal(t, true, replica.HasBlock(ctx, second))
+	assert.Equal(t, second, headRoot(replica))
+
+	// Old checkpoints are removed, while the one a replica has open stays linked.
+	for i := 0; i < replicaCheckpointsKept; i++ {
+		saveHead(primitives.Slot(3 + i))
+		require.NoError(t, writer.WriteReplicaCheckpoint())
+	}
+	checkpoints, err := replicaCheckpoints(filepath.Join(dir, ReplicaCheckpointsDirName))
+	require.NoError(t, err)
+	assert.Equal(t, replicaCheckpointsKept, len(checkpoints))
+	assert.Equal(t, true, replica.HasBlock(ctx, second))
+	clone := replica.replicaClone
+	_, err = os.Stat(clone)
+	require.NoError(t, err)
+	require.NoError(t, replica.Close())
+	_, err = os.Stat(clone)
+	assert.Equal(t, true, os.IsNotExist(err))
+
+	require.ErrorIs(t, writer.Refresh(ctx), errNotReplica)
+}
+
+func TestPruneReplicaCheckpoints_AbandonedClones(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writer, err := NewKVStore(ctx, dir, WithBackend(engine.Pebble))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, writer.Close())
+	}()
+	require.NoError(t, writer.WriteReplicaCheckpoint())
+	checkpointsDir := filepath.Join(dir, ReplicaCheckpointsDirName)
+	checkpoint, err := newestReplicaCheckpoint(checkpointsDir)
+	require.NoError(t, err)
+
+	// The links of a replica which stopped without removing them are removed once their checkpoint is.
+	abandoned, db, err := openReplicaCheckpoint(checkpointsDir, checkpoint)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	open, db, err := openReplicaCheckpoint(checkpointsDir, checkpoint)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	for i := 0; i < replicaCheckpointsKept; i++ {
+		require.NoError(t, writer.WriteReplicaCheckpoint())
+	}
+	_, err = os.Stat(abandoned)
+	assert.Equal(t, true, os.IsNotExist(err))
+	_, err = os.Stat(open)
+	require.NoError(t, err)
+}
+
+func TestStore_WriteReplicaCheckpoint_Bolt(t *testing.T) {
+	db := setupDB(t, engine.Bolt)
+	require.ErrorIs(t, db.WriteReplicaCheckpoint(), errReplicaCheckpointsBolt)
+}
+
+func TestStore_Replica_Bolt(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writer, err := NewKVStore(ctx, dir, WithBackend(engine.Bolt))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	_, err = NewKVStore(ctx, dir, WithReplica())
+	require.ErrorIs(t, err, errReplicaCheckpointsBolt)
+	require.ErrorContains(t, "uses the bolt backend", err)
+}
@@ -20,6 +20,9 @@ var (
 	// Light Client Updates Bucket
 	lightClientUpdatesBucket = []byte("light-client-updates")
 
+	// Finalized states stored as a hierarchy of snapshots and diffs, by slot.
+	stateDiffBucket = []byte("state-diff")
+
 	// Deprecated: This bucket was migrated in PR 6461. Do not use, except for migrations.
 	slotsHasObjectBucket = []byte("slots-has-objects")
 	// Deprecated: This bucket was migrated in PR 6461. Do not use, except for migrations.
@@ -40,6 +43,7 @@ var (
 	finalizedCheckpointKey     = []byte("finalized-checkpoint")
 	powchainDataKey            = []byte("powchain-data")
 	lastValidatedCheckpointKey = []byte("last-validated-checkpoint")
+	stateDiffExponentsKey      = []byte("state-diff-exponents")
 
 	// Below keys are used to identify objects are to be fork compatible.
 	// Objects that are only compatible with specific forks should be prefixed with such keys.
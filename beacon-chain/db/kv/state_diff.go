@@ -0,0 +1,273 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/statediff"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/time"
+)
+
+const (
+	stateDiffSnapshot byte = iota
+	stateDiffDiff
+)
+
+var errStateDiffDisabled = errors.New("states are not stored as diffs in this database")
+
+// stateDiffBases keeps the most recently stored or rebuilt state of every level of the state diff hierarchy.
+// States are usually stored in increasing slot order, so the base of the next state is almost always cached.
+type stateDiffBases struct {
+	sync.Mutex
+	states []state.BeaconState
+}
+
+func (c *stateDiffBases) get(level int, slot primitives.Slot) state.BeaconState {
+	c.Lock()
+	defer c.Unlock()
+	if level >= len(c.states) || c.states[level] == nil || c.states[level].Slot() != slot {
+		return nil
+	}
+	return c.states[level].Copy()
+}
+
+func (c *stateDiffBases) put(level int, st state.BeaconState) {
+	c.Lock()
+	defer c.Unlock()
+	if level < len(c.states) {
+		c.states[level] = st.Copy()
+	}
+}
+
+// WithStateDiffExponents stores finalized states as a hierarchy of snapshots and diffs with the given
+// exponents, see statediff.Hierarchy. The exponents are recorded in the database the first time they are used,
+// and can not be changed afterwards.
+func WithStateDiffExponents(exponents []uint8) KVStoreOption {
+	return func(s *Store) {
+		s.stateDiffExponents = exponents
+	}
+}
+
+// setupStateDiffHierarchy checks the configured state diff exponents against the ones the database was written
+// with. A database which already stores state diffs keeps doing so, even without the option.
+func (s *Store) setupStateDiffHierarchy() error {
+	var exponents []uint8
+	// A read only database can not store new exponents, which fails writing them.
+	update := s.db.Update
+	if s.readOnly {
+		update = s.db.View
+	}
+	if err := update(func(tx engine.Tx) error {
+		bkt := tx.Bucket(chainMetadataBucket)
+		stored := bkt.Get(stateDiffExponentsKey)
+		switch {
+		case stored == nil && s.stateDiffExponents == nil:
+			return nil
+		case stored == nil:
+			exponents = s.stateDiffExponents
+			return bkt.Put(stateDiffExponentsKey, exponents)
+		case s.stateDiffExponents != nil && !bytes.Equal(stored, s.stateDiffExponents):
+			return fmt.Errorf("database stores state diffs with exponents %v, can not use exponents %v", stored, s.stateDiffExponents)
+		default:
+			exponents = bytesutil.SafeCopyBytes(stored)
+			return nil
+		}
+	}); err != nil {
+		return err
+	}
+	if exponents == nil {
+		return nil
+	}
+	h, err := statediff.NewHierarchy(exponents)
+	if err != nil {
+		return err
+	}
+	s.stateDiffHierarchy = h
+	s.stateDiffBases = &stateDiffBases{states: make([]state.BeaconState, len(exponents))}
+	log.WithField("exponents", h.String()).Info("Storing finalized states as state diffs")
+	return nil
+}
+
+// StateDiffHierarchy returns the hierarchy finalized states are stored in, or nil if the database stores
+// finalized states at archive points instead.
+func (s *Store) StateDiffHierarchy() *statediff.Hierarchy {
+	return s.stateDiffHierarchy
+}
+
+// SaveStateDiff stores the state at its slot of the state diff hierarchy. The state is stored as a diff from the
+// state it is based on in the hierarchy, or in full if it is a snapshot or that state is not available.
+func (s *Store) SaveStateDiff(ctx context.Context, st state.ReadOnlyBeaconState) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveStateDiff")
+	defer span.End()
+	h := s.stateDiffHierarchy
+	if h == nil {
+		return errStateDiffDisabled
+	}
+	slot := st.Slot()
+	level, ok := h.Level(slot)
+	if !ok {
+		return fmt.Errorf("slot %d is not part of the state diff hierarchy", slot)
+	}
+
+	enc, err := s.encodeStateDiff(ctx, st)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Update(func(tx engine.Tx) error {
+		return tx.Bucket(stateDiffBucket).Put(bytesutil.SlotToBytesBigEndian(slot), enc)
+	}); err != nil {
+		return err
+	}
+	if bs, ok := st.(state.BeaconState); ok {
+		s.stateDiffBases.put(level, bs)
+	}
+	return nil
+}
+
+func (s *Store) encodeStateDiff(ctx context.Context, st state.ReadOnlyBeaconState) ([]byte, error) {
+	if baseSlot, ok := s.stateDiffHierarchy.Base(st.Slot()); ok {
+		base, err := s.StateDiff(ctx, baseSlot)
+		switch {
+		case errors.Is(err, ErrNotFoundState):
+			log.WithField("slot", st.Slot()).Debug("Base of state diff is not available, storing the state in full")
+		case err != nil:
+			return nil, errors.Wrapf(err, "could not get base state at slot %d", baseSlot)
+		default:
+			diff, err := statediff.Diff(base, st)
+			if err == nil {
+				enc := make([]byte, 9, 9+len(diff))
+				enc[0] = stateDiffDiff
+				binary.BigEndian.PutUint64(enc[1:], uint64(baseSlot))
+				return append(enc, diff...), nil
+			}
+			if !errors.Is(err, statediff.ErrVersionMismatch) {
+				return nil, errors.Wrapf(err, "could not compute state diff at slot %d", st.Slot())
+			}
+		}
+	}
+	enc, err := marshalState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{stateDiffSnapshot}, enc...), nil
+}
+
+// StateDiff returns the state stored at a slot of the state diff hierarchy, rebuilt from the snapshot and the
+// diffs it is based on. It returns ErrNotFoundState if no state is stored at the slot.
+func (s *Store) StateDiff(ctx context.Context, slot primitives.Slot) (state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.StateDiff")
+	defer span.End()
+	h := s.stateDiffHierarchy
+	if h == nil {
+		return nil, errStateDiffDisabled
+	}
+	startTime := time.Now()
+
+	// Collect the diffs down to a snapshot or a state which is still cached.
+	var st state.BeaconState
+	var diffs [][]byte
+	var levels []int
+	if err := s.db.View(func(tx engine.Tx) error {
+		bkt := tx.Bucket(stateDiffBucket)
+		for {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			level, ok := h.Level(slot)
+			if !ok {
+				return errors.Wrapf(ErrNotFoundState, "slot %d is not part of the state diff hierarchy", slot)
+			}
+			if cached := s.stateDiffBases.get(level, slot); cached != nil {
+				st = cached
+				return nil
+			}
+			enc := bkt.Get(bytesutil.SlotToBytesBigEndian(slot))
+			if len(enc) == 0 {
+				return errors.Wrapf(ErrNotFoundState, "no state diff at slot %d", slot)
+			}
+			switch enc[0] {
+			case stateDiffSnapshot:
+				var err error
+				st, err = decodeState(enc[1:], nil)
+				if err != nil {
+					return errors.Wrapf(err, "could not decode state snapshot at slot %d", slot)
+				}
+				s.stateDiffBases.put(level, st)
+				return nil
+			case stateDiffDiff:
+				if len(enc) < 9 {
+					return fmt.Errorf("invalid state diff at slot %d", slot)
+				}
+				diffs = append(diffs, bytesutil.SafeCopyBytes(enc[9:]))
+				levels = append(levels, level)
+				slot = primitives.Slot(binary.BigEndian.Uint64(enc[1:9]))
+			default:
+				return fmt.Errorf("unknown state diff kind %d at slot %d", enc[0], slot)
+			}
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	for i := len(diffs) - 1; i >= 0; i-- {
+		var err error
+		st, err = statediff.Apply(st, diffs[i])
+		if err != nil {
+			return nil, errors.Wrap(err, "could not apply state diff")
+		}
+		s.stateDiffBases.put(levels[i], st)
+	}
+	stateDiffReadingTime.Observe(float64(time.Since(startTime).Milliseconds()))
+	return st, nil
+}
+
+// HasStateDiff returns whether a state is stored at the slot of the state diff hierarchy.
+func (s *Store) HasStateDiff(ctx context.Context, slot primitives.Slot) bool {
+	_, span := trace.StartSpan(ctx, "BeaconDB.HasStateDiff")
+	defer span.End()
+	var exists bool
+	if err := s.db.View(func(tx engine.Tx) error {
+		exists = tx.Bucket(stateDiffBucket).Get(bytesutil.SlotToBytesBigEndian(slot)) != nil
+		return nil
+	}); err != nil { // This view never returns an error, but we'll handle anyway for sanity.
+		panic(err)
+	}
+	return exists
+}
+
+// HighestStateDiffSlot returns the highest slot at or below the given slot at which a state diff is stored. It
+// returns false if there is none.
+func (s *Store) HighestStateDiffSlot(ctx context.Context, slot primitives.Slot) (primitives.Slot, bool, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.HighestStateDiffSlot")
+	defer span.End()
+	var found primitives.Slot
+	var ok bool
+	err := s.db.View(func(tx engine.Tx) error {
+		found, ok = highestStateDiffSlot(tx, slot)
+		return nil
+	})
+	return found, ok, err
+}
+
+func highestStateDiffSlot(tx engine.Tx, slot primitives.Slot) (primitives.Slot, bool) {
+	c := tx.Bucket(stateDiffBucket).Cursor()
+	k, _ := c.Seek(bytesutil.SlotToBytesBigEndian(slot))
+	if k == nil {
+		k, _ = c.Last()
+	} else if bytesutil.BytesToSlotBigEndian(k) > slot {
+		k, _ = c.Prev()
+	}
+	if k == nil {
+		return 0, false
+	}
+	return bytesutil.BytesToSlotBigEndian(k), true
+}
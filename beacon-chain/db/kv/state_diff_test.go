@@ -0,0 +1,165 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func setupStateDiffDB(t testing.TB, backend engine.Backend, dir string, exponents []uint8) *Store {
+	db, err := NewKVStore(context.Background(), dir, WithBackend(backend), WithStateDiffExponents(exponents))
+	require.NoError(t, err, "Failed to instantiate DB")
+	t.Cleanup(func() {
+		require.NoError(t, db.Close(), "Failed to close database")
+	})
+	return db
+}
+
+// stateDiffStates returns states at every stored slot of a hierarchy with a state every 4 slots, each one
+// changing a few fields of the previous one.
+func stateDiffStates(t *testing.T, n int) []state.BeaconState {
+	st, _ := util.DeterministicGenesisStateDeneb(t, 32)
+	states := make([]state.BeaconState, n)
+	for i := range states {
+		st = st.Copy()
+		require.NoError(t, st.SetSlot(primitives.Slot(i*4)))
+		require.NoError(t, st.UpdateBalancesAtIndex(primitives.ValidatorIndex(i%32), uint64(i)))
+		require.NoError(t, st.UpdateRandaoMixesAtIndex(uint64(i), [32]byte{byte(i)}))
+		states[i] = st
+	}
+	return states
+}
+
+func stateDiffKind(t *testing.T, db *Store, slot primitives.Slot) byte {
+	var kind byte
+	require.NoError(t, db.db.View(func(tx engine.Tx) error {
+		enc := tx.Bucket(stateDiffBucket).Get(bytesutil.SlotToBytesBigEndian(slot))
+		require.NotEqual(t, 0, len(enc))
+		kind = enc[0]
+		return nil
+	}))
+	return kind
+}
+
+func requireSameState(t *testing.T, want, got state.BeaconState) {
+	wantRoot, err := want.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	gotRoot, err := got.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, wantRoot, gotRoot)
+}
+
+func TestStore_StateDiff(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		dir := t.TempDir()
+		db, err := NewKVStore(ctx, dir, WithBackend(backend), WithStateDiffExponents([]uint8{4, 3, 2}))
+		require.NoError(t, err)
+		states := stateDiffStates(t, 10)
+		for _, st := range states {
+			require.NoError(t, db.SaveStateDiff(ctx, st))
+		}
+		assert.Equal(t, stateDiffSnapshot, stateDiffKind(t, db, 0))
+		assert.Equal(t, stateDiffDiff, stateDiffKind(t, db, 4))
+		assert.Equal(t, stateDiffDiff, stateDiffKind(t, db, 8))
+		assert.Equal(t, stateDiffSnapshot, stateDiffKind(t, db, 16))
+		assert.Equal(t, stateDiffDiff, stateDiffKind(t, db, 28))
+
+		// Rebuild from the database alone.
+		require.NoError(t, db.Close())
+		db = setupStateDiffDB(t, backend, dir, nil)
+		require.NotNil(t, db.StateDiffHierarchy())
+		for i := len(states) - 1; i >= 0; i-- {
+			got, err := db.StateDiff(ctx, states[i].Slot())
+			require.NoError(t, err)
+			requireSameState(t, states[i], got)
+		}
+		assert.Equal(t, true, db.HasStateDiff(ctx, 36))
+		assert.Equal(t, false, db.HasStateDiff(ctx, 40))
+		_, err = db.StateDiff(ctx, 40)
+		require.ErrorIs(t, err, ErrNotFoundState)
+		_, err = db.StateDiff(ctx, 5)
+		require.ErrorIs(t, err, ErrNotFoundState)
+	})
+}
+
+func TestStore_SaveStateDiff_MissingBase(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupStateDiffDB(t, backend, t.TempDir(), []uint8{4, 2})
+		states := stateDiffStates(t, 3)
+		require.NoError(t, db.SaveStateDiff(ctx, states[2]))
+		assert.Equal(t, stateDiffSnapshot, stateDiffKind(t, db, 8))
+		got, err := db.StateDiff(ctx, 8)
+		require.NoError(t, err)
+		requireSameState(t, states[2], got)
+
+		st := states[1].Copy()
+		require.NoError(t, st.SetSlot(5))
+		require.ErrorContains(t, "not part of the state diff hierarchy", db.SaveStateDiff(ctx, st))
+	})
+}
+
+func TestStore_HighestStateDiffSlot(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupStateDiffDB(t, backend, t.TempDir(), []uint8{4, 2})
+		_, ok, err := db.HighestStateDiffSlot(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, false, ok)
+
+		states := stateDiffStates(t, 5)
+		for _, st := range states[1:4] {
+			require.NoError(t, db.SaveStateDiff(ctx, st))
+		}
+		tests := []struct {
+			slot  primitives.Slot
+			want  primitives.Slot
+			found bool
+		}{
+			{slot: 0, found: false},
+			{slot: 3, found: false},
+			{slot: 4, want: 4, found: true},
+			{slot: 7, want: 4, found: true},
+			{slot: 8, want: 8, found: true},
+			{slot: 100, want: 12, found: true},
+		}
+		for _, tt := range tests {
+			got, ok, err := db.HighestStateDiffSlot(ctx, tt.slot)
+			require.NoError(t, err)
+			assert.Equal(t, tt.found, ok, "slot %d", tt.slot)
+			assert.Equal(t, tt.want, got, "slot %d", tt.slot)
+		}
+	})
+}
+
+func TestStore_StateDiffExponents(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+
+		db, err := NewKVStore(ctx, t.TempDir(), WithBackend(backend))
+		require.NoError(t, err)
+		assert.Equal(t, true, db.StateDiffHierarchy() == nil)
+		require.ErrorIs(t, db.SaveStateDiff(ctx, stateDiffStates(t, 1)[0]), errStateDiffDisabled)
+		require.NoError(t, db.Close())
+
+		dir := t.TempDir()
+		db, err = NewKVStore(ctx, dir, WithBackend(backend), WithStateDiffExponents([]uint8{4, 2}))
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		_, err = NewKVStore(ctx, dir, WithBackend(backend), WithStateDiffExponents([]uint8{5, 2}))
+		require.ErrorContains(t, "can not use exponents", err)
+		_, err = NewKVStore(ctx, t.TempDir(), WithBackend(backend), WithStateDiffExponents([]uint8{2, 4}))
+		require.ErrorContains(t, "invalid state diff exponents", err)
+
+		db = setupStateDiffDB(t, backend, dir, nil)
+		assert.DeepEqual(t, []uint8{4, 2}, db.StateDiffHierarchy().Exponents())
+	})
+}
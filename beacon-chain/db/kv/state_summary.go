@@ -3,10 +3,10 @@ package kv
 import (
 	"context"
 
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
-	bolt "go.etcd.io/bbolt"
 )
 
 // SaveStateSummary saves a state summary object to the DB.
@@ -24,8 +24,11 @@ func (s *Store) SaveStateSummaries(ctx context.Context, summaries []*ethpb.State
 
 	// When we reach the state summary cache prune count,
 	// dump the cached state summaries to the DB.
+	// A read only database only holds summaries recovered from blocks, which are dropped instead.
 	if s.stateSummaryCache.len() >= stateSummaryCachePruneCount {
-		if err := s.saveCachedStateSummariesDB(ctx); err != nil {
+		if s.readOnly {
+			s.stateSummaryCache.clear()
+		} else if err := s.saveCachedStateSummariesDB(ctx); err != nil {
 			return err
 		}
 	}
@@ -46,7 +49,7 @@ func (s *Store) StateSummary(ctx context.Context, blockRoot [32]byte) (*ethpb.St
 		return s.stateSummaryCache.get(blockRoot), nil
 	}
 	var enc []byte
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		enc = tx.Bucket(stateSummaryBucket).Get(blockRoot[:])
 		return nil
 	}); err != nil {
@@ -72,7 +75,7 @@ func (s *Store) HasStateSummary(ctx context.Context, blockRoot [32]byte) bool {
 	}
 
 	var hasSummary bool
-	if err := s.db.View(func(tx *bolt.Tx) error {
+	if err := s.db.View(func(tx engine.Tx) error {
 		enc := tx.Bucket(stateSummaryBucket).Get(blockRoot[:])
 		hasSummary = len(enc) > 0
 		return nil
@@ -93,7 +96,7 @@ func (s *Store) saveCachedStateSummariesDB(ctx context.Context) error {
 		}
 		encs[i] = enc
 	}
-	if err := s.db.Update(func(tx *bolt.Tx) error {
+	if err := s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		for i, s := range summaries {
 			if err := bucket.Put(s.Root, encs[i]); err != nil {
@@ -111,7 +114,7 @@ func (s *Store) saveCachedStateSummariesDB(ctx context.Context) error {
 // deleteStateSummary deletes a state summary object from the db using input block root.
 func (s *Store) deleteStateSummary(blockRoot [32]byte) error {
 	s.stateSummaryCache.delete(blockRoot)
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.db.Update(func(tx engine.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		return bucket.Delete(blockRoot[:])
 	})
@@ -0,0 +1,500 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+// Kinds of the findings of Verify.
+const (
+	// FindingMissingBlock is a block of the finalized chain which is not stored, above the history which was
+	// pruned or not backfilled.
+	FindingMissingBlock = "missing_block"
+	// FindingCorruptBlock is a block which can not be decoded.
+	FindingCorruptBlock = "corrupt_block"
+	// FindingParentSlot is a block whose parent is not at a lower slot.
+	FindingParentSlot = "parent_slot_not_lower"
+	// FindingMissingSlotIndex is a finalized block missing from the block slot index.
+	FindingMissingSlotIndex = "missing_slot_index"
+	// FindingMissingParentIndex is a finalized block missing from the parent root index.
+	FindingMissingParentIndex = "missing_parent_root_index"
+	// FindingMissingFinalizedIndex is a finalized block missing from the finalized block roots index.
+	FindingMissingFinalizedIndex = "missing_finalized_index"
+	// FindingDanglingSlotIndex is an entry of the block slot index whose block is not stored.
+	FindingDanglingSlotIndex = "dangling_slot_index"
+	// FindingDanglingParentIndex is an entry of the parent root index whose block is not stored.
+	FindingDanglingParentIndex = "dangling_parent_root_index"
+	// FindingUnreplayableSummary is a state summary whose state can not be regenerated, as none of the
+	// ancestors of its block has a stored state or state diff.
+	FindingUnreplayableSummary = "unreplayable_state_summary"
+	// FindingDanglingArchivedPoint is an entry of the archived point index whose state is not stored.
+	FindingDanglingArchivedPoint = "dangling_archived_point_index"
+	// FindingMissingArchivedPoint is a stored state missing from the archived point index.
+	FindingMissingArchivedPoint = "missing_archived_point_index"
+	// FindingMissingBlobs is a block within the blob retention period whose blobs are not all in blob storage.
+	FindingMissingBlobs = "missing_blobs"
+)
+
+// BlobIndexer reports the indices of the blobs stored for a block root, see filesystem.BlobStorage.
+type BlobIndexer interface {
+	Indices(root [32]byte) ([fieldparams.MaxBlobsPerBlock]bool, error)
+}
+
+// VerifyFinding is an inconsistency found by Verify.
+type VerifyFinding struct {
+	Kind     string          `json:"kind"`
+	Slot     primitives.Slot `json:"slot"`
+	Root     string          `json:"root"`
+	Detail   string          `json:"detail,omitempty"`
+	Repaired bool            `json:"repaired"`
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	FinalizedEpoch        primitives.Epoch `json:"finalized_epoch"`
+	FinalizedRoot         string           `json:"finalized_root"`
+	EarliestAvailableSlot primitives.Slot  `json:"earliest_available_slot"`
+	FinalizedBlocks       int              `json:"finalized_blocks"`
+	StateSummaries        int              `json:"state_summaries"`
+	States                int              `json:"states"`
+	BlobBlocks            int              `json:"blob_blocks"`
+	Findings              []*VerifyFinding `json:"findings"`
+}
+
+// Unrepaired returns the number of findings which were not repaired.
+func (r *VerifyReport) Unrepaired() int {
+	n := 0
+	for _, f := range r.Findings {
+		if !f.Repaired {
+			n++
+		}
+	}
+	return n
+}
+
+// Verify checks the integrity of the database:
+//   - the finalized chain is walked from the finalized checkpoint through parent roots, down to genesis, the
+//     origin checkpoint or the history which was pruned or not backfilled, and each of its blocks must be stored
+//     and found in the slot, parent root and finalized block roots indices,
+//   - the entries of the slot and parent root indices must point to stored blocks,
+//   - the state of each state summary must be replayable from the stored state or state diff of an ancestor,
+//   - the entries of the archived point index must point to stored states, and each stored state must be indexed,
+//   - each block within the blob retention period must have the blobs its commitments require in blob storage,
+//     unless blobs is nil.
+//
+// With repair, the findings about the indices are fixed in a single transaction once the checks are done.
+func (s *Store) Verify(ctx context.Context, blobs BlobIndexer, repair bool) (*VerifyReport, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Verify")
+	defer span.End()
+
+	// A read only database has no cached state summaries, since they can not be saved.
+	if !s.readOnly {
+		if err := s.saveCachedStateSummariesDB(ctx); err != nil {
+			return nil, err
+		}
+	}
+	v := &verifier{
+		s:          s,
+		report:     &VerifyReport{Findings: make([]*VerifyFinding, 0)},
+		blocks:     make(map[[32]byte]*verifiedBlock),
+		replayable: make(map[[32]byte]bool),
+	}
+	if err := s.db.View(func(tx engine.Tx) error {
+		v.tx = tx
+		checks := []func(context.Context) error{
+			v.checkFinalizedChain,
+			v.checkBlockIndices,
+			v.checkStateSummaries,
+			v.checkArchivedPoints,
+		}
+		if blobs != nil {
+			checks = append(checks, func(ctx context.Context) error {
+				return v.checkBlobs(ctx, blobs)
+			})
+		}
+		for _, check := range checks {
+			if err := check(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "could not verify database")
+	}
+	if !repair || len(v.repairs) == 0 {
+		return v.report, nil
+	}
+	if s.readOnly {
+		return nil, errors.New("a read only database can not be repaired")
+	}
+
+	if err := s.db.Update(func(tx engine.Tx) error {
+		for _, r := range v.repairs {
+			if err := r.fix(tx); err != nil {
+				return errors.Wrapf(err, "could not repair %s of root %s", r.finding.Kind, r.finding.Root)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, r := range v.repairs {
+		r.finding.Repaired = true
+	}
+	return v.report, nil
+}
+
+type verifiedBlock struct {
+	slot   primitives.Slot
+	parent [32]byte
+}
+
+type verifyRepair struct {
+	finding *VerifyFinding
+	fix     func(tx engine.Tx) error
+}
+
+// verifier holds the state of a run of Verify, within its read transaction.
+type verifier struct {
+	s           *Store
+	tx          engine.Tx
+	report      *VerifyReport
+	repairs     []*verifyRepair
+	genesisRoot [32]byte
+	originRoot  [32]byte
+	earliest    primitives.Slot
+	// blocks caches the slot and parent of the blocks which were decoded, and nil for the missing ones.
+	blocks map[[32]byte]*verifiedBlock
+	// replayable caches whether the state of a block root can be regenerated.
+	replayable map[[32]byte]bool
+}
+
+func (v *verifier) add(kind string, slot primitives.Slot, root []byte, detail string) *VerifyFinding {
+	f := &VerifyFinding{Kind: kind, Slot: slot, Root: fmt.Sprintf("%#x", root), Detail: detail}
+	v.report.Findings = append(v.report.Findings, f)
+	return f
+}
+
+func (v *verifier) addRepairable(kind string, slot primitives.Slot, root []byte, fix func(tx engine.Tx) error) {
+	v.repairs = append(v.repairs, &verifyRepair{finding: v.add(kind, slot, root, ""), fix: fix})
+}
+
+// block returns the slot and parent of the stored block with the root, or nil if it is missing or corrupt.
+func (v *verifier) block(ctx context.Context, root [32]byte) *verifiedBlock {
+	if b, ok := v.blocks[root]; ok {
+		return b
+	}
+	var b *verifiedBlock
+	if enc := v.tx.Bucket(blocksBucket).Get(root[:]); enc != nil {
+		blk, err := unmarshalBlock(ctx, enc)
+		if err != nil {
+			v.add(FindingCorruptBlock, 0, root[:], err.Error())
+		} else {
+			b = &verifiedBlock{slot: blk.Block().Slot(), parent: blk.Block().ParentRoot()}
+		}
+	}
+	v.blocks[root] = b
+	return b
+}
+
+// historyMissingBelow returns whether the blocks below the slot were pruned or not backfilled, meaning that there
+// is no stored block from the earliest available slot up to it.
+func (v *verifier) historyMissingBelow(slot primitives.Slot) bool {
+	if v.earliest == 0 {
+		return false
+	}
+	k, _ := v.tx.Bucket(blockSlotIndicesBucket).Cursor().Seek(bytesutil.SlotToBytesBigEndian(v.earliest))
+	return k == nil || bytesutil.BytesToSlotBigEndian(k) >= slot
+}
+
+func (v *verifier) checkFinalizedChain(ctx context.Context) error {
+	tx := v.tx
+	blocksBkt := tx.Bucket(blocksBucket)
+	v.genesisRoot = bytesutil.ToBytes32(blocksBkt.Get(genesisBlockRootKey))
+	v.originRoot = bytesutil.ToBytes32(blocksBkt.Get(originCheckpointBlockRootKey))
+	earliest, err := earliestAvailableSlot(tx)
+	if err != nil {
+		return err
+	}
+	v.earliest = earliest
+	v.report.EarliestAvailableSlot = v.earliest
+
+	checkpoint := &ethpb.Checkpoint{Root: v.genesisRoot[:]}
+	if enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey); enc != nil {
+		if err := decode(ctx, enc, checkpoint); err != nil {
+			return err
+		}
+	}
+	v.report.FinalizedEpoch = checkpoint.Epoch
+	v.report.FinalizedRoot = fmt.Sprintf("%#x", checkpoint.Root)
+
+	finalizedBkt := tx.Bucket(finalizedBlockRootsIndexBucket)
+	root := bytesutil.ToBytes32(checkpoint.Root)
+	var child *verifiedBlock
+	var childRoot [32]byte
+	for root != params.BeaconConfig().ZeroHash {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		b := v.block(ctx, root)
+		if b == nil {
+			if child == nil || !v.historyMissingBelow(child.slot) {
+				v.add(FindingMissingBlock, 0, root[:], "")
+			}
+			return nil
+		}
+		v.report.FinalizedBlocks++
+		if child != nil && b.slot >= child.slot {
+			v.add(FindingParentSlot, child.slot, childRoot[:], fmt.Sprintf("parent %#x is at slot %d", root, b.slot))
+		}
+
+		r, slot, parent := root, b.slot, b.parent
+		for bucket, key := range blockIndices(slot, parent) {
+			if indexHas(tx.Bucket([]byte(bucket)).Get(key), r[:]) {
+				continue
+			}
+			kind := FindingMissingSlotIndex
+			if bucket == string(blockParentRootIndicesBucket) {
+				kind = FindingMissingParentIndex
+			}
+			indices := map[string][]byte{bucket: key}
+			v.addRepairable(kind, slot, r[:], func(tx engine.Tx) error {
+				return updateValueForIndices(ctx, indices, r[:], tx)
+			})
+		}
+		if r != v.genesisRoot && finalizedBkt.Get(r[:]) == nil {
+			container := &ethpb.FinalizedBlockRootContainer{ParentRoot: parent[:]}
+			if child != nil {
+				container.ChildRoot = bytesutil.SafeCopyBytes(childRoot[:])
+			}
+			v.addRepairable(FindingMissingFinalizedIndex, slot, r[:], func(tx engine.Tx) error {
+				enc, err := encode(ctx, container)
+				if err != nil {
+					return err
+				}
+				return tx.Bucket(finalizedBlockRootsIndexBucket).Put(r[:], enc)
+			})
+		}
+
+		if r == v.genesisRoot || r == v.originRoot {
+			return nil
+		}
+		child, childRoot = b, r
+		root = parent
+	}
+	return nil
+}
+
+// checkBlockIndices checks that the entries of the block slot and parent root indices point to stored blocks.
+func (v *verifier) checkBlockIndices(ctx context.Context) error {
+	blocksBkt := v.tx.Bucket(blocksBucket)
+	for _, bucket := range [][]byte{blockSlotIndicesBucket, blockParentRootIndicesBucket} {
+		kind := FindingDanglingSlotIndex
+		if bytes.Equal(bucket, blockParentRootIndicesBucket) {
+			kind = FindingDanglingParentIndex
+		}
+		c := v.tx.Bucket(bucket).Cursor()
+		for k, val := c.First(); k != nil; k, val = c.Next() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			for i := 0; i+32 <= len(val); i += 32 {
+				root := bytesutil.SafeCopyBytes(val[i : i+32])
+				if blocksBkt.Get(root) != nil {
+					continue
+				}
+				var slot primitives.Slot
+				if kind == FindingDanglingSlotIndex {
+					slot = bytesutil.BytesToSlotBigEndian(k)
+				}
+				indices := map[string][]byte{string(bucket): bytesutil.SafeCopyBytes(k)}
+				v.addRepairable(kind, slot, root, func(tx engine.Tx) error {
+					return deleteValueForIndices(ctx, indices, root, tx)
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// checkStateSummaries checks that the state of each state summary can be regenerated.
+func (v *verifier) checkStateSummaries(ctx context.Context) error {
+	c := v.tx.Bucket(stateSummaryBucket).Cursor()
+	for k, enc := c.First(); k != nil; k, enc = c.Next() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		summary := &ethpb.StateSummary{}
+		if err := decode(ctx, enc, summary); err != nil {
+			return errors.Wrapf(err, "could not decode state summary of root %#x", k)
+		}
+		v.report.StateSummaries++
+		if !v.isReplayable(ctx, bytesutil.ToBytes32(k)) {
+			v.add(FindingUnreplayableSummary, summary.Slot, k, "")
+		}
+	}
+	return nil
+}
+
+// isReplayable returns whether the state of the block root is stored, or can be regenerated by replaying blocks
+// on the stored state of an ancestor, or on a state diff below a finalized ancestor.
+func (v *verifier) isReplayable(ctx context.Context, root [32]byte) bool {
+	stateBkt := v.tx.Bucket(stateBucket)
+	finalizedBkt := v.tx.Bucket(finalizedBlockRootsIndexBucket)
+	var path [][32]byte
+	ok := false
+	for {
+		if known, seen := v.replayable[root]; seen {
+			ok = known
+			break
+		}
+		path = append(path, root)
+		if stateBkt.Get(root[:]) != nil {
+			ok = true
+			break
+		}
+		b := v.block(ctx, root)
+		if b == nil {
+			break
+		}
+		if v.s.stateDiffHierarchy != nil && (root == v.genesisRoot || finalizedBkt.Get(root[:]) != nil) {
+			if _, found := highestStateDiffSlot(v.tx, b.slot); found {
+				ok = true
+				break
+			}
+		}
+		root = b.parent
+	}
+	for _, r := range path {
+		v.replayable[r] = ok
+	}
+	return ok
+}
+
+// checkArchivedPoints checks that the entries of the archived point index point to stored states, and that each
+// stored state is indexed.
+func (v *verifier) checkArchivedPoints(ctx context.Context) error {
+	stateBkt := v.tx.Bucket(stateBucket)
+	indexBkt := v.tx.Bucket(stateSlotIndicesBucket)
+	c := indexBkt.Cursor()
+	for k, val := c.First(); k != nil; k, val = c.Next() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slot := bytesutil.BytesToSlotBigEndian(k)
+		for i := 0; i+32 <= len(val); i += 32 {
+			root := bytesutil.SafeCopyBytes(val[i : i+32])
+			if stateBkt.Get(root) != nil {
+				continue
+			}
+			indices := createStateIndicesFromStateSlot(ctx, slot)
+			v.addRepairable(FindingDanglingArchivedPoint, slot, root, func(tx engine.Tx) error {
+				return deleteValueForIndices(ctx, indices, root, tx)
+			})
+		}
+	}
+
+	var roots [][]byte
+	if err := stateBkt.ForEach(func(k, _ []byte) error {
+		if len(k) == 32 {
+			roots = append(roots, bytesutil.SafeCopyBytes(k))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		v.report.States++
+		slot, err := v.s.slotByBlockRoot(ctx, v.tx, root)
+		if err != nil {
+			return errors.Wrapf(err, "could not find the slot of the state of root %#x", root)
+		}
+		if indexHas(indexBkt.Get(bytesutil.SlotToBytesBigEndian(slot)), root) {
+			continue
+		}
+		indices := createStateIndicesFromStateSlot(ctx, slot)
+		v.addRepairable(FindingMissingArchivedPoint, slot, root, func(tx engine.Tx) error {
+			return updateValueForIndices(ctx, indices, root, tx)
+		})
+	}
+	return nil
+}
+
+// checkBlobs checks that the blocks within the blob retention period before the highest block have the blobs their
+// commitments require.
+func (v *verifier) checkBlobs(ctx context.Context, blobs BlobIndexer) error {
+	k, _ := v.tx.Bucket(blockSlotIndicesBucket).Cursor().Last()
+	if k == nil {
+		return nil
+	}
+	highest := bytesutil.BytesToSlotBigEndian(k)
+	retention, err := slots.EpochStart(params.BeaconConfig().MinEpochsForBlobsSidecarsRequest)
+	if err != nil {
+		return err
+	}
+	start := primitives.Slot(0)
+	if highest > retention {
+		start = highest - retention
+	}
+	blocksBkt := v.tx.Bucket(blocksBucket)
+	for _, sr := range rootsInSlotRange(v.tx, blockSlotIndicesBucket, start, highest+1) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		enc := blocksBkt.Get(sr.root)
+		if enc == nil {
+			continue
+		}
+		blk, err := unmarshalBlock(ctx, enc)
+		if err != nil || blk.Version() < version.Deneb {
+			continue
+		}
+		commitments, err := blk.Block().Body().BlobKzgCommitments()
+		if err != nil {
+			return err
+		}
+		if len(commitments) == 0 {
+			continue
+		}
+		v.report.BlobBlocks++
+		stored, err := blobs.Indices(bytesutil.ToBytes32(sr.root))
+		if err != nil {
+			return errors.Wrapf(err, "could not list the blobs of root %#x", sr.root)
+		}
+		var missing []int
+		for i := range commitments {
+			if i >= len(stored) || !stored[i] {
+				missing = append(missing, i)
+			}
+		}
+		if len(missing) > 0 {
+			v.add(FindingMissingBlobs, sr.slot, sr.root, fmt.Sprintf("missing blob indices %v of %d", missing, len(commitments)))
+		}
+	}
+	return nil
+}
+
+// indexHas returns whether the concatenated roots of an index entry hold the root.
+func indexHas(values, root []byte) bool {
+	for i := 0; i+32 <= len(values); i += 32 {
+		if bytes.Equal(values[i:i+32], root) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package kv
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+type testBlobIndexer map[[32]byte][fieldparams.MaxBlobsPerBlock]bool
+
+func (b testBlobIndexer) Indices(root [32]byte) ([fieldparams.MaxBlobsPerBlock]bool, error) {
+	return b[root], nil
+}
+
+func findingKinds(r *VerifyReport) []string {
+	kinds := make([]string, len(r.Findings))
+	for i, f := range r.Findings {
+		kinds[i] = f.Kind
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func TestStore_Verify(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupDB(t, backend)
+
+		genesis, err := blocks.NewSignedBeaconBlock(util.NewBeaconBlock())
+		require.NoError(t, err)
+		require.NoError(t, db.SaveBlock(ctx, genesis))
+		genesisRoot, err := genesis.Block().HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, db.SaveGenesisBlockRoot(ctx, genesisRoot))
+		st, err := util.NewBeaconState()
+		require.NoError(t, err)
+		require.NoError(t, db.SaveState(ctx, st, genesisRoot))
+		require.NoError(t, db.SaveStateSummary(ctx, &ethpb.StateSummary{Root: genesisRoot[:]}))
+
+		// Genesis <- blocks at slots 1 to 8 <- block with two blobs at slot 9, finalized at slot 8.
+		blks := makeBlocks(t, 0, 8, genesisRoot)
+		roots := make([][32]byte, len(blks))
+		for i, b := range blks {
+			roots[i], err = b.Block().HashTreeRoot()
+			require.NoError(t, err)
+			require.NoError(t, db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: b.Block().Slot(), Root: roots[i][:]}))
+		}
+		require.NoError(t, db.SaveBlocks(ctx, blks))
+		blobBlock, _ := util.GenerateTestDenebBlockWithSidecar(t, roots[7], 9, 2)
+		require.NoError(t, db.SaveBlocks(ctx, []interfaces.ReadOnlySignedBeaconBlock{blobBlock}))
+		require.NoError(t, db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: 9, Root: blobBlock.RootSlice()}))
+		require.NoError(t, db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: roots[7][:]}))
+		blobs := testBlobIndexer{blobBlock.Root(): {true, true}}
+
+		report, err := db.Verify(ctx, blobs, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, len(report.Findings))
+		assert.Equal(t, primitives.Epoch(1), report.FinalizedEpoch)
+		assert.Equal(t, 9, report.FinalizedBlocks)
+		assert.Equal(t, 10, report.StateSummaries)
+		assert.Equal(t, 1, report.States)
+		assert.Equal(t, 1, report.BlobBlocks)
+
+		// A state whose archived point index entry is lost.
+		st, err = util.NewBeaconState()
+		require.NoError(t, err)
+		require.NoError(t, st.SetSlot(2))
+		require.NoError(t, db.SaveState(ctx, st, roots[1]))
+		require.NoError(t, db.db.Update(func(tx engine.Tx) error {
+			slotIdx := map[string][]byte{string(blockSlotIndicesBucket): bytesutil.SlotToBytesBigEndian(4)}
+			if err := deleteValueForIndices(ctx, slotIdx, roots[3][:], tx); err != nil {
+				return err
+			}
+			if err := tx.Bucket(finalizedBlockRootsIndexBucket).Delete(roots[5][:]); err != nil {
+				return err
+			}
+			parentIdx := map[string][]byte{string(blockParentRootIndicesBucket): genesisRoot[:]}
+			if err := updateValueForIndices(ctx, parentIdx, bytesutil.PadTo([]byte{'p'}, 32), tx); err != nil {
+				return err
+			}
+			if err := updateValueForIndices(ctx, createStateIndicesFromStateSlot(ctx, 3), bytesutil.PadTo([]byte{'s'}, 32), tx); err != nil {
+				return err
+			}
+			return deleteValueForIndices(ctx, createStateIndicesFromStateSlot(ctx, 2), roots[1][:], tx)
+		}))
+		require.NoError(t, db.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: 20, Root: bytesutil.PadTo([]byte{'x'}, 32)}))
+		blobs[blobBlock.Root()] = [fieldparams.MaxBlobsPerBlock]bool{true}
+
+		want := []string{
+			FindingDanglingArchivedPoint,
+			FindingDanglingParentIndex,
+			FindingMissingArchivedPoint,
+			FindingMissingBlobs,
+			FindingMissingFinalizedIndex,
+			FindingMissingSlotIndex,
+			FindingUnreplayableSummary,
+		}
+		report, err = db.Verify(ctx, blobs, false)
+		require.NoError(t, err)
+		require.DeepEqual(t, want, findingKinds(report))
+		assert.Equal(t, len(want), report.Unrepaired())
+
+		report, err = db.Verify(ctx, blobs, true)
+		require.NoError(t, err)
+		require.DeepEqual(t, want, findingKinds(report))
+		assert.Equal(t, 2, report.Unrepaired())
+		for _, f := range report.Findings {
+			repairable := f.Kind != FindingMissingBlobs && f.Kind != FindingUnreplayableSummary
+			assert.Equal(t, repairable, f.Repaired, f.Kind)
+		}
+		assert.Equal(t, true, db.IsFinalizedBlock(ctx, roots[5]))
+		_, slotRoots, err := db.BlockRootsBySlot(ctx, 4)
+		require.NoError(t, err)
+		assert.DeepEqual(t, [][32]byte{roots[3]}, slotRoots)
+		assert.Equal(t, roots[1], db.ArchivedPointRoot(ctx, 2))
+
+		report, err = db.Verify(ctx, blobs, false)
+		require.NoError(t, err)
+		require.DeepEqual(t, []string{FindingMissingBlobs, FindingUnreplayableSummary}, findingKinds(report))
+	})
+}
+
+func TestStore_VerifyPrunedHistory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend engine.Backend) {
+		ctx := context.Background()
+		db := setupDB(t, backend)
+
+		// Blocks at slots 100 to 103, finalized at slot 103, whose history was pruned below slot 96.
+		blks := makeBlocks(t, 99, 4, bytesutil.ToBytes32([]byte{'p'}))
+		require.NoError(t, db.SaveBlocks(ctx, blks))
+		head, err := blks[3].Block().HashTreeRoot()
+		require.NoError(t, err)
+		missing, err := blks[2].Block().HashTreeRoot()
+		require.NoError(t, err)
+		st, err := util.NewBeaconState()
+		require.NoError(t, err)
+		require.NoError(t, st.SetSlot(blks[3].Block().Slot()))
+		require.NoError(t, db.SaveState(ctx, st, head))
+		require.NoError(t, db.db.Update(func(tx engine.Tx) error {
+			enc, err := encode(ctx, &ethpb.Checkpoint{Epoch: 3, Root: head[:]})
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(checkpointBucket).Put(finalizedCheckpointKey, enc); err != nil {
+				return err
+			}
+			return tx.Bucket(chainMetadataBucket).Put(historyPrunedBeforeKey, bytesutil.SlotToBytesBigEndian(96))
+		}))
+
+		report, err := db.Verify(ctx, nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, primitives.Slot(96), report.EarliestAvailableSlot)
+		assert.Equal(t, 4, report.FinalizedBlocks)
+		require.DeepEqual(t, []string{
+			FindingMissingFinalizedIndex,
+			FindingMissingFinalizedIndex,
+			FindingMissingFinalizedIndex,
+			FindingMissingFinalizedIndex,
+		}, findingKinds(report))
+		assert.Equal(t, 0, report.Unrepaired())
+		report, err = db.Verify(ctx, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, len(report.Findings))
+
+		// A missing block above the pruned history is reported.
+		require.NoError(t, db.db.Update(func(tx engine.Tx) error {
+			return tx.Bucket(blocksBucket).Delete(missing[:])
+		}))
+		report, err = db.Verify(ctx, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.FinalizedBlocks)
+		require.DeepEqual(t, []string{
+			FindingDanglingParentIndex,
+			FindingDanglingSlotIndex,
+			FindingMissingBlock,
+		}, findingKinds(report))
+	})
+}
+
+func TestStore_Verify_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writer, err := NewKVStore(ctx, dir)
+	require.NoError(t, err)
+	genesis, err := blocks.NewSignedBeaconBlock(util.NewBeaconBlock())
+	require.NoError(t, err)
+	require.NoError(t, writer.SaveBlock(ctx, genesis))
+	genesisRoot, err := genesis.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, writer.SaveGenesisBlockRoot(ctx, genesisRoot))
+	require.NoError(t, writer.Close())
+
+	// A database which is not repaired is verified without opening it for writes.
+	db, err := NewKVStore(ctx, dir, WithReadOnly())
+	require.NoError(t, err)
+	report, err := db.Verify(ctx, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.FinalizedBlocks)
+	require.NoError(t, db.Close())
+}
@@ -1,22 +1,39 @@
 package db
 
 import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path"
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filters"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
 	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	"github.com/prysmaticlabs/prysm/v5/io/file"
 	"github.com/prysmaticlabs/prysm/v5/io/prompt"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
 const dbExistsYesNoPrompt = "A database file already exists in the target directory. " +
 	"Are you sure that you want to overwrite it? [y/n]"
 
-// Restore a beacon chain database.
+// Restore a beacon chain database, either from a backup file or from a snapshot archive written by WriteSnapshot.
+// The blobs of a snapshot archive are restored to the blobs directory of the target directory, unless another
+// directory is given.
 func Restore(cliCtx *cli.Context) error {
 	sourceFile := cliCtx.String(cmd.RestoreSourceFileFlag.Name)
 	targetDir := cliCtx.String(cmd.RestoreTargetDirFlag.Name)
@@ -41,6 +58,26 @@ func Restore(cliCtx *cli.Context) error {
 			return nil
 		}
 	}
+	isSnapshot, err := isSnapshotArchive(sourceFile)
+	if err != nil {
+		return err
+	}
+	if isSnapshot {
+		blobDir := cliCtx.String(cmd.RestoreBlobTargetDirFlag.Name)
+		if blobDir == "" {
+			blobDir = path.Join(targetDir, snapshotBlobsDir)
+		}
+		manifest, err := RestoreSnapshot(cliCtx.Context, sourceFile, targetDir, blobDir)
+		if err != nil {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"headSlot":       manifest.HeadSlot,
+			"finalizedEpoch": manifest.FinalizedEpoch,
+			"blobs":          manifest.Blobs,
+		}).Info("Restore completed successfully")
+		return nil
+	}
 	if err := file.MkdirAll(restoreDir); err != nil {
 		return err
 	}
@@ -51,3 +88,276 @@ func Restore(cliCtx *cli.Context) error {
 	log.Info("Restore completed successfully")
 	return nil
 }
+
+// RestoreSnapshot restores a snapshot archive written by WriteSnapshot: the database to the database directory of
+// the target directory, and the blobs to the blob directory. The archive is extracted next to them first, and only
+// moved in place once validated: its files must match the checksums of the manifest, the database must hold the
+// head and finalized checkpoint of the manifest, and the blobs must hold every blob the blocks within the blob
+// retention period before the head commit to. A database already in the target directory is replaced.
+func RestoreSnapshot(ctx context.Context, archivePath, targetDir, blobDir string) (*SnapshotManifest, error) {
+	restoreDir := path.Join(targetDir, kv.BeaconNodeDbDirName)
+	dbStage := restoreDir + stagingSuffix
+	blobStage := path.Clean(blobDir) + stagingSuffix
+	for _, p := range []string{dbStage, blobStage} {
+		if err := os.RemoveAll(p); err != nil {
+			return nil, err
+		}
+		defer func(p string) {
+			if err := os.RemoveAll(p); err != nil {
+				log.WithError(err).WithField("path", p).Error("Could not remove extracted snapshot")
+			}
+		}(p)
+	}
+	if err := file.MkdirAll(dbStage); err != nil {
+		return nil, err
+	}
+	blobs, err := filesystem.NewBlobStorage(filesystem.WithBasePath(blobStage), filesystem.WithSaveFsync(true))
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("archive", archivePath).Info("Extracting snapshot")
+	manifest, err := extractSnapshot(archivePath, dbStage, blobs)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not extract snapshot")
+	}
+	if err := validateSnapshot(ctx, manifest, dbStage, blobs); err != nil {
+		return nil, errors.Wrap(err, "invalid snapshot")
+	}
+
+	if err := file.MkdirAll(restoreDir); err != nil {
+		return nil, err
+	}
+	for _, b := range engine.Backends {
+		if err := os.RemoveAll(kv.StoreDataPath(restoreDir, b)); err != nil {
+			return nil, errors.Wrap(err, "could not remove existing database")
+		}
+	}
+	if err := os.Rename(path.Join(dbStage, kv.DatabaseFileName), path.Join(restoreDir, kv.DatabaseFileName)); err != nil {
+		return nil, errors.Wrap(err, "could not move restored database in place")
+	}
+	if err := file.MkdirAll(blobDir); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(blobStage)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dst := path.Join(blobDir, e.Name())
+		if err := os.RemoveAll(dst); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(path.Join(blobStage, e.Name()), dst); err != nil {
+			return nil, errors.Wrap(err, "could not move restored blobs in place")
+		}
+	}
+	return manifest, nil
+}
+
+// stagingSuffix is appended to the paths a snapshot archive is extracted to before it is restored.
+const stagingSuffix = ".restore"
+
+// isSnapshotArchive returns whether the file is a tar archive, rather than a database backup file.
+func isSnapshotArchive(filePath string) (bool, error) {
+	f, err := os.Open(filePath) // #nosec G304
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close file")
+		}
+	}()
+	_, err = tar.NewReader(f).Next()
+	return err == nil, nil
+}
+
+// extractSnapshot extracts the database of the archive to the directory and its blobs to blob storage, and checks
+// them against the checksums of the manifest.
+func extractSnapshot(archivePath, dbDir string, blobs *filesystem.BlobStorage) (*SnapshotManifest, error) {
+	f, err := os.Open(archivePath) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close snapshot archive")
+		}
+	}()
+
+	var manifest *SnapshotManifest
+	extracted := make(map[string]*SnapshotFile)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			return nil, fmt.Errorf("unexpected file %s after the manifest", hdr.Name)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected entry %s which is not a regular file", hdr.Name)
+		}
+		if hdr.Name == snapshotManifestName {
+			manifest = &SnapshotManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, errors.Wrap(err, "could not decode manifest")
+			}
+			continue
+		}
+		if _, ok := extracted[hdr.Name]; ok {
+			return nil, fmt.Errorf("duplicate file %s", hdr.Name)
+		}
+		h := sha256.New()
+		r := io.TeeReader(tr, h)
+		switch {
+		case hdr.Name == kv.DatabaseFileName:
+			err = extractFile(r, path.Join(dbDir, kv.DatabaseFileName))
+		case strings.HasPrefix(hdr.Name, snapshotBlobsDir+"/"):
+			err = blobs.Import(strings.TrimPrefix(hdr.Name, snapshotBlobsDir+"/"), r)
+		default:
+			err = fmt.Errorf("unexpected file %s", hdr.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		extracted[hdr.Name] = &SnapshotFile{Name: hdr.Name, Size: hdr.Size, SHA256: checksum(h)}
+	}
+
+	if manifest == nil {
+		return nil, errors.New("archive has no manifest")
+	}
+	if manifest.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", manifest.Version)
+	}
+	if len(manifest.Files) != len(extracted) {
+		return nil, fmt.Errorf("manifest lists %d files, archive holds %d", len(manifest.Files), len(extracted))
+	}
+	for _, want := range manifest.Files {
+		got, ok := extracted[want.Name]
+		if !ok {
+			return nil, fmt.Errorf("file %s of the manifest is missing", want.Name)
+		}
+		if *got != *want {
+			return nil, fmt.Errorf("file %s does not match the manifest", want.Name)
+		}
+	}
+	if _, ok := extracted[kv.DatabaseFileName]; !ok {
+		return nil, errors.New("archive has no database")
+	}
+	return manifest, nil
+}
+
+func extractFile(r io.Reader, filePath string) error {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, params.BeaconIoConfig().ReadWritePermissions) // #nosec G304
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close file")
+		}
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close file")
+		}
+		return err
+	}
+	return f.Close()
+}
+
+// validateSnapshot checks that the extracted database holds the head and finalized checkpoint of the manifest,
+// and that blob storage holds the blobs of the blocks within the blob retention period before the head.
+func validateSnapshot(ctx context.Context, manifest *SnapshotManifest, dbDir string, blobs *filesystem.BlobStorage) error {
+	store, err := kv.NewKVStore(ctx, dbDir)
+	if err != nil {
+		return errors.Wrap(err, "could not open database")
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+
+	finalized, err := store.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if finalized.Epoch != manifest.FinalizedEpoch || fmt.Sprintf("%#x", finalized.Root) != manifest.FinalizedRoot {
+		return fmt.Errorf("database is finalized at epoch %d root %#x, manifest at epoch %d root %s",
+			finalized.Epoch, finalized.Root, manifest.FinalizedEpoch, manifest.FinalizedRoot)
+	}
+	finalizedRoot := bytesutil.ToBytes32(finalized.Root)
+	if finalizedRoot != params.BeaconConfig().ZeroHash && !store.HasBlock(ctx, finalizedRoot) {
+		return fmt.Errorf("database does not hold the finalized block %#x", finalizedRoot)
+	}
+	head, err := store.HeadBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if head == nil || head.IsNil() {
+		if manifest.HeadSlot != 0 {
+			return fmt.Errorf("database does not hold the head block %s", manifest.HeadRoot)
+		}
+		return nil
+	}
+	headRoot, err := head.Block().HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if head.Block().Slot() != manifest.HeadSlot || fmt.Sprintf("%#x", headRoot) != manifest.HeadRoot {
+		return fmt.Errorf("database head is block %#x at slot %d, manifest head is block %s at slot %d",
+			headRoot, head.Block().Slot(), manifest.HeadRoot, manifest.HeadSlot)
+	}
+
+	retention, err := slots.EpochStart(params.BeaconConfig().MinEpochsForBlobsSidecarsRequest)
+	if err != nil {
+		return err
+	}
+	start := primitives.Slot(0)
+	if manifest.HeadSlot > retention {
+		start = manifest.HeadSlot - retention
+	}
+	blks, roots, err := store.Blocks(ctx, filters.NewFilter().SetStartSlot(start).SetEndSlot(manifest.HeadSlot))
+	if err != nil {
+		return err
+	}
+	missing := 0
+	for i, b := range blks {
+		if b.Version() < version.Deneb {
+			continue
+		}
+		commitments, err := b.Block().Body().BlobKzgCommitments()
+		if err != nil {
+			return err
+		}
+		indices, err := blobs.Indices(roots[i])
+		if err != nil {
+			return err
+		}
+		for idx := range commitments {
+			if idx >= len(indices) || !indices[idx] {
+				log.WithFields(logrus.Fields{
+					"slot":  b.Block().Slot(),
+					"root":  fmt.Sprintf("%#x", roots[i]),
+					"index": idx,
+				}).Error("Snapshot is missing a blob")
+				missing++
+			}
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf("%d blobs of the blocks within the blob retention period are missing", missing)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// snapshotVersion is the version of the snapshot archive format.
+	snapshotVersion = 1
+	// snapshotManifestName is the name of the manifest in a snapshot archive, which is its last file.
+	snapshotManifestName = "manifest.json"
+	// snapshotBlobsDir is the directory of the blob files in a snapshot archive.
+	snapshotBlobsDir = "blobs"
+)
+
+// Snapshotter is a database which can write a copy of itself as of a single point in time, see kv.Store.Snapshot.
+type Snapshotter interface {
+	Snapshot(ctx context.Context, path string) (*iface.SnapshotInfo, error)
+}
+
+// SnapshotManifest describes the content of a snapshot archive: the chain the database was at and the checksum of
+// each of the other files of the archive.
+type SnapshotManifest struct {
+	Version        int              `json:"version"`
+	HeadSlot       primitives.Slot  `json:"head_slot"`
+	HeadRoot       string           `json:"head_root"`
+	FinalizedEpoch primitives.Epoch `json:"finalized_epoch"`
+	FinalizedRoot  string           `json:"finalized_root"`
+	Blobs          int              `json:"blobs"`
+	Files          []*SnapshotFile  `json:"files"`
+}
+
+// SnapshotFile is a file of a snapshot archive.
+type SnapshotFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// WriteSnapshot writes a snapshot archive of a beacon node to the output directory, and returns its path and
+// manifest. The archive is a tar file holding a consistent copy of the database, the blob files of storage and
+// a manifest with the head and finalized checkpoint of the copy. Blobs are exported after the database is copied,
+// and pruning is held back from before the copy starts, so the archive holds the blobs of every block of the copy
+// which storage kept.
+// Example for a snapshot at slot 345: $OUTPUTDIR/prysm_snapshot_at_slot_0000345.tar
+func WriteSnapshot(
+	ctx context.Context,
+	d Snapshotter,
+	blobs *filesystem.BlobStorage,
+	outputDir string,
+) (string, *SnapshotManifest, error) {
+	if err := file.MkdirAll(outputDir); err != nil {
+		return "", nil, err
+	}
+	dbPath := path.Join(outputDir, kv.DatabaseFileName+".snapshot")
+	defer func() {
+		if err := os.Remove(dbPath); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).Error("Could not remove database snapshot")
+		}
+	}()
+	log.WithField("path", outputDir).Info("Writing snapshot")
+	if blobs != nil {
+		resumePruning := blobs.PausePruning()
+		defer resumePruning()
+	}
+	info, err := d.Snapshot(ctx, dbPath)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "could not snapshot database")
+	}
+	manifest := &SnapshotManifest{
+		Version:        snapshotVersion,
+		HeadSlot:       info.HeadSlot,
+		HeadRoot:       fmt.Sprintf("%#x", info.HeadRoot),
+		FinalizedEpoch: info.Finalized.Epoch,
+		FinalizedRoot:  fmt.Sprintf("%#x", info.Finalized.Root),
+	}
+
+	archivePath := path.Join(outputDir, fmt.Sprintf("prysm_snapshot_at_slot_%07d.tar", info.HeadSlot))
+	partPath := archivePath + ".part"
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, params.BeaconIoConfig().ReadWritePermissions)
+	if err != nil {
+		return "", nil, err
+	}
+	moved := false
+	defer func() {
+		if moved {
+			return
+		}
+		if err := f.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			log.WithError(err).Error("Could not close snapshot archive")
+		}
+		if err := os.Remove(partPath); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).Error("Could not remove partial snapshot archive")
+		}
+	}()
+
+	tw := tar.NewWriter(f)
+	if err := writeSnapshotFile(tw, manifest, kv.DatabaseFileName, dbPath); err != nil {
+		return "", nil, errors.Wrap(err, "could not archive database snapshot")
+	}
+	if blobs != nil {
+		if err := blobs.Export(ctx, func(name string, size int64, r io.Reader) error {
+			manifest.Blobs++
+			return writeSnapshotEntry(tw, manifest, path.Join(snapshotBlobsDir, name), size, r)
+		}); err != nil {
+			return "", nil, errors.Wrap(err, "could not archive blobs")
+		}
+	}
+	enc, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: snapshotManifestName, Mode: 0600, Size: int64(len(enc))}); err != nil {
+		return "", nil, err
+	}
+	if _, err := tw.Write(enc); err != nil {
+		return "", nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return "", nil, err
+	}
+	if err := f.Sync(); err != nil {
+		return "", nil, err
+	}
+	if err := f.Close(); err != nil {
+		return "", nil, err
+	}
+	if err := os.Rename(partPath, archivePath); err != nil {
+		return "", nil, err
+	}
+	moved = true
+	log.WithFields(logrus.Fields{
+		"path":           archivePath,
+		"headSlot":       manifest.HeadSlot,
+		"finalizedEpoch": manifest.FinalizedEpoch,
+		"blobs":          manifest.Blobs,
+	}).Info("Wrote snapshot")
+	return archivePath, manifest, nil
+}
+
+func writeSnapshotFile(tw *tar.Writer, manifest *SnapshotManifest, name, filePath string) error {
+	f, err := os.Open(filePath) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close file")
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return writeSnapshotEntry(tw, manifest, name, info.Size(), f)
+}
+
+// writeSnapshotEntry writes a file to the archive and records its checksum in the manifest.
+func writeSnapshotEntry(tw *tar.Writer, manifest *SnapshotManifest, name string, size int64, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: size}); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(tw, io.TeeReader(r, h)); err != nil {
+		return errors.Wrapf(err, "could not write %s", name)
+	}
+	manifest.Files = append(manifest.Files, &SnapshotFile{Name: name, Size: size, SHA256: checksum(h)})
+	return nil
+}
+
+func checksum(h hash.Hash) string {
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
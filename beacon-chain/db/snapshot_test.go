@@ -0,0 +1,201 @@
+package db
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+// writeTestSnapshot writes the snapshot of a database whose head is a block with two blobs.
+func writeTestSnapshot(t *testing.T) (string, *SnapshotManifest, [32]byte) {
+	ctx := context.Background()
+	store, err := kv.NewKVStore(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	genesis := util.NewBeaconBlock()
+	wsb, err := blocks.NewSignedBeaconBlock(genesis)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveBlock(ctx, wsb))
+	genesisRoot, err := genesis.Block.HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveGenesisBlockRoot(ctx, genesisRoot))
+	require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Root: genesisRoot[:]}))
+	require.NoError(t, store.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Root: genesisRoot[:]}))
+
+	head, sidecars := util.GenerateTestDenebBlockWithSidecar(t, genesisRoot, 5, 2)
+	require.NoError(t, store.SaveBlock(ctx, head))
+	require.NoError(t, store.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: 5, Root: head.RootSlice()}))
+	require.NoError(t, store.SaveHeadBlockRoot(ctx, head.Root()))
+	blobs, err := filesystem.NewBlobStorage(filesystem.WithBasePath(t.TempDir()))
+	require.NoError(t, err)
+	verified, err := verification.BlobSidecarSliceNoop(sidecars)
+	require.NoError(t, err)
+	for _, sc := range verified {
+		require.NoError(t, blobs.Save(sc))
+	}
+
+	archivePath, manifest, err := WriteSnapshot(ctx, store, blobs, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	return archivePath, manifest, head.Root()
+}
+
+// rewriteArchive copies a snapshot archive, replacing the content of its files by the one fn returns, or dropping
+// them if it returns nil.
+func rewriteArchive(t *testing.T, src string, fn func(name string, content []byte) []byte) string {
+	in, err := os.Open(src)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, in.Close())
+	}()
+	dst := path.Join(t.TempDir(), "rewritten.tar")
+	out, err := os.Create(dst)
+	require.NoError(t, err)
+	tr, tw := tar.NewReader(in), tar.NewWriter(out)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		content = fn(hdr.Name, content)
+		if content == nil {
+			continue
+		}
+		hdr.Size = int64(len(content))
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, out.Close())
+	return dst
+}
+
+func TestSnapshot_WriteRestore(t *testing.T) {
+	ctx := context.Background()
+	archivePath, manifest, headRoot := writeTestSnapshot(t)
+	assert.Equal(t, 2, manifest.Blobs)
+	assert.Equal(t, 3, len(manifest.Files))
+	assert.Equal(t, fmt.Sprintf("%#x", headRoot), manifest.HeadRoot)
+	assert.Equal(t, "prysm_snapshot_at_slot_0000005.tar", path.Base(archivePath))
+	isSnapshot, err := isSnapshotArchive(archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, true, isSnapshot)
+
+	targetDir := t.TempDir()
+	blobDir := path.Join(targetDir, snapshotBlobsDir)
+	restored, err := RestoreSnapshot(ctx, archivePath, targetDir, blobDir)
+	require.NoError(t, err)
+	assert.DeepEqual(t, manifest, restored)
+
+	store, err := kv.NewKVStore(ctx, path.Join(targetDir, kv.BeaconNodeDbDirName))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+	assert.Equal(t, true, store.HasBlock(ctx, headRoot))
+	blobs, err := filesystem.NewBlobStorage(filesystem.WithBasePath(blobDir))
+	require.NoError(t, err)
+	indices, err := blobs.Indices(headRoot)
+	require.NoError(t, err)
+	assert.DeepEqual(t, [fieldparams.MaxBlobsPerBlock]bool{true, true}, indices)
+	_, err = os.Stat(path.Join(targetDir, kv.BeaconNodeDbDirName+stagingSuffix))
+	assert.Equal(t, true, os.IsNotExist(err))
+}
+
+func TestSnapshot_RestoreInvalid(t *testing.T) {
+	ctx := context.Background()
+	archivePath, manifest, headRoot := writeTestSnapshot(t)
+	blobName := path.Join(snapshotBlobsDir, fmt.Sprintf("%#x", headRoot), "1.ssz")
+
+	tests := []struct {
+		name    string
+		rewrite func(name string, content []byte) []byte
+		wantErr string
+	}{
+		{
+			name: "altered blob",
+			rewrite: func(name string, content []byte) []byte {
+				if name == blobName {
+					content[0] ^= 1
+				}
+				return content
+			},
+			wantErr: "does not match the manifest",
+		},
+		{
+			name: "missing manifest",
+			rewrite: func(name string, content []byte) []byte {
+				if name == snapshotManifestName {
+					return nil
+				}
+				return content
+			},
+			wantErr: "archive has no manifest",
+		},
+		{
+			name: "missing blob",
+			rewrite: func(name string, content []byte) []byte {
+				switch name {
+				case blobName:
+					return nil
+				case snapshotManifestName:
+					m := *manifest
+					m.Files = nil
+					for _, f := range manifest.Files {
+						if f.Name != blobName {
+							m.Files = append(m.Files, f)
+						}
+					}
+					enc, err := json.Marshal(&m)
+					require.NoError(t, err)
+					return enc
+				}
+				return content
+			},
+			wantErr: "1 blobs of the blocks within the blob retention period are missing",
+		},
+		{
+			name: "other finalized checkpoint",
+			rewrite: func(name string, content []byte) []byte {
+				if name == snapshotManifestName {
+					m := *manifest
+					m.FinalizedEpoch = 1
+					enc, err := json.Marshal(&m)
+					require.NoError(t, err)
+					return enc
+				}
+				return content
+			},
+			wantErr: "database is finalized at epoch 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := rewriteArchive(t, archivePath, tt.rewrite)
+			targetDir := t.TempDir()
+			_, err := RestoreSnapshot(ctx, invalid, targetDir, path.Join(targetDir, snapshotBlobsDir))
+			require.ErrorContains(t, tt.wantErr, err)
+			_, err = os.Stat(path.Join(targetDir, kv.BeaconNodeDbDirName, kv.DatabaseFileName))
+			assert.Equal(t, true, os.IsNotExist(err))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package era
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/prysmaticlabs/prysm/v5/network/forks"
+)
+
+var (
+	errNoEraFiles      = errors.New("no era files found")
+	errMixedNetworks   = errors.New("era files from different networks")
+	errDuplicateEra    = errors.New("several era files for the same era")
+	errEraNotInArchive = errors.New("era is not in the archive")
+)
+
+// Archive is a directory of era files from the same network.
+type Archive struct {
+	dir   string
+	cfg   *params.BeaconChainConfig
+	files map[uint64]string
+	eras  []uint64
+}
+
+// OpenArchive indexes the era files found in a directory. The network of the files is taken from their names,
+// and determines the configuration used to decode their content.
+func OpenArchive(dir string) (*Archive, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.era"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, errors.Wrapf(errNoEraFiles, "in %s", dir)
+	}
+	a := &Archive{dir: dir, files: make(map[uint64]string)}
+	var network string
+	for _, p := range paths {
+		name, era, err := parseFileName(filepath.Base(p))
+		if err != nil {
+			return nil, err
+		}
+		if network != "" && name != network {
+			return nil, errors.Wrapf(errMixedNetworks, "%s and %s", network, name)
+		}
+		network = name
+		if _, ok := a.files[era]; ok {
+			return nil, errors.Wrapf(errDuplicateEra, "era %d", era)
+		}
+		a.files[era] = p
+		a.eras = append(a.eras, era)
+	}
+	sort.Slice(a.eras, func(i, j int) bool { return a.eras[i] < a.eras[j] })
+	a.cfg, err = params.ByName(network)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown network %s in era file names", network)
+	}
+	return a, nil
+}
+
+func parseFileName(name string) (string, uint64, error) {
+	parts := strings.Split(strings.TrimSuffix(name, ".era"), "-")
+	if len(parts) < 3 {
+		return "", 0, errors.Errorf("era file name %s does not match <network>-<era>-<root>.era", name)
+	}
+	era, err := strconv.ParseUint(parts[len(parts)-2], 10, 64)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "invalid era number in file name %s", name)
+	}
+	return strings.Join(parts[:len(parts)-2], "-"), era, nil
+}
+
+// Config is the configuration of the network the archive belongs to.
+func (a *Archive) Config() *params.BeaconChainConfig {
+	return a.cfg
+}
+
+// Eras lists the era numbers in the archive, in increasing order.
+func (a *Archive) Eras() []uint64 {
+	return a.eras
+}
+
+func (a *Archive) eraOf(slot primitives.Slot) uint64 {
+	return uint64(slot/a.cfg.SlotsPerHistoricalRoot) + 1
+}
+
+// Covers reports whether the blocks of every slot in [start, end) can be read from the archive.
+func (a *Archive) Covers(start, end primitives.Slot) bool {
+	if end <= start {
+		return false
+	}
+	for era := a.eraOf(start); era <= a.eraOf(end-1); era++ {
+		if _, ok := a.files[era]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Blocks reads the blocks of the slots in [start, end) from the archive, in increasing slot order.
+func (a *Archive) Blocks(ctx context.Context, start, end primitives.Slot) ([]blocks.ROBlock, error) {
+	if !a.Covers(start, end) {
+		return nil, errors.Wrapf(errEraNotInArchive, "slots [%d, %d)", start, end)
+	}
+	var result []blocks.ROBlock
+	for era := a.eraOf(start); era <= a.eraOf(end-1); era++ {
+		f, err := Open(a.files[era])
+		if err != nil {
+			return nil, err
+		}
+		blks, err := a.readBlocks(ctx, f, start, end)
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, blks...)
+	}
+	return result, nil
+}
+
+func (a *Archive) readBlocks(ctx context.Context, f *File, start, end primitives.Slot) ([]blocks.ROBlock, error) {
+	first, last := f.BlockSlots()
+	if start > first {
+		first = start
+	}
+	if end < last {
+		last = end
+	}
+	var result []blocks.ROBlock
+	for slot := first; slot < last; slot++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		b, err := f.Block(slot)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			continue
+		}
+		blk, err := a.unmarshalBlock(slot, b)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, blk)
+	}
+	return result, nil
+}
+
+func (a *Archive) unmarshalBlock(slot primitives.Slot, b []byte) (blocks.ROBlock, error) {
+	v, err := forks.NewOrderedSchedule(a.cfg).VersionForEpoch(primitives.Epoch(slot / a.cfg.SlotsPerEpoch))
+	if err != nil {
+		return blocks.ROBlock{}, err
+	}
+	u, err := detect.FromForkVersion(v)
+	if err != nil {
+		return blocks.ROBlock{}, err
+	}
+	sb, err := u.UnmarshalBeaconBlock(b)
+	if err != nil {
+		return blocks.ROBlock{}, errors.Wrapf(err, "could not decode block at slot %d", slot)
+	}
+	if sb.Block().Slot() != slot {
+		return blocks.ROBlock{}, errors.Errorf("block indexed at slot %d has slot %d", slot, sb.Block().Slot())
+	}
+	return blocks.NewROBlock(sb)
+}
+
+// State reads the state at the end of the given era.
+func (a *Archive) State(era uint64) (state.BeaconState, error) {
+	p, ok := a.files[era]
+	if !ok {
+		return nil, errors.Wrapf(errEraNotInArchive, "era %d", era)
+	}
+	f, err := Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	b, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+	u, err := detect.FromState(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not detect the fork of the era %d state", era)
+	}
+	st, err := u.UnmarshalBeaconState(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decode the era %d state", era)
+	}
+	if st.Slot() != f.StateSlot() {
+		return nil, errors.Errorf("era %d state has slot %d, expected %d", era, st.Slot(), f.StateSlot())
+	}
+	return st, nil
+}
+
+// Path returns the path of the file holding the given era.
+func (a *Archive) Path(era uint64) (string, bool) {
+	p, ok := a.files[era]
+	return p, ok
+}
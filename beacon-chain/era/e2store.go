@@ -0,0 +1,126 @@
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// recordType identifies the content of an e2store record.
+type recordType [2]byte
+
+var (
+	typeVersion         = recordType{0x65, 0x32}
+	typeCompressedBlock = recordType{0x01, 0x00}
+	typeCompressedState = recordType{0x02, 0x00}
+	typeSlotIndex       = recordType{0x69, 0x32}
+)
+
+// headerSize is the size of the type, length and reserved fields which precede the data of every record.
+const headerSize = 8
+
+var (
+	errUnexpectedRecord = errors.New("unexpected e2store record type")
+	errInvalidRecord    = errors.New("invalid e2store record")
+)
+
+// writeRecord writes an e2store record and returns the number of bytes written.
+func writeRecord(w io.Writer, t recordType, data []byte) (int64, error) {
+	var h [headerSize]byte
+	copy(h[:2], t[:])
+	binary.LittleEndian.PutUint32(h[2:6], uint32(len(data)))
+	n, err := w.Write(h[:])
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(data)
+	return int64(n + m), err
+}
+
+// readRecord reads the record starting at the given offset, checking that it has the expected type.
+func readRecord(r io.ReaderAt, off int64, t recordType) ([]byte, error) {
+	var h [headerSize]byte
+	if _, err := r.ReadAt(h[:], off); err != nil {
+		return nil, errors.Wrapf(err, "could not read record header at offset %d", off)
+	}
+	if h[0] != t[0] || h[1] != t[1] {
+		return nil, errors.Wrapf(errUnexpectedRecord, "offset %d has type %#x, expected %#x", off, h[:2], t[:])
+	}
+	if h[6] != 0 || h[7] != 0 {
+		return nil, errors.Wrapf(errInvalidRecord, "reserved bytes are not zero at offset %d", off)
+	}
+	data := make([]byte, binary.LittleEndian.Uint32(h[2:6]))
+	if _, err := r.ReadAt(data, off+headerSize); err != nil {
+		return nil, errors.Wrapf(err, "could not read record data at offset %d", off)
+	}
+	return data, nil
+}
+
+func compress(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := snappy.NewBufferedWriter(buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	return io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+}
+
+// slotIndex maps the slots of an era to the offsets of their records, relative to the start of the index record.
+// Empty slots have a zero offset.
+type slotIndex struct {
+	start   uint64
+	offsets []int64
+}
+
+func (s *slotIndex) marshal() []byte {
+	b := make([]byte, 16+8*len(s.offsets))
+	binary.LittleEndian.PutUint64(b[:8], s.start)
+	for i, o := range s.offsets {
+		binary.LittleEndian.PutUint64(b[8+8*i:], uint64(o))
+	}
+	binary.LittleEndian.PutUint64(b[len(b)-8:], uint64(len(s.offsets)))
+	return b
+}
+
+// readSlotIndex reads the slot index record which ends at the given offset, and returns it along with the
+// offset at which the record starts.
+func readSlotIndex(r io.ReaderAt, end int64) (*slotIndex, int64, error) {
+	var cb [8]byte
+	if end < headerSize+16 {
+		return nil, 0, errors.Wrap(errInvalidRecord, "file too small to hold a slot index")
+	}
+	if _, err := r.ReadAt(cb[:], end-8); err != nil {
+		return nil, 0, errors.Wrap(err, "could not read slot index count")
+	}
+	count := binary.LittleEndian.Uint64(cb[:])
+	size := int64(headerSize + 16 + 8*count)
+	if count > uint64(end) || size > end {
+		return nil, 0, errors.Wrapf(errInvalidRecord, "slot index count %d does not fit in the file", count)
+	}
+	off := end - size
+	data, err := readRecord(r, off, typeSlotIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(len(data)) != size-headerSize {
+		return nil, 0, errors.Wrapf(errInvalidRecord, "slot index length %d does not match count %d", len(data), count)
+	}
+	idx := &slotIndex{
+		start:   binary.LittleEndian.Uint64(data[:8]),
+		offsets: make([]int64, count),
+	}
+	for i := range idx.offsets {
+		idx.offsets[i] = int64(binary.LittleEndian.Uint64(data[8+8*i:]))
+	}
+	return idx, off, nil
+}
@@ -0,0 +1,220 @@
+// Package era reads and writes era files, which archive the finalized history of the chain. An era file holds the
+// blocks of SLOTS_PER_HISTORICAL_ROOT consecutive slots along with the state at the end of that period, stored as
+// snappy compressed SSZ in e2store records and indexed by slot. Era N covers the blocks of slots
+// [(N-1)*SLOTS_PER_HISTORICAL_ROOT, N*SLOTS_PER_HISTORICAL_ROOT), and the state at slot N*SLOTS_PER_HISTORICAL_ROOT.
+// Era 0 only holds the genesis state.
+package era
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+)
+
+var (
+	errOutOfRange   = errors.New("slot is outside of the era")
+	errOutOfOrder   = errors.New("blocks must be added in increasing slot order")
+	errWriterClosed = errors.New("era writer is already finished")
+)
+
+// FileName returns the conventional name of an era file, made of the network name, the era number and the first 4
+// bytes of the historical root of the era.
+func FileName(network string, era uint64, historicalRoot [32]byte) string {
+	return fmt.Sprintf("%s-%05d-%x.era", network, era, historicalRoot[:4])
+}
+
+// Writer writes an era file. Blocks are added in slot order, then Finish writes the state and the slot indices.
+type Writer struct {
+	w           io.Writer
+	off         int64
+	era         uint64
+	slotsPerEra primitives.Slot
+	blocks      []int64
+	last        int
+	finished    bool
+}
+
+// NewWriter starts an era file for the given era number.
+func NewWriter(w io.Writer, era uint64, slotsPerEra primitives.Slot) (*Writer, error) {
+	ew := &Writer{w: w, era: era, slotsPerEra: slotsPerEra, last: -1}
+	if era > 0 {
+		ew.blocks = make([]int64, slotsPerEra)
+	}
+	if err := ew.write(typeVersion, nil); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+// StartSlot is the first slot of the blocks in the era.
+func (w *Writer) StartSlot() primitives.Slot {
+	if w.era == 0 {
+		return 0
+	}
+	return primitives.Slot(w.era-1) * w.slotsPerEra
+}
+
+// StateSlot is the slot of the state at the end of the era.
+func (w *Writer) StateSlot() primitives.Slot {
+	return primitives.Slot(w.era) * w.slotsPerEra
+}
+
+// AddBlock adds the SSZ encoded signed block proposed at the given slot.
+func (w *Writer) AddBlock(slot primitives.Slot, block []byte) error {
+	if w.finished {
+		return errWriterClosed
+	}
+	if w.era == 0 || slot < w.StartSlot() || slot >= w.StateSlot() {
+		return errors.Wrapf(errOutOfRange, "slot %d, era %d", slot, w.era)
+	}
+	i := int(slot - w.StartSlot())
+	if i <= w.last {
+		return errors.Wrapf(errOutOfOrder, "slot %d", slot)
+	}
+	c, err := compress(block)
+	if err != nil {
+		return errors.Wrapf(err, "could not compress block at slot %d", slot)
+	}
+	w.blocks[i] = w.off
+	w.last = i
+	return w.write(typeCompressedBlock, c)
+}
+
+// Finish writes the SSZ encoded state at the end of the era, followed by the slot indices.
+func (w *Writer) Finish(state []byte) error {
+	if w.finished {
+		return errWriterClosed
+	}
+	w.finished = true
+	c, err := compress(state)
+	if err != nil {
+		return errors.Wrap(err, "could not compress state")
+	}
+	stateOff := w.off
+	if err := w.write(typeCompressedState, c); err != nil {
+		return err
+	}
+	if w.era > 0 {
+		idx := &slotIndex{start: uint64(w.StartSlot()), offsets: make([]int64, len(w.blocks))}
+		// Block records always follow the version record, so a zero offset can only be an empty slot.
+		for i, o := range w.blocks {
+			if o != 0 {
+				idx.offsets[i] = o - w.off
+			}
+		}
+		if err := w.write(typeSlotIndex, idx.marshal()); err != nil {
+			return err
+		}
+	}
+	idx := &slotIndex{start: uint64(w.StateSlot()), offsets: []int64{stateOff - w.off}}
+	return w.write(typeSlotIndex, idx.marshal())
+}
+
+func (w *Writer) write(t recordType, data []byte) error {
+	n, err := writeRecord(w.w, t, data)
+	w.off += n
+	return err
+}
+
+// File is an era file opened for reading.
+type File struct {
+	f          *os.File
+	blockIndex *slotIndex
+	stateIndex *slotIndex
+	blocksOff  int64
+	stateOff   int64
+}
+
+// Open opens an era file and reads its slot indices.
+func Open(path string) (*File, error) {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	ef, err := open(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid era file %s", path)
+	}
+	return ef, nil
+}
+
+func open(f *os.File) (ef *File, err error) {
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+		}
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := readRecord(f, 0, typeVersion); err != nil {
+		return nil, err
+	}
+	ef = &File{f: f}
+	ef.stateIndex, ef.stateOff, err = readSlotIndex(f, fi.Size())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read state index")
+	}
+	if len(ef.stateIndex.offsets) != 1 {
+		return nil, errors.Wrapf(errInvalidRecord, "state index has %d entries", len(ef.stateIndex.offsets))
+	}
+	if ef.stateIndex.start == 0 {
+		return ef, nil
+	}
+	ef.blockIndex, ef.blocksOff, err = readSlotIndex(f, ef.stateOff)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read block index")
+	}
+	if ef.blockIndex.start+uint64(len(ef.blockIndex.offsets)) != ef.stateIndex.start {
+		return nil, errors.Wrapf(errInvalidRecord, "block index does not end at the state slot %d", ef.stateIndex.start)
+	}
+	return ef, nil
+}
+
+// Close closes the underlying file.
+func (f *File) Close() error {
+	return f.f.Close()
+}
+
+// StateSlot is the slot of the state at the end of the era.
+func (f *File) StateSlot() primitives.Slot {
+	return primitives.Slot(f.stateIndex.start)
+}
+
+// BlockSlots returns the half-open range of slots covered by the blocks of the era, empty for era 0.
+func (f *File) BlockSlots() (primitives.Slot, primitives.Slot) {
+	if f.blockIndex == nil {
+		return f.StateSlot(), f.StateSlot()
+	}
+	return primitives.Slot(f.blockIndex.start), f.StateSlot()
+}
+
+// Block returns the SSZ encoded signed block at the given slot, or nil when the slot is empty.
+func (f *File) Block(slot primitives.Slot) ([]byte, error) {
+	start, end := f.BlockSlots()
+	if slot < start || slot >= end {
+		return nil, errors.Wrapf(errOutOfRange, "slot %d is not within [%d, %d)", slot, start, end)
+	}
+	o := f.blockIndex.offsets[slot-start]
+	if o == 0 {
+		return nil, nil
+	}
+	data, err := readRecord(f.f, f.blocksOff+o, typeCompressedBlock)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read block at slot %d", slot)
+	}
+	return decompress(data)
+}
+
+// State returns the SSZ encoded state at the end of the era.
+func (f *File) State() ([]byte, error) {
+	data, err := readRecord(f.f, f.stateOff+f.stateIndex.offsets[0], typeCompressedState)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read state")
+	}
+	return decompress(data)
+}
@@ -0,0 +1,123 @@
+package era
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func writeTestFile(t *testing.T, era uint64, blks map[primitives.Slot][]byte, st []byte) string {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, era, 16)
+	require.NoError(t, err)
+	for slot := w.StartSlot(); slot < w.StateSlot(); slot++ {
+		if b, ok := blks[slot]; ok {
+			require.NoError(t, w.AddBlock(slot, b))
+		}
+	}
+	require.NoError(t, w.Finish(st))
+	p := filepath.Join(t.TempDir(), FileName("minimal", era, [32]byte{0xab}))
+	require.NoError(t, os.WriteFile(p, buf.Bytes(), 0600))
+	return p
+}
+
+func TestWriterReader(t *testing.T) {
+	blks := map[primitives.Slot][]byte{
+		16: bytes.Repeat([]byte{1}, 100),
+		17: bytes.Repeat([]byte{2}, 5000),
+		31: bytes.Repeat([]byte{3}, 1),
+	}
+	st := bytes.Repeat([]byte{4}, 10000)
+	p := writeTestFile(t, 2, blks, st)
+	require.Equal(t, "minimal-00002-ab000000.era", filepath.Base(p))
+
+	f, err := Open(p)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	start, end := f.BlockSlots()
+	require.Equal(t, primitives.Slot(16), start)
+	require.Equal(t, primitives.Slot(32), end)
+	require.Equal(t, primitives.Slot(32), f.StateSlot())
+	for slot := start; slot < end; slot++ {
+		b, err := f.Block(slot)
+		require.NoError(t, err)
+		require.DeepEqual(t, blks[slot], b)
+	}
+	_, err = f.Block(32)
+	require.ErrorIs(t, err, errOutOfRange)
+	s, err := f.State()
+	require.NoError(t, err)
+	require.DeepEqual(t, st, s)
+}
+
+func TestWriterReader_Genesis(t *testing.T) {
+	st := bytes.Repeat([]byte{4}, 100)
+	p := writeTestFile(t, 0, nil, st)
+	f, err := Open(p)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	start, end := f.BlockSlots()
+	require.Equal(t, start, end)
+	s, err := f.State()
+	require.NoError(t, err)
+	require.DeepEqual(t, st, s)
+}
+
+func TestWriter_Errors(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{}, 2, 16)
+	require.NoError(t, err)
+	require.ErrorIs(t, w.AddBlock(15, []byte{1}), errOutOfRange)
+	require.ErrorIs(t, w.AddBlock(32, []byte{1}), errOutOfRange)
+	require.NoError(t, w.AddBlock(20, []byte{1}))
+	require.ErrorIs(t, w.AddBlock(20, []byte{1}), errOutOfOrder)
+	require.NoError(t, w.Finish([]byte{1}))
+	require.ErrorIs(t, w.AddBlock(21, []byte{1}), errWriterClosed)
+
+	w, err = NewWriter(&bytes.Buffer{}, 0, 16)
+	require.NoError(t, err)
+	require.ErrorIs(t, w.AddBlock(0, []byte{1}), errOutOfRange)
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "invalid.era")
+	require.NoError(t, os.WriteFile(p, bytes.Repeat([]byte{1}, 64), 0600))
+	_, err := Open(p)
+	require.ErrorIs(t, err, errUnexpectedRecord)
+
+	// A truncated file loses its indices.
+	full, err := os.ReadFile(writeTestFile(t, 2, map[primitives.Slot][]byte{16: {1}}, []byte{2}))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(p, full[:len(full)-10], 0600))
+	_, err = Open(p)
+	require.NotNil(t, err)
+}
+
+func TestOpenArchive(t *testing.T) {
+	dir := t.TempDir()
+	_, err := OpenArchive(dir)
+	require.ErrorIs(t, err, errNoEraFiles)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "minimal-00001-00000000.era"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "minimal-00003-00000000.era"), nil, 0600))
+	a, err := OpenArchive(dir)
+	require.NoError(t, err)
+	require.DeepEqual(t, []uint64{1, 3}, a.Eras())
+	require.Equal(t, "minimal", a.Config().ConfigName)
+	spe := a.Config().SlotsPerHistoricalRoot
+	require.Equal(t, true, a.Covers(0, spe))
+	require.Equal(t, false, a.Covers(0, spe+1))
+	require.Equal(t, true, a.Covers(2*spe, 3*spe))
+	require.Equal(t, false, a.Covers(spe, spe))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mainnet-00002-00000000.era"), nil, 0600))
+	_, err = OpenArchive(dir)
+	require.ErrorIs(t, err, errMixedNetworks)
+}
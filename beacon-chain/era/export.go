@@ -0,0 +1,203 @@
+package era
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+var (
+	errEraNotFinalized = errors.New("era is not finalized")
+	errBlindedBlock    = errors.New("block is stored without its execution payload")
+	errMissingBlock    = errors.New("canonical block is missing from the database")
+)
+
+// finalizedHistory lets the state replayer walk the finalized chain of a database which is not attached to a
+// running node.
+type finalizedHistory struct {
+	db   db.ReadOnlyDatabase
+	slot primitives.Slot
+}
+
+func (h *finalizedHistory) IsCanonical(ctx context.Context, root [32]byte) (bool, error) {
+	return h.db.IsFinalizedBlock(ctx, root), nil
+}
+
+func (h *finalizedHistory) CurrentSlot() primitives.Slot {
+	return h.slot
+}
+
+// Exporter writes era files from the finalized history of a beacon database.
+type Exporter struct {
+	db      db.ReadOnlyDatabase
+	cfg     *params.BeaconChainConfig
+	history *stategen.CanonicalHistory
+	genesis state.BeaconState
+	// LastEra is the most recent era which is fully finalized.
+	LastEra uint64
+}
+
+// NewExporter prepares the export of a database. The network configuration is identified from the genesis state.
+func NewExporter(ctx context.Context, d db.ReadOnlyDatabase) (*Exporter, error) {
+	genesis, err := genesisState(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if genesis == nil {
+		return nil, errors.New("database does not have a genesis state")
+	}
+	cfg, err := params.ByVersion(bytesutil.ToBytes4(genesis.Fork().CurrentVersion))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not identify the network of the genesis state")
+	}
+	cp, err := d.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read finalized checkpoint")
+	}
+	fs := primitives.Slot(cp.Epoch) * cfg.SlotsPerEpoch
+	h := &finalizedHistory{db: d, slot: fs}
+	return &Exporter{
+		db:      d,
+		cfg:     cfg,
+		history: stategen.NewCanonicalHistory(d, h, h),
+		genesis: genesis,
+		LastEra: uint64(fs / cfg.SlotsPerHistoricalRoot),
+	}, nil
+}
+
+// Config returns the configuration of the network the database belongs to.
+func (e *Exporter) Config() *params.BeaconChainConfig {
+	return e.cfg
+}
+
+// Export writes the file of the given era into dir, and returns its path.
+func (e *Exporter) Export(ctx context.Context, dir string, era uint64) (string, error) {
+	if era > e.LastEra {
+		return "", errors.Wrapf(errEraNotFinalized, "era %d, last finalized era is %d", era, e.LastEra)
+	}
+	st, err := e.eraState(ctx, era)
+	if err != nil {
+		return "", err
+	}
+	root, err := historicalRoot(st, era)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, era, e.cfg.SlotsPerHistoricalRoot)
+	if err != nil {
+		return "", err
+	}
+	if era > 0 {
+		if err := e.writeBlocks(ctx, w, st); err != nil {
+			return "", err
+		}
+	}
+	sb, err := st.MarshalSSZ()
+	if err != nil {
+		return "", errors.Wrapf(err, "could not encode the era %d state", era)
+	}
+	if err := w.Finish(sb); err != nil {
+		return "", err
+	}
+	p := filepath.Join(dir, FileName(e.cfg.ConfigName, era, root))
+	tmp := p + ".tmp"
+	if err := file.WriteFile(tmp, buf.Bytes()); err != nil {
+		return "", err
+	}
+	return p, os.Rename(tmp, p)
+}
+
+// eraState computes the state at the end of the era, after the empty slots following the last block of the era.
+func (e *Exporter) eraState(ctx context.Context, era uint64) (state.BeaconState, error) {
+	if era == 0 {
+		return e.genesis, nil
+	}
+	end := primitives.Slot(era) * e.cfg.SlotsPerHistoricalRoot
+	st, err := e.history.ReplayerForSlot(end-1).ReplayToSlot(ctx, end)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not compute the state of era %d", era)
+	}
+	return st, nil
+}
+
+// writeBlocks adds the canonical blocks of the era, found from the block roots of the state at its end. The genesis
+// block is omitted, since it is derived from the genesis state.
+func (e *Exporter) writeBlocks(ctx context.Context, w *Writer, st state.BeaconState) error {
+	roots := st.BlockRoots()
+	var prev []byte
+	for i, r := range roots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		slot := w.StartSlot() + primitives.Slot(i)
+		if slot == 0 || bytes.Equal(r, prev) {
+			prev = r
+			continue
+		}
+		prev = r
+		blk, err := e.db.Block(ctx, bytesutil.ToBytes32(r))
+		if err != nil {
+			return errors.Wrapf(err, "could not read block %#x", r)
+		}
+		if blk == nil || blk.IsNil() {
+			return errors.Wrapf(errMissingBlock, "root %#x, slot %d", r, slot)
+		}
+		if blk.Block().Slot() != slot {
+			// The root carries over from the previous era, the first slots of this one are empty.
+			continue
+		}
+		if blk.IsBlinded() {
+			return errors.Wrapf(errBlindedBlock, "slot %d, run the node with --save-full-execution-payloads to export it", slot)
+		}
+		b, err := blk.MarshalSSZ()
+		if err != nil {
+			return errors.Wrapf(err, "could not encode block at slot %d", slot)
+		}
+		if err := w.AddBlock(slot, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historicalRoot is the root identifying the era in its file name: the genesis validators root for era 0, and the
+// root of the historical batch accumulated over the era otherwise.
+func historicalRoot(st state.ReadOnlyBeaconState, era uint64) ([32]byte, error) {
+	if era == 0 {
+		return bytesutil.ToBytes32(st.GenesisValidatorsRoot()), nil
+	}
+	hb := &ethpb.HistoricalBatch{BlockRoots: st.BlockRoots(), StateRoots: st.StateRoots()}
+	return hb.HashTreeRoot()
+}
+
+// genesisState reads the state saved for the genesis block, or nil when the database has no genesis block. Unlike
+// GenesisState, it never falls back to the genesis state embedded in the binary.
+func genesisState(ctx context.Context, d db.ReadOnlyDatabase) (state.BeaconState, error) {
+	gr, err := d.GenesisBlockRoot(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read genesis block root")
+	}
+	st, err := d.State(ctx, gr)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read genesis state")
+	}
+	if st == nil || st.IsNil() {
+		return nil, nil
+	}
+	return st, nil
+}
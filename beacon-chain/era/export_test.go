@@ -0,0 +1,179 @@
+package era
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	dbtest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+// setupChain saves a finalized chain of blocks covering the first era to the database. Most slots are left empty to
+// keep the chain short, since the era length is fixed by the size of the state block roots.
+func setupChain(t *testing.T, d db.HeadAccessDatabase) []blocks.ROBlock {
+	ctx := context.Background()
+	st, keys := util.DeterministicGenesisState(t, 64)
+	require.NoError(t, d.SaveGenesisData(ctx, st))
+	spe := params.BeaconConfig().SlotsPerHistoricalRoot
+
+	var chain []blocks.ROBlock
+	for _, slot := range []primitives.Slot{1, 2, 4, 64, spe - 2, spe + 1} {
+		sb, err := blocks.NewSignedBeaconBlock(nextBlock(t, st, keys, slot))
+		require.NoError(t, err)
+		st, err = transition.ExecuteStateTransition(ctx, st, sb)
+		require.NoError(t, err)
+		rb, err := blocks.NewROBlock(sb)
+		require.NoError(t, err)
+		require.NoError(t, d.SaveROBlocks(ctx, []blocks.ROBlock{rb}, false))
+		chain = append(chain, rb)
+	}
+	last := chain[len(chain)-1]
+	r := last.Root()
+	require.NoError(t, d.SaveState(ctx, st, r))
+	require.NoError(t, d.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: primitives.Epoch((spe + 1) / params.BeaconConfig().SlotsPerEpoch), Root: r[:]}))
+	return chain
+}
+
+// nextBlock builds a block at the given slot on top of the state, which may be several epochs behind.
+func nextBlock(t *testing.T, st state.BeaconState, keys []bls.SecretKey, slot primitives.Slot) *ethpb.SignedBeaconBlock {
+	ctx := context.Background()
+	adv, err := transition.ProcessSlots(ctx, st.Copy(), slot)
+	require.NoError(t, err)
+	idx, err := helpers.BeaconProposerIndex(ctx, adv)
+	require.NoError(t, err)
+	reveal, err := util.RandaoReveal(adv, slots.ToEpoch(slot), keys)
+	require.NoError(t, err)
+	pr, err := adv.LatestBlockHeader().HashTreeRoot()
+	require.NoError(t, err)
+	b := util.NewBeaconBlock()
+	b.Block.Slot = slot
+	b.Block.ProposerIndex = idx
+	b.Block.ParentRoot = pr[:]
+	b.Block.Body.RandaoReveal = reveal
+	b.Block.Body.Eth1Data = adv.Eth1Data()
+	sig, err := util.BlockSignature(st, b.Block, keys)
+	require.NoError(t, err)
+	b.Signature = sig.Marshal()
+	return b
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	// Only one test database can be open at a time, the source is closed once exported.
+	src, err := kv.NewKVStore(ctx, t.TempDir())
+	require.NoError(t, err)
+	chain := setupChain(t, src)
+	e, err := NewExporter(ctx, src)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), e.LastEra)
+	dir := t.TempDir()
+	for era := uint64(0); era <= e.LastEra; era++ {
+		_, err := e.Export(ctx, dir, era)
+		require.NoError(t, err)
+	}
+	_, err = e.Export(ctx, dir, 2)
+	require.ErrorIs(t, err, errEraNotFinalized)
+	require.NoError(t, src.Close())
+
+	a, err := OpenArchive(dir)
+	require.NoError(t, err)
+	require.DeepEqual(t, []uint64{0, 1}, a.Eras())
+	spe := params.BeaconConfig().SlotsPerHistoricalRoot
+	blks, err := a.Blocks(ctx, 0, spe)
+	require.NoError(t, err)
+	inEra := 0
+	for _, b := range chain {
+		if b.Block().Slot() < spe {
+			require.Equal(t, b.Root(), blks[inEra].Root())
+			inEra++
+		}
+	}
+	require.Equal(t, inEra, len(blks))
+	st, err := a.State(1)
+	require.NoError(t, err)
+
+	require.Equal(t, spe, st.Slot())
+
+	t.Run("fresh database", func(t *testing.T) {
+		dst := dbtest.SetupDB(t)
+		require.NoError(t, Import(ctx, dst, a))
+		for _, b := range blks {
+			require.Equal(t, true, dst.HasBlock(ctx, b.Root()))
+		}
+		require.Equal(t, true, dst.HasState(ctx, blks[len(blks)-1].Root()))
+		gs, err := dst.GenesisState(ctx)
+		require.NoError(t, err)
+		require.Equal(t, primitives.Slot(0), gs.Slot())
+	})
+	t.Run("below backfill", func(t *testing.T) {
+		only := t.TempDir()
+		p, ok := a.Path(1)
+		require.Equal(t, true, ok)
+		b, err := os.ReadFile(p)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(only, filepath.Base(p)), b, 0600))
+		oa, err := OpenArchive(only)
+		require.NoError(t, err)
+
+		dst := dbtest.SetupDB(t)
+		_, err = dst.BackfillStatus(ctx)
+		require.ErrorIs(t, err, db.ErrNotFound)
+		require.ErrorIs(t, Import(ctx, dst, oa), errNoImportAnchor)
+
+		above := chain[inEra]
+		ar, pr := above.Root(), above.Block().ParentRoot()
+		// The node was checkpoint synced from the block above the era, which is the first finalized block it has.
+		require.NoError(t, dst.SaveROBlocks(ctx, []blocks.ROBlock{above}, false))
+		require.NoError(t, dst.(*kv.Store).SaveOriginCheckpointBlockRoot(ctx, ar))
+		require.NoError(t, dst.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: slots.ToEpoch(above.Block().Slot()), Root: ar[:]}))
+		require.NoError(t, dst.SaveBackfillStatus(ctx, &dbval.BackfillStatus{
+			LowSlot:       uint64(above.Block().Slot()),
+			LowRoot:       ar[:],
+			LowParentRoot: pr[:],
+			OriginSlot:    uint64(above.Block().Slot()),
+			OriginRoot:    ar[:],
+		}))
+		require.NoError(t, Import(ctx, dst, oa))
+		bs, err := dst.BackfillStatus(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(blks[0].Block().Slot()), bs.LowSlot)
+		lr := blks[0].Root()
+		require.DeepEqual(t, lr[:], bs.LowRoot)
+		// The imported blocks are part of the finalized chain, like backfilled ones.
+		for _, b := range blks {
+			require.Equal(t, true, dst.IsFinalizedBlock(ctx, b.Root()))
+		}
+		require.Equal(t, true, dst.IsFinalizedBlock(ctx, ar))
+	})
+	t.Run("not consecutive", func(t *testing.T) {
+		gap := t.TempDir()
+		for _, era := range []uint64{0, 1} {
+			p, _ := a.Path(era)
+			b, err := os.ReadFile(p)
+			require.NoError(t, err)
+			name := filepath.Base(p)
+			if era == 1 {
+				name = FileName(a.Config().ConfigName, 2, [32]byte{})
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(gap, name), b, 0600))
+		}
+		ga, err := OpenArchive(gap)
+		require.NoError(t, err)
+		require.ErrorIs(t, Import(ctx, dbtest.SetupDB(t), ga), errNotConsecutive)
+	})
+}
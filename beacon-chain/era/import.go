@@ -0,0 +1,236 @@
+package era
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "era")
+
+var (
+	errNotConsecutive  = errors.New("eras to import must be consecutive")
+	errChainMismatch   = errors.New("era blocks do not match the chain")
+	errNoImportAnchor  = errors.New("era blocks do not link to a block known to the database")
+	errGenesisMismatch = errors.New("era genesis state does not match the database")
+)
+
+// Import saves the blocks and states of the archive to the database. The blocks must either descend from a block
+// already in the database, or be the ancestors of the lowest block backfilled so far, in which case the backfill
+// status is moved down to the lowest imported block. Every block is checked against the block roots of the state
+// at the end of its era, and against its parent and child.
+func Import(ctx context.Context, d db.HeadAccessDatabase, a *Archive) error {
+	eras := a.Eras()
+	for i := 1; i < len(eras); i++ {
+		if eras[i] != eras[i-1]+1 {
+			return errors.Wrapf(errNotConsecutive, "missing era %d", eras[i-1]+1)
+		}
+	}
+	if eras[0] == 0 {
+		if err := importGenesis(ctx, d, a); err != nil {
+			return err
+		}
+		eras = eras[1:]
+	}
+	if len(eras) == 0 {
+		return nil
+	}
+
+	bs, err := d.BackfillStatus(ctx)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return errors.Wrap(err, "could not read backfill status")
+	}
+	fromBackfill, err := checkAnchor(ctx, d, a, eras, bs)
+	if err != nil {
+		return err
+	}
+
+	var parent, lowest *blocks.ROBlock
+	for _, era := range eras {
+		st, err := a.State(era)
+		if err != nil {
+			return err
+		}
+		start := primitives.Slot(era-1) * a.cfg.SlotsPerHistoricalRoot
+		blks, err := a.Blocks(ctx, start, st.Slot())
+		if err != nil {
+			return err
+		}
+		if err := checkEra(st, blks, parent); err != nil {
+			return errors.Wrapf(err, "era %d", era)
+		}
+		if len(blks) > 0 {
+			parent = &blks[len(blks)-1]
+			if lowest == nil {
+				lowest = &blks[0]
+			}
+		}
+		if err := d.SaveROBlocks(ctx, blks, false); err != nil {
+			return errors.Wrapf(err, "could not save blocks of era %d", era)
+		}
+		if err := saveEraState(ctx, d, st); err != nil {
+			return errors.Wrapf(err, "could not save state of era %d", era)
+		}
+		log.WithField("era", era).WithField("blocks", len(blks)).Info("Imported era")
+	}
+
+	if fromBackfill && lowest != nil {
+		if err := indexFinalized(ctx, d, a, eras, bytesutil.ToBytes32(bs.LowRoot)); err != nil {
+			return err
+		}
+		pr := lowest.Block().ParentRoot()
+		lr := lowest.Root()
+		bs.LowSlot = uint64(lowest.Block().Slot())
+		bs.LowRoot = lr[:]
+		bs.LowParentRoot = pr[:]
+		if err := d.SaveBackfillStatus(ctx, bs); err != nil {
+			return errors.Wrap(err, "could not update backfill status")
+		}
+		log.WithField("lowSlot", bs.LowSlot).Info("Moved backfill status down to the imported blocks")
+	}
+	return nil
+}
+
+// indexFinalized adds the blocks of the eras to the finalized block roots index, as backfill does for the blocks it
+// saves. The index is extended down from the lowest backfilled block, so the eras are indexed from the last one.
+func indexFinalized(ctx context.Context, d db.HeadAccessDatabase, a *Archive, eras []uint64, childRoot [32]byte) error {
+	for i := len(eras) - 1; i >= 0; i-- {
+		start := primitives.Slot(eras[i]-1) * a.cfg.SlotsPerHistoricalRoot
+		blks, err := a.Blocks(ctx, start, start+a.cfg.SlotsPerHistoricalRoot)
+		if err != nil {
+			return err
+		}
+		if len(blks) == 0 {
+			continue
+		}
+		if err := d.BackfillFinalizedIndex(ctx, blks, childRoot); err != nil {
+			return errors.Wrapf(err, "could not update finalized index for era %d", eras[i])
+		}
+		childRoot = blks[0].Root()
+	}
+	return nil
+}
+
+func importGenesis(ctx context.Context, d db.HeadAccessDatabase, a *Archive) error {
+	st, err := a.State(0)
+	if err != nil {
+		return err
+	}
+	gs, err := genesisState(ctx, d)
+	if err != nil {
+		return err
+	}
+	if gs == nil {
+		if err := d.SaveGenesisData(ctx, st); err != nil {
+			return errors.Wrap(err, "could not save genesis state")
+		}
+		log.Info("Imported genesis state")
+		return nil
+	}
+	if bytesutil.ToBytes32(gs.GenesisValidatorsRoot()) != bytesutil.ToBytes32(st.GenesisValidatorsRoot()) {
+		return errGenesisMismatch
+	}
+	return nil
+}
+
+// checkAnchor ensures the imported blocks are tied to the chain known by the database, either by the parent of the
+// first block, or by the last block being the parent of the lowest backfilled block. It returns true in the latter
+// case.
+func checkAnchor(ctx context.Context, d db.HeadAccessDatabase, a *Archive, eras []uint64, bs *dbval.BackfillStatus) (bool, error) {
+	first, err := a.firstBlock(ctx, eras)
+	if err != nil {
+		return false, err
+	}
+	if first == nil {
+		return false, errors.Wrap(errNoImportAnchor, "eras have no blocks")
+	}
+	if d.HasBlock(ctx, first.Block().ParentRoot()) {
+		return false, nil
+	}
+	if bs != nil {
+		last, err := a.lastBlock(ctx, eras)
+		if err != nil {
+			return false, err
+		}
+		if last.Root() == bytesutil.ToBytes32(bs.LowParentRoot) {
+			return true, nil
+		}
+	}
+	return false, errNoImportAnchor
+}
+
+func (a *Archive) firstBlock(ctx context.Context, eras []uint64) (*blocks.ROBlock, error) {
+	for _, era := range eras {
+		start := primitives.Slot(era-1) * a.cfg.SlotsPerHistoricalRoot
+		blks, err := a.Blocks(ctx, start, start+a.cfg.SlotsPerHistoricalRoot)
+		if err != nil {
+			return nil, err
+		}
+		if len(blks) > 0 {
+			return &blks[0], nil
+		}
+	}
+	return nil, nil
+}
+
+func (a *Archive) lastBlock(ctx context.Context, eras []uint64) (*blocks.ROBlock, error) {
+	for i := len(eras) - 1; i >= 0; i-- {
+		start := primitives.Slot(eras[i]-1) * a.cfg.SlotsPerHistoricalRoot
+		blks, err := a.Blocks(ctx, start, start+a.cfg.SlotsPerHistoricalRoot)
+		if err != nil {
+			return nil, err
+		}
+		if len(blks) > 0 {
+			return &blks[len(blks)-1], nil
+		}
+	}
+	return nil, errNoImportAnchor
+}
+
+// checkEra verifies that the blocks of an era are the ones recorded in the block roots of the state at its end,
+// and that they form a chain.
+func checkEra(st state.BeaconState, blks []blocks.ROBlock, parent *blocks.ROBlock) error {
+	roots := st.BlockRoots()
+	n := primitives.Slot(len(roots))
+	for i := range blks {
+		b := &blks[i]
+		slot := b.Block().Slot()
+		if b.Root() != bytesutil.ToBytes32(roots[slot%n]) {
+			return errors.Wrapf(errChainMismatch, "block at slot %d is not in the era state block roots", slot)
+		}
+		if parent != nil && b.Block().ParentRoot() != parent.Root() {
+			return errors.Wrapf(errChainMismatch, "block at slot %d is not a child of the block at slot %d", slot, parent.Block().Slot())
+		}
+		parent = b
+	}
+	if parent != nil {
+		hr, err := st.LatestBlockHeader().HashTreeRoot()
+		if err != nil {
+			return err
+		}
+		if hr != parent.Root() {
+			return errors.Wrap(errChainMismatch, "the latest block header of the era state is not the last block")
+		}
+	}
+	return nil
+}
+
+// saveEraState stores the state at the end of the era under the root of its latest block, which is how the database
+// indexes states.
+func saveEraState(ctx context.Context, d db.HeadAccessDatabase, st state.BeaconState) error {
+	root, err := st.LatestBlockHeader().HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if d.HasState(ctx, root) {
+		return nil
+	}
+	return d.SaveState(ctx, st, root)
+}
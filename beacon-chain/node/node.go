@@ -27,6 +27,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache/depositsnapshot"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/slasherkv"
@@ -47,6 +48,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/slasher"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/statediff"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
 	regularsync "github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill"
@@ -184,6 +186,16 @@ func New(cliCtx *cli.Context, cancel context.CancelFunc, opts ...Option) (*Beaco
 		beacon.BlobStorage = blobs
 	}
 
+	if cliCtx.Bool(flags.ReadOnlyReplica.Name) {
+		if err := registerReplicaServices(cliCtx, beacon, synchronizer); err != nil {
+			return nil, errors.Wrap(err, "could not register read-only replica services")
+		}
+		if err := beacon.finishNew(); err != nil {
+			return nil, err
+		}
+		return beacon, nil
+	}
+
 	bfs, err := startBaseServices(cliCtx, beacon, depositAddress)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not start modules")
@@ -209,20 +221,28 @@ func New(cliCtx *cli.Context, cancel context.CancelFunc, opts ...Option) (*Beaco
 		return nil, errors.Wrap(err, "could not register services")
 	}
 
+	if err := beacon.finishNew(); err != nil {
+		return nil, err
+	}
+	return beacon, nil
+}
+
+// finishNew completes the node once its services are registered.
+func (b *BeaconNode) finishNew() error {
 	// db.DatabasePath is the path to the containing directory
 	// db.NewFileName expands that to the canonical full path using
 	// the same construction as NewDB()
-	c, err := newBeaconNodePromCollector(db.NewFileName(beacon.db.DatabasePath()))
+	c, err := newBeaconNodePromCollector(db.NewFileName(b.db.DatabasePath()))
 	if err != nil {
-		return nil, err
+		return err
 	}
-	beacon.collector = c
+	b.collector = c
 
 	// Do not store the finalized state as it has been provided to the respective services during
 	// their initialization.
-	beacon.finalizedStateAtStartUp = nil
+	b.finalizedStateAtStartUp = nil
 
-	return beacon, nil
+	return nil
 }
 
 func configureBeacon(cliCtx *cli.Context) error {
@@ -461,7 +481,7 @@ func (b *BeaconNode) Close() {
 	close(b.stop)
 }
 
-func (b *BeaconNode) clearDB(clearDB, forceClearDB bool, d *kv.Store, dbPath string) (*kv.Store, error) {
+func (b *BeaconNode) clearDB(clearDB, forceClearDB bool, d *kv.Store, dbPath string, opts ...kv.KVStoreOption) (*kv.Store, error) {
 	var err error
 	clearDBConfirmed := false
 
@@ -489,7 +509,7 @@ func (b *BeaconNode) clearDB(clearDB, forceClearDB bool, d *kv.Store, dbPath str
 			return nil, errors.Wrap(err, "could not clear blob storage")
 		}
 
-		d, err = kv.NewKVStore(b.ctx, dbPath)
+		d, err = kv.NewKVStore(b.ctx, dbPath, opts...)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create new database")
 		}
@@ -529,15 +549,31 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context, depositAddress string) error {
 	clearDBRequired := cliCtx.Bool(cmd.ClearDB.Name)
 	forceClearDBRequired := cliCtx.Bool(cmd.ForceClearDB.Name)
 
+	var opts []kv.KVStoreOption
+	if cliCtx.IsSet(flags.DBBackend.Name) {
+		backend, err := engine.ParseBackend(cliCtx.String(flags.DBBackend.Name))
+		if err != nil {
+			return errors.Wrapf(err, "could not parse --%s", flags.DBBackend.Name)
+		}
+		opts = append(opts, kv.WithBackend(backend))
+	}
+	if cliCtx.IsSet(flags.StateDiffExponents.Name) {
+		exponents, err := statediff.ParseExponents(cliCtx.String(flags.StateDiffExponents.Name))
+		if err != nil {
+			return errors.Wrapf(err, "could not parse --%s", flags.StateDiffExponents.Name)
+		}
+		opts = append(opts, kv.WithStateDiffExponents(exponents))
+	}
+
 	log.WithField("databasePath", dbPath).Info("Checking DB")
 
-	d, err := kv.NewKVStore(b.ctx, dbPath)
+	d, err := kv.NewKVStore(b.ctx, dbPath, opts...)
 	if err != nil {
 		return errors.Wrapf(err, "could not create database at %s", dbPath)
 	}
 
 	if clearDBRequired || forceClearDBRequired {
-		d, err = b.clearDB(clearDBRequired, forceClearDBRequired, d, dbPath)
+		d, err = b.clearDB(clearDBRequired, forceClearDBRequired, d, dbPath, opts...)
 		if err != nil {
 			return errors.Wrap(err, "could not clear database")
 		}
@@ -548,6 +584,15 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context, depositAddress string) error {
 	}
 
 	b.db = d
+	if ratio := cliCtx.Float64(flags.DBCompactionFreeRatio.Name); ratio > 0 {
+		go d.RunCompaction(b.ctx, ratio)
+	}
+	if interval := cliCtx.Duration(flags.ReplicaCheckpointInterval.Name); interval > 0 {
+		if d.Backend() != engine.Pebble {
+			return errors.Errorf("--%s needs the %s backend", flags.ReplicaCheckpointInterval.Name, engine.Pebble)
+		}
+		go d.RunReplicaCheckpoints(b.ctx, interval)
+	}
 
 	depositCache, err = depositsnapshot.New()
 	if err != nil {
@@ -634,6 +679,17 @@ func (b *BeaconNode) startSlasherDB(cliCtx *cli.Context) error {
 
 func (b *BeaconNode) startStateGen(ctx context.Context, bfs coverage.AvailableBlocker, fc forkchoice.ForkChoicer) error {
 	opts := []stategen.Option{stategen.WithAvailableBlocker(bfs)}
+	// The boundary states of a read-only replica would share the directory of the node writing to its database.
+	if size := b.cliCtx.Uint64(flags.BoundaryStateCacheSize.Name); size > 0 && !b.cliCtx.Bool(flags.ReadOnlyReplica.Name) {
+		dir := filepath.Join(b.cliCtx.String(cmd.DataDirFlag.Name), stategen.BoundaryStatesDirName)
+		cfg := params.BeaconConfig()
+		epochs := b.cliCtx.Uint64(flags.BoundaryStateCacheDays.Name) * 24 * 60 * 60 / (cfg.SecondsPerSlot * uint64(cfg.SlotsPerEpoch))
+		store, err := stategen.NewBoundaryStateStore(dir, size<<20, primitives.Epoch(epochs))
+		if err != nil {
+			return errors.Wrap(err, "could not open boundary state store")
+		}
+		opts = append(opts, stategen.WithBoundaryStateStore(store))
+	}
 	sg := stategen.New(b.db, fc, opts...)
 
 	cp, err := b.db.FinalizedCheckpoint(ctx)
@@ -672,28 +728,31 @@ func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 	}
 
 	svc, err := p2p.NewService(b.ctx, &p2p.Config{
-		NoDiscovery:          cliCtx.Bool(cmd.NoDiscovery.Name),
-		StaticPeers:          slice.SplitCommaSeparated(cliCtx.StringSlice(cmd.StaticPeers.Name)),
-		Discv5BootStrapAddrs: p2p.ParseBootStrapAddrs(bootstrapNodeAddrs),
-		RelayNodeAddr:        cliCtx.String(cmd.RelayNode.Name),
-		DataDir:              dataDir,
-		LocalIP:              cliCtx.String(cmd.P2PIP.Name),
-		HostAddress:          cliCtx.String(cmd.P2PHost.Name),
-		HostDNS:              cliCtx.String(cmd.P2PHostDNS.Name),
-		PrivateKey:           cliCtx.String(cmd.P2PPrivKey.Name),
-		StaticPeerID:         cliCtx.Bool(cmd.P2PStaticID.Name),
-		MetaDataDir:          cliCtx.String(cmd.P2PMetadata.Name),
-		QUICPort:             cliCtx.Uint(cmd.P2PQUICPort.Name),
-		TCPPort:              cliCtx.Uint(cmd.P2PTCPPort.Name),
-		UDPPort:              cliCtx.Uint(cmd.P2PUDPPort.Name),
-		MaxPeers:             cliCtx.Uint(cmd.P2PMaxPeers.Name),
-		QueueSize:            cliCtx.Uint(cmd.PubsubQueueSize.Name),
-		AllowListCIDR:        cliCtx.String(cmd.P2PAllowList.Name),
-		DenyListCIDR:         slice.SplitCommaSeparated(cliCtx.StringSlice(cmd.P2PDenyList.Name)),
-		EnableUPnP:           cliCtx.Bool(cmd.EnableUPnPFlag.Name),
-		StateNotifier:        b,
-		DB:                   b.db,
-		ClockWaiter:          b.clockWaiter,
+		NoDiscovery:           cliCtx.Bool(cmd.NoDiscovery.Name),
+		StaticPeers:           slice.SplitCommaSeparated(cliCtx.StringSlice(cmd.StaticPeers.Name)),
+		Discv5BootStrapAddrs:  p2p.ParseBootStrapAddrs(bootstrapNodeAddrs),
+		RelayNodeAddr:         cliCtx.String(cmd.RelayNode.Name),
+		DataDir:               dataDir,
+		LocalIP:               cliCtx.String(cmd.P2PIP.Name),
+		HostAddress:           cliCtx.String(cmd.P2PHost.Name),
+		HostDNS:               cliCtx.String(cmd.P2PHostDNS.Name),
+		PrivateKey:            cliCtx.String(cmd.P2PPrivKey.Name),
+		StaticPeerID:          cliCtx.Bool(cmd.P2PStaticID.Name),
+		MetaDataDir:           cliCtx.String(cmd.P2PMetadata.Name),
+		QUICPort:              cliCtx.Uint(cmd.P2PQUICPort.Name),
+		TCPPort:               cliCtx.Uint(cmd.P2PTCPPort.Name),
+		UDPPort:               cliCtx.Uint(cmd.P2PUDPPort.Name),
+		MaxPeers:              cliCtx.Uint(cmd.P2PMaxPeers.Name),
+		QueueSize:             cliCtx.Uint(cmd.PubsubQueueSize.Name),
+		AllowListCIDR:         cliCtx.String(cmd.P2PAllowList.Name),
+		DenyListCIDR:          slice.SplitCommaSeparated(cliCtx.StringSlice(cmd.P2PDenyList.Name)),
+		EnableUPnP:            cliCtx.Bool(cmd.EnableUPnPFlag.Name),
+		StateNotifier:         b,
+		DB:                    b.db,
+		ClockWaiter:           b.clockWaiter,
+		GossipTraceFile:       cliCtx.String(cmd.P2PGossipTraceFile.Name),
+		GossipTraceSampleRate: cliCtx.Float64(cmd.P2PGossipTraceSampleRate.Name),
+		GossipTraceMaxSizeMB:  cliCtx.Int(cmd.P2PGossipTraceMaxSize.Name),
 	})
 	if err != nil {
 		return err
@@ -920,6 +979,16 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 		return err
 	}
 
+	var regularSyncService *regularsync.Service
+	if err := b.services.FetchService(&regularSyncService); err != nil {
+		return err
+	}
+
+	var backfillService *backfill.Service
+	if err := b.services.FetchService(&backfillService); err != nil {
+		return err
+	}
+
 	var slasherService *slasher.Service
 	if features.Get().EnableSlasher {
 		if err := b.services.FetchService(&slasherService); err != nil {
@@ -952,6 +1021,15 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 	maxMsgSize := b.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name)
 	enableDebugRPCEndpoints := !b.cliCtx.Bool(flags.DisableDebugRPCEndpoints.Name)
 
+	snapshotToken, err := snapshotAuthToken(b.cliCtx)
+	if err != nil {
+		return err
+	}
+	snapshotDir := b.cliCtx.String(cmd.BackupWebhookOutputDir.Name)
+	if snapshotDir == "" {
+		snapshotDir = filepath.Join(b.db.DatabasePath(), kv.BackupsDirName)
+	}
+
 	p2pService := b.fetchP2P()
 	rpcService := rpc.NewService(b.ctx, &rpc.Config{
 		ExecutionEngineCaller:     web3Service,
@@ -989,6 +1067,9 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 		ChainStartFetcher:         chainStartFetcher,
 		MockEth1Votes:             mockEth1DataVotes,
 		SyncService:               syncService,
+		RateLimitUsageFetcher:     regularSyncService,
+		PendingBlocksFetcher:      regularSyncService,
+		BackfillController:        backfillService,
 		DepositFetcher:            depositFetcher,
 		PendingDepositFetcher:     b.depositCache,
 		BlockNotifier:             b,
@@ -1003,18 +1084,41 @@ func (b *BeaconNode) registerRPCService(router *http.ServeMux) error {
 		BlobStorage:               b.BlobStorage,
 		TrackedValidatorsCache:    b.trackedValidatorsCache,
 		PayloadIDCache:            b.payloadIDCache,
+		Snapshotter:               b.db,
+		SnapshotDir:               snapshotDir,
+		SnapshotAuthToken:         snapshotToken,
 	})
 
 	return b.services.RegisterService(rpcService)
 }
 
+// snapshotAuthToken reads the token authorizing snapshot requests, which is empty if snapshots are disabled.
+func snapshotAuthToken(cliCtx *cli.Context) (string, error) {
+	tokenPath := cliCtx.String(flags.SnapshotAuthTokenFile.Name)
+	if tokenPath == "" {
+		return "", nil
+	}
+	enc, err := os.ReadFile(tokenPath) // #nosec G304
+	if err != nil {
+		return "", errors.Wrap(err, "could not read snapshot auth token file")
+	}
+	token := strings.TrimSpace(string(enc))
+	if token == "" {
+		return "", fmt.Errorf("snapshot auth token file %s is empty", tokenPath)
+	}
+	return token, nil
+}
+
 func (b *BeaconNode) registerPrometheusService(_ *cli.Context) error {
 	var additionalHandlers []prometheus.Handler
-	var p *p2p.Service
-	if err := b.services.FetchService(&p); err != nil {
-		panic(err)
+	// A read-only replica runs without p2p.
+	if !b.cliCtx.Bool(flags.ReadOnlyReplica.Name) {
+		var p *p2p.Service
+		if err := b.services.FetchService(&p); err != nil {
+			panic(err)
+		}
+		additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/p2p", Handler: p.InfoHandler})
 	}
-	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/p2p", Handler: p.InfoHandler})
 
 	var c *blockchain.Service
 	if err := b.services.FetchService(&c); err != nil {
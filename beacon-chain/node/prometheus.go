@@ -2,7 +2,9 @@ package node
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -49,11 +51,29 @@ func (bc *bcnodeCollector) Collect(ch chan<- prometheus.Metric) {
 }
 
 func (bc *bcnodeCollector) getCurrentDbBytes() (float64, error) {
-	fs, err := os.Stat(bc.dbPath)
+	info, err := os.Stat(bc.dbPath)
 	if err != nil {
 		return 0, fmt.Errorf("could not collect database file size for prometheus, path=%s, err=%w", bc.dbPath, err)
 	}
-	return float64(fs.Size()), nil
+	if !info.IsDir() {
+		return float64(info.Size()), nil
+	}
+	// Databases stored in a directory are as large as all their files.
+	var size int64
+	if err := filepath.WalkDir(bc.dbPath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("could not collect database directory size for prometheus, path=%s, err=%w", bc.dbPath, err)
+	}
+	return float64(size), nil
 }
 
 func (bc *bcnodeCollector) unregister() {
@@ -0,0 +1,189 @@
+package node
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/cache/depositsnapshot"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	regularsync "github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill"
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/flags"
+	"github.com/urfave/cli/v2"
+)
+
+// registerReplicaServices opens the database of the data directory read only and registers the services of a
+// read-only replica, which serves the REST API from the database and blobs another node writes to. It runs no p2p,
+// sync or execution client, and writes to neither.
+func registerReplicaServices(cliCtx *cli.Context, beacon *BeaconNode, synchronizer *startup.ClockSynchronizer) error {
+	ctx := cliCtx.Context
+	log.Debugln("Starting DB read only")
+	if err := beacon.startReplicaDB(cliCtx); err != nil {
+		return errors.Wrap(err, "could not start DB")
+	}
+	beacon.BlobStorage.WarmCache()
+
+	bfs, err := backfill.NewUpdater(ctx, beacon.db)
+	if err != nil {
+		return errors.Wrap(err, "could not create backfill updater")
+	}
+
+	log.Debugln("Starting State Gen")
+	if err := beacon.startStateGen(ctx, bfs, beacon.forkChoicer); err != nil {
+		return errors.Wrap(err, "could not start state generation")
+	}
+
+	log.Debugln("Registering Attestation Pool Service")
+	if err := beacon.registerAttestationPool(); err != nil {
+		return errors.Wrap(err, "could not register attestation pool service")
+	}
+
+	log.Debugln("Registering Blockchain Service")
+	if err := beacon.registerReplicaBlockchainService(synchronizer); err != nil {
+		return errors.Wrap(err, "could not register blockchain service")
+	}
+
+	log.Debugln("Registering RPC Service")
+	router := http.NewServeMux()
+	if err := beacon.registerReplicaRPCService(router); err != nil {
+		return errors.Wrap(err, "could not register RPC service")
+	}
+
+	log.Debugln("Registering HTTP Service")
+	if err := beacon.registerHTTPService(router); err != nil {
+		return errors.Wrap(err, "could not register HTTP service")
+	}
+
+	if !cliCtx.Bool(cmd.DisableMonitoringFlag.Name) {
+		log.Debugln("Registering Prometheus Service")
+		if err := beacon.registerPrometheusService(cliCtx); err != nil {
+			return errors.Wrap(err, "could not register prometheus service")
+		}
+	}
+
+	return nil
+}
+
+// startReplicaDB opens the newest replica checkpoint of the database of the data directory. Unlike startDB, it
+// neither runs migrations nor initializes genesis, as the node writing to the database does.
+func (b *BeaconNode) startReplicaDB(cliCtx *cli.Context) error {
+	dbPath := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), kv.BeaconNodeDbDirName)
+	opts := []kv.KVStoreOption{kv.WithReplica()}
+	if cliCtx.IsSet(flags.DBBackend.Name) {
+		backend, err := engine.ParseBackend(cliCtx.String(flags.DBBackend.Name))
+		if err != nil {
+			return errors.Wrapf(err, "could not parse --%s", flags.DBBackend.Name)
+		}
+		if backend != engine.Pebble {
+			return errors.Errorf("--%s needs the %s backend, not %s", flags.ReadOnlyReplica.Name, engine.Pebble, backend)
+		}
+		opts = append(opts, kv.WithBackend(backend))
+	}
+
+	log.WithField("databasePath", dbPath).Info("Checking DB")
+
+	d, err := kv.NewKVStore(b.ctx, dbPath, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "could not open database at %s", dbPath)
+	}
+	b.db = d
+
+	depositCache, err := depositsnapshot.New()
+	if err != nil {
+		return errors.Wrap(err, "could not create deposit cache")
+	}
+	b.depositCache = depositCache
+	return nil
+}
+
+func (b *BeaconNode) registerReplicaBlockchainService(gs *startup.ClockSynchronizer) error {
+	refresher, ok := b.db.(blockchain.DatabaseRefresher)
+	if !ok {
+		return errors.New("database can not be refreshed")
+	}
+
+	var attService *attestations.Service
+	if err := b.services.FetchService(&attService); err != nil {
+		return err
+	}
+
+	// skipcq: CRT-D0001
+	opts := append(
+		b.serviceFlagOpts.blockchainFlagOpts,
+		blockchain.WithForkChoiceStore(b.forkChoicer),
+		blockchain.WithDatabase(b.db),
+		blockchain.WithDepositCache(b.depositCache),
+		blockchain.WithAttestationPool(b.attestationPool),
+		blockchain.WithExitPool(b.exitPool),
+		blockchain.WithSlashingPool(b.slashingsPool),
+		blockchain.WithBLSToExecPool(b.blsToExecPool),
+		blockchain.WithStateNotifier(b),
+		blockchain.WithAttestationService(attService),
+		blockchain.WithStateGen(b.stateGen),
+		blockchain.WithFinalizedStateAtStartUp(b.finalizedStateAtStartUp),
+		blockchain.WithClockSynchronizer(gs),
+		blockchain.WithBlobStorage(b.BlobStorage),
+		blockchain.WithTrackedValidatorsCache(b.trackedValidatorsCache),
+		blockchain.WithPayloadIDCache(b.payloadIDCache),
+		blockchain.WithReadOnlyReplica(refresher, b.cliCtx.Duration(flags.ReplicaRefreshInterval.Name)),
+	)
+
+	blockchainService, err := blockchain.NewService(b.ctx, opts...)
+	if err != nil {
+		return errors.Wrap(err, "could not register blockchain service")
+	}
+	return b.services.RegisterService(blockchainService)
+}
+
+// registerReplicaRPCService registers the REST API of a read-only replica. The endpoints needing the p2p, sync or
+// execution services the replica runs without respond that they are not supported.
+func (b *BeaconNode) registerReplicaRPCService(router *http.ServeMux) error {
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+
+	rpcService := rpc.NewService(b.ctx, &rpc.Config{
+		BeaconMonitoringHost:    b.cliCtx.String(cmd.MonitoringHostFlag.Name),
+		BeaconMonitoringPort:    b.cliCtx.Int(flags.MonitoringPortFlag.Name),
+		BeaconDB:                b.db,
+		ChainInfoFetcher:        chainService,
+		HeadFetcher:             chainService,
+		CanonicalFetcher:        chainService,
+		ForkFetcher:             chainService,
+		ForkchoiceFetcher:       chainService,
+		FinalizationFetcher:     chainService,
+		GenesisTimeFetcher:      chainService,
+		GenesisFetcher:          chainService,
+		OptimisticModeFetcher:   chainService,
+		AttestationsPool:        b.attestationPool,
+		ExitPool:                b.exitPool,
+		SlashingsPool:           b.slashingsPool,
+		BLSChangesPool:          b.blsToExecPool,
+		SyncCommitteeObjectPool: b.syncCommitteePool,
+		SyncService:             regularsync.ReplicaChecker{},
+		DepositFetcher:          b.depositCache,
+		PendingDepositFetcher:   b.depositCache,
+		BlockNotifier:           b,
+		StateNotifier:           b,
+		OperationNotifier:       b,
+		StateGen:                b.stateGen,
+		EnableDebugRPCEndpoints: !b.cliCtx.Bool(flags.DisableDebugRPCEndpoints.Name),
+		MaxMsgSize:              b.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
+		Router:                  router,
+		ClockWaiter:             b.clockWaiter,
+		BlobStorage:             b.BlobStorage,
+		TrackedValidatorsCache:  b.trackedValidatorsCache,
+		PayloadIDCache:          b.payloadIDCache,
+		ReadOnly:                true,
+	})
+
+	return b.services.RegisterService(rpcService)
+}
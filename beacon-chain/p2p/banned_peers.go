@@ -0,0 +1,119 @@
+package p2p
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/sirupsen/logrus"
+)
+
+const bannedPeersPath = "banned-peers"
+
+// bannedPeer is the on-disk representation of a manual peer ban.
+type bannedPeer struct {
+	PeerID string `json:"peer_id"`
+	// Expiry is the unix timestamp at which the ban is lifted, 0 means the ban never expires.
+	Expiry int64 `json:"expiry"`
+}
+
+// BanPeer manually bans a peer until the provided expiry and disconnects from it.
+// A zero expiry bans the peer until it is explicitly unbanned. Bans are persisted
+// in the data directory and restored on the next node start.
+func (s *Service) BanPeer(pid peer.ID, expiry time.Time) error {
+	s.bannedPeersLock.Lock()
+	defer s.bannedPeersLock.Unlock()
+
+	s.peers.BanPeer(pid, expiry)
+	if err := saveBannedPeers(s.cfg, s.peers); err != nil {
+		return errors.Wrap(err, "could not persist banned peers")
+	}
+	log.WithFields(logrus.Fields{
+		"peer":   pid,
+		"expiry": expiry,
+	}).Info("Banned peer")
+	if s.host == nil {
+		return nil
+	}
+	return s.Disconnect(pid)
+}
+
+// UnbanPeer lifts a manual ban from the peer.
+func (s *Service) UnbanPeer(pid peer.ID) error {
+	s.bannedPeersLock.Lock()
+	defer s.bannedPeersLock.Unlock()
+
+	if !s.peers.UnbanPeer(pid) {
+		return nil
+	}
+	if err := saveBannedPeers(s.cfg, s.peers); err != nil {
+		return errors.Wrap(err, "could not persist banned peers")
+	}
+	log.WithField("peer", pid).Info("Unbanned peer")
+	return nil
+}
+
+// loadBannedPeers restores the manual peer bans stored in the data directory.
+func loadBannedPeers(cfg *Config, status *peers.Status) error {
+	if cfg.DataDir == "" {
+		return nil
+	}
+	bansPath := path.Join(cfg.DataDir, bannedPeersPath)
+	exists, err := file.Exists(bansPath, file.Regular)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	enc, err := os.ReadFile(bansPath) // #nosec G304
+	if err != nil {
+		return err
+	}
+	var bans []*bannedPeer
+	if err := json.Unmarshal(enc, &bans); err != nil {
+		return errors.Wrap(err, "could not decode banned peers")
+	}
+	for _, b := range bans {
+		pid, err := peer.Decode(b.PeerID)
+		if err != nil {
+			log.WithError(err).WithField("peer", b.PeerID).Warn("Skipping invalid banned peer")
+			continue
+		}
+		var expiry time.Time
+		if b.Expiry != 0 {
+			expiry = time.Unix(b.Expiry, 0)
+		}
+		status.BanPeer(pid, expiry)
+	}
+	return nil
+}
+
+// saveBannedPeers writes all active manual peer bans to the data directory.
+func saveBannedPeers(cfg *Config, status *peers.Status) error {
+	if cfg.DataDir == "" {
+		return nil
+	}
+	bannedPeers := status.BannedPeers()
+	bans := make([]*bannedPeer, 0, len(bannedPeers))
+	for pid, expiry := range bannedPeers {
+		b := &bannedPeer{PeerID: pid.String()}
+		if !expiry.IsZero() {
+			b.Expiry = expiry.Unix()
+		}
+		bans = append(bans, b)
+	}
+	enc, err := json.Marshal(bans)
+	if err != nil {
+		return err
+	}
+	if err := file.MkdirAll(cfg.DataDir); err != nil {
+		return err
+	}
+	return file.WriteFile(path.Join(cfg.DataDir, bannedPeersPath), enc)
+}
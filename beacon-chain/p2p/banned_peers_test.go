@@ -0,0 +1,59 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestService_BanPeer_Persisted(t *testing.T) {
+	cfg := &Config{DataDir: t.TempDir()}
+	newStatus := func() *peers.Status {
+		return peers.NewStatus(context.Background(), &peers.StatusConfig{
+			PeerLimit:    30,
+			ScorerParams: &scorers.Config{},
+		})
+	}
+	s := &Service{cfg: cfg, peers: newStatus()}
+
+	permanent, err := peer.Decode("16Uiu2HAm1n583t4huDMMqEUUBuQs6bLts21mxCfX3tiqu9JfHvRJ")
+	require.NoError(t, err)
+	temporary, err := peer.Decode("16Uiu2HAm7yD5fhhw1Kihg5pffaGbvKV3k7sqxRGHMZzkb7u9UUxQ")
+	require.NoError(t, err)
+	expiry := time.Now().Add(time.Hour)
+
+	require.NoError(t, s.BanPeer(permanent, time.Time{}))
+	require.NoError(t, s.BanPeer(temporary, expiry))
+	assert.Equal(t, false, s.InterceptPeerDial(permanent))
+	assert.Equal(t, false, s.InterceptPeerDial(temporary))
+
+	restored := newStatus()
+	require.NoError(t, loadBannedPeers(cfg, restored))
+	bans := restored.BannedPeers()
+	require.Equal(t, 2, len(bans))
+	assert.Equal(t, true, bans[permanent].IsZero())
+	assert.Equal(t, expiry.Unix(), bans[temporary].Unix())
+
+	require.NoError(t, s.UnbanPeer(permanent))
+	assert.Equal(t, true, s.InterceptPeerDial(permanent))
+
+	restored = newStatus()
+	require.NoError(t, loadBannedPeers(cfg, restored))
+	assert.Equal(t, false, restored.IsBanned(permanent))
+	assert.Equal(t, true, restored.IsBanned(temporary))
+}
+
+func TestLoadBannedPeers_NoFile(t *testing.T) {
+	status := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		PeerLimit:    30,
+		ScorerParams: &scorers.Config{},
+	})
+	require.NoError(t, loadBannedPeers(&Config{DataDir: t.TempDir()}, status))
+	assert.Equal(t, 0, len(status.BannedPeers()))
+}
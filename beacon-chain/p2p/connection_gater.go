@@ -25,8 +25,9 @@ const (
 )
 
 // InterceptPeerDial tests whether we're permitted to Dial the specified peer.
-func (*Service) InterceptPeerDial(_ peer.ID) (allow bool) {
-	return true
+func (s *Service) InterceptPeerDial(pid peer.ID) (allow bool) {
+	// Never dial manually banned peers.
+	return !s.peers.IsBanned(pid)
 }
 
 // InterceptAddrDial tests whether we're permitted to dial the specified
@@ -63,7 +64,13 @@ func (s *Service) InterceptAccept(n network.ConnMultiaddrs) (allow bool) {
 
 // InterceptSecured tests whether a given connection, now authenticated,
 // is allowed.
-func (*Service) InterceptSecured(_ network.Direction, _ peer.ID, _ network.ConnMultiaddrs) (allow bool) {
+func (s *Service) InterceptSecured(_ network.Direction, pid peer.ID, n network.ConnMultiaddrs) (allow bool) {
+	// Inbound dials only reveal the remote peer id once secured.
+	if s.peers.IsBanned(pid) {
+		log.WithFields(logrus.Fields{"peer": n.RemoteMultiaddr(),
+			"reason": "peer is banned"}).Trace("Not accepting connection")
+		return false
+	}
 	return true
 }
 
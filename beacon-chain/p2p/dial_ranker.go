@@ -0,0 +1,54 @@
+package p2p
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// tcpFallbackDelay is how long TCP dials are held back while a QUIC dial to the same
+// peer is in flight. The swarm starts the TCP dials right away if all QUIC dials fail.
+const tcpFallbackDelay = 2 * time.Second
+
+// quicFirstDialRanker is a dial ranker that dials the QUIC addresses of a peer first and
+// only falls back to its other addresses if no QUIC connection could be established.
+func quicFirstDialRanker(addrs []ma.Multiaddr) []network.AddrDelay {
+	hasQUIC := false
+	for _, a := range addrs {
+		if isQUICAddr(a) {
+			hasQUIC = true
+			break
+		}
+	}
+	delays := make([]network.AddrDelay, 0, len(addrs))
+	for _, a := range addrs {
+		var delay time.Duration
+		if hasQUIC && !isQUICAddr(a) {
+			delay = tcpFallbackDelay
+		}
+		delays = append(delays, network.AddrDelay{Addr: a, Delay: delay})
+	}
+	return delays
+}
+
+// transportFromAddr returns the name of the transport used by the multiaddr, as used in metric labels.
+func transportFromAddr(a ma.Multiaddr) string {
+	switch {
+	case isQUICAddr(a):
+		return quic
+	case hasProtocol(a, ma.P_TCP):
+		return tcp
+	default:
+		return "other"
+	}
+}
+
+func isQUICAddr(a ma.Multiaddr) bool {
+	return hasProtocol(a, ma.P_QUIC_V1)
+}
+
+func hasProtocol(a ma.Multiaddr, code int) bool {
+	_, err := a.ValueForProtocol(code)
+	return err == nil
+}
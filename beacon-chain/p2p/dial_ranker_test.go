@@ -0,0 +1,110 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestQUICFirstDialRanker(t *testing.T) {
+	tcpAddr, err := ma.NewMultiaddr("/ip4/1.2.3.4/tcp/13000")
+	require.NoError(t, err)
+	quicAddr, err := ma.NewMultiaddr("/ip4/1.2.3.4/udp/13000/quic-v1")
+	require.NoError(t, err)
+
+	t.Run("QUIC and TCP", func(t *testing.T) {
+		delays := quicFirstDialRanker([]ma.Multiaddr{tcpAddr, quicAddr})
+		require.Equal(t, 2, len(delays))
+		assert.Equal(t, tcpFallbackDelay, delays[0].Delay)
+		assert.Equal(t, time.Duration(0), delays[1].Delay)
+	})
+	t.Run("TCP only", func(t *testing.T) {
+		delays := quicFirstDialRanker([]ma.Multiaddr{tcpAddr})
+		require.Equal(t, 1, len(delays))
+		assert.Equal(t, time.Duration(0), delays[0].Delay)
+	})
+}
+
+func TestTransportFromAddr(t *testing.T) {
+	tcpAddr, err := ma.NewMultiaddr("/ip4/1.2.3.4/tcp/13000")
+	require.NoError(t, err)
+	quicAddr, err := ma.NewMultiaddr("/ip4/1.2.3.4/udp/13000/quic-v1")
+	require.NoError(t, err)
+	udpAddr, err := ma.NewMultiaddr("/ip4/1.2.3.4/udp/13000")
+	require.NoError(t, err)
+
+	assert.Equal(t, tcp, transportFromAddr(tcpAddr))
+	assert.Equal(t, quic, transportFromAddr(quicAddr))
+	assert.Equal(t, "other", transportFromAddr(udpAddr))
+}
+
+func TestService_ConnectOverQUIC(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	resetCfg := features.InitWithReset(&features.Flags{EnableQUIC: true})
+	defer resetCfg()
+
+	newService := func(tcpPort, udpPort uint) *Service {
+		s, err := NewService(context.Background(), &Config{
+			StateNotifier: &mock.MockStateNotifier{},
+			NoDiscovery:   true,
+			LocalIP:       "127.0.0.1",
+			TCPPort:       tcpPort,
+			QUICPort:      udpPort,
+			UDPPort:       udpPort,
+			MaxPeers:      10,
+		})
+		require.NoError(t, err)
+		// Inbound connections are only accepted once the service is started.
+		s.started = true
+		return s
+	}
+	s1 := newService(freePort(t, "tcp"), freePort(t, "udp"))
+	quicPort := freePort(t, "udp")
+	s2 := newService(freePort(t, "tcp"), quicPort)
+	defer func() {
+		require.NoError(t, s1.host.Close())
+		require.NoError(t, s2.host.Close())
+	}()
+
+	// Only provide the QUIC address of the second node.
+	quicAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/udp/%d/quic-v1", quicPort))
+	require.NoError(t, err)
+	info := peer.AddrInfo{ID: s2.host.ID(), Addrs: []ma.Multiaddr{quicAddr}}
+	require.NoError(t, s1.connectWithPeer(context.Background(), info))
+
+	conns := s1.host.Network().ConnsToPeer(s2.host.ID())
+	require.Equal(t, 1, len(conns))
+	assert.Equal(t, quic, transportFromAddr(conns[0].RemoteMultiaddr()))
+	conns = s2.host.Network().ConnsToPeer(s1.host.ID())
+	require.Equal(t, 1, len(conns))
+	assert.Equal(t, quic, transportFromAddr(conns[0].RemoteMultiaddr()))
+}
+
+// freePort returns a port of the given network ("tcp" or "udp") which the system reports as unused on the loopback
+// interface.
+func freePort(t *testing.T, network string) uint {
+	if network == "udp" {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+		return uint(conn.LocalAddr().(*net.UDPAddr).Port)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, l.Close())
+	}()
+	return uint(l.Addr().(*net.TCPAddr).Port)
+}
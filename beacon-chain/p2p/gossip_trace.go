@@ -0,0 +1,174 @@
+package p2p
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Types of gossip events written to the gossip trace file.
+const (
+	// GossipTraceReceive is recorded for every copy of a message received in an RPC, before pubsub checks whether
+	// it was already seen. Pubsub does not tell the tracer which peer sent the RPC, so it has no peer ID.
+	GossipTraceReceive = "receive"
+	// GossipTraceValidate is recorded when a message is seen for the first time and queued for validation.
+	GossipTraceValidate = "validate"
+	// GossipTraceReject is recorded when a message fails validation.
+	GossipTraceReject = "reject"
+	// GossipTraceDuplicate is recorded when an already seen message is received again.
+	GossipTraceDuplicate = "duplicate"
+	// GossipTraceDeliver is recorded when a message passed validation and is delivered to subscribers.
+	GossipTraceDeliver = "deliver"
+)
+
+const (
+	// Size of the buffer between the pubsub tracer and the trace file. Events are
+	// dropped rather than blocking pubsub when the buffer is full.
+	gossipTraceBufferSize = 4096
+	// Number of rotated trace files to keep around.
+	gossipTraceMaxBackups = 10
+	// Interval at which buffered events are flushed to the trace file.
+	gossipTraceFlushInterval = time.Second
+)
+
+// GossipTraceEvent is a single gossip event as written to the trace file, one JSON object per line.
+type GossipTraceEvent struct {
+	Type string `json:"type"`
+	// Time is the unix timestamp of the event in nanoseconds.
+	Time   int64  `json:"time"`
+	PeerID string `json:"peer_id,omitempty"`
+	Topic  string `json:"topic"`
+	// MsgID is the hex encoded gossip message ID.
+	MsgID  string `json:"msg_id"`
+	Local  bool   `json:"local,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// gossipTraceWriter writes sampled gossip events to a rotating trace file. Sampling is decided
+// from the message ID so that nodes with the same sample rate trace the same messages, which
+// allows comparing traces from different nodes.
+type gossipTraceWriter struct {
+	sampleThreshold uint64
+	events          chan *GossipTraceEvent
+	out             io.WriteCloser
+	done            chan struct{}
+	closed          bool
+	sync.RWMutex
+}
+
+// newGossipTraceWriter creates a trace writer for the given file, rotating it once it grows
+// above maxSizeMB megabytes. The sample rate is the fraction of messages to trace, in [0, 1].
+func newGossipTraceWriter(path string, sampleRate float64, maxSizeMB int) *gossipTraceWriter {
+	out := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: gossipTraceMaxBackups,
+	}
+	return startGossipTraceWriter(out, sampleRate)
+}
+
+func startGossipTraceWriter(out io.WriteCloser, sampleRate float64) *gossipTraceWriter {
+	var threshold uint64
+	switch {
+	case sampleRate >= 1:
+		threshold = math.MaxUint64
+	case sampleRate > 0:
+		threshold = uint64(sampleRate * math.MaxUint64)
+	}
+	w := &gossipTraceWriter{
+		sampleThreshold: threshold,
+		events:          make(chan *GossipTraceEvent, gossipTraceBufferSize),
+		out:             out,
+		done:            make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// sampled reports whether events for the message with the given ID should be traced.
+func (w *gossipTraceWriter) sampled(msgID string) bool {
+	if w.sampleThreshold == math.MaxUint64 {
+		return true
+	}
+	h := hash.Hash([]byte(msgID))
+	return binary.LittleEndian.Uint64(h[:8]) < w.sampleThreshold
+}
+
+// trace records an event for the message if it is sampled. It never blocks.
+func (w *gossipTraceWriter) trace(typ string, pid peer.ID, topic, msgID string, local bool, reason string) {
+	if !w.sampled(msgID) {
+		return
+	}
+	evt := &GossipTraceEvent{
+		Type:   typ,
+		Time:   time.Now().UnixNano(),
+		Topic:  topic,
+		MsgID:  hex.EncodeToString([]byte(msgID)),
+		Local:  local,
+		Reason: reason,
+	}
+	if pid != "" {
+		evt.PeerID = pid.String()
+	}
+	w.RLock()
+	defer w.RUnlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.events <- evt:
+	default:
+		gossipTraceEventsDropped.Inc()
+	}
+}
+
+func (w *gossipTraceWriter) run() {
+	defer close(w.done)
+	buf := bufio.NewWriter(w.out)
+	enc := json.NewEncoder(buf)
+	ticker := time.NewTicker(gossipTraceFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case evt, ok := <-w.events:
+			if !ok {
+				if err := buf.Flush(); err != nil {
+					log.WithError(err).Error("Could not flush gossip trace")
+				}
+				return
+			}
+			if err := enc.Encode(evt); err != nil {
+				log.WithError(err).Error("Could not write gossip trace event")
+			}
+		case <-ticker.C:
+			if err := buf.Flush(); err != nil {
+				log.WithError(err).Error("Could not flush gossip trace")
+			}
+		}
+	}
+}
+
+// close flushes the pending events and closes the trace file. Events traced after
+// close are dropped.
+func (w *gossipTraceWriter) close() error {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.events)
+	w.Unlock()
+
+	<-w.done
+	return w.out.Close()
+}
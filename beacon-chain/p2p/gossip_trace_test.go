@@ -0,0 +1,113 @@
+package p2p
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestGossipTraceWriter(t *testing.T) {
+	tracePath := path.Join(t.TempDir(), "gossip.jsonl")
+	w := newGossipTraceWriter(tracePath, 1, 1)
+	pid, err := peer.Decode("16Uiu2HAm1n583t4huDMMqEUUBuQs6bLts21mxCfX3tiqu9JfHvRJ")
+	require.NoError(t, err)
+
+	w.trace(GossipTraceValidate, pid, "topic", "msg", false, "")
+	w.trace(GossipTraceReject, pid, "topic", "msg", false, "invalid")
+	require.NoError(t, w.close())
+	// Events traced after close are dropped.
+	w.trace(GossipTraceDeliver, pid, "topic", "msg", false, "")
+
+	events := readGossipTraceEvents(t, tracePath)
+	require.Equal(t, 2, len(events))
+	assert.Equal(t, GossipTraceValidate, events[0].Type)
+	assert.Equal(t, pid.String(), events[0].PeerID)
+	assert.Equal(t, "topic", events[0].Topic)
+	assert.Equal(t, hex.EncodeToString([]byte("msg")), events[0].MsgID)
+	assert.NotEqual(t, int64(0), events[0].Time)
+	assert.Equal(t, GossipTraceReject, events[1].Type)
+	assert.Equal(t, "invalid", events[1].Reason)
+}
+
+func TestGossipTracer_UsesPubsubMessageID(t *testing.T) {
+	tracePath := path.Join(t.TempDir(), "gossip.jsonl")
+	w := newGossipTraceWriter(tracePath, 1, 1)
+	g := gossipTracer{trace: w, msgID: func(*pubsubpb.Message) string {
+		return "id"
+	}}
+	pid, err := peer.Decode("16Uiu2HAm1n583t4huDMMqEUUBuQs6bLts21mxCfX3tiqu9JfHvRJ")
+	require.NoError(t, err)
+
+	topic := "topic"
+	pmsg := &pubsubpb.Message{Topic: &topic, Data: []byte("data")}
+	// Received messages are traced with the ID pubsub assigns them afterwards.
+	g.RecvRPC(&pubsub.RPC{RPC: pubsubpb.RPC{Publish: []*pubsubpb.Message{pmsg}}})
+	g.ValidateMessage(&pubsub.Message{Message: pmsg, ID: "id", ReceivedFrom: pid})
+	g.RecvRPC(&pubsub.RPC{RPC: pubsubpb.RPC{Publish: []*pubsubpb.Message{pmsg}}})
+	g.DuplicateMessage(&pubsub.Message{Message: pmsg, ID: "id", ReceivedFrom: pid})
+	require.NoError(t, w.close())
+
+	events := readGossipTraceEvents(t, tracePath)
+	require.Equal(t, 4, len(events))
+	for i, typ := range []string{GossipTraceReceive, GossipTraceValidate, GossipTraceReceive, GossipTraceDuplicate} {
+		assert.Equal(t, typ, events[i].Type)
+		assert.Equal(t, hex.EncodeToString([]byte("id")), events[i].MsgID)
+		assert.Equal(t, topic, events[i].Topic)
+	}
+	// The sender of an RPC is not known to the tracer.
+	assert.Equal(t, "", events[0].PeerID)
+	assert.Equal(t, pid.String(), events[1].PeerID)
+	assert.Equal(t, pid.String(), events[3].PeerID)
+}
+
+func TestGossipTraceWriter_Sampling(t *testing.T) {
+	all := newGossipTraceWriter(path.Join(t.TempDir(), "all.jsonl"), 1, 1)
+	none := newGossipTraceWriter(path.Join(t.TempDir(), "none.jsonl"), 0, 1)
+	some := newGossipTraceWriter(path.Join(t.TempDir(), "some.jsonl"), 0.5, 1)
+	other := newGossipTraceWriter(path.Join(t.TempDir(), "other.jsonl"), 0.5, 1)
+	defer func() {
+		for _, w := range []*gossipTraceWriter{all, none, some, other} {
+			require.NoError(t, w.close())
+		}
+	}()
+
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("msg-%d", i)
+		assert.Equal(t, true, all.sampled(id))
+		assert.Equal(t, false, none.sampled(id))
+		// Writers with the same sample rate sample the same messages.
+		assert.Equal(t, some.sampled(id), other.sampled(id))
+		if some.sampled(id) {
+			sampled++
+		}
+	}
+	assert.Equal(t, true, sampled > 400 && sampled < 600, "unexpected number of sampled messages: %d", sampled)
+}
+
+func readGossipTraceEvents(t *testing.T, tracePath string) []*GossipTraceEvent {
+	f, err := os.Open(tracePath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	var events []*GossipTraceEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		evt := &GossipTraceEvent{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), evt))
+		events = append(events, evt)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
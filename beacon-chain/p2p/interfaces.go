@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -85,6 +86,8 @@ type PeerManager interface {
 	RefreshENR()
 	FindPeersWithSubnet(ctx context.Context, topic string, subIndex uint64, threshold int) (bool, error)
 	AddPingMethod(reqFunc func(ctx context.Context, id peer.ID) error)
+	BanPeer(pid peer.ID, expiry time.Time) error
+	UnbanPeer(pid peer.ID) error
 }
 
 // Sender abstracts the sending functionality from libp2p.
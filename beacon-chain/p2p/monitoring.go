@@ -2,9 +2,12 @@ package p2p
 
 import (
 	"strings"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/net/swarm"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -161,6 +164,30 @@ var (
 		Help: "The number of publish messages sent via rpc for a particular topic",
 	},
 		[]string{"topic"})
+	gossipTraceEventsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "p2p_gossip_trace_events_dropped_total",
+		Help: "The number of gossip trace events dropped because the trace file could not keep up",
+	})
+	connectionCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "p2p_connection_count",
+		Help: "The number of open libp2p connections by transport and direction",
+	},
+		[]string{"transport", "direction"})
+	dialDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "p2p_dial_duration_seconds",
+		Help:    "Time taken to dial a peer and complete the security and muxer handshakes, by transport of the established connection",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	},
+		[]string{"transport"})
+	dialFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_dial_failures_total",
+		Help: "The number of failed dials to a peer address by transport",
+	},
+		[]string{"transport"})
+	quicFallbacks = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "p2p_quic_fallback_total",
+		Help: "The number of dials to peers advertising QUIC which ended up connected over another transport",
+	})
 )
 
 func (s *Service) updateMetrics() {
@@ -171,6 +198,11 @@ func (s *Service) updateMetrics() {
 	p2pPeerCount.WithLabelValues("Disconnecting").Set(float64(len(s.peers.Disconnecting())))
 	p2pPeerCount.WithLabelValues("Bad").Set(float64(len(s.peers.Bad())))
 
+	connectionCount.Reset()
+	for _, conn := range s.host.Network().Conns() {
+		connectionCount.WithLabelValues(transportFromAddr(conn.RemoteMultiaddr()), conn.Stat().Direction.String()).Inc()
+	}
+
 	store := s.Host().Peerstore()
 	numConnectedPeersByClient := make(map[string]float64)
 	peerScoresByClient := make(map[string][]float64)
@@ -226,3 +258,34 @@ func agentFromPid(pid peer.ID, store peerstore.Peerstore) string {
 	}
 	return foundName
 }
+
+// recordDial records the duration of a successful dial to a peer, and whether the dial had to fall
+// back from QUIC to another transport.
+func (s *Service) recordDial(info peer.AddrInfo, duration time.Duration) {
+	conns := s.host.Network().ConnsToPeer(info.ID)
+	if len(conns) == 0 {
+		return
+	}
+	transport := transportFromAddr(conns[0].RemoteMultiaddr())
+	dialDuration.WithLabelValues(transport).Observe(duration.Seconds())
+	if transport == quic {
+		return
+	}
+	for _, a := range info.Addrs {
+		if isQUICAddr(a) {
+			quicFallbacks.Inc()
+			return
+		}
+	}
+}
+
+// recordDialFailures records the failed dials of every address contained in the dial error.
+func recordDialFailures(err error) {
+	var dialErr *swarm.DialError
+	if !errors.As(err, &dialErr) {
+		return
+	}
+	for _, e := range dialErr.DialErrors {
+		dialFailures.WithLabelValues(transportFromAddr(e.Address)).Inc()
+	}
+}
@@ -10,6 +10,7 @@ import (
 	mplex "github.com/libp2p/go-libp2p-mplex"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/swarm"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
 	libp2pquic "github.com/libp2p/go-libp2p/p2p/transport/quic"
 	libp2ptcp "github.com/libp2p/go-libp2p/p2p/transport/tcp"
@@ -100,7 +101,11 @@ func (s *Service) buildOptions(ip net.IP, priKey *ecdsa.PrivateKey) ([]libp2p.Op
 	}
 
 	if features.Get().EnableQUIC {
-		options = append(options, libp2p.Transport(libp2pquic.NewTransport))
+		options = append(options,
+			libp2p.Transport(libp2pquic.NewTransport),
+			// Prefer QUIC when dialing peers supporting it, falling back to TCP.
+			libp2p.SwarmOpts(swarm.WithDialRanker(quicFirstDialRanker)),
+		)
 	}
 
 	if cfg.EnableUPnP {
@@ -42,6 +42,7 @@ type Store struct {
 	config       *StoreConfig
 	peers        map[peer.ID]*PeerData
 	trustedPeers map[peer.ID]bool
+	bannedPeers  map[peer.ID]time.Time
 }
 
 // PeerData aggregates protocol and application level info about a single peer.
@@ -74,6 +75,7 @@ func NewStore(ctx context.Context, config *StoreConfig) *Store {
 		config:       config,
 		peers:        make(map[peer.ID]*PeerData),
 		trustedPeers: make(map[peer.ID]bool),
+		bannedPeers:  make(map[peer.ID]time.Time),
 	}
 }
 
@@ -133,6 +135,32 @@ func (s *Store) DeleteTrustedPeers(peers []peer.ID) {
 	}
 }
 
+// SetBannedPeer marks the peer as manually banned until the given expiry.
+// A zero expiry bans the peer indefinitely.
+// Important: it is assumed that store mutex is locked when calling this method.
+func (s *Store) SetBannedPeer(pid peer.ID, expiry time.Time) {
+	s.bannedPeers[pid] = expiry
+}
+
+// DeleteBannedPeer lifts a manual ban from the peer.
+// Important: it is assumed that store mutex is locked when calling this method.
+func (s *Store) DeleteBannedPeer(pid peer.ID) {
+	delete(s.bannedPeers, pid)
+}
+
+// BannedPeer returns the ban expiry of a manually banned peer, if any.
+// Important: it is assumed that store mutex is locked when calling this method.
+func (s *Store) BannedPeer(pid peer.ID) (time.Time, bool) {
+	expiry, ok := s.bannedPeers[pid]
+	return expiry, ok
+}
+
+// BannedPeers returns the map of manually banned peers and their ban expiries.
+// Important: it is assumed that store mutex is locked when calling this method.
+func (s *Store) BannedPeers() map[peer.ID]time.Time {
+	return s.bannedPeers
+}
+
 // Peers returns map of peer data objects.
 // Important: it is assumed that store mutex is locked when calling this method.
 func (s *Store) Peers() map[peer.ID]*PeerData {
@@ -41,6 +41,15 @@ type Service struct {
 	totalWeight float64
 }
 
+// ScoreBreakdown holds the weighted contribution of every registered scorer to the overall peer score.
+type ScoreBreakdown struct {
+	BadResponses  float64
+	BlockProvider float64
+	PeerStatus    float64
+	Gossip        float64
+	Total         float64
+}
+
 // Config holds configuration parameters for scoring service.
 type Config struct {
 	BadResponsesScorerConfig  *BadResponsesScorerConfig
@@ -123,6 +132,27 @@ func (s *Service) ScoreNoLock(pid peer.ID) float64 {
 	return math.Round(score*ScoreRoundingFactor) / ScoreRoundingFactor
 }
 
+// ScoreBreakdown returns the weighted per-scorer components of the peer score.
+// Returns nil if the peer is unknown.
+func (s *Service) ScoreBreakdown(pid peer.ID) *ScoreBreakdown {
+	s.store.RLock()
+	defer s.store.RUnlock()
+
+	if _, ok := s.store.PeerData(pid); !ok {
+		return nil
+	}
+	round := func(score float64) float64 {
+		return math.Round(score*ScoreRoundingFactor) / ScoreRoundingFactor
+	}
+	return &ScoreBreakdown{
+		BadResponses:  round(s.scorers.badResponsesScorer.scoreNoLock(pid) * s.scorerWeight(s.scorers.badResponsesScorer)),
+		BlockProvider: round(s.scorers.blockProviderScorer.scoreNoLock(pid) * s.scorerWeight(s.scorers.blockProviderScorer)),
+		PeerStatus:    round(s.scorers.peerStatusScorer.scoreNoLock(pid) * s.scorerWeight(s.scorers.peerStatusScorer)),
+		Gossip:        round(s.scorers.gossipScorer.scoreNoLock(pid) * s.scorerWeight(s.scorers.gossipScorer)),
+		Total:         s.ScoreNoLock(pid),
+	}
+}
+
 // IsBadPeer traverses all the scorers to see if any of them classifies peer as bad.
 func (s *Service) IsBadPeer(pid peer.ID) bool {
 	s.store.RLock()
@@ -27,6 +27,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enr"
@@ -86,11 +87,20 @@ const (
 
 // Status is the structure holding the peer status information.
 type Status struct {
-	ctx       context.Context
-	scorers   *scorers.Service
-	store     *peerdata.Store
-	ipTracker map[string]uint64
-	rand      *rand.Rand
+	ctx               context.Context
+	scorers           *scorers.Service
+	store             *peerdata.Store
+	ipTracker         map[string]uint64
+	rand              *rand.Rand
+	minPeersPerSubnet int
+	neededSubnets     neededSubnets
+}
+
+// neededSubnets holds the attestation and sync committee subnets our validators need now or soon.
+type neededSubnets struct {
+	attestation   map[uint64]bool
+	syncCommittee map[uint64]bool
+	sync.RWMutex
 }
 
 // StatusConfig represents peer status service params.
@@ -99,6 +109,8 @@ type StatusConfig struct {
 	PeerLimit int
 	// ScorerParams holds peer scorer configuration params.
 	ScorerParams *scorers.Config
+	// MinPeersPerSubnet specifies the amount of peers kept on every needed subnet when pruning.
+	MinPeersPerSubnet int
 }
 
 // NewStatus creates a new status entity.
@@ -106,6 +118,10 @@ func NewStatus(ctx context.Context, config *StatusConfig) *Status {
 	store := peerdata.NewStore(ctx, &peerdata.StoreConfig{
 		MaxPeers: maxLimitBuffer + config.PeerLimit,
 	})
+	minPeersPerSubnet := config.MinPeersPerSubnet
+	if minPeersPerSubnet < 1 {
+		minPeersPerSubnet = 1
+	}
 	return &Status{
 		ctx:       ctx,
 		store:     store,
@@ -113,7 +129,8 @@ func NewStatus(ctx context.Context, config *StatusConfig) *Status {
 		ipTracker: map[string]uint64{},
 		// Random generator used to calculate dial backoff period.
 		// It is ok to use deterministic generator, no need for true entropy.
-		rand: rand.NewDeterministicGenerator(),
+		rand:              rand.NewDeterministicGenerator(),
+		minPeersPerSubnet: minPeersPerSubnet,
 	}
 }
 
@@ -343,6 +360,10 @@ func (p *Status) IsBad(pid peer.ID) bool {
 
 // isBad is the lock-free version of IsBad.
 func (p *Status) isBad(pid peer.ID) bool {
+	// Manual bans take precedence over everything else, including trust.
+	if p.isBanned(pid) {
+		return true
+	}
 	// Do not disconnect from trusted peers.
 	if p.store.IsTrustedPeer(pid) {
 		return false
@@ -848,14 +869,11 @@ func (p *Status) PeersToPrune() []peer.ID {
 	if excessInbound > amountToPrune {
 		amountToPrune = excessInbound
 	}
-	if amountToPrune < uint64(len(peersToPrune)) {
-		peersToPrune = peersToPrune[:amountToPrune]
-	}
 	ids := make([]peer.ID, 0, len(peersToPrune))
 	for _, pr := range peersToPrune {
 		ids = append(ids, pr.pid)
 	}
-	return ids
+	return p.selectPeersToPrune(ids, amountToPrune)
 }
 
 // Deprecated: Is used to represent the older method
@@ -913,16 +931,109 @@ func (p *Status) deprecatedPeersToPrune() []peer.ID {
 	if excessInbound > amountToPrune {
 		amountToPrune = excessInbound
 	}
-	if amountToPrune < uint64(len(peersToPrune)) {
-		peersToPrune = peersToPrune[:amountToPrune]
-	}
 	ids := make([]peer.ID, 0, len(peersToPrune))
 	for _, pr := range peersToPrune {
 		ids = append(ids, pr.pid)
 	}
+	return p.selectPeersToPrune(ids, amountToPrune)
+}
+
+// selectPeersToPrune picks up to amount peers out of the candidates, which are ordered by pruning
+// preference. Peers whose removal would leave one of the needed subnets with less than the minimum
+// amount of peers are kept. This method assumes the store lock is acquired before executing the method.
+func (p *Status) selectPeersToPrune(candidates []peer.ID, amount uint64) []peer.ID {
+	p.neededSubnets.RLock()
+	defer p.neededSubnets.RUnlock()
+
+	// Count the connected peers on every needed subnet.
+	attCoverage := make(map[uint64]int, len(p.neededSubnets.attestation))
+	syncCoverage := make(map[uint64]int, len(p.neededSubnets.syncCommittee))
+	for pid, peerData := range p.store.Peers() {
+		if peerData.ConnState != PeerConnected {
+			continue
+		}
+		attSubnets, syncSubnets := p.neededSubnetsOfPeer(pid)
+		for _, idx := range attSubnets {
+			attCoverage[idx]++
+		}
+		for _, idx := range syncSubnets {
+			syncCoverage[idx]++
+		}
+	}
+
+	ids := make([]peer.ID, 0, amount)
+	for _, pid := range candidates {
+		if uint64(len(ids)) >= amount {
+			break
+		}
+		attSubnets, syncSubnets := p.neededSubnetsOfPeer(pid)
+		if p.coversScarceSubnet(attSubnets, attCoverage) || p.coversScarceSubnet(syncSubnets, syncCoverage) {
+			continue
+		}
+		for _, idx := range attSubnets {
+			attCoverage[idx]--
+		}
+		for _, idx := range syncSubnets {
+			syncCoverage[idx]--
+		}
+		ids = append(ids, pid)
+	}
 	return ids
 }
 
+// coversScarceSubnet returns true if one of the subnets has no more peers than the minimum we keep per subnet.
+func (p *Status) coversScarceSubnet(subnets []uint64, coverage map[uint64]int) bool {
+	for _, idx := range subnets {
+		if coverage[idx] <= p.minPeersPerSubnet {
+			return true
+		}
+	}
+	return false
+}
+
+// neededSubnetsOfPeer returns the needed attestation and sync committee subnets the peer advertises
+// in its metadata. This method assumes the store and needed subnets locks are acquired before executing the method.
+func (p *Status) neededSubnetsOfPeer(pid peer.ID) ([]uint64, []uint64) {
+	peerData, ok := p.store.PeerData(pid)
+	if !ok || peerData.MetaData == nil || peerData.MetaData.IsNil() {
+		return nil, nil
+	}
+	var attSubnets, syncSubnets []uint64
+	if attnets := peerData.MetaData.AttnetsBitfield(); attnets != nil {
+		for _, idx := range indicesFromBitfield(attnets) {
+			if p.neededSubnets.attestation[idx] {
+				attSubnets = append(attSubnets, idx)
+			}
+		}
+	}
+	if md := peerData.MetaData.MetadataObjV1(); md != nil {
+		syncnets := bitfield.Bitvector4(md.Syncnets)
+		for idx := uint64(0); idx < syncnets.Len(); idx++ {
+			if syncnets.BitAt(idx) && p.neededSubnets.syncCommittee[idx] {
+				syncSubnets = append(syncSubnets, idx)
+			}
+		}
+	}
+	return attSubnets, syncSubnets
+}
+
+// SetNeededSubnets sets the attestation and sync committee subnets needed by our validators now
+// or in the near future. Peers on these subnets are protected from pruning, as long as there
+// are not more of them than the minimum amount of peers kept per subnet.
+func (p *Status) SetNeededSubnets(attSubnets, syncSubnets []uint64) {
+	p.neededSubnets.Lock()
+	defer p.neededSubnets.Unlock()
+
+	p.neededSubnets.attestation = make(map[uint64]bool, len(attSubnets))
+	for _, idx := range attSubnets {
+		p.neededSubnets.attestation[idx] = true
+	}
+	p.neededSubnets.syncCommittee = make(map[uint64]bool, len(syncSubnets))
+	for _, idx := range syncSubnets {
+		p.neededSubnets.syncCommittee[idx] = true
+	}
+}
+
 // HighestEpoch returns the highest epoch reported epoch amongst peers.
 func (p *Status) HighestEpoch() primitives.Epoch {
 	p.store.RLock()
@@ -980,6 +1091,57 @@ func (p *Status) isTrustedPeers(pid peer.ID) bool {
 	return p.store.IsTrustedPeer(pid)
 }
 
+// BanPeer manually bans a peer until the provided expiry. A zero expiry bans
+// the peer until it is explicitly unbanned.
+func (p *Status) BanPeer(pid peer.ID, expiry time.Time) {
+	p.store.Lock()
+	defer p.store.Unlock()
+	p.store.SetBannedPeer(pid, expiry)
+}
+
+// UnbanPeer lifts a manual ban from the peer. It returns false if the peer was not banned.
+func (p *Status) UnbanPeer(pid peer.ID) bool {
+	p.store.Lock()
+	defer p.store.Unlock()
+	if _, ok := p.store.BannedPeer(pid); !ok {
+		return false
+	}
+	p.store.DeleteBannedPeer(pid)
+	return true
+}
+
+// IsBanned returns if given peer is currently manually banned.
+func (p *Status) IsBanned(pid peer.ID) bool {
+	p.store.RLock()
+	defer p.store.RUnlock()
+	return p.isBanned(pid)
+}
+
+// isBanned is the lock-free version of IsBanned.
+func (p *Status) isBanned(pid peer.ID) bool {
+	expiry, ok := p.store.BannedPeer(pid)
+	if !ok {
+		return false
+	}
+	return expiry.IsZero() || prysmTime.Now().Before(expiry)
+}
+
+// BannedPeers returns all manually banned peers along with their ban expiry.
+// Expired bans are removed from the store.
+func (p *Status) BannedPeers() map[peer.ID]time.Time {
+	p.store.Lock()
+	defer p.store.Unlock()
+	bans := make(map[peer.ID]time.Time, len(p.store.BannedPeers()))
+	for pid, expiry := range p.store.BannedPeers() {
+		if !p.isBanned(pid) {
+			p.store.DeleteBannedPeer(pid)
+			continue
+		}
+		bans[pid] = expiry
+	}
+	return bans
+}
+
 // this method assumes the store lock is acquired before
 // executing the method.
 func (p *Status) isfromBadIP(pid peer.ID) bool {
@@ -755,6 +755,72 @@ func TestPrunePeers(t *testing.T) {
 	}
 }
 
+func TestPrunePeers_NeededSubnets(t *testing.T) {
+	resetCfg := features.InitWithReset(&features.Flags{
+		EnablePeerScorer: false,
+	})
+	defer resetCfg()
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		PeerLimit: 30,
+		ScorerParams: &scorers.Config{
+			BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
+				Threshold: 10,
+			},
+		},
+	})
+	for i := 0; i < 15; i++ {
+		createPeer(t, p, nil, network.DirOutbound, peerdata.PeerConnectionState(ethpb.ConnectionState_CONNECTED))
+	}
+	var inbound []peer.ID
+	for i := 0; i < 18; i++ {
+		inbound = append(inbound, createPeer(t, p, nil, network.DirInbound, peerdata.PeerConnectionState(ethpb.ConnectionState_CONNECTED)))
+	}
+	// The first three inbound peers are the worst ones, the first two are the only peers on
+	// attestation subnet 5 and sync committee subnet 1 respectively.
+	for i, pid := range inbound[:3] {
+		for j := 0; j < 3-i; j++ {
+			p.Scorers().BadResponsesScorer().Increment(pid)
+		}
+	}
+	attnets := bitfield.NewBitvector64()
+	attnets.SetBitAt(5, true)
+	p.SetMetadata(inbound[0], wrapper.WrappedMetadataV1(&pb.MetaDataV1{
+		Attnets:  attnets,
+		Syncnets: bitfield.NewBitvector4(),
+	}))
+	syncnets := bitfield.NewBitvector4()
+	syncnets.SetBitAt(1, true)
+	p.SetMetadata(inbound[1], wrapper.WrappedMetadataV1(&pb.MetaDataV1{
+		Attnets:  bitfield.NewBitvector64(),
+		Syncnets: syncnets,
+	}))
+
+	// Without needed subnets the worst peers are pruned.
+	peersToPrune := p.PeersToPrune()
+	require.Equal(t, 3, len(peersToPrune))
+	assert.DeepEqual(t, inbound[:3], peersToPrune)
+
+	// Peers which are the only ones on a needed subnet are kept.
+	p.SetNeededSubnets([]uint64{5}, []uint64{1})
+	peersToPrune = p.PeersToPrune()
+	require.Equal(t, 3, len(peersToPrune))
+	for _, pid := range peersToPrune {
+		assert.NotEqual(t, inbound[0], pid)
+		assert.NotEqual(t, inbound[1], pid)
+	}
+	assert.Equal(t, inbound[2], peersToPrune[0])
+
+	// A peer is no longer protected once it has company on the subnet.
+	p.SetMetadata(inbound[17], wrapper.WrappedMetadataV1(&pb.MetaDataV1{
+		Attnets:  attnets,
+		Syncnets: bitfield.NewBitvector4(),
+	}))
+	peersToPrune = p.PeersToPrune()
+	require.Equal(t, 3, len(peersToPrune))
+	assert.Equal(t, inbound[0], peersToPrune[0])
+	assert.Equal(t, inbound[2], peersToPrune[1])
+}
+
 func TestPrunePeers_TrustedPeers(t *testing.T) {
 	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
 		PeerLimit: 30,
@@ -1324,3 +1390,37 @@ func createPeer(t *testing.T, p *peers.Status, addr ma.Multiaddr,
 	p.SetConnectionState(id, state)
 	return id
 }
+
+func TestStatus_BannedPeers(t *testing.T) {
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		PeerLimit:    30,
+		ScorerParams: &scorers.Config{},
+	})
+	permanent := createPeer(t, p, nil, network.DirOutbound, peers.PeerConnected)
+	temporary := createPeer(t, p, nil, network.DirOutbound, peers.PeerConnected)
+	expired := createPeer(t, p, nil, network.DirOutbound, peers.PeerConnected)
+	trusted := createPeer(t, p, nil, network.DirOutbound, peers.PeerConnected)
+	p.SetTrustedPeers([]peer.ID{trusted})
+
+	p.BanPeer(permanent, time.Time{})
+	p.BanPeer(temporary, time.Now().Add(time.Hour))
+	p.BanPeer(expired, time.Now().Add(-time.Second))
+	p.BanPeer(trusted, time.Now().Add(time.Hour))
+
+	assert.Equal(t, true, p.IsBanned(permanent))
+	assert.Equal(t, true, p.IsBanned(temporary))
+	assert.Equal(t, false, p.IsBanned(expired))
+	// Manual bans take precedence over trust.
+	assert.Equal(t, true, p.IsBad(trusted))
+	assert.Equal(t, false, p.IsBad(expired))
+
+	bans := p.BannedPeers()
+	assert.Equal(t, 3, len(bans))
+	_, ok := bans[expired]
+	assert.Equal(t, false, ok)
+
+	assert.Equal(t, true, p.UnbanPeer(permanent))
+	assert.Equal(t, false, p.UnbanPeer(permanent))
+	assert.Equal(t, false, p.IsBanned(permanent))
+	assert.Equal(t, 2, len(p.BannedPeers()))
+}
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
@@ -20,9 +21,14 @@ const (
 )
 
 // This tracer is used to implement metrics collection for messages received
-// and broadcasted through gossipsub.
+// and broadcasted through gossipsub. If a trace writer is set, message events
+// are also written to the gossip trace file.
 type gossipTracer struct {
-	host host.Host
+	host  host.Host
+	trace *gossipTraceWriter
+	// msgID is the message ID function pubsub is configured with, which received messages are traced with
+	// before pubsub assigns them their ID.
+	msgID func(*pubsubpb.Message) string
 }
 
 // AddPeer .
@@ -58,21 +64,25 @@ func (g gossipTracer) Prune(p peer.ID, topic string) {
 // ValidateMessage .
 func (g gossipTracer) ValidateMessage(msg *pubsub.Message) {
 	pubsubMessageValidate.WithLabelValues(*msg.Topic).Inc()
+	g.traceMessage(GossipTraceValidate, msg, "")
 }
 
 // DeliverMessage .
 func (g gossipTracer) DeliverMessage(msg *pubsub.Message) {
 	pubsubMessageDeliver.WithLabelValues(*msg.Topic).Inc()
+	g.traceMessage(GossipTraceDeliver, msg, "")
 }
 
 // RejectMessage .
 func (g gossipTracer) RejectMessage(msg *pubsub.Message, reason string) {
 	pubsubMessageReject.WithLabelValues(*msg.Topic, reason).Inc()
+	g.traceMessage(GossipTraceReject, msg, reason)
 }
 
 // DuplicateMessage .
 func (g gossipTracer) DuplicateMessage(msg *pubsub.Message) {
 	pubsubMessageDuplicate.WithLabelValues(*msg.Topic).Inc()
+	g.traceMessage(GossipTraceDuplicate, msg, "")
 }
 
 // UndeliverableMessage .
@@ -89,6 +99,7 @@ func (g gossipTracer) ThrottlePeer(p peer.ID) {
 // RecvRPC .
 func (g gossipTracer) RecvRPC(rpc *pubsub.RPC) {
 	g.setMetricFromRPC(recv, pubsubRPCSubRecv, pubsubRPCPubRecv, pubsubRPCRecv, rpc)
+	g.traceReceived(rpc)
 }
 
 // SendRPC .
@@ -118,3 +129,28 @@ func (g gossipTracer) setMetricFromRPC(act action, subCtr prometheus.Counter, pu
 		pubCtr.WithLabelValues(*msg.Topic).Inc()
 	}
 }
+
+// traceReceived writes a receive event to the gossip trace for every message of the RPC. The messages do not have
+// their ID yet, so it is computed with the function pubsub uses, for the events of a message to share its ID.
+func (g gossipTracer) traceReceived(rpc *pubsub.RPC) {
+	if g.trace == nil || g.msgID == nil {
+		return
+	}
+	for _, msg := range rpc.Publish {
+		if msg.Topic == nil {
+			continue
+		}
+		g.trace.trace(GossipTraceReceive, "", *msg.Topic, g.msgID(msg), false, "")
+	}
+}
+
+// traceMessage writes a message event to the gossip trace. Pubsub computes the message ID before
+// invoking the tracer, so the ID of the message is reused rather than recomputed. Every copy of a
+// message received from the network is traced as received, and then either as the first copy queued
+// for validation or as a duplicate.
+func (g gossipTracer) traceMessage(typ string, msg *pubsub.Message, reason string) {
+	if g.trace == nil {
+		return
+	}
+	g.trace.trace(typ, msg.ReceivedFrom, *msg.Topic, msg.ID, msg.Local, reason)
+}
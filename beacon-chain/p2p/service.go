@@ -24,6 +24,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	leakybucket "github.com/prysmaticlabs/prysm/v5/container/leaky-bucket"
@@ -68,6 +69,7 @@ type Service struct {
 	pubsub                *pubsub.PubSub
 	joinedTopics          map[string]*pubsub.Topic
 	joinedTopicsLock      sync.RWMutex
+	bannedPeersLock       sync.Mutex
 	subnetsLock           map[uint64]*sync.RWMutex
 	subnetsLockLock       sync.Mutex // Lock access to subnetsLock
 	initializationLock    sync.Mutex
@@ -78,6 +80,7 @@ type Service struct {
 	genesisTime           time.Time
 	genesisValidatorsRoot []byte
 	activeValidatorCount  uint64
+	gossipTrace           *gossipTraceWriter
 }
 
 // NewService initializes a new p2p service compatible with shared.Service interface. No
@@ -135,6 +138,14 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 
 	s.host = h
 
+	if cfg.GossipTraceFile != "" {
+		s.gossipTrace = newGossipTraceWriter(cfg.GossipTraceFile, cfg.GossipTraceSampleRate, cfg.GossipTraceMaxSizeMB)
+		log.WithFields(logrus.Fields{
+			"file":       cfg.GossipTraceFile,
+			"sampleRate": cfg.GossipTraceSampleRate,
+		}).Info("Writing gossip traces")
+	}
+
 	// Gossipsub registration is done before we add in any new peers
 	// due to libp2p's gossipsub implementation not taking into
 	// account previously added peers when creating the gossipsub
@@ -156,7 +167,8 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 	s.pubsub = gs
 
 	s.peers = peers.NewStatus(ctx, &peers.StatusConfig{
-		PeerLimit: int(s.cfg.MaxPeers),
+		PeerLimit:         int(s.cfg.MaxPeers),
+		MinPeersPerSubnet: flags.Get().MinimumPeersPerSubnet,
 		ScorerParams: &scorers.Config{
 			BadResponsesScorerConfig: &scorers.BadResponsesScorerConfig{
 				Threshold:     maxBadResponses,
@@ -165,6 +177,10 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 		},
 	})
 
+	if err := loadBannedPeers(s.cfg, s.peers); err != nil {
+		log.WithError(err).Error("Could not load banned peers")
+	}
+
 	// Initialize Data maps.
 	types.InitializeDataMaps()
 
@@ -235,6 +251,7 @@ func (s *Service) Start() {
 	async.RunEvery(s.ctx, 30*time.Minute, s.Peers().Prune)
 	async.RunEvery(s.ctx, time.Duration(params.BeaconConfig().RespTimeout)*time.Second, s.updateMetrics)
 	async.RunEvery(s.ctx, refreshRate, s.RefreshENR)
+	async.RunEvery(s.ctx, refreshRate, s.updateNeededSubnets)
 	async.RunEvery(s.ctx, 1*time.Minute, func() {
 		inboundQUICCount := len(s.peers.InboundConnectedWithProtocol(peers.QUIC))
 		inboundTCPCount := len(s.peers.InboundConnectedWithProtocol(peers.TCP))
@@ -282,6 +299,11 @@ func (s *Service) Stop() error {
 	if s.dv5Listener != nil {
 		s.dv5Listener.Close()
 	}
+	if s.gossipTrace != nil {
+		if err := s.gossipTrace.close(); err != nil {
+			log.WithError(err).Error("Could not close gossip trace file")
+		}
+	}
 	return nil
 }
 
@@ -467,10 +489,16 @@ func (s *Service) connectWithPeer(ctx context.Context, info peer.AddrInfo) error
 	}
 	ctx, cancel := context.WithTimeout(ctx, maxDialTimeout)
 	defer cancel()
+	alreadyConnected := s.host.Network().Connectedness(info.ID) == network.Connected
+	start := time.Now()
 	if err := s.host.Connect(ctx, info); err != nil {
+		recordDialFailures(err)
 		s.Peers().Scorers().BadResponsesScorer().Increment(info.ID)
 		return err
 	}
+	if !alreadyConnected {
+		s.recordDial(info, time.Since(start))
+	}
 	return nil
 }
 
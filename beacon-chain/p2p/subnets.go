@@ -17,11 +17,13 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/wrapper"
+	"github.com/prysmaticlabs/prysm/v5/container/slice"
 	"github.com/prysmaticlabs/prysm/v5/crypto/hash"
 	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	mathutil "github.com/prysmaticlabs/prysm/v5/math"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
 	pb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
 )
 
 var attestationSubnetCount = params.BeaconConfig().AttestationSubnetCount
@@ -153,6 +155,24 @@ func (s *Service) filterPeerForSyncSubnet(index uint64) func(node *enode.Node) b
 	}
 }
 
+// updateNeededSubnets protects peers on the subnets our validators need from being pruned. These are
+// the persistent attestation subnets, the attestation subnets of duties up to an epoch ahead and
+// the sync committee subnets up to a full sync committee period ahead.
+func (s *Service) updateNeededSubnets() {
+	if !s.isInitialized() {
+		return
+	}
+	currSlot := slots.CurrentSlot(uint64(s.genesisTime.Unix()))
+	attSubnets := cache.SubnetIDs.GetAllSubnets()
+	for slot := currSlot; slot < currSlot+params.BeaconConfig().SlotsPerEpoch; slot++ {
+		attSubnets = append(attSubnets, cache.SubnetIDs.GetAttesterSubnetIDs(slot)...)
+		attSubnets = append(attSubnets, cache.SubnetIDs.GetAggregatorSubnetIDs(slot)...)
+	}
+	lookaheadEpoch := slots.ToEpoch(currSlot) + params.BeaconConfig().EpochsPerSyncCommitteePeriod
+	syncSubnets := cache.SyncSubnetIDs.GetAllSubnets(lookaheadEpoch)
+	s.peers.SetNeededSubnets(slice.SetUint64(attSubnets), syncSubnets)
+}
+
 // lower threshold to broadcast object compared to searching
 // for a subnet. So that even in the event of poor peer
 // connectivity, we can still broadcast an attestation.
@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -45,6 +46,16 @@ func (_ *FakeP2P) AddPingMethod(_ func(ctx context.Context, id peer.ID) error) {
 
 }
 
+// BanPeer -- fake.
+func (_ *FakeP2P) BanPeer(_ peer.ID, _ time.Time) error {
+	return nil
+}
+
+// UnbanPeer -- fake.
+func (_ *FakeP2P) UnbanPeer(_ peer.ID) error {
+	return nil
+}
+
 // PeerID -- fake.
 func (_ *FakeP2P) PeerID() peer.ID {
 	return "fake"
@@ -3,6 +3,7 @@ package testing
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/libp2p/go-libp2p/core/host"
@@ -57,3 +58,13 @@ func (_ MockPeerManager) FindPeersWithSubnet(_ context.Context, _ string, _ uint
 
 // AddPingMethod .
 func (_ MockPeerManager) AddPingMethod(_ func(ctx context.Context, id peer.ID) error) {}
+
+// BanPeer .
+func (_ MockPeerManager) BanPeer(_ peer.ID, _ time.Time) error {
+	return nil
+}
+
+// UnbanPeer .
+func (_ MockPeerManager) UnbanPeer(_ peer.ID) error {
+	return nil
+}
@@ -390,6 +390,18 @@ func (_ *TestP2P) AddPingMethod(_ func(ctx context.Context, id peer.ID) error) {
 	// no-op
 }
 
+// BanPeer mocks the p2p func.
+func (p *TestP2P) BanPeer(pid peer.ID, expiry time.Time) error {
+	p.peers.BanPeer(pid, expiry)
+	return nil
+}
+
+// UnbanPeer mocks the p2p func.
+func (p *TestP2P) UnbanPeer(pid peer.ID) error {
+	p.peers.UnbanPeer(pid)
+	return nil
+}
+
 // InterceptPeerDial .
 func (_ *TestP2P) InterceptPeerDial(peer.ID) (allow bool) {
 	return true
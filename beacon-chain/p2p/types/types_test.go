@@ -112,6 +112,26 @@ func TestErrorResponse_Limit(t *testing.T) {
 func TestRoundTripSerialization(t *testing.T) {
 	roundTripTestBlocksByRootReq(t)
 	roundTripTestErrorMessage(t)
+	roundTripTestLightClientRequests(t)
+}
+
+func roundTripTestLightClientRequests(t *testing.T) {
+	bootstrapReq := LightClientBootstrapReq{'a', 'b'}
+	marshalledObj, err := bootstrapReq.MarshalSSZ()
+	require.NoError(t, err)
+	var newBootstrapReq LightClientBootstrapReq
+	require.NoError(t, newBootstrapReq.UnmarshalSSZ(marshalledObj))
+	assert.Equal(t, bootstrapReq, newBootstrapReq)
+	require.ErrorIs(t, newBootstrapReq.UnmarshalSSZ(marshalledObj[1:]), ssz.ErrSize)
+
+	rangeReq := &LightClientUpdatesByRangeReq{StartPeriod: 10, Count: 128}
+	marshalledObj, err = rangeReq.MarshalSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, "0a000000000000008000000000000000", hex.EncodeToString(marshalledObj))
+	newRangeReq := &LightClientUpdatesByRangeReq{}
+	require.NoError(t, newRangeReq.UnmarshalSSZ(marshalledObj))
+	assert.DeepEqual(t, rangeReq, newRangeReq)
+	require.ErrorIs(t, newRangeReq.UnmarshalSSZ(marshalledObj[:8]), ssz.ErrSize)
 }
 
 func roundTripTestBlocksByRootReq(t *testing.T) {
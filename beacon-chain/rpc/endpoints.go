@@ -20,10 +20,12 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/eth/validator"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/lookup"
 	beaconprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/beacon"
+	debugprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/debug"
 	nodeprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/node"
 	validatorv1alpha1 "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/v1alpha1/validator"
 	validatorprysm "github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/prysm/validator"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
 )
 
 type endpoint struct {
@@ -72,10 +74,68 @@ func (s *Service) endpoints(
 	endpoints = append(endpoints, s.prysmValidatorEndpoints(stater, coreService)...)
 	if enableDebug {
 		endpoints = append(endpoints, s.debugEndpoints(stater)...)
+		endpoints = append(endpoints, s.prysmDebugEndpoints()...)
+	}
+	if s.cfg.ReadOnly {
+		for i := range endpoints {
+			if replicaUnsupportedEndpoints[endpoints[i].name] {
+				endpoints[i].handler = replicaUnsupported
+			}
+		}
 	}
 	return endpoints
 }
 
+// replicaUnsupportedEndpoints names the endpoints a read-only replica can not serve, as they submit objects to the
+// network, produce blocks or manage the peers and sync services the replica runs without.
+var replicaUnsupportedEndpoints = map[string]bool{
+	"validator.SubmitContributionAndProofs":       true,
+	"validator.SubmitAggregateAndProofs":          true,
+	"validator.SubmitAggregateAndProofsV2":        true,
+	"validator.SubmitSyncCommitteeSubscription":   true,
+	"validator.SubmitBeaconCommitteeSubscription": true,
+	"validator.RegisterValidator":                 true,
+	"validator.PrepareBeaconProposer":             true,
+	"validator.ProduceBlockV2":                    true,
+	"validator.ProduceBlindedBlock":               true,
+	"validator.ProduceBlockV3":                    true,
+	"node.GetIdentity":                            true,
+	"node.GetPeer":                                true,
+	"node.GetPeers":                               true,
+	"node.GetPeerCount":                           true,
+	"beacon.PublishBlock":                         true,
+	"beacon.PublishBlindedBlock":                  true,
+	"beacon.PublishBlockV2":                       true,
+	"beacon.PublishBlindedBlockV2":                true,
+	"beacon.SubmitAttestations":                   true,
+	"beacon.SubmitVoluntaryExit":                  true,
+	"beacon.SubmitSyncCommitteeSignatures":        true,
+	"beacon.SubmitBLSToExecutionChanges":          true,
+	"beacon.SubmitAttesterSlashings":              true,
+	"beacon.SubmitAttesterSlashingsV2":            true,
+	"beacon.SubmitProposerSlashing":               true,
+	"prysm.beacon.PublishBlobs":                   true,
+	"prysm.node.ListTrustedPeer":                  true,
+	"prysm.node.AddTrustedPeer":                   true,
+	"prysm.node.RemoveTrustedPeer":                true,
+	"prysm.node.GetPeerScore":                     true,
+	"prysm.node.DisconnectPeer":                   true,
+	"prysm.node.ListBannedPeers":                  true,
+	"prysm.node.BanPeer":                          true,
+	"prysm.node.UnbanPeer":                        true,
+	"prysm.node.GetBackfillStatus":                true,
+	"prysm.node.PauseBackfill":                    true,
+	"prysm.node.ResumeBackfill":                   true,
+	"prysm.node.SetBackfillConfig":                true,
+	"prysm.node.WriteSnapshot":                    true,
+	"prysm.debug.GetRateLimits":                   true,
+	"prysm.debug.GetPendingBlocks":                true,
+}
+
+func replicaUnsupported(w http.ResponseWriter, _ *http.Request) {
+	httputil.HandleError(w, "Not supported by a read-only replica, send the request to a node that syncs", http.StatusServiceUnavailable)
+}
+
 func (s *Service) rewardsEndpoints(blocker lookup.Blocker, stater lookup.Stater, rewardFetcher rewards.BlockRewardsFetcher) []endpoint {
 	server := &rewards.Server{
 		Blocker:               blocker,
@@ -1074,10 +1134,14 @@ func (s *Service) prysmNodeEndpoints() []endpoint {
 		MetadataProvider:          s.cfg.MetadataProvider,
 		HeadFetcher:               s.cfg.HeadFetcher,
 		ExecutionChainInfoFetcher: s.cfg.ExecutionChainInfoFetcher,
+		BackfillController:        s.cfg.BackfillController,
+		Snapshotter:               s.cfg.Snapshotter,
+		BlobStorage:               s.cfg.BlobStorage,
+		SnapshotDir:               s.cfg.SnapshotDir,
 	}
 
 	const namespace = "prysm.node"
-	return []endpoint{
+	endpoints := []endpoint{
 		{
 			template: "/prysm/node/trusted_peers",
 			name:     namespace + ".ListTrustedPeer",
@@ -1134,7 +1198,113 @@ func (s *Service) prysmNodeEndpoints() []endpoint {
 			handler: server.RemoveTrustedPeer,
 			methods: []string{http.MethodDelete},
 		},
+		{
+			template: "/prysm/v1/node/peers/{peer_id}/score",
+			name:     namespace + ".GetPeerScore",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetPeerScore,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/node/peers/{peer_id}/disconnect",
+			name:     namespace + ".DisconnectPeer",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.DisconnectPeer,
+			methods: []string{http.MethodPost},
+		},
+		{
+			template: "/prysm/v1/node/banned_peers",
+			name:     namespace + ".ListBannedPeers",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.ListBannedPeers,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/node/banned_peers",
+			name:     namespace + ".BanPeer",
+			middleware: []middleware.Middleware{
+				middleware.ContentTypeHandler([]string{api.JsonMediaType}),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.BanPeer,
+			methods: []string{http.MethodPost},
+		},
+		{
+			template: "/prysm/v1/node/banned_peers/{peer_id}",
+			name:     namespace + ".UnbanPeer",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.UnbanPeer,
+			methods: []string{http.MethodDelete},
+		},
+		{
+			template: "/prysm/v1/node/backfill",
+			name:     namespace + ".GetBackfillStatus",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetBackfillStatus,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/node/backfill/pause",
+			name:     namespace + ".PauseBackfill",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.PauseBackfill,
+			methods: []string{http.MethodPost},
+		},
+		{
+			template: "/prysm/v1/node/backfill/resume",
+			name:     namespace + ".ResumeBackfill",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.ResumeBackfill,
+			methods: []string{http.MethodPost},
+		},
+		{
+			template: "/prysm/v1/node/backfill/config",
+			name:     namespace + ".SetBackfillConfig",
+			middleware: []middleware.Middleware{
+				middleware.ContentTypeHandler([]string{api.JsonMediaType}),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.SetBackfillConfig,
+			methods: []string{http.MethodPost},
+		},
+		{
+			template: "/prysm/v1/node/history",
+			name:     namespace + ".GetHistory",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetHistory,
+			methods: []string{http.MethodGet},
+		},
+	}
+	// Snapshots copy the whole database and blob storage, so only the holders of the token may request them.
+	if s.cfg.SnapshotAuthToken != "" {
+		endpoints = append(endpoints, endpoint{
+			template: "/prysm/v1/node/snapshot",
+			name:     namespace + ".WriteSnapshot",
+			middleware: []middleware.Middleware{
+				middleware.AuthorizationHandler(s.cfg.SnapshotAuthToken),
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.WriteSnapshot,
+			methods: []string{http.MethodPost},
+		})
 	}
+	return endpoints
 }
 
 func (s *Service) prysmValidatorEndpoints(stater lookup.Stater, coreService *core.Service) []endpoint {
@@ -1186,3 +1356,32 @@ func (s *Service) prysmValidatorEndpoints(stater lookup.Stater, coreService *cor
 		},
 	}
 }
+
+func (s *Service) prysmDebugEndpoints() []endpoint {
+	server := &debugprysm.Server{
+		RateLimitUsageFetcher: s.cfg.RateLimitUsageFetcher,
+		PendingBlocksFetcher:  s.cfg.PendingBlocksFetcher,
+	}
+
+	const namespace = "prysm.debug"
+	return []endpoint{
+		{
+			template: "/prysm/v1/debug/rate_limits",
+			name:     namespace + ".GetRateLimits",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetRateLimits,
+			methods: []string{http.MethodGet},
+		},
+		{
+			template: "/prysm/v1/debug/pending_blocks",
+			name:     namespace + ".GetPendingBlocks",
+			middleware: []middleware.Middleware{
+				middleware.AcceptHeaderHandler([]string{api.JsonMediaType}),
+			},
+			handler: server.GetPendingBlocks,
+			methods: []string{http.MethodGet},
+		},
+	}
+}
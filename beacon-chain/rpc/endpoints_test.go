@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"slices"
 	"testing"
 
@@ -76,6 +77,8 @@ func Test_endpoints(t *testing.T) {
 		"/eth/v2/debug/beacon/states/{state_id}": {http.MethodGet},
 		"/eth/v2/debug/beacon/heads":             {http.MethodGet},
 		"/eth/v1/debug/fork_choice":              {http.MethodGet},
+		"/prysm/v1/debug/rate_limits":            {http.MethodGet},
+		"/prysm/v1/debug/pending_blocks":         {http.MethodGet},
 	}
 
 	eventsRoutes := map[string][]string{
@@ -123,10 +126,19 @@ func Test_endpoints(t *testing.T) {
 	}
 
 	prysmNodeRoutes := map[string][]string{
-		"/prysm/node/trusted_peers":              {http.MethodGet, http.MethodPost},
-		"/prysm/v1/node/trusted_peers":           {http.MethodGet, http.MethodPost},
-		"/prysm/node/trusted_peers/{peer_id}":    {http.MethodDelete},
-		"/prysm/v1/node/trusted_peers/{peer_id}": {http.MethodDelete},
+		"/prysm/node/trusted_peers":                 {http.MethodGet, http.MethodPost},
+		"/prysm/v1/node/trusted_peers":              {http.MethodGet, http.MethodPost},
+		"/prysm/node/trusted_peers/{peer_id}":       {http.MethodDelete},
+		"/prysm/v1/node/trusted_peers/{peer_id}":    {http.MethodDelete},
+		"/prysm/v1/node/peers/{peer_id}/score":      {http.MethodGet},
+		"/prysm/v1/node/peers/{peer_id}/disconnect": {http.MethodPost},
+		"/prysm/v1/node/banned_peers":               {http.MethodGet, http.MethodPost},
+		"/prysm/v1/node/banned_peers/{peer_id}":     {http.MethodDelete},
+		"/prysm/v1/node/backfill":                   {http.MethodGet},
+		"/prysm/v1/node/backfill/pause":             {http.MethodPost},
+		"/prysm/v1/node/backfill/resume":            {http.MethodPost},
+		"/prysm/v1/node/backfill/config":            {http.MethodPost},
+		"/prysm/v1/node/history":                    {http.MethodGet},
 	}
 
 	prysmValidatorRoutes := map[string][]string{
@@ -153,3 +165,20 @@ func Test_endpoints(t *testing.T) {
 		return slices.Equal(expectedMethods, actualMethods)
 	}))
 }
+
+func Test_endpoints_ReadOnly(t *testing.T) {
+	s := &Service{cfg: &Config{ReadOnly: true, SnapshotAuthToken: "token"}}
+
+	endpoints := s.endpoints(true, nil, nil, nil, nil, nil, nil)
+	unsupported := make(map[string]bool)
+	for _, e := range endpoints {
+		if !replicaUnsupportedEndpoints[e.name] {
+			continue
+		}
+		unsupported[e.name] = true
+		rec := httptest.NewRecorder()
+		e.handler(rec, httptest.NewRequest(e.methods[0], "http://example.com", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, e.name)
+	}
+	assert.Equal(t, len(replicaUnsupportedEndpoints), len(unsupported), "every unsupported endpoint should exist")
+}
@@ -14,15 +14,20 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
 	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	dbtesting "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/rpc/testutil"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
 )
 
 func TestLightClientHandler_GetLightClientBootstrap_Altair(t *testing.T) {
@@ -225,6 +230,25 @@ func TestLightClientHandler_GetLightClientBootstrap_Electra(t *testing.T) {
 	require.NotNil(t, resp.Data.CurrentSyncCommitteeBranch)
 }
 
+func saveLightClientTestUpdate(t *testing.T, beaconDB db.HeadAccessDatabase, l *util.TestLightClient, period uint64) *ethpbv2.LightClientUpdate {
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock, l.FinalizedBlock)
+	require.NoError(t, err)
+	require.NoError(t, beaconDB.SaveLightClientUpdate(l.Ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(l.AttestedBlock.Version()),
+		Data:    update,
+	}))
+	return update
+}
+
+func getLightClientUpdatesByRange(t *testing.T, s *Server, startPeriod, count uint64) *httptest.ResponseRecorder {
+	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
+	request := httptest.NewRequest("GET", url, nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+	s.GetLightClientUpdatesByRange(writer, request)
+	return writer
+}
+
 func TestLightClientHandler_GetLightClientUpdatesByRangeAltair(t *testing.T) {
 	helpers.ClearCache()
 	ctx := context.Background()
@@ -288,28 +312,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRangeAltair(t *testing.T) {
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
 	url := fmt.Sprintf("http://foo.com/?count=1&start_period=%d", startPeriod)
 	request := httptest.NewRequest("GET", url, nil)
@@ -394,28 +405,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRangeCapella(t *testing.T) {
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
 	url := fmt.Sprintf("http://foo.com/?count=1&start_period=%d", startPeriod)
 	request := httptest.NewRequest("GET", url, nil)
@@ -500,28 +498,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRangeDeneb(t *testing.T) {
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
 	url := fmt.Sprintf("http://foo.com/?count=1&start_period=%d", startPeriod)
 	request := httptest.NewRequest("GET", url, nil)
@@ -606,28 +591,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigInputCountAltair(
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
 	count := 129 // config.MaxRequestLightClientUpdates is 128
 	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
@@ -713,28 +685,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigInputCountCapella
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
 	count := 129 // config.MaxRequestLightClientUpdates is 128
 	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
@@ -820,28 +779,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigInputCountDeneb(t
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
 	count := 129 // config.MaxRequestLightClientUpdates is 128
 	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
@@ -864,7 +810,6 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigInputCountDeneb(t
 	require.NotNil(t, resp)
 }
 
-// TODO - check for not having any blocks from the min period, and startPeriod being too early
 func TestLightClientHandler_GetLightClientUpdatesByRange_TooEarlyPeriodAltair(t *testing.T) {
 	helpers.ClearCache()
 	ctx := context.Background()
@@ -928,28 +873,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooEarlyPeriodAltair(t
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := 1 // very early period before Altair fork
 	count := 1
 	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
@@ -972,7 +904,6 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooEarlyPeriodAltair(t
 	require.NotNil(t, resp)
 }
 
-// TODO - same as above
 func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigCountAltair(t *testing.T) {
 	helpers.ClearCache()
 	ctx := context.Background()
@@ -1036,28 +967,15 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigCountAltair(t *te
 	signedBlock, err = blocks.NewSignedBeaconBlock(block)
 	require.NoError(t, err)
 
-	root, err := block.Block.HashTreeRoot()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(ctx, st, signedBlock, attestedState, signedParent, nil)
 	require.NoError(t, err)
-
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
-	}
+	db := dbtesting.SetupDB(t)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(attestedHeader.Slot))
+	require.NoError(t, db.SaveLightClientUpdate(ctx, period, &ethpbv2.LightClientUpdateWithVersion{
+		Version: ethpbv2.Version(signedParent.Version()),
+		Data:    update,
+	}))
+	s := &Server{BeaconDB: db}
 	startPeriod := 1 // very early period before Altair fork
 	count := 10      // This is big count as we only have one period in test case.
 	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
@@ -1082,98 +1000,68 @@ func TestLightClientHandler_GetLightClientUpdatesByRange_TooBigCountAltair(t *te
 
 func TestLightClientHandler_GetLightClientUpdatesByRange_BeforeAltair(t *testing.T) {
 	helpers.ClearCache()
-	ctx := context.Background()
 	config := params.BeaconConfig()
 	slot := primitives.Slot(config.AltairForkEpoch * primitives.Epoch(config.SlotsPerEpoch)).Sub(1)
 
-	attestedState, err := util.NewBeaconStateCapella()
-	require.NoError(t, err)
-	err = attestedState.SetSlot(slot.Sub(1))
-	require.NoError(t, err)
-
-	parent := util.NewBeaconBlockCapella()
-	parent.Block.Slot = slot.Sub(1)
-
-	signedParent, err := blocks.NewSignedBeaconBlock(parent)
-	require.NoError(t, err)
-
-	parentHeader, err := signedParent.Header()
-	require.NoError(t, err)
-	attestedHeader := parentHeader.Header
-
-	err = attestedState.SetLatestBlockHeader(attestedHeader)
-	require.NoError(t, err)
-	attestedStateRoot, err := attestedState.HashTreeRoot(ctx)
-	require.NoError(t, err)
-
-	// get a new signed block so the root is updated with the new state root
-	parent.Block.StateRoot = attestedStateRoot[:]
-	signedParent, err = blocks.NewSignedBeaconBlock(parent)
-	require.NoError(t, err)
-
-	st, err := util.NewBeaconStateCapella()
-	require.NoError(t, err)
-	err = st.SetSlot(slot)
-	require.NoError(t, err)
+	s := &Server{BeaconDB: dbtesting.SetupDB(t)}
+	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
+	count := 1
+	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
+	request := httptest.NewRequest("GET", url, nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
 
-	parentRoot, err := signedParent.Block().HashTreeRoot()
-	require.NoError(t, err)
+	s.GetLightClientUpdatesByRange(writer, request)
 
-	block := util.NewBeaconBlockCapella()
-	block.Block.Slot = slot
-	block.Block.ParentRoot = parentRoot[:]
+	require.Equal(t, http.StatusNotFound, writer.Code)
+}
 
-	for i := uint64(0); i < config.SyncCommitteeSize; i++ {
-		block.Block.Body.SyncAggregate.SyncCommitteeBits.SetBitAt(i, true)
+func TestLightClientHandler_GetLightClientUpdatesByRange_StopsAtMissingPeriod(t *testing.T) {
+	helpers.ClearCache()
+	db := dbtesting.SetupDB(t)
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	altairPeriod := slots.SyncCommitteePeriod(params.BeaconConfig().AltairForkEpoch)
+	for _, offset := range []uint64{0, 1, 2, 4} {
+		saveLightClientTestUpdate(t, db, l, altairPeriod+offset)
 	}
+	s := &Server{BeaconDB: db}
 
-	signedBlock, err := blocks.NewSignedBeaconBlock(block)
-	require.NoError(t, err)
-
-	h, err := signedBlock.Header()
-	require.NoError(t, err)
-
-	err = st.SetLatestBlockHeader(h.Header)
-	require.NoError(t, err)
-	stateRoot, err := st.HashTreeRoot(ctx)
-	require.NoError(t, err)
+	writer := getLightClientUpdatesByRange(t, s, altairPeriod, 10)
+	require.Equal(t, http.StatusOK, writer.Code)
+	var resp structs.LightClientUpdatesByRangeResponse
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &resp.Updates))
+	require.Equal(t, 3, len(resp.Updates))
 
-	// get a new signed block so the root is updated with the new state root
-	block.Block.StateRoot = stateRoot[:]
-	signedBlock, err = blocks.NewSignedBeaconBlock(block)
-	require.NoError(t, err)
+	writer = getLightClientUpdatesByRange(t, s, altairPeriod+3, 10)
+	require.Equal(t, http.StatusNotFound, writer.Code)
+}
 
-	root, err := block.Block.HashTreeRoot()
-	require.NoError(t, err)
+func TestLightClientHandler_GetLightClientUpdatesByRange_CountCappedByConfig(t *testing.T) {
+	helpers.ClearCache()
+	params.SetupTestConfigCleanup(t)
+	config := params.BeaconConfig().Copy()
+	config.MaxRequestLightClientUpdates = 2
+	params.OverrideBeaconConfig(config)
 
-	mockBlocker := &testutil.MockBlocker{
-		RootBlockMap: map[[32]byte]interfaces.ReadOnlySignedBeaconBlock{
-			parentRoot: signedParent,
-			root:       signedBlock,
-		},
-		SlotBlockMap: map[primitives.Slot]interfaces.ReadOnlySignedBeaconBlock{
-			slot.Sub(1): signedParent,
-			slot:        signedBlock,
-		},
-	}
-	mockChainService := &mock.ChainService{Optimistic: true, Slot: &slot, State: st}
-	s := &Server{
-		Stater: &testutil.MockStater{StatesBySlot: map[primitives.Slot]state.BeaconState{
-			slot.Sub(1): attestedState,
-			slot:        st,
-		}},
-		Blocker:     mockBlocker,
-		HeadFetcher: mockChainService,
+	db := dbtesting.SetupDB(t)
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	altairPeriod := slots.SyncCommitteePeriod(config.AltairForkEpoch)
+	for _, offset := range []uint64{0, 1, 2} {
+		saveLightClientTestUpdate(t, db, l, altairPeriod+offset)
 	}
-	startPeriod := slot.Div(uint64(config.EpochsPerSyncCommitteePeriod)).Div(uint64(config.SlotsPerEpoch))
-	count := 1
-	url := fmt.Sprintf("http://foo.com/?count=%d&start_period=%d", count, startPeriod)
-	request := httptest.NewRequest("GET", url, nil)
-	writer := httptest.NewRecorder()
-	writer.Body = &bytes.Buffer{}
+	s := &Server{BeaconDB: db}
 
-	s.GetLightClientUpdatesByRange(writer, request)
+	writer := getLightClientUpdatesByRange(t, s, altairPeriod, 10)
+	require.Equal(t, http.StatusOK, writer.Code)
+	var resp structs.LightClientUpdatesByRangeResponse
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &resp.Updates))
+	require.Equal(t, 2, len(resp.Updates))
+}
+
+func TestLightClientHandler_GetLightClientUpdatesByRange_NotFound(t *testing.T) {
+	s := &Server{BeaconDB: dbtesting.SetupDB(t)}
 
+	writer := getLightClientUpdatesByRange(t, s, 1, 1)
 	require.Equal(t, http.StatusNotFound, writer.Code)
 }
 
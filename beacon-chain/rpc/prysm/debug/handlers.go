@@ -0,0 +1,63 @@
+package debug
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+// GetRateLimits retrieves the current req/resp quota usage of every connected peer.
+func (s *Server) GetRateLimits(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "debug.GetRateLimits")
+	defer span.End()
+
+	usage := s.RateLimitUsageFetcher.RateLimitUsage()
+	data := make([]*structs.PeerRateLimits, len(usage))
+	for i, u := range usage {
+		topics := make([]*structs.TopicRateLimits, len(u.Topics))
+		for j, t := range u.Topics {
+			topics[j] = &structs.TopicRateLimits{
+				Topic:         t.Topic,
+				Used:          strconv.FormatInt(t.Used, 10),
+				Capacity:      strconv.FormatInt(t.Capacity, 10),
+				BytesUsed:     strconv.FormatInt(t.BytesUsed, 10),
+				BytesCapacity: strconv.FormatInt(t.BytesCapacity, 10),
+			}
+		}
+		data[i] = &structs.PeerRateLimits{
+			PeerId:      u.PeerID.String(),
+			QuotaFactor: strconv.FormatFloat(u.QuotaFactor, 'f', -1, 64),
+			Topics:      topics,
+		}
+	}
+	httputil.WriteJson(w, &structs.GetRateLimitsResponse{Data: data})
+}
+
+// GetPendingBlocks retrieves the chains of blocks waiting in the pending queue for a missing ancestor.
+func (s *Server) GetPendingBlocks(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "debug.GetPendingBlocks")
+	defer span.End()
+
+	chains := s.PendingBlocksFetcher.PendingChains()
+	data := make([]*structs.PendingChain, len(chains))
+	for i, c := range chains {
+		peers := make([]string, len(c.Peers))
+		for j, pid := range c.Peers {
+			peers[j] = pid.String()
+		}
+		data[i] = &structs.PendingChain{
+			MissingRoot: fmt.Sprintf("%#x", c.MissingRoot),
+			BaseSlot:    strconv.FormatUint(uint64(c.BaseSlot), 10),
+			HeadRoot:    fmt.Sprintf("%#x", c.HeadRoot),
+			HeadSlot:    strconv.FormatUint(uint64(c.HeadSlot), 10),
+			Blocks:      strconv.Itoa(c.Blocks),
+			Bytes:       strconv.Itoa(c.Bytes),
+			Peers:       peers,
+		}
+	}
+	httputil.WriteJson(w, &structs.GetPendingBlocksResponse{Data: data})
+}
@@ -0,0 +1,110 @@
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+type mockRateLimitUsageFetcher struct {
+	usage []*sync.PeerQuotaUsage
+}
+
+func (m *mockRateLimitUsageFetcher) RateLimitUsage() []*sync.PeerQuotaUsage {
+	return m.usage
+}
+
+func TestGetRateLimits(t *testing.T) {
+	pid, err := peer.Decode("16Uiu2HAm1n583t4huDMMqEUUBuQs6bLts21mxCfX3tiqu9JfHvRJ")
+	require.NoError(t, err)
+	s := &Server{
+		RateLimitUsageFetcher: &mockRateLimitUsageFetcher{
+			usage: []*sync.PeerQuotaUsage{
+				{
+					PeerID:      pid,
+					QuotaFactor: 0.5,
+					Topics: []*sync.TopicQuotaUsage{
+						{
+							Topic:         "/eth2/beacon_chain/req/beacon_blocks_by_range/2/ssz_snappy",
+							Used:          10,
+							Capacity:      128,
+							BytesUsed:     1024,
+							BytesCapacity: 4096,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/debug/rate_limits", nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+	s.GetRateLimits(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetRateLimitsResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	require.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, pid.String(), resp.Data[0].PeerId)
+	assert.Equal(t, "0.5", resp.Data[0].QuotaFactor)
+	require.Equal(t, 1, len(resp.Data[0].Topics))
+	topic := resp.Data[0].Topics[0]
+	assert.Equal(t, "/eth2/beacon_chain/req/beacon_blocks_by_range/2/ssz_snappy", topic.Topic)
+	assert.Equal(t, "10", topic.Used)
+	assert.Equal(t, "128", topic.Capacity)
+	assert.Equal(t, "1024", topic.BytesUsed)
+	assert.Equal(t, "4096", topic.BytesCapacity)
+}
+
+type mockPendingBlocksFetcher struct {
+	chains []*sync.PendingChain
+}
+
+func (m *mockPendingBlocksFetcher) PendingChains() []*sync.PendingChain {
+	return m.chains
+}
+
+func TestGetPendingBlocks(t *testing.T) {
+	pid, err := peer.Decode("16Uiu2HAm1n583t4huDMMqEUUBuQs6bLts21mxCfX3tiqu9JfHvRJ")
+	require.NoError(t, err)
+	s := &Server{
+		PendingBlocksFetcher: &mockPendingBlocksFetcher{
+			chains: []*sync.PendingChain{
+				{
+					MissingRoot: [32]byte{'a'},
+					BaseSlot:    10,
+					HeadRoot:    [32]byte{'b'},
+					HeadSlot:    12,
+					Blocks:      3,
+					Bytes:       3000,
+					Peers:       []peer.ID{pid},
+				},
+			},
+		},
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/debug/pending_blocks", nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+	s.GetPendingBlocks(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetPendingBlocksResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	require.Equal(t, 1, len(resp.Data))
+	c := resp.Data[0]
+	assert.Equal(t, "0x6100000000000000000000000000000000000000000000000000000000000000", c.MissingRoot)
+	assert.Equal(t, "10", c.BaseSlot)
+	assert.Equal(t, "0x6200000000000000000000000000000000000000000000000000000000000000", c.HeadRoot)
+	assert.Equal(t, "12", c.HeadSlot)
+	assert.Equal(t, "3", c.Blocks)
+	assert.Equal(t, "3000", c.Bytes)
+	assert.DeepEqual(t, []string{pid.String()}, c.Peers)
+}
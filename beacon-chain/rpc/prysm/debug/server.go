@@ -0,0 +1,10 @@
+package debug
+
+import (
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+)
+
+type Server struct {
+	RateLimitUsageFetcher sync.RateLimitUsageFetcher
+	PendingBlocksFetcher  sync.PendingBlocksFetcher
+}
@@ -0,0 +1,136 @@
+package node
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+// GetBackfillStatus retrieves the progress of backfill, its estimated completion time and the download
+// throughput of the peers serving it.
+func (s *Server) GetBackfillStatus(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.GetBackfillStatus")
+	defer span.End()
+
+	if !s.backfillAvailable(w) {
+		return
+	}
+	p := s.BackfillController.Progress()
+	peers := make([]*structs.BackfillPeerThroughput, len(p.Peers))
+	for i, pt := range p.Peers {
+		peers[i] = &structs.BackfillPeerThroughput{
+			PeerId:         pt.PeerID.String(),
+			Batches:        strconv.FormatUint(pt.Batches, 10),
+			Blocks:         strconv.FormatUint(pt.Blocks, 10),
+			Bytes:          strconv.FormatUint(pt.Bytes, 10),
+			BytesPerSecond: strconv.FormatFloat(pt.BytesPerSecond(), 'f', 0, 64),
+		}
+	}
+	var hours string
+	if p.ActiveHours != nil {
+		hours = p.ActiveHours.String()
+	}
+	httputil.WriteJson(w, &structs.GetBackfillStatusResponse{
+		Data: &structs.BackfillStatus{
+			Running:        p.Running,
+			Paused:         p.Paused,
+			Throttled:      p.Throttled,
+			LowSlot:        strconv.FormatUint(uint64(p.LowSlot), 10),
+			TargetSlot:     strconv.FormatUint(uint64(p.TargetSlot), 10),
+			OriginSlot:     strconv.FormatUint(uint64(p.OriginSlot), 10),
+			SlotsPerSecond: strconv.FormatFloat(p.SlotsPerSecond, 'f', 2, 64),
+			EtaSeconds:     strconv.FormatInt(int64(p.ETA.Seconds()), 10),
+			BandwidthLimit: strconv.FormatUint(p.BandwidthLimit, 10),
+			ActiveHours:    hours,
+			Peers:          peers,
+		},
+	})
+}
+
+// PauseBackfill stops backfill from requesting new batches. Batches in flight are still processed.
+func (s *Server) PauseBackfill(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.PauseBackfill")
+	defer span.End()
+
+	if !s.backfillAvailable(w) {
+		return
+	}
+	s.BackfillController.Pause()
+	w.WriteHeader(http.StatusOK)
+}
+
+// ResumeBackfill lets a paused backfill request batches again.
+func (s *Server) ResumeBackfill(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.ResumeBackfill")
+	defer span.End()
+
+	if !s.backfillAvailable(w) {
+		return
+	}
+	s.BackfillController.Resume()
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetBackfillConfig updates the bandwidth limit and active hours of backfill. Settings which are
+// not part of the request are left unchanged.
+func (s *Server) SetBackfillConfig(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.SetBackfillConfig")
+	defer span.End()
+
+	if !s.backfillAvailable(w) {
+		return
+	}
+	var req structs.SetBackfillConfigRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var limit uint64
+	if req.BandwidthLimit != nil {
+		limit, err = strconv.ParseUint(*req.BandwidthLimit, 10, 64)
+		if err != nil {
+			httputil.HandleError(w, "Invalid bandwidth limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var hours *backfill.ActiveHours
+	if req.ActiveHours != nil && *req.ActiveHours != "" {
+		hours, err = backfill.ParseActiveHours(*req.ActiveHours)
+		if err != nil {
+			httputil.HandleError(w, "Invalid active hours: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.BandwidthLimit != nil {
+		s.BackfillController.SetBandwidthLimit(limit)
+	}
+	if req.ActiveHours != nil {
+		if err := s.BackfillController.SetActiveHours(hours); err != nil {
+			httputil.HandleError(w, "Could not set active hours: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) backfillAvailable(w http.ResponseWriter) bool {
+	if s.BackfillController == nil {
+		httputil.HandleError(w, "Backfill is not available", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
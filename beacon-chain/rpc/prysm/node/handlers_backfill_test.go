@@ -0,0 +1,142 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+type mockBackfillController struct {
+	progress *backfill.Progress
+	paused   bool
+	limit    uint64
+	hours    *backfill.ActiveHours
+}
+
+func (m *mockBackfillController) Progress() *backfill.Progress {
+	return m.progress
+}
+
+func (m *mockBackfillController) Pause() {
+	m.paused = true
+}
+
+func (m *mockBackfillController) Resume() {
+	m.paused = false
+}
+
+func (m *mockBackfillController) SetBandwidthLimit(bytesPerSecond uint64) {
+	m.limit = bytesPerSecond
+}
+
+func (m *mockBackfillController) SetActiveHours(w *backfill.ActiveHours) error {
+	m.hours = w
+	return nil
+}
+
+func TestGetBackfillStatus(t *testing.T) {
+	id, err := peer.Decode(testPeerId)
+	require.NoError(t, err)
+	hours, err := backfill.NewActiveHours(22, 6)
+	require.NoError(t, err)
+	bc := &mockBackfillController{progress: &backfill.Progress{
+		Running:        true,
+		LowSlot:        1000,
+		TargetSlot:     100,
+		OriginSlot:     1200,
+		SlotsPerSecond: 2,
+		ETA:            450 * time.Second,
+		BandwidthLimit: 1 << 20,
+		ActiveHours:    hours,
+		Peers:          []backfill.PeerThroughput{{PeerID: id, Batches: 2, Blocks: 64, Bytes: 4000, Duration: 2 * time.Second}},
+	}}
+	s := &Server{BackfillController: bc}
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+	s.GetBackfillStatus(writer, request)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetBackfillStatusResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	assert.Equal(t, true, resp.Data.Running)
+	assert.Equal(t, "1000", resp.Data.LowSlot)
+	assert.Equal(t, "100", resp.Data.TargetSlot)
+	assert.Equal(t, "1200", resp.Data.OriginSlot)
+	assert.Equal(t, "2.00", resp.Data.SlotsPerSecond)
+	assert.Equal(t, "450", resp.Data.EtaSeconds)
+	assert.Equal(t, "1048576", resp.Data.BandwidthLimit)
+	assert.Equal(t, "22-6", resp.Data.ActiveHours)
+	require.Equal(t, 1, len(resp.Data.Peers))
+	assert.Equal(t, testPeerId, resp.Data.Peers[0].PeerId)
+	assert.Equal(t, "64", resp.Data.Peers[0].Blocks)
+	assert.Equal(t, "2000", resp.Data.Peers[0].BytesPerSecond)
+}
+
+func TestPauseResumeBackfill(t *testing.T) {
+	bc := &mockBackfillController{}
+	s := &Server{BackfillController: bc}
+
+	writer := httptest.NewRecorder()
+	s.PauseBackfill(writer, httptest.NewRequest(http.MethodPost, "http://example.com", nil))
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, true, bc.paused)
+
+	writer = httptest.NewRecorder()
+	s.ResumeBackfill(writer, httptest.NewRequest(http.MethodPost, "http://example.com", nil))
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, false, bc.paused)
+}
+
+func TestSetBackfillConfig(t *testing.T) {
+	setConfig := func(s *Server, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "http://example.com", bytes.NewBufferString(body))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.SetBackfillConfig(writer, request)
+		return writer
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		bc := &mockBackfillController{}
+		s := &Server{BackfillController: bc}
+		writer := setConfig(s, `{"bandwidth_limit":"1000","active_hours":"1-5"}`)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, uint64(1000), bc.limit)
+		assert.DeepEqual(t, &backfill.ActiveHours{Start: 1, End: 5}, bc.hours)
+
+		// Omitted settings are left as they are, an empty window removes the restriction.
+		writer = setConfig(s, `{"active_hours":""}`)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, uint64(1000), bc.limit)
+		assert.Equal(t, true, bc.hours == nil)
+	})
+	t.Run("invalid active hours", func(t *testing.T) {
+		bc := &mockBackfillController{}
+		s := &Server{BackfillController: bc}
+		writer := setConfig(s, `{"bandwidth_limit":"1000","active_hours":"5-5"}`)
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+		assert.Equal(t, uint64(0), bc.limit)
+	})
+	t.Run("invalid bandwidth limit", func(t *testing.T) {
+		writer := setConfig(&Server{BackfillController: &mockBackfillController{}}, `{"bandwidth_limit":"-1"}`)
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+	t.Run("no body", func(t *testing.T) {
+		writer := setConfig(&Server{BackfillController: &mockBackfillController{}}, "")
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+	})
+	t.Run("backfill not available", func(t *testing.T) {
+		writer := setConfig(&Server{}, `{"bandwidth_limit":"1000"}`)
+		assert.Equal(t, http.StatusServiceUnavailable, writer.Code)
+	})
+}
@@ -0,0 +1,28 @@
+package node
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+// GetHistory returns the lowest slot from which the node stores blocks and states. It is above genesis until
+// backfill downloads the older blocks, and moves up as the node prunes history older than its retention period.
+func (s *Server) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "node.GetHistory")
+	defer span.End()
+
+	earliestSlot, err := s.BeaconDB.EarliestAvailableSlot(ctx)
+	if err != nil {
+		httputil.HandleError(w, "Could not get earliest available slot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	httputil.WriteJson(w, &structs.GetHistoryResponse{
+		Data: &structs.History{
+			EarliestAvailableSlot: strconv.FormatUint(uint64(earliestSlot), 10),
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	dbtest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestGetHistory(t *testing.T) {
+	beaconDB := dbtest.SetupDB(t)
+	require.NoError(t, beaconDB.SaveBackfillStatus(context.Background(), &dbval.BackfillStatus{LowSlot: 64}))
+	s := &Server{BeaconDB: beaconDB}
+
+	writer := httptest.NewRecorder()
+	s.GetHistory(writer, httptest.NewRequest(http.MethodGet, "http://example.com/prysm/v1/node/history", nil))
+	require.Equal(t, http.StatusOK, writer.Code)
+	resp := &structs.GetHistoryResponse{}
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+	assert.Equal(t, "64", resp.Data.EarliestAvailableSlot)
+}
@@ -0,0 +1,156 @@
+package node
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	prysmTime "github.com/prysmaticlabs/prysm/v5/time"
+)
+
+// GetPeerScore retrieves the overall score of the given peer along with the contribution of every scorer.
+func (s *Server) GetPeerScore(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.GetPeerScore")
+	defer span.End()
+
+	id, ok := peerIdFromPath(w, r)
+	if !ok {
+		return
+	}
+	peerStatus := s.PeersFetcher.Peers()
+	breakdown := peerStatus.Scorers().ScoreBreakdown(id)
+	if breakdown == nil {
+		httputil.HandleError(w, "Peer not found", http.StatusNotFound)
+		return
+	}
+	resp := &structs.GetPeerScoreResponse{
+		Data: &structs.PeerScore{
+			PeerId:        id.String(),
+			Score:         formatScore(breakdown.Total),
+			BadResponses:  formatScore(breakdown.BadResponses),
+			BlockProvider: formatScore(breakdown.BlockProvider),
+			PeerStatus:    formatScore(breakdown.PeerStatus),
+			Gossip:        formatScore(breakdown.Gossip),
+			IsBad:         peerStatus.IsBad(id),
+			IsBanned:      peerStatus.IsBanned(id),
+		},
+	}
+	httputil.WriteJson(w, resp)
+}
+
+// ListBannedPeers retrieves all manually banned peers along with their ban expiry.
+func (s *Server) ListBannedPeers(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.ListBannedPeers")
+	defer span.End()
+
+	bans := s.PeersFetcher.Peers().BannedPeers()
+	bannedPeers := make([]*structs.BannedPeer, 0, len(bans))
+	for id, expiry := range bans {
+		var expiryUnix int64
+		if !expiry.IsZero() {
+			expiryUnix = expiry.Unix()
+		}
+		bannedPeers = append(bannedPeers, &structs.BannedPeer{
+			PeerId: id.String(),
+			Expiry: strconv.FormatInt(expiryUnix, 10),
+		})
+	}
+	httputil.WriteJson(w, &structs.BannedPeersResponse{Peers: bannedPeers})
+}
+
+// BanPeer manually bans a peer and disconnects from it. The ban is lifted once the requested
+// duration elapses, or never if no duration is provided.
+func (s *Server) BanPeer(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.BanPeer")
+	defer span.End()
+
+	var req structs.BanPeerRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	switch {
+	case errors.Is(err, io.EOF):
+		httputil.HandleError(w, "No data submitted", http.StatusBadRequest)
+		return
+	case err != nil:
+		httputil.HandleError(w, "Could not decode request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := peer.Decode(req.PeerId)
+	if err != nil {
+		httputil.HandleError(w, "Invalid peer ID: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var expiry time.Time
+	if req.DurationSeconds != "" {
+		duration, err := strconv.ParseUint(req.DurationSeconds, 10, 64)
+		if err != nil {
+			httputil.HandleError(w, "Invalid ban duration: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if duration > 0 {
+			expiry = prysmTime.Now().Add(time.Duration(duration) * time.Second)
+		}
+	}
+	if err := s.PeerManager.BanPeer(id, expiry); err != nil {
+		httputil.HandleError(w, "Could not ban peer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// UnbanPeer lifts a manual ban from the given peer.
+func (s *Server) UnbanPeer(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.UnbanPeer")
+	defer span.End()
+
+	id, ok := peerIdFromPath(w, r)
+	if !ok {
+		return
+	}
+	if err := s.PeerManager.UnbanPeer(id); err != nil {
+		httputil.HandleError(w, "Could not unban peer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// DisconnectPeer closes all connections to the given peer. The peer is free to reconnect
+// unless it is also banned.
+func (s *Server) DisconnectPeer(w http.ResponseWriter, r *http.Request) {
+	_, span := trace.StartSpan(r.Context(), "node.DisconnectPeer")
+	defer span.End()
+
+	id, ok := peerIdFromPath(w, r)
+	if !ok {
+		return
+	}
+	if err := s.PeerManager.Disconnect(id); err != nil {
+		httputil.HandleError(w, "Could not disconnect peer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func peerIdFromPath(w http.ResponseWriter, r *http.Request) (peer.ID, bool) {
+	rawId := r.PathValue("peer_id")
+	if rawId == "" {
+		httputil.HandleError(w, "peer_id is required in URL params", http.StatusBadRequest)
+		return "", false
+	}
+	id, err := peer.Decode(rawId)
+	if err != nil {
+		httputil.HandleError(w, "Invalid peer ID: "+err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return id, true
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
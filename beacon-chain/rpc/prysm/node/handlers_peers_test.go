@@ -0,0 +1,151 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corenet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	mockp2p "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+const testPeerId = "16Uiu2HAm1n583t4huDMMqEUUBuQs6bLts21mxCfX3tiqu9JfHvRJ"
+
+func TestGetPeerScore(t *testing.T) {
+	p := mockp2p.NewTestP2P(t)
+	id, err := peer.Decode(testPeerId)
+	require.NoError(t, err)
+	addr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/30303")
+	require.NoError(t, err)
+	p.Peers().Add(nil, id, addr, corenet.DirOutbound)
+	p.Peers().Scorers().BadResponsesScorer().Increment(id)
+	s := Server{PeersFetcher: p}
+
+	t.Run("OK", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		request.SetPathValue("peer_id", testPeerId)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.GetPeerScore(writer, request)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		resp := &structs.GetPeerScoreResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, testPeerId, resp.Data.PeerId)
+		assert.Equal(t, "-0.6", resp.Data.BadResponses)
+		assert.Equal(t, "0", resp.Data.Gossip)
+		assert.Equal(t, false, resp.Data.IsBad)
+		assert.Equal(t, false, resp.Data.IsBanned)
+	})
+	t.Run("unknown peer", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		request.SetPathValue("peer_id", "16Uiu2HAm7yD5fhhw1Kihg5pffaGbvKV3k7sqxRGHMZzkb7u9UUxQ")
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.GetPeerScore(writer, request)
+		assert.Equal(t, http.StatusNotFound, writer.Code)
+	})
+	t.Run("invalid peer id", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		request.SetPathValue("peer_id", "foo")
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.GetPeerScore(writer, request)
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+		e := &httputil.DefaultJsonError{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+		assert.StringContains(t, "Invalid peer ID", e.Message)
+	})
+}
+
+func TestBanPeer(t *testing.T) {
+	p := mockp2p.NewTestP2P(t)
+	s := Server{PeersFetcher: p, PeerManager: p}
+	id, err := peer.Decode(testPeerId)
+	require.NoError(t, err)
+
+	t.Run("with duration", func(t *testing.T) {
+		body, err := json.Marshal(&structs.BanPeerRequest{PeerId: testPeerId, DurationSeconds: "3600"})
+		require.NoError(t, err)
+		request := httptest.NewRequest(http.MethodPost, "http://example.com", bytes.NewReader(body))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.BanPeer(writer, request)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, true, p.Peers().IsBanned(id))
+		assert.Equal(t, true, p.Peers().IsBad(id))
+
+		request = httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		writer = httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.ListBannedPeers(writer, request)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		resp := &structs.BannedPeersResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		require.Equal(t, 1, len(resp.Peers))
+		assert.Equal(t, testPeerId, resp.Peers[0].PeerId)
+		assert.NotEqual(t, "0", resp.Peers[0].Expiry)
+
+		request = httptest.NewRequest(http.MethodDelete, "http://example.com", nil)
+		request.SetPathValue("peer_id", testPeerId)
+		writer = httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.UnbanPeer(writer, request)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, false, p.Peers().IsBanned(id))
+	})
+	t.Run("without duration", func(t *testing.T) {
+		body, err := json.Marshal(&structs.BanPeerRequest{PeerId: testPeerId})
+		require.NoError(t, err)
+		request := httptest.NewRequest(http.MethodPost, "http://example.com", bytes.NewReader(body))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.BanPeer(writer, request)
+		assert.Equal(t, http.StatusOK, writer.Code)
+		assert.Equal(t, true, p.Peers().IsBanned(id))
+		bans := p.Peers().BannedPeers()
+		expiry, ok := bans[id]
+		require.Equal(t, true, ok)
+		assert.Equal(t, true, expiry.IsZero())
+	})
+	t.Run("invalid duration", func(t *testing.T) {
+		body, err := json.Marshal(&structs.BanPeerRequest{PeerId: testPeerId, DurationSeconds: "foo"})
+		require.NoError(t, err)
+		request := httptest.NewRequest(http.MethodPost, "http://example.com", bytes.NewReader(body))
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.BanPeer(writer, request)
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+		e := &httputil.DefaultJsonError{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+		assert.StringContains(t, "Invalid ban duration", e.Message)
+	})
+	t.Run("no body", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+		writer := httptest.NewRecorder()
+		writer.Body = &bytes.Buffer{}
+		s.BanPeer(writer, request)
+		assert.Equal(t, http.StatusBadRequest, writer.Code)
+		e := &httputil.DefaultJsonError{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), e))
+		assert.StringContains(t, "No data submitted", e.Message)
+	})
+}
+
+func TestDisconnectPeer(t *testing.T) {
+	s := Server{PeerManager: &mockp2p.MockPeerManager{}}
+
+	request := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+	request.SetPathValue("peer_id", testPeerId)
+	writer := httptest.NewRecorder()
+	writer.Body = &bytes.Buffer{}
+	s.DisconnectPeer(writer, request)
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
@@ -0,0 +1,39 @@
+package node
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/network/httputil"
+)
+
+// WriteSnapshot writes a snapshot archive of the database and blobs of the node to the snapshot directory, and
+// returns its path along with the head and finalized checkpoint it holds. The snapshot is abandoned if the
+// request is.
+func (s *Server) WriteSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "node.WriteSnapshot")
+	defer span.End()
+
+	if s.Snapshotter == nil {
+		httputil.HandleError(w, "Snapshots are not available", http.StatusServiceUnavailable)
+		return
+	}
+	archivePath, manifest, err := db.WriteSnapshot(ctx, s.Snapshotter, s.BlobStorage, s.SnapshotDir)
+	if err != nil {
+		httputil.HandleError(w, "Could not write snapshot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	httputil.WriteJson(w, &structs.WriteSnapshotResponse{
+		Data: &structs.Snapshot{
+			Path:           archivePath,
+			HeadSlot:       strconv.FormatUint(uint64(manifest.HeadSlot), 10),
+			HeadRoot:       manifest.HeadRoot,
+			FinalizedEpoch: strconv.FormatUint(uint64(manifest.FinalizedEpoch), 10),
+			FinalizedRoot:  manifest.FinalizedRoot,
+			Blobs:          strconv.Itoa(manifest.Blobs),
+		},
+	})
+}
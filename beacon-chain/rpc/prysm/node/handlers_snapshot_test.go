@@ -0,0 +1,38 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	dbtest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	t.Run("unavailable", func(t *testing.T) {
+		s := &Server{}
+		writer := httptest.NewRecorder()
+		s.WriteSnapshot(writer, httptest.NewRequest(http.MethodPost, "http://example.com/prysm/v1/node/snapshot", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, writer.Code)
+	})
+	t.Run("ok", func(t *testing.T) {
+		s := &Server{
+			Snapshotter: dbtest.SetupDB(t),
+			SnapshotDir: t.TempDir(),
+		}
+		writer := httptest.NewRecorder()
+		s.WriteSnapshot(writer, httptest.NewRequest(http.MethodPost, "http://example.com/prysm/v1/node/snapshot", nil))
+		require.Equal(t, http.StatusOK, writer.Code)
+		resp := &structs.WriteSnapshotResponse{}
+		require.NoError(t, json.Unmarshal(writer.Body.Bytes(), resp))
+		assert.Equal(t, "0", resp.Data.HeadSlot)
+		assert.Equal(t, "0", resp.Data.Blobs)
+		_, err := os.Stat(resp.Data.Path)
+		require.NoError(t, err)
+	})
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
 	chainSync "github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/io/logs"
@@ -121,6 +122,9 @@ type Config struct {
 	SyncCommitteeObjectPool   synccommittee.Pool
 	BLSChangesPool            blstoexec.PoolManager
 	SyncService               chainSync.Checker
+	RateLimitUsageFetcher     chainSync.RateLimitUsageFetcher
+	PendingBlocksFetcher      chainSync.PendingBlocksFetcher
+	BackfillController        backfill.Controller
 	Broadcaster               p2p.Broadcaster
 	PeersFetcher              p2p.PeersProvider
 	PeerManager               p2p.PeerManager
@@ -140,6 +144,10 @@ type Config struct {
 	BlobStorage               *filesystem.BlobStorage
 	TrackedValidatorsCache    *cache.TrackedValidatorsCache
 	PayloadIDCache            *cache.PayloadIDCache
+	Snapshotter               db.Snapshotter
+	SnapshotDir               string
+	SnapshotAuthToken         string
+	ReadOnly                  bool
 }
 
 // NewService instantiates a new RPC service instance that will
@@ -154,13 +162,16 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		connectedRPCClients: make(map[net.Addr]bool),
 	}
 
-	address := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
-	lis, err := net.Listen("tcp", address)
-	if err != nil {
-		log.WithError(err).Errorf("Could not listen to port in Start() %s", address)
+	// A read-only replica serves the REST API only, since validator clients connect over gRPC.
+	if !s.cfg.ReadOnly {
+		address := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			log.WithError(err).Errorf("Could not listen to port in Start() %s", address)
+		}
+		s.listener = lis
+		log.WithField("address", address).Info("gRPC server listening on port")
 	}
-	s.listener = lis
-	log.WithField("address", address).Info("gRPC server listening on port")
 
 	opts := []grpc.ServerOption{
 		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
@@ -196,11 +207,14 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 	s.grpcServer = grpc.NewServer(opts...)
 
 	var stateCache stategen.CachedGetter
+	var boundaryStates *stategen.BoundaryStateStore
 	if s.cfg.StateGen != nil {
 		stateCache = s.cfg.StateGen.CombinedCache()
+		boundaryStates = s.cfg.StateGen.BoundaryStates()
 	}
 	withCache := stategen.WithCache(stateCache)
-	ch := stategen.NewCanonicalHistory(s.cfg.BeaconDB, s.cfg.ChainInfoFetcher, s.cfg.ChainInfoFetcher, withCache)
+	withBoundaryStates := stategen.WithBoundaryStates(boundaryStates)
+	ch := stategen.NewCanonicalHistory(s.cfg.BeaconDB, s.cfg.ChainInfoFetcher, s.cfg.ChainInfoFetcher, withCache, withBoundaryStates)
 	stater := &lookup.BeaconDbStater{
 		BeaconDB:           s.cfg.BeaconDB,
 		ChainInfoFetcher:   s.cfg.ChainInfoFetcher,
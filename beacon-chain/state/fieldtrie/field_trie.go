@@ -7,7 +7,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native/types"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	multi_value_slice "github.com/prysmaticlabs/prysm/v5/container/multi-value-slice"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz"
 	pmath "github.com/prysmaticlabs/prysm/v5/math"
 )
 
@@ -25,11 +27,15 @@ type sliceAccessor interface {
 }
 
 // FieldTrie is the representation of the representative
-// trie of the particular field.
+// trie of the particular field. With the persistent state tree
+// feature, the trie is a tree of immutable nodes instead of layers,
+// which copies of the trie share.
 type FieldTrie struct {
 	*sync.RWMutex
 	reference     *stateutil.Reference
 	fieldLayers   [][]*[32]byte
+	tree          *trieNode
+	depth         uint8
 	field         types.FieldIndex
 	dataType      types.DataType
 	length        uint64
@@ -68,6 +74,9 @@ func NewFieldTrie(field types.FieldIndex, fieldInfo types.DataType, elements int
 	}
 	switch fieldInfo {
 	case types.BasicArray:
+		if features.Get().EnablePersistentStateTree {
+			return newPersistentFieldTrie(field, fieldInfo, fieldRoots, length, numOfElems), nil
+		}
 		fl, err := stateutil.ReturnTrieLayer(fieldRoots, length)
 		if err != nil {
 			return nil, err
@@ -82,6 +91,9 @@ func NewFieldTrie(field types.FieldIndex, fieldInfo types.DataType, elements int
 			numOfElems:  numOfElems,
 		}, nil
 	case types.CompositeArray, types.CompressedArray:
+		if features.Get().EnablePersistentStateTree {
+			return newPersistentFieldTrie(field, fieldInfo, fieldRoots, length, numOfElems), nil
+		}
 		return &FieldTrie{
 			fieldLayers: stateutil.ReturnTrieLayerVariable(fieldRoots, length),
 			field:       field,
@@ -96,6 +108,21 @@ func NewFieldTrie(field types.FieldIndex, fieldInfo types.DataType, elements int
 	}
 }
 
+// newPersistentFieldTrie builds the persistent trie of the field from the roots of its elements.
+func newPersistentFieldTrie(field types.FieldIndex, fieldInfo types.DataType, fieldRoots [][32]byte, length uint64, numOfElems int) *FieldTrie {
+	depth := ssz.Depth(length)
+	return &FieldTrie{
+		tree:       buildTrie(fieldRoots, depth),
+		depth:      depth,
+		field:      field,
+		dataType:   fieldInfo,
+		reference:  stateutil.NewRef(1),
+		RWMutex:    new(sync.RWMutex),
+		length:     length,
+		numOfElems: numOfElems,
+	}
+}
+
 // RecomputeTrie rebuilds the affected branches in the trie according to the provided
 // changed indices and elements. This recomputes the trie according to the particular
 // field the trie is based on.
@@ -122,12 +149,20 @@ func (f *FieldTrie) RecomputeTrie(indices []uint64, elements interface{}) ([32]b
 	}
 	switch f.dataType {
 	case types.BasicArray:
+		if f.tree != nil {
+			f.tree = updateTrie(f.tree, f.depth, indices, fieldRoots)
+			return f.tree.root, nil
+		}
 		fieldRoot, f.fieldLayers, err = stateutil.RecomputeFromLayer(fieldRoots, indices, f.fieldLayers)
 		if err != nil {
 			return [32]byte{}, err
 		}
 		return fieldRoot, nil
 	case types.CompositeArray:
+		if f.tree != nil {
+			f.tree = updateTrie(f.tree, f.depth, indices, fieldRoots)
+			return stateutil.AddInMixin(f.tree.root, uint64(f.numOfElems))
+		}
 		fieldRoot, f.fieldLayers, err = stateutil.RecomputeFromLayerVariable(fieldRoots, indices, f.fieldLayers)
 		if err != nil {
 			return [32]byte{}, err
@@ -156,6 +191,10 @@ func (f *FieldTrie) RecomputeTrie(indices []uint64, elements interface{}) ([32]b
 			indexExists[startIdx] = true
 			newRoots = append(newRoots, fieldRoots[i])
 		}
+		if f.tree != nil {
+			f.tree = updateTrie(f.tree, f.depth, newIndices, newRoots)
+			return stateutil.AddInMixin(f.tree.root, uint64(f.numOfElems))
+		}
 		fieldRoot, f.fieldLayers, err = stateutil.RecomputeFromLayerVariable(newRoots, newIndices, f.fieldLayers)
 		if err != nil {
 			return [32]byte{}, err
@@ -167,8 +206,20 @@ func (f *FieldTrie) RecomputeTrie(indices []uint64, elements interface{}) ([32]b
 }
 
 // CopyTrie copies the references to the elements the trie
-// is built on.
+// is built on. A persistent trie shares its nodes with the copy.
 func (f *FieldTrie) CopyTrie() *FieldTrie {
+	if f.tree != nil {
+		return &FieldTrie{
+			tree:       f.tree,
+			depth:      f.depth,
+			field:      f.field,
+			dataType:   f.dataType,
+			reference:  stateutil.NewRef(1),
+			RWMutex:    new(sync.RWMutex),
+			length:     f.length,
+			numOfElems: f.numOfElems,
+		}
+	}
 	if f.fieldLayers == nil {
 		return &FieldTrie{
 			field:      f.field,
@@ -207,7 +258,12 @@ func (f *FieldTrie) Length() uint64 {
 // trie will unlikely need it for recomputation. This helps
 // us save on a copy. Any caller of this method will need
 // to take care that this isn't called on an empty trie.
+// A persistent trie is copied instead, as the copy costs
+// nothing and the states referencing the trie keep it.
 func (f *FieldTrie) TransferTrie() *FieldTrie {
+	if f.tree != nil {
+		return f.CopyTrie()
+	}
 	if f.fieldLayers == nil {
 		return &FieldTrie{
 			field:      f.field,
@@ -238,6 +294,12 @@ func (f *FieldTrie) TrieRoot() ([32]byte, error) {
 	if f.Empty() {
 		return [32]byte{}, ErrEmptyFieldTrie
 	}
+	if f.tree != nil {
+		if f.dataType == types.BasicArray {
+			return f.tree.root, nil
+		}
+		return stateutil.AddInMixin(f.tree.root, uint64(f.numOfElems))
+	}
 	if len(f.fieldLayers[len(f.fieldLayers)-1]) == 0 {
 		return [32]byte{}, ErrInvalidFieldTrie
 	}
@@ -264,7 +326,7 @@ func (f *FieldTrie) FieldReference() *stateutil.Reference {
 // Empty checks whether the underlying field trie is
 // empty or not.
 func (f *FieldTrie) Empty() bool {
-	return f == nil || len(f.fieldLayers) == 0 || f.isTransferred
+	return f == nil || (len(f.fieldLayers) == 0 && f.tree == nil) || f.isTransferred
 }
 
 // InsertFieldLayer manually inserts a field layer. This method
@@ -0,0 +1,112 @@
+package fieldtrie
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/prysmaticlabs/prysm/v5/container/trie"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash/htr"
+)
+
+// trieNode is a node of a persistent Merkle trie. A node is never modified once built. Updating leaves builds new
+// nodes along the paths to them only, so that a trie and its copies share every other node along with its hash.
+type trieNode struct {
+	left, right *trieNode
+	root        [32]byte
+}
+
+// zeroNodes[i] is the root node of a trie of depth i whose leaves are all zero.
+var zeroNodes = func() []*trieNode {
+	nodes := make([]*trieNode, 65)
+	nodes[0] = &trieNode{root: trie.ZeroHashes[0]}
+	for i := 1; i < len(nodes); i++ {
+		nodes[i] = &trieNode{left: nodes[i-1], right: nodes[i-1], root: trie.ZeroHashes[i]}
+	}
+	return nodes
+}()
+
+// buildTrie builds the trie of the given depth whose first leaves are the given ones, and the rest are zero. Each
+// layer is hashed at once, and its nodes are allocated together.
+func buildTrie(leaves [][32]byte, depth uint8) *trieNode {
+	if len(leaves) == 0 {
+		return zeroNodes[depth]
+	}
+	layer := make([]trieNode, len(leaves))
+	nodes := make([]*trieNode, len(leaves))
+	for i := range leaves {
+		layer[i].root = leaves[i]
+		nodes[i] = &layer[i]
+	}
+	for d := uint8(0); d < depth; d++ {
+		if len(nodes)%2 == 1 {
+			nodes = append(nodes, zeroNodes[d])
+		}
+		chunks := make([][32]byte, len(nodes))
+		for i, n := range nodes {
+			chunks[i] = n.root
+		}
+		roots := htr.VectorizedSha256(chunks)
+		parents := make([]trieNode, len(roots))
+		next := make([]*trieNode, len(roots))
+		for i := range roots {
+			parents[i] = trieNode{left: nodes[2*i], right: nodes[2*i+1], root: roots[i]}
+			next[i] = &parents[i]
+		}
+		nodes = next
+	}
+	return nodes[0]
+}
+
+// updateTrie returns the root node of the trie of the given depth with the leaves at the indices replaced. The
+// nodes of n are left untouched.
+func updateTrie(n *trieNode, depth uint8, indices []uint64, leaves [][32]byte) *trieNode {
+	if len(indices) == 0 {
+		return n
+	}
+	if !slices.IsSorted(indices) {
+		indices, leaves = sortLeaves(indices, leaves)
+	}
+	hasher := hash.CustomSHA256Hasher()
+	var chunks [64]byte
+	var update func(n *trieNode, depth uint8, offset uint64, indices []uint64, leaves [][32]byte) *trieNode
+	update = func(n *trieNode, depth uint8, offset uint64, indices []uint64, leaves [][32]byte) *trieNode {
+		if depth == 0 {
+			// The last leaf wins when an index is repeated.
+			return &trieNode{root: leaves[len(leaves)-1]}
+		}
+		mid := offset + uint64(1)<<(depth-1)
+		split := sort.Search(len(indices), func(i int) bool {
+			return indices[i] >= mid
+		})
+		left, right := n.left, n.right
+		if split > 0 {
+			left = update(left, depth-1, offset, indices[:split], leaves[:split])
+		}
+		if split < len(indices) {
+			right = update(right, depth-1, mid, indices[split:], leaves[split:])
+		}
+		copy(chunks[:32], left.root[:])
+		copy(chunks[32:], right.root[:])
+		return &trieNode{left: left, right: right, root: hasher(chunks[:])}
+	}
+	return update(n, depth, 0, indices, leaves)
+}
+
+// sortLeaves sorts the indices and their leaves by index, keeping the order of leaves of repeated indices.
+func sortLeaves(indices []uint64, leaves [][32]byte) ([]uint64, [][32]byte) {
+	order := make([]int, len(indices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return indices[order[i]] < indices[order[j]]
+	})
+	sortedIndices := make([]uint64, len(indices))
+	sortedLeaves := make([][32]byte, len(leaves))
+	for i, o := range order {
+		sortedIndices[i] = indices[o]
+		sortedLeaves[i] = leaves[o]
+	}
+	return sortedIndices, sortedLeaves
+}
@@ -0,0 +1,140 @@
+package fieldtrie_test
+
+import (
+	"testing"
+
+	. "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/fieldtrie"
+	customtypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native/custom-types"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native/types"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+// newTriePair returns the field trie built from the given elements with layers, and the one built as a persistent tree.
+func newTriePair(t *testing.T, field types.FieldIndex, dataType types.DataType, elements interface{}, length uint64) (*FieldTrie, *FieldTrie) {
+	layered, err := NewFieldTrie(field, dataType, elements, length)
+	require.NoError(t, err)
+	reset := features.InitWithReset(&features.Flags{EnablePersistentStateTree: true})
+	defer reset()
+	persistent, err := NewFieldTrie(field, dataType, elements, length)
+	require.NoError(t, err)
+	return layered, persistent
+}
+
+func assertSameRoots(t *testing.T, layered, persistent *FieldTrie) {
+	want, err := layered.TrieRoot()
+	require.NoError(t, err)
+	got, err := persistent.TrieRoot()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestFieldTrie_PersistentTree_BasicArray(t *testing.T) {
+	newState, _ := util.DeterministicGenesisState(t, 32)
+	blockRoots := func() customtypes.BlockRoots {
+		roots := newState.BlockRoots()
+		br := make([][32]byte, len(roots))
+		for i, r := range roots {
+			br[i] = [32]byte(r)
+		}
+		return br
+	}
+	length := uint64(params.BeaconConfig().SlotsPerHistoricalRoot)
+	layered, persistent := newTriePair(t, types.BlockRoots, types.BasicArray, blockRoots(), length)
+	assertSameRoots(t, layered, persistent)
+
+	// Unsorted and repeated indices.
+	changedIdx := []uint64{length - 1, 7, 0, 7, 4000}
+	for i, idx := range changedIdx {
+		require.NoError(t, newState.UpdateBlockRootAtIndex(idx, [32]byte{byte(i + 1)}))
+	}
+	want, err := layered.RecomputeTrie(changedIdx, blockRoots())
+	require.NoError(t, err)
+	got, err := persistent.RecomputeTrie(changedIdx, blockRoots())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	expected, err := stateutil.RootsArrayHashTreeRoot(newState.BlockRoots(), length)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+	assertSameRoots(t, layered, persistent)
+}
+
+func TestFieldTrie_PersistentTree_CompositeArray(t *testing.T) {
+	newState, _ := util.DeterministicGenesisState(t, 64)
+	layered, persistent := newTriePair(t, types.Validators, types.CompositeArray, newState.Validators(), params.BeaconConfig().ValidatorRegistryLimit)
+	assertSameRoots(t, layered, persistent)
+
+	changedIdx := []uint64{40, 3, 63, 3}
+	for _, idx := range changedIdx {
+		val, err := newState.ValidatorAtIndex(primitives.ValidatorIndex(idx))
+		require.NoError(t, err)
+		val.ExitEpoch = primitives.Epoch(idx)
+		val.Slashed = true
+		require.NoError(t, newState.UpdateValidatorAtIndex(primitives.ValidatorIndex(idx), val))
+	}
+	want, err := layered.RecomputeTrie(changedIdx, newState.Validators())
+	require.NoError(t, err)
+	got, err := persistent.RecomputeTrie(changedIdx, newState.Validators())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	expected, err := stateutil.ValidatorRegistryRoot(newState.Validators())
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+	assertSameRoots(t, layered, persistent)
+}
+
+func TestFieldTrie_PersistentTree_CompressedArray(t *testing.T) {
+	newState, _ := util.DeterministicGenesisState(t, 64)
+	layered, persistent := newTriePair(t, types.Balances, types.CompressedArray, newState.Balances(), stateutil.ValidatorLimitForBalancesChunks())
+	assertSameRoots(t, layered, persistent)
+
+	changedIdx := []uint64{33, 1, 2, 60}
+	for i, idx := range changedIdx {
+		require.NoError(t, newState.UpdateBalancesAtIndex(primitives.ValidatorIndex(idx), uint64(i+1)*1e9))
+	}
+	want, err := layered.RecomputeTrie(changedIdx, newState.Balances())
+	require.NoError(t, err)
+	got, err := persistent.RecomputeTrie(changedIdx, newState.Balances())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	expected, err := stateutil.Uint64ListRootWithRegistryLimit(newState.Balances())
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestFieldTrie_PersistentTree_CopyAndTransfer(t *testing.T) {
+	resetCfg := features.InitWithReset(&features.Flags{EnablePersistentStateTree: true})
+	defer resetCfg()
+
+	newState, _ := util.DeterministicGenesisState(t, 32)
+	trie, err := NewFieldTrie(types.Validators, types.CompositeArray, newState.Validators(), params.BeaconConfig().ValidatorRegistryLimit)
+	require.NoError(t, err)
+	oldRoot, err := trie.TrieRoot()
+	require.NoError(t, err)
+
+	copied := trie.CopyTrie()
+	transferred := trie.TransferTrie()
+	require.Equal(t, false, trie.Empty(), "Transferring a persistent trie must keep the original")
+
+	val, err := newState.ValidatorAtIndex(5)
+	require.NoError(t, err)
+	val.Slashed = true
+	require.NoError(t, newState.UpdateValidatorAtIndex(5, val))
+	newRoot, err := trie.RecomputeTrie([]uint64{5}, newState.Validators())
+	require.NoError(t, err)
+	require.NotEqual(t, oldRoot, newRoot)
+
+	for _, other := range []*FieldTrie{copied, transferred} {
+		root, err := other.TrieRoot()
+		require.NoError(t, err)
+		assert.Equal(t, oldRoot, root)
+	}
+	root, err := copied.RecomputeTrie([]uint64{5}, newState.Validators())
+	require.NoError(t, err)
+	assert.Equal(t, newRoot, root)
+}
@@ -0,0 +1,434 @@
+package statediff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	ssz "github.com/prysmaticlabs/fastssz"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	statenative "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrVersionMismatch is returned when diffing states of different forks. Such states have to be stored in full.
+var ErrVersionMismatch = errors.New("states are of different forks")
+
+var errMalformedDiff = errors.New("malformed state diff")
+
+// Diff returns the difference of the target state from the base state. Fields are compared one by one: lists
+// record only the elements which changed or were appended, balance-like lists record the change of every element,
+// and every other field is recorded in full when it changed. Elements are SSZ encoded.
+func Diff(base, target state.ReadOnlyBeaconState) ([]byte, error) {
+	if base.Version() != target.Version() {
+		return nil, ErrVersionMismatch
+	}
+	bm, err := protoState(base)
+	if err != nil {
+		return nil, err
+	}
+	tm, err := protoState(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var fieldsBuf bytes.Buffer
+	changed := 0
+	fields := tm.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		var fieldBuf bytes.Buffer
+		ok, err := diffField(&fieldBuf, fd, bm, tm)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not diff field %s", fd.Name())
+		}
+		if !ok {
+			continue
+		}
+		writeUvarint(&fieldsBuf, uint64(fd.Number()))
+		fieldsBuf.Write(fieldBuf.Bytes())
+		changed++
+	}
+
+	var buf bytes.Buffer
+	writeUvarint(&buf, uint64(target.Version()))
+	writeUvarint(&buf, uint64(changed))
+	buf.Write(fieldsBuf.Bytes())
+	return snappy.Encode(nil, buf.Bytes()), nil
+}
+
+// Apply returns the state obtained by applying a diff created by Diff to the base state it was created from.
+func Apply(base state.ReadOnlyBeaconState, diff []byte) (state.BeaconState, error) {
+	enc, err := snappy.Decode(nil, diff)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress state diff")
+	}
+	r := bytes.NewReader(enc)
+	v, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errMalformedDiff
+	}
+	if int(v) != base.Version() {
+		return nil, ErrVersionMismatch
+	}
+	pb, ok := base.ToProto().(proto.Message)
+	if !ok {
+		return nil, errors.New("state is not a proto message")
+	}
+	m := pb.ProtoReflect()
+	changed, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errMalformedDiff
+	}
+	fields := m.Descriptor().Fields()
+	for i := uint64(0); i < changed; i++ {
+		num, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errMalformedDiff
+		}
+		fd := fields.ByNumber(protoreflect.FieldNumber(num))
+		if fd == nil {
+			return nil, errors.Wrapf(errMalformedDiff, "unknown field %d", num)
+		}
+		if err := applyField(r, fd, m); err != nil {
+			return nil, errors.Wrapf(err, "could not apply diff of field %s", fd.Name())
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.Wrap(errMalformedDiff, "trailing bytes")
+	}
+	return initializeState(pb)
+}
+
+func protoState(st state.ReadOnlyBeaconState) (protoreflect.Message, error) {
+	pb, ok := st.ToProtoUnsafe().(proto.Message)
+	if !ok {
+		return nil, errors.New("state is not a proto message")
+	}
+	return pb.ProtoReflect(), nil
+}
+
+func initializeState(pb proto.Message) (state.BeaconState, error) {
+	switch st := pb.(type) {
+	case *ethpb.BeaconState:
+		return statenative.InitializeFromProtoUnsafePhase0(st)
+	case *ethpb.BeaconStateAltair:
+		return statenative.InitializeFromProtoUnsafeAltair(st)
+	case *ethpb.BeaconStateBellatrix:
+		return statenative.InitializeFromProtoUnsafeBellatrix(st)
+	case *ethpb.BeaconStateCapella:
+		return statenative.InitializeFromProtoUnsafeCapella(st)
+	case *ethpb.BeaconStateDeneb:
+		return statenative.InitializeFromProtoUnsafeDeneb(st)
+	case *ethpb.BeaconStateElectra:
+		return statenative.InitializeFromProtoUnsafeElectra(st)
+	default:
+		return nil, fmt.Errorf("unsupported state type %T", pb)
+	}
+}
+
+// diffField writes the difference of a field to buf and returns whether the field changed.
+func diffField(buf *bytes.Buffer, fd protoreflect.FieldDescriptor, base, target protoreflect.Message) (bool, error) {
+	if fd.IsList() {
+		return diffList(buf, fd, base.Get(fd).List(), target.Get(fd).List())
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		if !target.Has(fd) {
+			if !base.Has(fd) {
+				return false, nil
+			}
+			buf.WriteByte(0)
+			return true, nil
+		}
+		t := target.Get(fd).Message().Interface()
+		if base.Has(fd) && proto.Equal(base.Get(fd).Message().Interface(), t) {
+			return false, nil
+		}
+		enc, err := marshalSSZ(t)
+		if err != nil {
+			return false, err
+		}
+		buf.WriteByte(1)
+		writeBytes(buf, enc)
+		return true, nil
+	case protoreflect.BytesKind:
+		b, t := base.Get(fd).Bytes(), target.Get(fd).Bytes()
+		if bytes.Equal(b, t) {
+			return false, nil
+		}
+		// Recording the xor with the base keeps unchanged bytes at zero, which compresses well.
+		writeUvarint(buf, uint64(len(t)))
+		for i := range t {
+			if i < len(b) {
+				buf.WriteByte(t[i] ^ b[i])
+			} else {
+				buf.WriteByte(t[i])
+			}
+		}
+		return true, nil
+	case protoreflect.Uint64Kind, protoreflect.Uint32Kind:
+		b, t := base.Get(fd).Uint(), target.Get(fd).Uint()
+		if b == t {
+			return false, nil
+		}
+		writeUvarint(buf, t)
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported field kind %s", fd.Kind())
+	}
+}
+
+func diffList(buf *bytes.Buffer, fd protoreflect.FieldDescriptor, base, target protoreflect.List) (bool, error) {
+	switch fd.Kind() {
+	case protoreflect.Uint64Kind:
+		same := base.Len() == target.Len()
+		for i := 0; same && i < target.Len(); i++ {
+			same = base.Get(i).Uint() == target.Get(i).Uint()
+		}
+		if same {
+			return false, nil
+		}
+		// Balances and inactivity scores change by small amounts for most validators, so every element is
+		// recorded as the change from the base.
+		writeUvarint(buf, uint64(target.Len()))
+		for i := 0; i < target.Len(); i++ {
+			var b uint64
+			if i < base.Len() {
+				b = base.Get(i).Uint()
+			}
+			writeVarint(buf, int64(target.Get(i).Uint()-b))
+		}
+		return true, nil
+	case protoreflect.BytesKind, protoreflect.MessageKind:
+		var changed []int
+		for i := 0; i < target.Len(); i++ {
+			if i >= base.Len() {
+				changed = append(changed, i)
+				continue
+			}
+			var equal bool
+			if fd.Kind() == protoreflect.BytesKind {
+				equal = bytes.Equal(base.Get(i).Bytes(), target.Get(i).Bytes())
+			} else {
+				equal = proto.Equal(base.Get(i).Message().Interface(), target.Get(i).Message().Interface())
+			}
+			if !equal {
+				changed = append(changed, i)
+			}
+		}
+		if len(changed) == 0 && base.Len() == target.Len() {
+			return false, nil
+		}
+		writeUvarint(buf, uint64(target.Len()))
+		writeUvarint(buf, uint64(len(changed)))
+		for _, i := range changed {
+			writeUvarint(buf, uint64(i))
+			if fd.Kind() == protoreflect.BytesKind {
+				writeBytes(buf, target.Get(i).Bytes())
+				continue
+			}
+			enc, err := marshalSSZ(target.Get(i).Message().Interface())
+			if err != nil {
+				return false, err
+			}
+			writeBytes(buf, enc)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported list kind %s", fd.Kind())
+	}
+}
+
+func applyField(r *bytes.Reader, fd protoreflect.FieldDescriptor, m protoreflect.Message) error {
+	if fd.IsList() {
+		return applyList(r, fd, m)
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		present, err := r.ReadByte()
+		if err != nil {
+			return errMalformedDiff
+		}
+		if present == 0 {
+			m.Clear(fd)
+			return nil
+		}
+		enc, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		v := m.NewField(fd)
+		if err := unmarshalSSZ(v.Message().Interface(), enc); err != nil {
+			return err
+		}
+		m.Set(fd, v)
+		return nil
+	case protoreflect.BytesKind:
+		n, err := readLength(r)
+		if err != nil {
+			return err
+		}
+		b := m.Get(fd).Bytes()
+		t := make([]byte, n)
+		if _, err := io.ReadFull(r, t); err != nil {
+			return errMalformedDiff
+		}
+		for i := 0; i < len(t) && i < len(b); i++ {
+			t[i] ^= b[i]
+		}
+		m.Set(fd, protoreflect.ValueOfBytes(t))
+		return nil
+	case protoreflect.Uint64Kind:
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return errMalformedDiff
+		}
+		m.Set(fd, protoreflect.ValueOfUint64(v))
+		return nil
+	case protoreflect.Uint32Kind:
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return errMalformedDiff
+		}
+		m.Set(fd, protoreflect.ValueOfUint32(uint32(v)))
+		return nil
+	default:
+		return fmt.Errorf("unsupported field kind %s", fd.Kind())
+	}
+}
+
+func applyList(r *bytes.Reader, fd protoreflect.FieldDescriptor, m protoreflect.Message) error {
+	n, err := readIndex(r)
+	if err != nil {
+		return err
+	}
+	list := m.Mutable(fd).List()
+	if list.Len() > n {
+		list.Truncate(n)
+	}
+	switch fd.Kind() {
+	case protoreflect.Uint64Kind:
+		for i := 0; i < n; i++ {
+			d, err := binary.ReadVarint(r)
+			if err != nil {
+				return errMalformedDiff
+			}
+			if i < list.Len() {
+				list.Set(i, protoreflect.ValueOfUint64(list.Get(i).Uint()+uint64(d)))
+			} else {
+				list.Append(protoreflect.ValueOfUint64(uint64(d)))
+			}
+		}
+		return nil
+	case protoreflect.BytesKind, protoreflect.MessageKind:
+		changed, err := readLength(r)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < changed; j++ {
+			i, err := readIndex(r)
+			if err != nil {
+				return err
+			}
+			enc, err := readBytes(r)
+			if err != nil {
+				return err
+			}
+			var v protoreflect.Value
+			if fd.Kind() == protoreflect.BytesKind {
+				v = protoreflect.ValueOfBytes(enc)
+			} else {
+				v = list.NewElement()
+				if err := unmarshalSSZ(v.Message().Interface(), enc); err != nil {
+					return err
+				}
+			}
+			switch {
+			case i < list.Len():
+				list.Set(i, v)
+			case i == list.Len():
+				list.Append(v)
+			default:
+				return errors.Wrapf(errMalformedDiff, "element %d is out of order", i)
+			}
+		}
+		if list.Len() != n {
+			return errors.Wrapf(errMalformedDiff, "list has %d elements, expected %d", list.Len(), n)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported list kind %s", fd.Kind())
+	}
+}
+
+func marshalSSZ(m proto.Message) ([]byte, error) {
+	s, ok := m.(ssz.Marshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T is not SSZ encodable", m)
+	}
+	return s.MarshalSSZ()
+}
+
+func unmarshalSSZ(m proto.Message, enc []byte) error {
+	s, ok := m.(ssz.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("%T is not SSZ decodable", m)
+	}
+	return s.UnmarshalSSZ(enc)
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func writeVarint(buf *bytes.Buffer, v int64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutVarint(b[:], v)])
+}
+
+func writeBytes(buf *bytes.Buffer, b []byte) {
+	writeUvarint(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+// readLength reads the length of something which is encoded in the rest of the diff with at least one byte per
+// element, so that no valid length exceeds the remaining size of the diff.
+func readLength(r *bytes.Reader) (int, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, errMalformedDiff
+	}
+	if n > uint64(r.Len()) {
+		return 0, errors.Wrapf(errMalformedDiff, "length %d exceeds the diff size", n)
+	}
+	return int(n), nil
+}
+
+// readIndex reads a list index or list length.
+func readIndex(r *bytes.Reader) (int, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > math.MaxInt32 {
+		return 0, errMalformedDiff
+	}
+	return int(n), nil
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, errMalformedDiff
+	}
+	return b, nil
+}
@@ -0,0 +1,117 @@
+package statediff_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/statediff"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func requireSameState(t *testing.T, want, got state.BeaconState) {
+	wantRoot, err := want.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	gotRoot, err := got.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, wantRoot, gotRoot)
+}
+
+// advance mutates a copy of the state the way a few epochs of blocks would.
+func advance(t *testing.T, base state.BeaconState) state.BeaconState {
+	st := base.Copy()
+	require.NoError(t, st.SetSlot(base.Slot()+64))
+	require.NoError(t, st.UpdateBalancesAtIndex(0, 31_000_000_000))
+	require.NoError(t, st.UpdateBalancesAtIndex(3, 32_000_000_001))
+	require.NoError(t, st.UpdateRandaoMixesAtIndex(2, [32]byte{'r'}))
+	require.NoError(t, st.UpdateBlockRootAtIndex(5, [32]byte{'b'}))
+	require.NoError(t, st.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 1, Root: bytesutil.PadTo([]byte{'f'}, 32)}))
+	v, err := st.ValidatorAtIndex(1)
+	require.NoError(t, err)
+	v.ExitEpoch = 10
+	require.NoError(t, st.UpdateValidatorAtIndex(1, v))
+	require.NoError(t, st.AppendValidator(&ethpb.Validator{
+		PublicKey:             bytesutil.PadTo([]byte{'n'}, 48),
+		WithdrawalCredentials: make([]byte, 32),
+		EffectiveBalance:      32_000_000_000,
+	}))
+	require.NoError(t, st.AppendBalance(32_000_000_000))
+	require.NoError(t, st.AppendEth1DataVotes(&ethpb.Eth1Data{DepositRoot: make([]byte, 32), BlockHash: make([]byte, 32)}))
+	if st.Version() >= version.Altair {
+		require.NoError(t, st.AppendInactivityScore(0))
+		require.NoError(t, st.AppendCurrentParticipationBits(7))
+		require.NoError(t, st.AppendPreviousParticipationBits(0))
+		require.NoError(t, st.ModifyCurrentParticipationBits(func(val []byte) ([]byte, error) {
+			val[2] = 3
+			return val, nil
+		}))
+	}
+	return st
+}
+
+func TestDiff(t *testing.T) {
+	for name, genesis := range map[string]func(testing.TB, uint64) (state.BeaconState, []bls.SecretKey){
+		"phase0":    util.DeterministicGenesisState,
+		"altair":    util.DeterministicGenesisStateAltair,
+		"bellatrix": util.DeterministicGenesisStateBellatrix,
+		"capella":   util.DeterministicGenesisStateCapella,
+		"deneb":     util.DeterministicGenesisStateDeneb,
+		"electra":   util.DeterministicGenesisStateElectra,
+	} {
+		t.Run(name, func(t *testing.T) {
+			base, _ := genesis(t, 16)
+			target := advance(t, base)
+
+			diff, err := statediff.Diff(base, target)
+			require.NoError(t, err)
+			got, err := statediff.Apply(base, diff)
+			require.NoError(t, err)
+			requireSameState(t, target, got)
+
+			// The base state is left untouched.
+			wantBase, _ := genesis(t, 16)
+			requireSameState(t, wantBase, base)
+
+			// Going backwards shrinks the lists again.
+			diff, err = statediff.Diff(target, base)
+			require.NoError(t, err)
+			got, err = statediff.Apply(target, diff)
+			require.NoError(t, err)
+			requireSameState(t, base, got)
+		})
+	}
+}
+
+func TestDiff_Unchanged(t *testing.T) {
+	base, _ := util.DeterministicGenesisStateDeneb(t, 16)
+	diff, err := statediff.Diff(base, base)
+	require.NoError(t, err)
+	got, err := statediff.Apply(base, diff)
+	require.NoError(t, err)
+	requireSameState(t, base, got)
+}
+
+func TestDiff_VersionMismatch(t *testing.T) {
+	base, _ := util.DeterministicGenesisStateCapella(t, 16)
+	target, _ := util.DeterministicGenesisStateDeneb(t, 16)
+	_, err := statediff.Diff(base, target)
+	require.ErrorIs(t, err, statediff.ErrVersionMismatch)
+
+	diff, err := statediff.Diff(target, advance(t, target))
+	require.NoError(t, err)
+	_, err = statediff.Apply(base, diff)
+	require.ErrorIs(t, err, statediff.ErrVersionMismatch)
+}
+
+func TestApply_Malformed(t *testing.T) {
+	base, _ := util.DeterministicGenesisStateDeneb(t, 16)
+	diff, err := statediff.Diff(base, advance(t, base))
+	require.NoError(t, err)
+	_, err = statediff.Apply(base, diff[:len(diff)/2])
+	require.NotNil(t, err)
+}
@@ -0,0 +1,116 @@
+// Package statediff stores historical beacon states as a hierarchy of full snapshots and diffs, so that any
+// stored state can be rebuilt from a snapshot and a handful of diffs without replaying blocks.
+package statediff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+)
+
+// DefaultExponents stores a full snapshot every 2^21 slots and diffs down to every epoch (2^5 slots) of mainnet.
+var DefaultExponents = []uint8{21, 18, 16, 13, 11, 9, 5}
+
+var errInvalidExponents = errors.New("invalid state diff exponents")
+
+// Hierarchy describes the slots at which states are stored and the state each of them is stored relative to.
+// Exponents are ordered from the coarsest level to the finest one. The state at a slot which is a multiple of
+// 2^exponents[0] is stored in full, the state at a slot whose coarsest multiple is 2^exponents[i] is stored as a
+// diff from the state at the previous multiple of 2^exponents[i-1]. Rebuilding any stored state therefore takes
+// one snapshot and at most len(exponents)-1 diffs.
+type Hierarchy struct {
+	exponents []uint8
+}
+
+// NewHierarchy returns the hierarchy for the given exponents, which must be strictly decreasing.
+func NewHierarchy(exponents []uint8) (*Hierarchy, error) {
+	if len(exponents) == 0 {
+		return nil, errors.Wrap(errInvalidExponents, "no exponents")
+	}
+	for i, e := range exponents {
+		if e >= 64 {
+			return nil, errors.Wrapf(errInvalidExponents, "exponent %d is too large", e)
+		}
+		if i > 0 && e >= exponents[i-1] {
+			return nil, errors.Wrapf(errInvalidExponents, "exponents %v are not strictly decreasing", exponents)
+		}
+	}
+	return &Hierarchy{exponents: append([]uint8{}, exponents...)}, nil
+}
+
+// ParseExponents parses a comma separated list of exponents, such as "21,18,16,13,11,9,5".
+func ParseExponents(s string) ([]uint8, error) {
+	parts := strings.Split(s, ",")
+	exponents := make([]uint8, 0, len(parts))
+	for _, p := range parts {
+		e, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+		if err != nil {
+			return nil, errors.Wrapf(errInvalidExponents, "could not parse %q", p)
+		}
+		exponents = append(exponents, uint8(e))
+	}
+	return exponents, nil
+}
+
+// Exponents returns a copy of the exponents of the hierarchy.
+func (h *Hierarchy) Exponents() []uint8 {
+	return append([]uint8{}, h.exponents...)
+}
+
+// String returns the exponents in the form accepted by ParseExponents.
+func (h *Hierarchy) String() string {
+	parts := make([]string, len(h.exponents))
+	for i, e := range h.exponents {
+		parts[i] = fmt.Sprint(e)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Interval returns the number of slots between two stored states.
+func (h *Hierarchy) Interval() primitives.Slot {
+	return h.interval(len(h.exponents) - 1)
+}
+
+// Stored returns whether the state at the slot is part of the hierarchy.
+func (h *Hierarchy) Stored(slot primitives.Slot) bool {
+	return slot%h.Interval() == 0
+}
+
+// Floor returns the highest slot at or below the given slot whose state is part of the hierarchy.
+func (h *Hierarchy) Floor(slot primitives.Slot) primitives.Slot {
+	return slot - slot%h.Interval()
+}
+
+// SnapshotFloor returns the slot of the highest full snapshot at or below the given slot. The states stored at or
+// above the slot only depend on states stored at or above the returned slot.
+func (h *Hierarchy) SnapshotFloor(slot primitives.Slot) primitives.Slot {
+	return slot - slot%h.interval(0)
+}
+
+// Level returns the level of the state at the slot, 0 being the level of full snapshots. It returns false if the
+// state is not part of the hierarchy.
+func (h *Hierarchy) Level(slot primitives.Slot) (int, bool) {
+	for i := range h.exponents {
+		if slot%h.interval(i) == 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Base returns the slot of the state the state at the slot is stored relative to. It returns false for snapshots
+// and for slots which are not part of the hierarchy.
+func (h *Hierarchy) Base(slot primitives.Slot) (primitives.Slot, bool) {
+	level, ok := h.Level(slot)
+	if !ok || level == 0 {
+		return 0, false
+	}
+	return slot - slot%h.interval(level-1), true
+}
+
+func (h *Hierarchy) interval(level int) primitives.Slot {
+	return primitives.Slot(1) << h.exponents[level]
+}
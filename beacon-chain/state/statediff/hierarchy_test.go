@@ -0,0 +1,61 @@
+package statediff
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestNewHierarchy(t *testing.T) {
+	_, err := NewHierarchy(nil)
+	require.ErrorIs(t, err, errInvalidExponents)
+	_, err = NewHierarchy([]uint8{5, 9})
+	require.ErrorIs(t, err, errInvalidExponents)
+	_, err = NewHierarchy([]uint8{9, 9})
+	require.ErrorIs(t, err, errInvalidExponents)
+	_, err = NewHierarchy([]uint8{64, 5})
+	require.ErrorIs(t, err, errInvalidExponents)
+
+	exponents, err := ParseExponents("21, 18,16,13,11,9,5")
+	require.NoError(t, err)
+	require.DeepEqual(t, DefaultExponents, exponents)
+	h, err := NewHierarchy(exponents)
+	require.NoError(t, err)
+	require.Equal(t, "21,18,16,13,11,9,5", h.String())
+
+	_, err = ParseExponents("21,x")
+	require.ErrorIs(t, err, errInvalidExponents)
+}
+
+func TestHierarchy(t *testing.T) {
+	h, err := NewHierarchy([]uint8{8, 6, 4})
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(16), h.Interval())
+	require.Equal(t, primitives.Slot(48), h.Floor(63))
+	require.Equal(t, false, h.Stored(63))
+	require.Equal(t, primitives.Slot(256), h.SnapshotFloor(511))
+
+	for _, tt := range []struct {
+		slot   primitives.Slot
+		level  int
+		stored bool
+		base   primitives.Slot
+		diff   bool
+	}{
+		{slot: 0, level: 0, stored: true},
+		{slot: 256, level: 0, stored: true},
+		{slot: 320, level: 1, stored: true, base: 256, diff: true},
+		{slot: 336, level: 2, stored: true, base: 320, diff: true},
+		{slot: 272, level: 2, stored: true, base: 256, diff: true},
+		{slot: 64, level: 1, stored: true, base: 0, diff: true},
+		{slot: 17, stored: false},
+	} {
+		level, stored := h.Level(tt.slot)
+		require.Equal(t, tt.stored, stored, "slot %d", tt.slot)
+		require.Equal(t, tt.level, level, "slot %d", tt.slot)
+		base, diff := h.Base(tt.slot)
+		require.Equal(t, tt.diff, diff, "slot %d", tt.slot)
+		require.Equal(t, tt.base, base, "slot %d", tt.slot)
+	}
+}
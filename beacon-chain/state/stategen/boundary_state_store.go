@@ -0,0 +1,421 @@
+package stategen
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BoundaryStatesDirName is the name of the directory of the boundary state store in the data directory.
+	BoundaryStatesDirName = "boundary-states"
+	boundaryStateExt      = ".ssz_snappy"
+	boundaryStatePartExt  = ".part"
+)
+
+// BoundaryStateStore is a persistent, size bounded cache of the finalized states at the start of epochs. It lets
+// historical states be rebuilt by replaying the blocks of less than an epoch, instead of the blocks since the last
+// stored state. States are saved as they are regenerated by historical queries, and only the ones within the
+// retention period before the finalized slot are kept. When the store is full, the states which were used the least
+// since the node started are evicted first.
+//
+// Each state is stored along with the root of the block it is the post state of, after the skipped slots, so that a
+// state is only used as the ancestor of blocks which descend from that block.
+type BoundaryStateStore struct {
+	dir       string
+	maxBytes  uint64
+	retention primitives.Slot
+	lock      sync.Mutex
+	entries   map[primitives.Slot]*boundaryEntry
+	size      uint64
+	finalized primitives.Slot
+	clock     uint64
+}
+
+type boundaryEntry struct {
+	slot     primitives.Slot
+	root     [32]byte
+	size     uint64
+	hits     uint64
+	lastUsed uint64
+}
+
+// NewBoundaryStateStore opens the store in the directory, creating it if needed, and loads the states it already
+// holds. The store holds at most maxBytes of compressed states, and the states of the retention epochs before the
+// finalized slot.
+func NewBoundaryStateStore(dir string, maxBytes uint64, retention primitives.Epoch) (*BoundaryStateStore, error) {
+	retentionSlots, err := slots.EpochStart(retention)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	b := &BoundaryStateStore{
+		dir:       dir,
+		maxBytes:  maxBytes,
+		retention: retentionSlots,
+		entries:   make(map[primitives.Slot]*boundaryEntry),
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasSuffix(name, boundaryStatePartExt) {
+			if err := os.Remove(path.Join(dir, name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		slot, root, ok := parseBoundaryStateName(name)
+		if !ok {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			return nil, err
+		}
+		b.entries[slot] = &boundaryEntry{slot: slot, root: root, size: uint64(info.Size())}
+		b.size += uint64(info.Size())
+	}
+	if err := b.evict(); err != nil {
+		return nil, err
+	}
+	boundaryStateCacheBytes.Set(float64(b.size))
+	log.WithFields(logrus.Fields{
+		"path":   dir,
+		"states": len(b.entries),
+		"bytes":  b.size,
+	}).Info("Opened boundary state store")
+	return b, nil
+}
+
+// SetFinalized updates the finalized slot of the chain. States above it are not saved, and the ones older than the
+// retention period before it are deleted.
+func (b *BoundaryStateStore) SetFinalized(slot primitives.Slot) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if slot <= b.finalized {
+		return nil
+	}
+	b.finalized = slot
+	if slot <= b.retention {
+		return nil
+	}
+	for s, e := range b.entries {
+		if s < slot-b.retention {
+			if err := b.remove(e); err != nil {
+				return err
+			}
+		}
+	}
+	boundaryStateCacheBytes.Set(float64(b.size))
+	return nil
+}
+
+// wants returns whether the state at the slot should be saved.
+func (b *BoundaryStateStore) wants(slot primitives.Slot) bool {
+	if b == nil {
+		return false
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.entries[slot]; ok {
+		return false
+	}
+	return slot <= b.finalized && slot+b.retention >= b.finalized && slots.IsEpochStart(slot)
+}
+
+// isFinalized returns whether the slot is finalized, meaning that a lookup of the store for it counts towards its
+// hit rate.
+func (b *BoundaryStateStore) isFinalized(slot primitives.Slot) bool {
+	if b == nil {
+		return false
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return slot <= b.finalized
+}
+
+// highest returns the slot and block root of the stored state with the highest slot at or below the given one.
+func (b *BoundaryStateStore) highest(slot primitives.Slot) (primitives.Slot, [32]byte, bool) {
+	if b == nil {
+		return 0, [32]byte{}, false
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var best *boundaryEntry
+	for s, e := range b.entries {
+		if s <= slot && (best == nil || s > best.slot) {
+			best = e
+		}
+	}
+	if best == nil {
+		return 0, [32]byte{}, false
+	}
+	return best.slot, best.root, true
+}
+
+// state reads the stored state at the slot, and counts it as used. The file is read and decoded without holding the
+// lock, so a state evicted meanwhile is reported as missing.
+func (b *BoundaryStateStore) state(slot primitives.Slot) (state.BeaconState, error) {
+	st, _, err := b.read(slot)
+	return st, err
+}
+
+// read reads the stored state at the slot, and returns the entry it was read from.
+func (b *BoundaryStateStore) read(slot primitives.Slot) (state.BeaconState, *boundaryEntry, error) {
+	b.lock.Lock()
+	e, ok := b.entries[slot]
+	var p string
+	if ok {
+		p = b.path(e)
+	}
+	b.lock.Unlock()
+	if !ok {
+		return nil, nil, ErrNotInCache
+	}
+	enc, err := os.ReadFile(p) // #nosec G304
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotInCache
+	}
+	if err != nil {
+		return nil, e, err
+	}
+	enc, err = snappy.Decode(nil, enc)
+	if err != nil {
+		return nil, e, errors.Wrapf(err, "could not decompress boundary state at slot %d", slot)
+	}
+	u, err := detect.FromState(enc)
+	if err != nil {
+		return nil, e, err
+	}
+	st, err := u.UnmarshalBeaconState(enc)
+	if err != nil {
+		return nil, e, errors.Wrapf(err, "could not unmarshal boundary state at slot %d", slot)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.entries[slot] == e {
+		b.clock++
+		e.hits++
+		e.lastUsed = b.clock
+	}
+	return st, e, nil
+}
+
+// replayBoundary returns the start slot of the epoch of the target when a replay from the start slot passes it, and
+// whether the store wants the state at it.
+func (b *BoundaryStateStore) replayBoundary(start, target primitives.Slot) (primitives.Slot, bool) {
+	boundary, err := slots.EpochStart(slots.ToEpoch(target))
+	if err != nil || start >= boundary {
+		return 0, false
+	}
+	return boundary, b.wants(boundary)
+}
+
+// saveAt advances the state through the skipped slots up to the boundary slot, and saves it. Replays go on when the
+// state can not be saved.
+func (b *BoundaryStateStore) saveAt(ctx context.Context, st state.BeaconState, slot primitives.Slot) (state.BeaconState, error) {
+	if st.Slot() < slot {
+		var err error
+		st, err = ReplayProcessSlots(ctx, st, slot)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := b.save(ctx, st); err != nil {
+		log.WithError(err).WithField("slot", slot).Error("Could not save boundary state")
+	}
+	return st, nil
+}
+
+// lookup counts the regeneration of a state towards the hit rate of the store, when the state is finalized.
+func (b *BoundaryStateStore) lookup(slot primitives.Slot) {
+	if b.isFinalized(slot) {
+		boundaryStateCacheLookups.Inc()
+	}
+}
+
+// load reads the stored state at the slot. An unreadable state is deleted and reported as missing, so that the
+// state is regenerated without the store.
+func (b *BoundaryStateStore) load(slot primitives.Slot) (state.BeaconState, bool) {
+	st, e, err := b.read(slot)
+	if errors.Is(err, ErrNotInCache) {
+		return nil, false
+	}
+	if err != nil {
+		log.WithError(err).WithField("slot", slot).Warn("Could not read boundary state")
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		// The state may have been replaced while it was read.
+		if b.entries[slot] == e {
+			if err := b.remove(e); err != nil {
+				log.WithError(err).WithField("slot", slot).Error("Could not delete boundary state")
+			}
+		}
+		return nil, false
+	}
+	boundaryStateCacheHits.Inc()
+	return st, true
+}
+
+// save stores the state, which must be the canonical state at the start of an epoch.
+func (b *BoundaryStateStore) save(ctx context.Context, st state.BeaconState) error {
+	if !b.wants(st.Slot()) {
+		return nil
+	}
+	root, err := latestBlockRoot(ctx, st)
+	if err != nil {
+		return err
+	}
+	enc, err := st.MarshalSSZ()
+	if err != nil {
+		return err
+	}
+	enc = snappy.Encode(nil, enc)
+	e := &boundaryEntry{slot: st.Slot(), root: root, size: uint64(len(enc))}
+
+	// The state is written to a part file of its own without holding the lock, and only moved in place if no other
+	// save stored the slot meanwhile.
+	partPath, err := writeBoundaryStatePart(b.dir, path.Base(b.path(e)), enc)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.entries[e.slot]; ok {
+		return os.Remove(partPath)
+	}
+	if err := os.Rename(partPath, b.path(e)); err != nil {
+		removePart(partPath)
+		return err
+	}
+	b.clock++
+	e.lastUsed = b.clock
+	b.entries[e.slot] = e
+	b.size += e.size
+	if err := b.evict(); err != nil {
+		return err
+	}
+	boundaryStateCacheBytes.Set(float64(b.size))
+	log.WithFields(logrus.Fields{
+		"slot":  e.slot,
+		"bytes": e.size,
+	}).Debug("Saved boundary state")
+	return nil
+}
+
+// writeBoundaryStatePart writes the encoded state to a new part file of the directory, named after the file of the
+// state, and returns its path.
+func writeBoundaryStatePart(dir, name string, enc []byte) (string, error) {
+	part, err := os.CreateTemp(dir, name+"-*"+boundaryStatePartExt)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(enc); err != nil {
+		if closeErr := part.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close boundary state part file")
+		}
+		removePart(part.Name())
+		return "", err
+	}
+	if err := part.Close(); err != nil {
+		removePart(part.Name())
+		return "", err
+	}
+	return part.Name(), nil
+}
+
+func removePart(partPath string) {
+	if err := os.Remove(partPath); err != nil {
+		log.WithError(err).WithField("path", partPath).Error("Could not remove boundary state part file")
+	}
+}
+
+// evict deletes the least used states until the store fits its size, the least recently used first among the ones
+// used as often. A state which was just saved is thus evicted first only when all the others were used.
+func (b *BoundaryStateStore) evict() error {
+	for b.size > b.maxBytes {
+		var victim *boundaryEntry
+		for _, e := range b.entries {
+			if victim == nil || e.hits < victim.hits || (e.hits == victim.hits && e.lastUsed < victim.lastUsed) {
+				victim = e
+			}
+		}
+		if victim == nil {
+			return nil
+		}
+		if err := b.remove(victim); err != nil {
+			return err
+		}
+		boundaryStateCacheEvictions.Inc()
+	}
+	return nil
+}
+
+func (b *BoundaryStateStore) remove(e *boundaryEntry) error {
+	if err := os.Remove(b.path(e)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(b.entries, e.slot)
+	b.size -= e.size
+	return nil
+}
+
+func (b *BoundaryStateStore) path(e *boundaryEntry) string {
+	return path.Join(b.dir, fmt.Sprintf("%d_%#x%s", e.slot, e.root, boundaryStateExt))
+}
+
+func parseBoundaryStateName(name string) (primitives.Slot, [32]byte, bool) {
+	slotStr, rootStr, ok := strings.Cut(strings.TrimSuffix(name, boundaryStateExt), "_")
+	if !ok || !strings.HasSuffix(name, boundaryStateExt) {
+		return 0, [32]byte{}, false
+	}
+	slot, err := strconv.ParseUint(slotStr, 10, 64)
+	if err != nil {
+		return 0, [32]byte{}, false
+	}
+	root, err := hex.DecodeString(strings.TrimPrefix(rootStr, "0x"))
+	if err != nil || len(root) != 32 {
+		return 0, [32]byte{}, false
+	}
+	return primitives.Slot(slot), bytesutil.ToBytes32(root), true
+}
+
+// latestBlockRoot returns the root of the latest block applied to the state. The state root of the block header is
+// only filled in by the processing of the next slot, so it is computed when the state is at the slot of the block.
+func latestBlockRoot(ctx context.Context, st state.BeaconState) ([32]byte, error) {
+	header := st.LatestBlockHeader()
+	if header == nil {
+		return [32]byte{}, errNilState
+	}
+	if bytesutil.ToBytes32(header.StateRoot) == params.BeaconConfig().ZeroHash {
+		stateRoot, err := st.HashTreeRoot(ctx)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		header.StateRoot = stateRoot[:]
+	}
+	return header.HashTreeRoot()
+}
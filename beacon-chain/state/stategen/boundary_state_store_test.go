@@ -0,0 +1,218 @@
+package stategen
+
+import (
+	"context"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	doublylinkedtree "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/doubly-linked-tree"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func boundaryTestState(t *testing.T, slot primitives.Slot) state.BeaconState {
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	require.NoError(t, st.SetSlot(slot))
+	return st
+}
+
+func TestBoundaryStateStore_SaveLoad(t *testing.T) {
+	ctx := context.Background()
+	dir := path.Join(t.TempDir(), BoundaryStatesDirName)
+	b, err := NewBoundaryStateStore(dir, 1<<30, 3)
+	require.NoError(t, err)
+	require.NoError(t, b.SetFinalized(100))
+
+	// Only finalized states at the start of the epochs within the retention period are saved.
+	for _, slot := range []primitives.Slot{32, 33, 64, 128} {
+		require.NoError(t, b.save(ctx, boundaryTestState(t, slot)))
+	}
+	require.Equal(t, 2, len(b.entries))
+	require.Equal(t, false, b.wants(64))
+	require.Equal(t, true, b.wants(96))
+	require.Equal(t, false, b.wants(0))
+
+	want := boundaryTestState(t, 64)
+	wantRoot, err := latestBlockRoot(ctx, want)
+	require.NoError(t, err)
+	slot, root, ok := b.highest(90)
+	require.Equal(t, true, ok)
+	require.Equal(t, primitives.Slot(64), slot)
+	require.Equal(t, wantRoot, root)
+	_, _, ok = b.highest(31)
+	require.Equal(t, false, ok)
+	st, ok := b.load(64)
+	require.Equal(t, true, ok)
+	requireStateRoot(t, want, st)
+
+	// A reopened store holds the saved states, leftovers of interrupted writes are deleted.
+	require.NoError(t, os.WriteFile(path.Join(dir, "96_0x00.ssz_snappy.part"), []byte{1}, 0600))
+	b, err = NewBoundaryStateStore(dir, 1<<30, 3)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(b.entries))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+	st, err = b.state(64)
+	require.NoError(t, err)
+	requireStateRoot(t, want, st)
+
+	// States older than the retention period before the finalized slot are deleted.
+	require.NoError(t, b.SetFinalized(140))
+	_, ok = b.entries[32]
+	require.Equal(t, false, ok)
+	_, ok = b.entries[64]
+	require.Equal(t, true, ok)
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+}
+
+func TestBoundaryStateStore_Evict(t *testing.T) {
+	ctx := context.Background()
+	b, err := NewBoundaryStateStore(t.TempDir(), 1<<30, 10)
+	require.NoError(t, err)
+	require.NoError(t, b.SetFinalized(320))
+	for _, slot := range []primitives.Slot{32, 64, 96} {
+		require.NoError(t, b.save(ctx, boundaryTestState(t, slot)))
+	}
+	_, err = b.state(32)
+	require.NoError(t, err)
+	_, err = b.state(32)
+	require.NoError(t, err)
+	_, err = b.state(96)
+	require.NoError(t, err)
+
+	// The store only fits three states, the least used one is evicted when a fourth is saved.
+	b.maxBytes = b.size
+	require.NoError(t, b.save(ctx, boundaryTestState(t, 128)))
+	require.Equal(t, 3, len(b.entries))
+	_, ok := b.entries[64]
+	require.Equal(t, false, ok)
+
+	// The new state is the least used one now.
+	require.NoError(t, b.save(ctx, boundaryTestState(t, 160)))
+	require.Equal(t, 3, len(b.entries))
+	_, ok = b.entries[128]
+	require.Equal(t, false, ok)
+	for _, slot := range []primitives.Slot{32, 96, 160} {
+		_, ok = b.entries[slot]
+		require.Equal(t, true, ok, "slot %d", slot)
+	}
+}
+
+func TestBoundaryStateStore_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	b, err := NewBoundaryStateStore(dir, 1<<30, 10)
+	require.NoError(t, err)
+	require.NoError(t, b.SetFinalized(320))
+	require.NoError(t, b.save(ctx, boundaryTestState(t, 32)))
+	// The store fits two states, so saves evict the states read meanwhile.
+	b.maxBytes = 2 * b.size
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for slot := primitives.Slot(32); slot <= 320; slot += 32 {
+				assert.NoError(t, b.save(ctx, boundaryTestState(t, slot)))
+				if st, ok := b.load(slot); ok {
+					assert.Equal(t, slot, st.Slot())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, len(b.entries), len(files))
+	var size uint64
+	for _, f := range files {
+		info, err := f.Info()
+		require.NoError(t, err)
+		size += uint64(info.Size())
+	}
+	require.Equal(t, b.size, size)
+}
+
+func TestStateByRoot_BoundaryStates(t *testing.T) {
+	ctx := context.Background()
+	c := newStateDiffChain(t, nil, 40, 32)
+	b, err := NewBoundaryStateStore(t.TempDir(), 1<<30, 10)
+	require.NoError(t, err)
+	service := New(c.db, doublylinkedtree.New(), WithBoundaryStateStore(b))
+	service.SaveFinalizedState(40, c.roots[40], c.states[40])
+
+	// Slot 32 is skipped, the replay to the block at slot 35 saves the state of slot 31 advanced to slot 32.
+	st, err := service.StateByRoot(ctx, c.roots[35])
+	require.NoError(t, err)
+	requireStateRoot(t, c.states[35], st)
+	slot, root, ok := b.highest(40)
+	require.Equal(t, true, ok)
+	require.Equal(t, primitives.Slot(32), slot)
+	require.Equal(t, c.roots[31], root)
+
+	service = New(c.db, doublylinkedtree.New(), WithBoundaryStateStore(b))
+	ancestor, err := service.latestAncestor(ctx, c.roots[36])
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(32), ancestor.Slot())
+	st, err = service.StateByRoot(ctx, c.roots[36])
+	require.NoError(t, err)
+	requireStateRoot(t, c.states[36], st)
+	// The stored state is not an ancestor of the block at slot 31.
+	ancestor, err = service.latestAncestor(ctx, c.roots[31])
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(0), ancestor.Slot())
+}
+
+func TestChainForSlot_BoundaryStates(t *testing.T) {
+	ctx := context.Background()
+	specs := []mockHistorySpec{
+		{slot: 50, canonicalBlock: true},
+		{slot: 51, canonicalBlock: true},
+		{slot: 60, canonicalBlock: true},
+		{slot: 70, canonicalBlock: true},
+	}
+	mh := newMockHistory(t, specs, 80)
+	b, err := NewBoundaryStateStore(t.TempDir(), 1<<30, 10)
+	require.NoError(t, err)
+	require.NoError(t, b.SetFinalized(80))
+	ch := NewCanonicalHistory(mh, mh, mh, WithBoundaryStates(b))
+
+	st, err := ch.ReplayerForSlot(70).ReplayBlocks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, primitives.Slot(70), st.Slot())
+	boundary, err := ReplayProcessSlots(ctx, mh.hiddenStates[mh.slotMap[60]].Copy(), 64)
+	require.NoError(t, err)
+	_, root, ok := b.highest(64)
+	require.Equal(t, true, ok)
+	require.Equal(t, mh.slotMap[60], root)
+
+	cases := []struct {
+		slot       primitives.Slot
+		state      state.BeaconState
+		blockSlots []primitives.Slot
+	}{
+		{slot: 75, state: boundary, blockSlots: []primitives.Slot{70}},
+		{slot: 64, state: boundary, blockSlots: []primitives.Slot{}},
+		{slot: 62, state: mh.states[mh.slotMap[0]], blockSlots: []primitives.Slot{50, 51, 60}},
+	}
+	for _, c := range cases {
+		st, blks, err := ch.chainForSlot(ctx, c.slot)
+		require.NoError(t, err)
+		requireStateRoot(t, c.state, st)
+		require.Equal(t, len(c.blockSlots), len(blks), "slot %d", c.slot)
+		for i, b := range blks {
+			require.Equal(t, c.blockSlots[i], b.Block().Slot())
+		}
+	}
+}
@@ -268,6 +268,15 @@ func (s *State) latestAncestor(ctx context.Context, blockRoot [32]byte) (state.B
 		return nil, err
 	}
 
+	// The closest stored epoch boundary state below the block, used when the walk reaches the block it was built from.
+	var boundarySlot primitives.Slot
+	var boundaryRoot [32]byte
+	hasBoundary := false
+	if b.Block().Slot() > 0 {
+		s.boundaryStates.lookup(b.Block().Slot())
+		boundarySlot, boundaryRoot, hasBoundary = s.boundaryStates.highest(b.Block().Slot() - 1)
+	}
+
 	for {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -304,12 +313,32 @@ func (s *State) latestAncestor(ctx context.Context, blockRoot [32]byte) (state.B
 			return cachedInfo.state, nil
 		}
 
+		// Does the state exist in the boundary state store, past the parent block.
+		if hasBoundary && boundarySlot >= b.Block().Slot() {
+			hasBoundary = false
+		}
+		if hasBoundary && parentRoot == boundaryRoot {
+			if st, ok := s.boundaryStates.load(boundarySlot); ok {
+				return st, nil
+			}
+			hasBoundary = false
+		}
+
 		// Does the state exists in DB.
 		if s.beaconDB.HasState(ctx, parentRoot) {
 			s, err := s.beaconDB.State(ctx, parentRoot)
 			return s, errors.Wrap(err, "failed to retrieve state from db")
 		}
 
+		// Does a state diff exist between the finalized parent and the block.
+		diffState, err := s.stateDiffAncestor(ctx, parentRoot, b.Block().Slot())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve state diff from db")
+		}
+		if diffState != nil {
+			return diffState, nil
+		}
+
 		b, err = s.beaconDB.Block(ctx, parentRoot)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to retrieve block from db")
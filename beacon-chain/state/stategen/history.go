@@ -20,6 +20,13 @@ func WithCache(c CachedGetter) CanonicalHistoryOption {
 	}
 }
 
+// WithBoundaryStates gives the history a store of finalized epoch boundary states to start replays from.
+func WithBoundaryStates(b *BoundaryStateStore) CanonicalHistoryOption {
+	return func(h *CanonicalHistory) {
+		h.boundaries = b
+	}
+}
+
 type CanonicalHistoryOption func(*CanonicalHistory)
 
 func NewCanonicalHistory(h HistoryAccessor, cc CanonicalChecker, cs CurrentSlotter, opts ...CanonicalHistoryOption) *CanonicalHistory {
@@ -35,14 +42,15 @@ func NewCanonicalHistory(h HistoryAccessor, cc CanonicalChecker, cs CurrentSlott
 }
 
 type CanonicalHistory struct {
-	h     HistoryAccessor
-	cc    CanonicalChecker
-	cs    CurrentSlotter
-	cache CachedGetter
+	h          HistoryAccessor
+	cc         CanonicalChecker
+	cs         CurrentSlotter
+	cache      CachedGetter
+	boundaries *BoundaryStateStore
 }
 
 func (c *CanonicalHistory) ReplayerForSlot(target primitives.Slot) Replayer {
-	return &stateReplayer{chainer: c, method: forSlot, target: target}
+	return &stateReplayer{chainer: c, method: forSlot, target: target, boundaries: c.boundaries}
 }
 
 func (c *CanonicalHistory) BlockRootForSlot(ctx context.Context, target primitives.Slot) ([32]byte, error) {
@@ -114,6 +122,26 @@ func (c *CanonicalHistory) chainForSlot(ctx context.Context, target primitives.S
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "unable to retrieve canonical block for slot, root=%#x", r)
 	}
+	// A state diff stored after the canonical block already includes it, only the remaining slots are processed.
+	diffs, diffSlot, hasDiff, err := c.highestStateDiff(ctx, target)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find the highest state diff")
+	}
+	// So does a boundary state built from the canonical block, which is used when it is closer than the state diff.
+	c.boundaries.lookup(target)
+	boundarySlot, boundaryRoot, hasBoundary := c.boundaries.highest(target)
+	if hasBoundary && boundaryRoot == r && (!hasDiff || boundarySlot > diffSlot) {
+		if s, ok := c.boundaries.load(boundarySlot); ok {
+			return s, []interfaces.ReadOnlySignedBeaconBlock{}, nil
+		}
+	}
+	if hasDiff && diffSlot >= b.Block().Slot() {
+		s, err := diffs.StateDiff(ctx, diffSlot)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not rebuild state diff at slot %d", diffSlot)
+		}
+		return s, []interfaces.ReadOnlySignedBeaconBlock{}, nil
+	}
 	s, descendants, err := c.ancestorChain(ctx, b)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to query for ancestor and descendant blocks")
@@ -144,6 +172,11 @@ func (c *CanonicalHistory) ancestorChain(ctx context.Context, tail interfaces.Re
 	ctx, span := trace.StartSpan(ctx, "canonicalChainer.ancestorChain")
 	defer span.End()
 	chain := make([]interfaces.ReadOnlySignedBeaconBlock, 0)
+	diffs, diffSlot, hasDiff, err := c.highestStateDiff(ctx, tail.Block().Slot())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find the highest state diff")
+	}
+	boundarySlot, boundaryRoot, hasBoundary := c.boundaries.highest(tail.Block().Slot())
 	for {
 		if err := ctx.Err(); err != nil {
 			msg := fmt.Sprintf("context canceled while finding ancestors of block at slot %d", tail.Block().Slot())
@@ -156,6 +189,26 @@ func (c *CanonicalHistory) ancestorChain(ctx context.Context, tail interfaces.Re
 			msg := fmt.Sprintf("could not compute htr for descendant block at slot=%d", b.Slot())
 			return nil, nil, errors.Wrap(err, msg)
 		}
+		// A boundary state is only used when it was built from this block, since the store may hold the state of
+		// another chain at the same slot.
+		if hasBoundary && b.Slot() <= boundarySlot {
+			if root == boundaryRoot {
+				if st, ok := c.boundaries.load(boundarySlot); ok {
+					reverseChain(chain)
+					return st, chain, nil
+				}
+			}
+			hasBoundary = false
+		}
+		// The state diff already includes the block and every ancestor of it, since both are canonical.
+		if hasDiff && b.Slot() <= diffSlot {
+			st, err := diffs.StateDiff(ctx, diffSlot)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "could not rebuild state diff at slot %d", diffSlot)
+			}
+			reverseChain(chain)
+			return st, chain, nil
+		}
 		st, err := c.getState(ctx, root)
 		// err == nil, we've got a real state - the job is done!
 		// Note: in cases where there are skipped slots we could find a state that is a descendant
@@ -185,6 +238,17 @@ func (c *CanonicalHistory) ancestorChain(ctx context.Context, tail interfaces.Re
 	}
 }
 
+// highestStateDiff returns the highest slot at or below the given slot at which the history stores a state diff,
+// if the history stores states as diffs at all.
+func (c *CanonicalHistory) highestStateDiff(ctx context.Context, slot primitives.Slot) (stateDiffAccessor, primitives.Slot, bool, error) {
+	diffs, ok := c.h.(stateDiffAccessor)
+	if !ok {
+		return nil, 0, false, nil
+	}
+	diffSlot, ok, err := diffs.HighestStateDiffSlot(ctx, slot)
+	return diffs, diffSlot, ok, err
+}
+
 func reverseChain(c []interfaces.ReadOnlySignedBeaconBlock) {
 	last := len(c) - 1
 	swaps := (last + 1) / 2
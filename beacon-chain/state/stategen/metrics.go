@@ -25,4 +25,28 @@ var (
 			Help: "Time it took to replay to slot",
 		},
 	)
+	boundaryStateCacheHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "boundary_state_cache_hits_total",
+			Help: "The number of finalized states which were regenerated from the boundary state store",
+		},
+	)
+	boundaryStateCacheLookups = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "boundary_state_cache_lookups_total",
+			Help: "The number of finalized states which were regenerated while the boundary state store was enabled",
+		},
+	)
+	boundaryStateCacheEvictions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "boundary_state_cache_evictions_total",
+			Help: "The number of states evicted from the boundary state store to fit its size",
+		},
+	)
+	boundaryStateCacheBytes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "boundary_state_cache_bytes",
+			Help: "The size of the compressed states held by the boundary state store",
+		},
+	)
 )
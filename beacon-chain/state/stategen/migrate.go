@@ -38,12 +38,23 @@ func (s *State) MigrateToCold(ctx context.Context, fRoot [32]byte) error {
 	}
 
 	// Start at previous finalized slot, stop at current finalized slot (it will be handled in the next migration).
-	// If the slot is on archived point, save the state of that slot to the DB.
+	// If the slot is on archived point, save the state of that slot to the DB. When the DB stores finalized
+	// states as state diffs, the states at the slots of the hierarchy are saved instead.
+	h := s.beaconDB.StateDiffHierarchy()
 	for slot := oldFSlot; slot < fSlot; slot++ {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
+		if h != nil {
+			if h.Stored(slot) {
+				if err := s.saveStateDiff(ctx, slot); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
 		if slot%s.slotsPerArchivedPoint == 0 && slot != 0 {
 			cached, exists, err := s.epochBoundaryStateCache.getBySlot(slot)
 			if err != nil {
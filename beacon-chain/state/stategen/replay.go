@@ -19,7 +19,7 @@ import (
 // ReplayBlocks replays the input blocks on the input state until the target slot is reached.
 //
 // WARNING Blocks passed to the function must be in decreasing slots order.
-func (*State) replayBlocks(
+func (s *State) replayBlocks(
 	ctx context.Context,
 	state state.BeaconState,
 	signed []interfaces.ReadOnlySignedBeaconBlock,
@@ -36,6 +36,7 @@ func (*State) replayBlocks(
 		"diff":      targetSlot - state.Slot(),
 	})
 	rLog.Debug("Replaying state")
+	boundary, saveBoundary := s.boundaryStates.replayBoundary(state.Slot(), targetSlot)
 	// The input block list is sorted in decreasing slots order.
 	if len(signed) > 0 {
 		for i := len(signed) - 1; i >= 0; i-- {
@@ -49,6 +50,13 @@ func (*State) replayBlocks(
 			if state.Slot() >= signed[i].Block().Slot() {
 				continue
 			}
+			if saveBoundary && signed[i].Block().Slot() > boundary {
+				state, err = s.boundaryStates.saveAt(ctx, state, boundary)
+				if err != nil {
+					return nil, err
+				}
+				saveBoundary = false
+			}
 			state, err = executeStateTransitionStateGen(ctx, state, signed[i])
 			if err != nil {
 				return nil, err
@@ -56,6 +64,13 @@ func (*State) replayBlocks(
 		}
 	}
 
+	if saveBoundary {
+		state, err = s.boundaryStates.saveAt(ctx, state, boundary)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// If there are skip slots at the end.
 	if targetSlot > state.Slot() {
 		state, err = ReplayProcessSlots(ctx, state, targetSlot)
@@ -63,9 +63,10 @@ type chainer interface {
 }
 
 type stateReplayer struct {
-	target  primitives.Slot
-	method  retrievalMethod
-	chainer chainer
+	target     primitives.Slot
+	method     retrievalMethod
+	chainer    chainer
+	boundaries *BoundaryStateStore
 }
 
 // ReplayBlocks applies all the blocks that were accumulated when building the Replayer.
@@ -103,15 +104,30 @@ func (rs *stateReplayer) ReplayBlocks(ctx context.Context) (state.BeaconState, e
 		"diff":      diff,
 	}).Debug("Replaying canonical blocks from most recent state")
 
+	// The state at the start of the epoch of the target is saved on the way, when the boundary state store wants it.
+	boundary, saveBoundary := rs.boundaries.replayBoundary(s.Slot(), rs.target)
 	for _, b := range descendants {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
+		if saveBoundary && b.Block().Slot() > boundary {
+			s, err = rs.boundaries.saveAt(ctx, s, boundary)
+			if err != nil {
+				return nil, err
+			}
+			saveBoundary = false
+		}
 		s, err = executeStateTransitionStateGen(ctx, s, b)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if saveBoundary {
+		s, err = rs.boundaries.saveAt(ctx, s, boundary)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if rs.target > s.Slot() {
 		s, err = ReplayProcessSlots(ctx, s, rs.target)
 		if err != nil {
@@ -56,6 +56,7 @@ type State struct {
 	avb                     coverage.AvailableBlocker
 	migrationLock           *sync.Mutex
 	fc                      forkchoice.ForkChoicer
+	boundaryStates          *BoundaryStateStore
 }
 
 // This tracks the config in the event of long non-finality,
@@ -88,6 +89,14 @@ func WithAvailableBlocker(avb coverage.AvailableBlocker) Option {
 	}
 }
 
+// WithBoundaryStateStore gives stategen a store of finalized epoch boundary states, which historical states are
+// regenerated from when it holds a closer ancestor than the DB.
+func WithBoundaryStateStore(b *BoundaryStateStore) Option {
+	return func(sg *State) {
+		sg.boundaryStates = b
+	}
+}
+
 // New returns a new state management object.
 func New(beaconDB db.NoHeadAccessDatabase, fc forkchoice.ForkChoicer, opts ...Option) *State {
 	s := &State{
@@ -146,6 +155,7 @@ func (s *State) Resume(ctx context.Context, fState state.BeaconState) (state.Bea
 	}()
 
 	s.finalizedInfo = &finalizedInfo{slot: fState.Slot(), root: fRoot, state: fState.Copy()}
+	s.setBoundaryStatesFinalized(fState.Slot())
 	fEpoch := slots.ToEpoch(fState.Slot())
 
 	// Pre-populate the pubkey cache with the validator public keys from the finalized state.
@@ -182,6 +192,21 @@ func (s *State) SaveFinalizedState(fSlot primitives.Slot, fRoot [32]byte, fState
 	s.finalizedInfo.root = fRoot
 	s.finalizedInfo.state = fState.Copy()
 	s.finalizedInfo.slot = fSlot
+	s.setBoundaryStatesFinalized(fSlot)
+}
+
+// BoundaryStates returns the store of finalized epoch boundary states, which is nil when it is disabled.
+func (s *State) BoundaryStates() *BoundaryStateStore {
+	return s.boundaryStates
+}
+
+func (s *State) setBoundaryStatesFinalized(fSlot primitives.Slot) {
+	if s.boundaryStates == nil {
+		return
+	}
+	if err := s.boundaryStates.SetFinalized(fSlot); err != nil {
+		log.WithError(err).Error("Could not prune boundary state store")
+	}
 }
 
 // Returns true if input root equals to cached finalized root.
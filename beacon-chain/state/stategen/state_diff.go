@@ -0,0 +1,104 @@
+package stategen
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+)
+
+// stateDiffAccessor is implemented by databases which can store finalized states as state diffs.
+type stateDiffAccessor interface {
+	HighestStateDiffSlot(ctx context.Context, slot primitives.Slot) (primitives.Slot, bool, error)
+	StateDiff(ctx context.Context, slot primitives.Slot) (state.BeaconState, error)
+}
+
+// saveStateDiff stores the canonical state at a slot of the state diff hierarchy. The slot is expected to be
+// finalized.
+func (s *State) saveStateDiff(ctx context.Context, slot primitives.Slot) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.saveStateDiff")
+	defer span.End()
+
+	if s.beaconDB.HasStateDiff(ctx, slot) {
+		return nil
+	}
+	_, roots, err := s.beaconDB.HighestRootsBelowSlot(ctx, slot+1)
+	if err != nil {
+		return err
+	}
+	root, err := s.finalizedRoot(ctx, roots)
+	if err != nil {
+		return err
+	}
+
+	var st state.BeaconState
+	cached, ok, err := s.epochBoundaryStateCache.getByBlockRoot(root)
+	if err != nil {
+		return err
+	}
+	if ok && cached.state.Slot() == slot {
+		st = cached.state
+	} else {
+		st, err = s.StateByRoot(ctx, root)
+		if err != nil {
+			return err
+		}
+		// The state is advanced through the skipped slots up to the slot of the hierarchy.
+		if st.Slot() < slot {
+			st, err = ReplayProcessSlots(ctx, st.Copy(), slot)
+			if err != nil {
+				return errors.Wrapf(err, "could not process slots up to %d", slot)
+			}
+		}
+	}
+
+	if err := s.beaconDB.SaveStateDiff(ctx, st); err != nil {
+		return err
+	}
+	log.WithField("slot", slot).Debug("Saved state diff in DB")
+	return nil
+}
+
+// finalizedRoot returns the root among the given ones which is finalized. Once a slot is finalized the db should
+// not have more than one canonical block for it, we error out when this happens.
+func (s *State) finalizedRoot(ctx context.Context, roots [][32]byte) ([32]byte, error) {
+	if len(roots) == 1 {
+		return roots[0], nil
+	}
+	for _, r := range roots {
+		if s.beaconDB.IsFinalizedBlock(ctx, r) {
+			return r, nil
+		}
+	}
+	return [32]byte{}, errUnknownBlock
+}
+
+// stateDiffAncestor returns the state stored as a state diff which the child of the finalized parent block can be
+// applied to. It returns nil if there is no such state, i.e. when no state diff is stored between the parent and
+// the child, or when the finalized chain has another child of the parent before the stored state.
+func (s *State) stateDiffAncestor(ctx context.Context, parentRoot [32]byte, childSlot primitives.Slot) (state.BeaconState, error) {
+	if s.beaconDB.StateDiffHierarchy() == nil || childSlot == 0 || !s.beaconDB.IsFinalizedBlock(ctx, parentRoot) {
+		return nil, nil
+	}
+	slot, ok, err := s.beaconDB.HighestStateDiffSlot(ctx, childSlot-1)
+	if err != nil || !ok {
+		return nil, err
+	}
+	parent, err := s.beaconDB.Block(ctx, parentRoot)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil || parent.IsNil() || slot < parent.Block().Slot() {
+		return nil, nil
+	}
+	child, err := s.beaconDB.FinalizedChildBlock(ctx, parentRoot)
+	if err != nil {
+		return nil, err
+	}
+	if child != nil && !child.IsNil() && child.Block().Slot() <= slot {
+		return nil, nil
+	}
+	return s.beaconDB.StateDiff(ctx, slot)
+}
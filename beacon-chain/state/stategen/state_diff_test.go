@@ -0,0 +1,240 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	doublylinkedtree "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/doubly-linked-tree"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	consensusblocks "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+type stateDiffChain struct {
+	db       *kv.Store
+	genesis  state.BeaconState
+	gRoot    [32]byte
+	roots    map[primitives.Slot][32]byte
+	states   map[primitives.Slot]state.BeaconState
+	blocks   []interfaces.ReadOnlySignedBeaconBlock
+	headSlot primitives.Slot
+}
+
+// newStateDiffChain saves a chain with a block at every slot up to the head slot, except for the skipped ones,
+// to a database storing finalized states as state diffs.
+func newStateDiffChain(t testing.TB, exponents []uint8, headSlot primitives.Slot, skipped ...primitives.Slot) *stateDiffChain {
+	ctx := context.Background()
+	beaconDB, err := kv.NewKVStore(ctx, t.TempDir(), kv.WithStateDiffExponents(exponents))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, beaconDB.Close())
+	})
+
+	genesis, pks := util.DeterministicGenesisState(t, 32)
+	genesisStateRoot, err := genesis.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	gBlk := blocks.NewGenesisBlock(genesisStateRoot[:])
+	util.SaveBlock(t, ctx, beaconDB, gBlk)
+	gRoot, err := gBlk.Block.HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, beaconDB.SaveState(ctx, genesis, gRoot))
+	require.NoError(t, beaconDB.SaveGenesisBlockRoot(ctx, gRoot))
+
+	c := &stateDiffChain{
+		db:       beaconDB,
+		genesis:  genesis,
+		gRoot:    gRoot,
+		roots:    map[primitives.Slot][32]byte{0: gRoot},
+		states:   map[primitives.Slot]state.BeaconState{0: genesis},
+		headSlot: headSlot,
+	}
+	isSkipped := make(map[primitives.Slot]bool)
+	for _, s := range skipped {
+		isSkipped[s] = true
+	}
+	st := genesis.Copy()
+	for slot := primitives.Slot(1); slot <= headSlot; slot++ {
+		if isSkipped[slot] {
+			continue
+		}
+		b, err := util.GenerateFullBlock(st, pks, util.DefaultBlockGenConfig(), slot)
+		require.NoError(t, err)
+		wsb, err := consensusblocks.NewSignedBeaconBlock(b)
+		require.NoError(t, err)
+		st, err = executeStateTransitionStateGen(ctx, st.Copy(), wsb)
+		require.NoError(t, err)
+		root, err := b.Block.HashTreeRoot()
+		require.NoError(t, err)
+		require.NoError(t, beaconDB.SaveBlock(ctx, wsb))
+		require.NoError(t, beaconDB.SaveStateSummary(ctx, &ethpb.StateSummary{Slot: slot, Root: root[:]}))
+		c.roots[slot] = root
+		c.states[slot] = st
+		c.blocks = append(c.blocks, wsb)
+	}
+	head := c.roots[headSlot]
+	require.NoError(t, beaconDB.SaveState(ctx, st, head))
+	require.NoError(t, beaconDB.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Root: head[:]}))
+	return c
+}
+
+func requireStateRoot(t *testing.T, want, got state.BeaconState) {
+	wantRoot, err := want.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	gotRoot, err := got.HashTreeRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, wantRoot, gotRoot)
+}
+
+func TestMigrateToCold_StateDiffs(t *testing.T) {
+	ctx := context.Background()
+	c := newStateDiffChain(t, []uint8{3, 2}, 10, 4)
+	service := New(c.db, doublylinkedtree.New())
+	service.finalizedInfo = &finalizedInfo{slot: 0, root: c.gRoot, state: c.genesis}
+	require.NoError(t, service.MigrateToCold(ctx, c.roots[10]))
+
+	for _, slot := range []primitives.Slot{0, 4, 8} {
+		require.Equal(t, true, c.db.HasStateDiff(ctx, slot), "slot %d", slot)
+	}
+	require.Equal(t, false, c.db.HasStateDiff(ctx, 6))
+	// No archived states are saved in full.
+	require.Equal(t, false, c.db.HasState(ctx, c.roots[8]))
+
+	got, err := c.db.StateDiff(ctx, 8)
+	require.NoError(t, err)
+	requireStateRoot(t, c.states[8], got)
+	// Slot 4 is skipped, the state diff advances the state of slot 3.
+	want, err := ReplayProcessSlots(ctx, c.states[3].Copy(), 4)
+	require.NoError(t, err)
+	got, err = c.db.StateDiff(ctx, 4)
+	require.NoError(t, err)
+	requireStateRoot(t, want, got)
+}
+
+func TestStateByRoot_StateDiffAncestor(t *testing.T) {
+	ctx := context.Background()
+	c := newStateDiffChain(t, []uint8{3, 2}, 10, 4)
+	service := New(c.db, doublylinkedtree.New())
+	service.finalizedInfo = &finalizedInfo{slot: 0, root: c.gRoot, state: c.genesis}
+	require.NoError(t, service.MigrateToCold(ctx, c.roots[10]))
+
+	service = New(c.db, doublylinkedtree.New())
+	st, err := service.stateDiffAncestor(ctx, c.roots[8], 9)
+	require.NoError(t, err)
+	require.NotNil(t, st)
+	requireStateRoot(t, c.states[8], st)
+
+	// The state diff at slot 4 is between the block at slot 3 and its child.
+	st, err = service.stateDiffAncestor(ctx, c.roots[3], 5)
+	require.NoError(t, err)
+	require.NotNil(t, st)
+	require.Equal(t, primitives.Slot(4), st.Slot())
+
+	st, err = service.stateDiffAncestor(ctx, c.roots[5], 6)
+	require.NoError(t, err)
+	require.Equal(t, nil, st)
+	// The block at slot 9 is the child of the block at slot 8, a sibling at slot 10 can not use the state diff at
+	// slot 8 either.
+	st, err = service.stateDiffAncestor(ctx, c.roots[7], 10)
+	require.NoError(t, err)
+	require.Equal(t, nil, st)
+
+	st, err = service.StateByRoot(ctx, c.roots[9])
+	require.NoError(t, err)
+	requireStateRoot(t, c.states[9], st)
+}
+
+type mockStateDiffHistory struct {
+	*mockHistory
+	diffs map[primitives.Slot]state.BeaconState
+}
+
+func (m *mockStateDiffHistory) HighestStateDiffSlot(_ context.Context, slot primitives.Slot) (primitives.Slot, bool, error) {
+	var highest primitives.Slot
+	var ok bool
+	for s := range m.diffs {
+		if s <= slot && (!ok || s > highest) {
+			highest, ok = s, true
+		}
+	}
+	return highest, ok, nil
+}
+
+func (m *mockStateDiffHistory) StateDiff(_ context.Context, slot primitives.Slot) (state.BeaconState, error) {
+	return m.diffs[slot].Copy(), nil
+}
+
+func TestChainForSlot_StateDiffs(t *testing.T) {
+	ctx := context.Background()
+	specs := []mockHistorySpec{
+		{slot: 50, canonicalBlock: true},
+		{slot: 51, canonicalBlock: true},
+		{slot: 60, canonicalBlock: true},
+		{slot: 70, canonicalBlock: true},
+	}
+	mh := newMockHistory(t, specs, 80)
+	diff32, err := ReplayProcessSlots(ctx, mh.states[mh.slotMap[0]].Copy(), 32)
+	require.NoError(t, err)
+	diff64, err := ReplayProcessSlots(ctx, mh.hiddenStates[mh.slotMap[60]].Copy(), 64)
+	require.NoError(t, err)
+	hist := &mockStateDiffHistory{mockHistory: mh, diffs: map[primitives.Slot]state.BeaconState{32: diff32, 64: diff64}}
+	ch := &CanonicalHistory{h: hist, cc: mh, cs: mh}
+
+	cases := []struct {
+		slot       primitives.Slot
+		state      state.BeaconState
+		blockSlots []primitives.Slot
+	}{
+		{slot: 75, state: diff64, blockSlots: []primitives.Slot{70}},
+		{slot: 64, state: diff64, blockSlots: []primitives.Slot{}},
+		{slot: 62, state: diff32, blockSlots: []primitives.Slot{50, 51, 60}},
+		{slot: 20, state: mh.states[mh.slotMap[0]], blockSlots: []primitives.Slot{}},
+	}
+	for _, c := range cases {
+		st, blks, err := ch.chainForSlot(ctx, c.slot)
+		require.NoError(t, err)
+		requireStateRoot(t, c.state, st)
+		require.Equal(t, len(c.blockSlots), len(blks), "slot %d", c.slot)
+		for i, b := range blks {
+			require.Equal(t, c.blockSlots[i], b.Block().Slot())
+		}
+	}
+}
+
+func BenchmarkStateDiff(b *testing.B) {
+	ctx := context.Background()
+	c := newStateDiffChain(b, []uint8{5, 4, 2}, 64)
+	service := New(c.db, doublylinkedtree.New())
+	service.finalizedInfo = &finalizedInfo{slot: 0, root: c.gRoot, state: c.genesis}
+	require.NoError(b, service.MigrateToCold(ctx, c.roots[64]))
+	// The blocks to replay are in decreasing slot order.
+	replayed := make([]interfaces.ReadOnlySignedBeaconBlock, 0, 60)
+	for i := 59; i >= 0; i-- {
+		replayed = append(replayed, c.blocks[i])
+	}
+
+	b.Run("rebuild", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			// Alternate between the sections of the hierarchy, so that every rebuild applies diffs.
+			slot := primitives.Slot(28)
+			if i%2 == 1 {
+				slot = 60
+			}
+			_, err := c.db.StateDiff(ctx, slot)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("replay", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := service.replayBlocks(ctx, c.genesis.Copy(), replayed, 60)
+			require.NoError(b, err)
+		}
+	})
+}
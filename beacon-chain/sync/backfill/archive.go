@@ -0,0 +1,31 @@
+package backfill
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+)
+
+// BlockArchive is a local source of finalized blocks, such as a directory of era files. Batches covered by the
+// archive are read from it instead of being requested from peers. The blocks go through the same verification as
+// the ones downloaded from peers.
+type BlockArchive interface {
+	// Covers reports whether the archive holds the blocks of every slot in [start, end).
+	Covers(start, end primitives.Slot) bool
+	// Blocks returns the blocks of the slots in [start, end), in increasing slot order.
+	Blocks(ctx context.Context, start, end primitives.Slot) ([]blocks.ROBlock, error)
+}
+
+// WithBlockArchive sets a local archive of blocks which backfill reads from before turning to peers.
+func WithBlockArchive(a BlockArchive) ServiceOption {
+	return func(s *Service) error {
+		s.archive = a
+		return nil
+	}
+}
+
+// archived reports whether the blocks of the batch can be read from the archive.
+func archived(a BlockArchive, b batch) bool {
+	return a != nil && b.state != batchBlobSync && a.Covers(b.begin, b.end)
+}
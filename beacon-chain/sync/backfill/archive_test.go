@@ -0,0 +1,59 @@
+package backfill
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+type mockArchive struct {
+	start, end primitives.Slot
+}
+
+func (m *mockArchive) Covers(start, end primitives.Slot) bool {
+	return start >= m.start && end <= m.end && start < end
+}
+
+func (m *mockArchive) Blocks(_ context.Context, _, _ primitives.Slot) ([]blocks.ROBlock, error) {
+	return nil, nil
+}
+
+func TestPoolAssignArchived(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := newP2PBatchWorkerPool(nil, 3, newControls(), &mockArchive{start: 0, end: 100})
+	pool.ctx = ctx
+	todo := []batch{
+		{begin: 100, end: 110, state: batchSequenced, busy: "peer"},
+		{begin: 90, end: 100, state: batchErrRetryable, busy: "peer"},
+		{begin: 80, end: 90, state: batchBlobSync},
+	}
+	got := make(chan batch, len(todo))
+	go func() {
+		for b := range pool.toWorkers {
+			got <- b
+		}
+	}()
+	earliest := primitives.Slot(math.MaxUint64)
+	remaining, err := pool.assignArchived(todo, &earliest)
+	require.NoError(t, err)
+
+	// Only the batch needing blocks within the archive is read from it, without a peer.
+	b := <-got
+	require.Equal(t, primitives.Slot(90), b.begin)
+	require.Equal(t, true, b.busy == "" && b.blockPid == "")
+	require.Equal(t, primitives.Slot(90), earliest)
+	require.Equal(t, 2, len(remaining))
+	require.Equal(t, primitives.Slot(100), remaining[0].begin)
+	require.Equal(t, primitives.Slot(80), remaining[1].begin)
+	close(pool.toWorkers)
+
+	pool.archive = nil
+	remaining, err = pool.assignArchived(todo, &earliest)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(remaining))
+}
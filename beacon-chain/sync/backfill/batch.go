@@ -75,6 +75,10 @@ type batch struct {
 	blockPid       peer.ID
 	blobPid        peer.ID
 	bs             *blobSync
+	// dlBlocks, dlBytes and dlTime measure the last download made for the batch by a worker.
+	dlBlocks int
+	dlBytes  int
+	dlTime   time.Duration
 }
 
 func (b batch) logFields() logrus.Fields {
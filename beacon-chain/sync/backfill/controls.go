@@ -0,0 +1,231 @@
+package backfill
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+)
+
+var errInvalidActiveHours = errors.New("active hours must be between 0 and 23, with different start and end")
+
+// Controller provides the progress of the backfill service and allows it to be tuned at runtime.
+type Controller interface {
+	Progress() *Progress
+	Pause()
+	Resume()
+	SetBandwidthLimit(bytesPerSecond uint64)
+	SetActiveHours(w *ActiveHours) error
+}
+
+// ActiveHours is a daily window of UTC hours during which backfill is allowed to download batches. The window
+// starts at the beginning of the Start hour and ends at the beginning of the End hour, and wraps around midnight
+// when End is lower than Start.
+type ActiveHours struct {
+	Start int
+	End   int
+}
+
+// NewActiveHours validates and returns an ActiveHours window.
+func NewActiveHours(start, end int) (*ActiveHours, error) {
+	if start < 0 || start > 23 || end < 0 || end > 23 || start == end {
+		return nil, errors.Wrapf(errInvalidActiveHours, "start=%d, end=%d", start, end)
+	}
+	return &ActiveHours{Start: start, End: end}, nil
+}
+
+// ParseActiveHours parses an ActiveHours window written as "start-end", e.g. "22-6".
+func ParseActiveHours(v string) (*ActiveHours, error) {
+	parts := strings.Split(v, "-")
+	if len(parts) != 2 {
+		return nil, errors.Wrapf(errInvalidActiveHours, "expected start-end, got %q", v)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, errors.Wrapf(errInvalidActiveHours, "invalid start hour %q", parts[0])
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, errors.Wrapf(errInvalidActiveHours, "invalid end hour %q", parts[1])
+	}
+	return NewActiveHours(start, end)
+}
+
+// String formats the window the way ParseActiveHours expects it.
+func (w *ActiveHours) String() string {
+	return fmt.Sprintf("%d-%d", w.Start, w.End)
+}
+
+func (w *ActiveHours) contains(t time.Time) bool {
+	h := t.UTC().Hour()
+	if w.Start < w.End {
+		return h >= w.Start && h < w.End
+	}
+	return h >= w.Start || h < w.End
+}
+
+// PeerThroughput summarizes the block batches downloaded from a peer by backfill.
+type PeerThroughput struct {
+	PeerID   peer.ID
+	Batches  uint64
+	Blocks   uint64
+	Bytes    uint64
+	Duration time.Duration
+}
+
+// BytesPerSecond is the average download speed of the batches requested from the peer.
+func (p PeerThroughput) BytesPerSecond() float64 {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return float64(p.Bytes) / p.Duration.Seconds()
+}
+
+// Progress is a snapshot of the state of the backfill service.
+type Progress struct {
+	// Running is true once the service started downloading batches, until backfill completes.
+	Running bool
+	// Paused is true when backfill was paused by the operator.
+	Paused bool
+	// Throttled is true when batch downloads are held back, by the bandwidth limit or outside of active hours.
+	Throttled bool
+	// LowSlot is the lowest slot backfilled so far.
+	LowSlot primitives.Slot
+	// TargetSlot is the lowest slot backfill needs to reach.
+	TargetSlot primitives.Slot
+	// OriginSlot is the slot of the checkpoint sync origin block, where backfill started.
+	OriginSlot primitives.Slot
+	// SlotsPerSecond is the rate at which LowSlot has moved down since the service started.
+	SlotsPerSecond float64
+	// ETA is the estimated time until TargetSlot is reached, zero when it can not be estimated.
+	ETA time.Duration
+	// BandwidthLimit is the download limit in bytes per second, zero when unlimited.
+	BandwidthLimit uint64
+	// ActiveHours is the daily window in which backfill runs, nil when it can run at any time.
+	ActiveHours *ActiveHours
+	// Peers is the download throughput of each peer which served a batch.
+	Peers []PeerThroughput
+}
+
+// controls holds the runtime settings of backfill, shared by the service and the worker pool, along with the
+// throughput statistics needed to enforce and report them.
+type controls struct {
+	sync.Mutex
+	now       func() time.Time
+	paused    bool
+	hours     *ActiveHours
+	limit     uint64
+	tokens    float64
+	refreshed time.Time
+	peers     map[peer.ID]*PeerThroughput
+}
+
+func newControls() *controls {
+	return &controls{
+		now:   time.Now,
+		peers: make(map[peer.ID]*PeerThroughput),
+	}
+}
+
+func (c *controls) setPaused(paused bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.paused = paused
+}
+
+func (c *controls) setLimit(bytesPerSecond uint64) {
+	c.Lock()
+	defer c.Unlock()
+	c.limit = bytesPerSecond
+	c.tokens = 0
+	c.refreshed = c.now()
+}
+
+func (c *controls) setHours(w *ActiveHours) {
+	c.Lock()
+	defer c.Unlock()
+	c.hours = w
+}
+
+// refill credits the bandwidth budget accrued since the last call. The budget is capped at one second worth of
+// downloads, so that idle periods do not allow a burst afterwards.
+func (c *controls) refill() {
+	now := c.now()
+	if c.limit > 0 {
+		c.tokens += now.Sub(c.refreshed).Seconds() * float64(c.limit)
+		if c.tokens > float64(c.limit) {
+			c.tokens = float64(c.limit)
+		}
+	}
+	c.refreshed = now
+}
+
+// throttled reports whether new batches should be held back.
+func (c *controls) throttled() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.throttledNoLock()
+}
+
+func (c *controls) throttledNoLock() bool {
+	if c.hours != nil && !c.hours.contains(c.now()) {
+		return true
+	}
+	c.refill()
+	return c.limit > 0 && c.tokens < 0
+}
+
+// allow checks whether new batches can be assigned to workers.
+func (c *controls) allow() bool {
+	c.Lock()
+	defer c.Unlock()
+	return !c.paused && !c.throttledNoLock()
+}
+
+// downloaded records a batch of blocks downloaded from a peer, and charges it to the bandwidth budget.
+func (c *controls) downloaded(pid peer.ID, blocks int, bytes int, d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.refill()
+	if c.limit > 0 {
+		c.tokens -= float64(bytes)
+	}
+	if pid == "" {
+		return
+	}
+	pt, ok := c.peers[pid]
+	if !ok {
+		pt = &PeerThroughput{PeerID: pid}
+		c.peers[pid] = pt
+	}
+	pt.Batches++
+	pt.Blocks += uint64(blocks)
+	pt.Bytes += uint64(bytes)
+	pt.Duration += d
+}
+
+// fill sets the settings and peer statistics of the progress report.
+func (c *controls) fill(p *Progress) {
+	c.Lock()
+	defer c.Unlock()
+	p.Paused = c.paused
+	p.Throttled = c.throttledNoLock()
+	p.BandwidthLimit = c.limit
+	if c.hours != nil {
+		h := *c.hours
+		p.ActiveHours = &h
+	}
+	p.Peers = make([]PeerThroughput, 0, len(c.peers))
+	for _, pt := range c.peers {
+		p.Peers = append(p.Peers, *pt)
+	}
+	sort.Slice(p.Peers, func(i, j int) bool {
+		return p.Peers[i].BytesPerSecond() > p.Peers[j].BytesPerSecond()
+	})
+}
@@ -0,0 +1,133 @@
+package backfill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	p2ptest "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/proto/dbval"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func TestActiveHours(t *testing.T) {
+	_, err := NewActiveHours(3, 3)
+	require.ErrorIs(t, err, errInvalidActiveHours)
+	_, err = NewActiveHours(-1, 3)
+	require.ErrorIs(t, err, errInvalidActiveHours)
+	_, err = NewActiveHours(1, 24)
+	require.ErrorIs(t, err, errInvalidActiveHours)
+
+	_, err = ParseActiveHours("22")
+	require.ErrorIs(t, err, errInvalidActiveHours)
+	_, err = ParseActiveHours("a-6")
+	require.ErrorIs(t, err, errInvalidActiveHours)
+	parsed, err := ParseActiveHours("22-6")
+	require.NoError(t, err)
+	require.Equal(t, "22-6", parsed.String())
+
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 1, hour, 30, 0, 0, time.UTC)
+	}
+	day, err := NewActiveHours(9, 17)
+	require.NoError(t, err)
+	require.Equal(t, false, day.contains(at(8)))
+	require.Equal(t, true, day.contains(at(9)))
+	require.Equal(t, true, day.contains(at(16)))
+	require.Equal(t, false, day.contains(at(17)))
+
+	night, err := NewActiveHours(22, 6)
+	require.NoError(t, err)
+	require.Equal(t, true, night.contains(at(23)))
+	require.Equal(t, true, night.contains(at(0)))
+	require.Equal(t, true, night.contains(at(5)))
+	require.Equal(t, false, night.contains(at(6)))
+	require.Equal(t, false, night.contains(at(12)))
+}
+
+func TestControls_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newControls()
+	c.now = func() time.Time { return now }
+	require.Equal(t, true, c.allow())
+
+	c.setPaused(true)
+	require.Equal(t, false, c.allow())
+	c.setPaused(false)
+	require.Equal(t, true, c.allow())
+
+	night, err := NewActiveHours(22, 6)
+	require.NoError(t, err)
+	c.setHours(night)
+	require.Equal(t, false, c.allow())
+	require.Equal(t, true, c.throttled())
+	c.setHours(nil)
+	require.Equal(t, true, c.allow())
+
+	// Downloading more than the budget holds assignments until it has been earned back.
+	c.setLimit(1000)
+	c.downloaded("peer", 10, 1500, time.Second)
+	require.Equal(t, false, c.allow())
+	now = now.Add(time.Second)
+	require.Equal(t, false, c.allow())
+	now = now.Add(600 * time.Millisecond)
+	require.Equal(t, true, c.allow())
+
+	// Idle time does not accumulate beyond a second worth of budget.
+	now = now.Add(time.Hour)
+	require.Equal(t, true, c.allow())
+	c.downloaded("peer", 10, 2500, time.Second)
+	require.Equal(t, false, c.allow())
+
+	c.setLimit(0)
+	require.Equal(t, true, c.allow())
+}
+
+func TestServiceProgress(t *testing.T) {
+	ctx := context.Background()
+	su, err := NewUpdater(ctx, &mockBackfillDB{})
+	require.NoError(t, err)
+	su.bs = &dbval.BackfillStatus{LowSlot: 1000, OriginSlot: 1200}
+	hours, err := NewActiveHours(1, 2)
+	require.NoError(t, err)
+	srv, err := NewService(ctx, su, filesystem.NewEphemeralBlobStorage(t), startup.NewClockSynchronizer(),
+		p2ptest.NewTestP2P(t), &mockAssigner{}, WithBandwidthLimit(1<<20), WithActiveHours(hours))
+	require.NoError(t, err)
+	srv.ms = func(primitives.Slot) primitives.Slot { return 100 }
+
+	p := srv.Progress()
+	require.Equal(t, false, p.Running)
+	require.Equal(t, primitives.Slot(1000), p.LowSlot)
+	require.Equal(t, primitives.Slot(1200), p.OriginSlot)
+	require.Equal(t, uint64(1<<20), p.BandwidthLimit)
+	require.DeepEqual(t, hours, p.ActiveHours)
+
+	srv.progress.start(startup.NewClock(time.Now(), [32]byte{}), 1200)
+	srv.progress.started = time.Now().Add(-100 * time.Second)
+	srv.ctl.downloaded(peer.ID("slow"), 32, 1000, 10*time.Second)
+	srv.ctl.downloaded(peer.ID("fast"), 32, 1000, time.Second)
+	srv.ctl.downloaded(peer.ID("fast"), 32, 3000, time.Second)
+	srv.Pause()
+
+	p = srv.Progress()
+	require.Equal(t, true, p.Running)
+	require.Equal(t, true, p.Paused)
+	require.Equal(t, primitives.Slot(100), p.TargetSlot)
+	require.Equal(t, true, p.SlotsPerSecond > 1.9 && p.SlotsPerSecond < 2.1)
+	require.Equal(t, true, p.ETA > 400*time.Second && p.ETA < 500*time.Second)
+	require.Equal(t, 2, len(p.Peers))
+	require.Equal(t, peer.ID("fast"), p.Peers[0].PeerID)
+	require.Equal(t, uint64(2), p.Peers[0].Batches)
+	require.Equal(t, uint64(64), p.Peers[0].Blocks)
+	require.Equal(t, float64(2000), p.Peers[0].BytesPerSecond())
+
+	srv.Resume()
+	require.Equal(t, false, srv.Progress().Paused)
+	require.ErrorIs(t, srv.SetActiveHours(&ActiveHours{Start: 5, End: 5}), errInvalidActiveHours)
+	require.NoError(t, srv.SetActiveHours(nil))
+	require.Equal(t, true, srv.Progress().ActiveHours == nil)
+}
@@ -28,9 +28,11 @@ type worker interface {
 
 type newWorker func(id workerId, in, out chan batch, c *startup.Clock, v *verifier, cm sync.ContextByteVersions, nbv verification.NewBlobVerifier, bfs *filesystem.BlobStorage) worker
 
-func defaultNewWorker(p p2p.P2P) newWorker {
+func defaultNewWorker(p p2p.P2P, a BlockArchive) newWorker {
 	return func(id workerId, in, out chan batch, c *startup.Clock, v *verifier, cm sync.ContextByteVersions, nbv verification.NewBlobVerifier, bfs *filesystem.BlobStorage) worker {
-		return newP2pWorker(id, p, in, out, c, v, cm, nbv, bfs)
+		w := newP2pWorker(id, p, in, out, c, v, cm, nbv, bfs)
+		w.archive = a
+		return w
 	}
 }
 
@@ -42,6 +44,8 @@ type p2pBatchWorkerPool struct {
 	toRouter    chan batch
 	fromRouter  chan batch
 	shutdownErr chan error
+	ctl         *controls
+	archive     BlockArchive
 	endSeq      []batch
 	ctx         context.Context
 	cancel      func()
@@ -49,8 +53,8 @@ type p2pBatchWorkerPool struct {
 
 var _ batchWorkerPool = &p2pBatchWorkerPool{}
 
-func newP2PBatchWorkerPool(p p2p.P2P, maxBatches int) *p2pBatchWorkerPool {
-	nw := defaultNewWorker(p)
+func newP2PBatchWorkerPool(p p2p.P2P, maxBatches int, ctl *controls, a BlockArchive) *p2pBatchWorkerPool {
+	nw := defaultNewWorker(p, a)
 	return &p2pBatchWorkerPool{
 		newWorker:   nw,
 		toRouter:    make(chan batch, maxBatches),
@@ -59,6 +63,8 @@ func newP2PBatchWorkerPool(p p2p.P2P, maxBatches int) *p2pBatchWorkerPool {
 		fromWorkers: make(chan batch),
 		maxBatches:  maxBatches,
 		shutdownErr: make(chan error),
+		ctl:         ctl,
+		archive:     a,
 	}
 }
 
@@ -117,6 +123,9 @@ func (p *p2pBatchWorkerPool) batchRouter(pa PeerAssigner) {
 		case b := <-p.fromWorkers:
 			pid := b.busy
 			busy[pid] = false
+			if b.dlTime > 0 {
+				p.ctl.downloaded(pid, b.dlBlocks, b.dlBytes, b.dlTime)
+			}
 			if b.state == batchBlobSync {
 				todo = append(todo, b)
 				sortBatchDesc(todo)
@@ -131,6 +140,21 @@ func (p *p2pBatchWorkerPool) batchRouter(pa PeerAssigner) {
 		if len(todo) == 0 {
 			continue
 		}
+		// Hold new assignments while backfill is paused or throttled, the ticker will retry them.
+		if !p.ctl.allow() {
+			continue
+		}
+		// Batches covered by the block archive are read locally, without tying up a peer.
+		var err error
+		todo, err = p.assignArchived(todo, &earliest)
+		if err != nil {
+			log.WithError(p.ctx.Err()).Info("p2pBatchWorkerPool context canceled, shutting down")
+			p.shutdown(p.ctx.Err())
+			return
+		}
+		if len(todo) == 0 {
+			continue
+		}
 		// Try to assign as many outstanding batches as possible to peers and feed the assigned batches to workers.
 		assigned, err := pa.Assign(busy, len(todo))
 		if err != nil {
@@ -151,15 +175,40 @@ func (p *p2pBatchWorkerPool) batchRouter(pa PeerAssigner) {
 			busy[pid] = true
 			todo[0].busy = pid
 			p.toWorkers <- todo[0].withPeer(pid)
-			if todo[0].begin < earliest {
-				earliest = todo[0].begin
-				oldestBatch.Set(float64(earliest))
-			}
+			trackOldest(&earliest, todo[0])
 			todo = todo[1:]
 		}
 	}
 }
 
+// assignArchived feeds the batches which can be read from the block archive to workers, and returns the others.
+func (p *p2pBatchWorkerPool) assignArchived(todo []batch, earliest *primitives.Slot) ([]batch, error) {
+	if p.archive == nil {
+		return todo, nil
+	}
+	remaining := make([]batch, 0, len(todo))
+	for _, b := range todo {
+		if !archived(p.archive, b) {
+			remaining = append(remaining, b)
+			continue
+		}
+		if err := b.waitUntilReady(p.ctx); err != nil {
+			return nil, err
+		}
+		b.busy = ""
+		p.toWorkers <- b.withPeer("")
+		trackOldest(earliest, b)
+	}
+	return remaining, nil
+}
+
+func trackOldest(earliest *primitives.Slot, b batch) {
+	if b.begin < *earliest {
+		*earliest = b.begin
+		oldestBatch.Set(float64(*earliest))
+	}
+}
+
 func (p *p2pBatchWorkerPool) shutdown(err error) {
 	p.cancel()
 	p.shutdownErr <- err
@@ -0,0 +1,100 @@
+package backfill
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+)
+
+var _ Controller = (*Service)(nil)
+
+// progressTracker measures how fast the backfill low slot moves down while the service is running.
+type progressTracker struct {
+	sync.RWMutex
+	running  bool
+	clock    *startup.Clock
+	started  time.Time
+	startLow primitives.Slot
+}
+
+func (t *progressTracker) start(clock *startup.Clock, low primitives.Slot) {
+	t.Lock()
+	defer t.Unlock()
+	t.running = true
+	t.clock = clock
+	t.started = time.Now()
+	t.startLow = low
+}
+
+func (t *progressTracker) stop() {
+	t.Lock()
+	defer t.Unlock()
+	t.running = false
+}
+
+// Progress returns a snapshot of the backfill progress and runtime settings.
+func (s *Service) Progress() *Progress {
+	p := &Progress{}
+	s.ctl.fill(p)
+	if s.store.isGenesisSync() {
+		return p
+	}
+	status := s.store.status()
+	p.LowSlot = primitives.Slot(status.LowSlot)
+	p.OriginSlot = primitives.Slot(status.OriginSlot)
+
+	s.progress.RLock()
+	defer s.progress.RUnlock()
+	if s.progress.clock != nil {
+		p.TargetSlot = s.ms(s.progress.clock.CurrentSlot())
+	}
+	p.Running = s.progress.running
+	if !p.Running {
+		return p
+	}
+	elapsed := time.Since(s.progress.started)
+	if elapsed <= 0 || p.LowSlot >= s.progress.startLow {
+		return p
+	}
+	p.SlotsPerSecond = float64(s.progress.startLow-p.LowSlot) / elapsed.Seconds()
+	if p.LowSlot > p.TargetSlot {
+		p.ETA = time.Duration(float64(p.LowSlot-p.TargetSlot) / p.SlotsPerSecond * float64(time.Second))
+	}
+	return p
+}
+
+// Pause stops backfill from assigning new batches to workers. Batches already downloading are completed.
+func (s *Service) Pause() {
+	s.ctl.setPaused(true)
+	log.Info("Backfill paused")
+}
+
+// Resume lets a paused backfill assign batches to workers again.
+func (s *Service) Resume() {
+	s.ctl.setPaused(false)
+	log.Info("Backfill resumed")
+}
+
+// SetBandwidthLimit limits the average download rate of backfill, in bytes per second. Zero removes the limit.
+func (s *Service) SetBandwidthLimit(bytesPerSecond uint64) {
+	s.ctl.setLimit(bytesPerSecond)
+	log.WithField("bytesPerSecond", bytesPerSecond).Info("Backfill bandwidth limit updated")
+}
+
+// SetActiveHours restricts backfill downloads to a daily window of UTC hours. A nil window removes the restriction.
+func (s *Service) SetActiveHours(w *ActiveHours) error {
+	if w != nil {
+		if _, err := NewActiveHours(w.Start, w.End); err != nil {
+			return err
+		}
+	}
+	s.ctl.setHours(w)
+	if w == nil {
+		log.Info("Backfill active hours removed")
+	} else {
+		log.WithField("start", w.Start).WithField("end", w.End).Info("Backfill active hours updated")
+	}
+	return nil
+}
@@ -39,6 +39,9 @@ type Service struct {
 	batchImporter   batchImporter
 	blobStore       *filesystem.BlobStorage
 	initSyncWaiter  func() error
+	ctl             *controls
+	progress        progressTracker
+	archive         BlockArchive
 }
 
 var _ runtime.Service = (*Service)(nil)
@@ -102,6 +105,30 @@ func WithInitSyncWaiter(w func() error) ServiceOption {
 	}
 }
 
+// WithBandwidthLimit sets the initial limit on the average download rate of backfill, in bytes per second.
+// Zero means unlimited. The limit can be changed at runtime with SetBandwidthLimit.
+func WithBandwidthLimit(bytesPerSecond uint64) ServiceOption {
+	return func(s *Service) error {
+		s.ctl.setLimit(bytesPerSecond)
+		return nil
+	}
+}
+
+// WithActiveHours restricts backfill downloads to a daily window of UTC hours, see ActiveHours.
+// The window can be changed at runtime with SetActiveHours.
+func WithActiveHours(w *ActiveHours) ServiceOption {
+	return func(s *Service) error {
+		if w == nil {
+			return nil
+		}
+		if _, err := NewActiveHours(w.Start, w.End); err != nil {
+			return err
+		}
+		s.ctl.setHours(w)
+		return nil
+	}
+}
+
 // InitializerWaiter is an interface that is satisfied by verification.InitializerWaiter.
 // Using this interface enables node init to satisfy this requirement for the backfill service
 // while also allowing backfill to mock it in tests.
@@ -148,13 +175,14 @@ func NewService(ctx context.Context, su *Store, bStore *filesystem.BlobStorage,
 		p2p:           p,
 		pa:            pa,
 		batchImporter: defaultBatchImporter,
+		ctl:           newControls(),
 	}
 	for _, o := range opts {
 		if err := o(s); err != nil {
 			return nil, err
 		}
 	}
-	s.pool = newP2PBatchWorkerPool(p, s.nWorkers)
+	s.pool = newP2PBatchWorkerPool(p, s.nWorkers, s.ctl, s.archive)
 
 	return s, nil
 }
@@ -297,6 +325,8 @@ func (s *Service) Start() {
 		}
 	}
 	s.pool.spawn(ctx, s.nWorkers, clock, s.pa, s.verifier, s.ctxMap, s.newBlobVerifier, s.blobStore)
+	s.progress.start(clock, primitives.Slot(status.LowSlot))
+	defer s.progress.stop()
 	s.batchSeq = newBatchSequencer(s.nWorkers, s.ms(s.clock.CurrentSlot()), primitives.Slot(status.LowSlot), primitives.Slot(s.batchSize))
 	if err = s.initBatches(); err != nil {
 		log.WithError(err).Error("Non-recoverable error in backfill service")
@@ -331,6 +361,10 @@ func (s *Service) initBatches() error {
 }
 
 func (s *Service) downscore(b batch) {
+	if b.blockPid == "" {
+		// The blocks were read from the archive.
+		return
+	}
 	s.p2p.Peers().Scorers().BadResponsesScorer().Increment(b.blockPid)
 }
 
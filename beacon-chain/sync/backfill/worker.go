@@ -10,6 +10,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
 )
 
 type workerId int
@@ -24,6 +25,8 @@ type p2pWorker struct {
 	cm   sync.ContextByteVersions
 	nbv  verification.NewBlobVerifier
 	bfs  *filesystem.BlobStorage
+	// archive, when set, serves the batches it covers in place of peers.
+	archive BlockArchive
 }
 
 func (w *p2pWorker) run(ctx context.Context) {
@@ -31,6 +34,7 @@ func (w *p2pWorker) run(ctx context.Context) {
 		select {
 		case b := <-w.todo:
 			log.WithFields(b.logFields()).WithField("backfillWorker", w.id).Debug("Backfill worker received batch")
+			b.dlBlocks, b.dlBytes, b.dlTime = 0, 0, 0
 			if b.state == batchBlobSync {
 				w.done <- w.handleBlobs(ctx, b)
 			} else {
@@ -49,9 +53,23 @@ func (w *p2pWorker) handleBlocks(ctx context.Context, b batch) batch {
 	if err != nil {
 		return b.withRetryableError(errors.Wrap(err, "configuration issue, could not compute minimum blob retention slot"))
 	}
-	b.blockPid = b.busy
+	fromArchive := archived(w.archive, b)
 	start := time.Now()
-	results, err := sync.SendBeaconBlocksByRangeRequest(ctx, w.c, w.p2p, b.blockPid, b.blockRequest(), blockValidationMetrics)
+	var results []interfaces.ReadOnlySignedBeaconBlock
+	if fromArchive {
+		blks, err := w.archive.Blocks(ctx, b.begin, b.end)
+		if err != nil {
+			log.WithError(err).WithFields(b.logFields()).Warn("Could not read batch from the block archive")
+			return b.withRetryableError(err)
+		}
+		results = make([]interfaces.ReadOnlySignedBeaconBlock, len(blks))
+		for i := range blks {
+			results[i] = blks[i]
+		}
+	} else {
+		b.blockPid = b.busy
+		results, err = sync.SendBeaconBlocksByRangeRequest(ctx, w.c, w.p2p, b.blockPid, b.blockRequest(), blockValidationMetrics)
+	}
 	dlt := time.Now()
 	backfillBatchTimeDownloadingBlocks.Observe(float64(dlt.Sub(start).Milliseconds()))
 	if err != nil {
@@ -73,6 +91,9 @@ func (w *p2pWorker) handleBlocks(ctx context.Context, b batch) batch {
 	}
 	backfillBlocksApproximateBytes.Add(float64(bdl))
 	log.WithFields(b.logFields()).WithField("dlbytes", bdl).Debug("Backfill batch block bytes downloaded")
+	if !fromArchive {
+		b.dlBlocks, b.dlBytes, b.dlTime = len(vb), bdl, dlt.Sub(start)
+	}
 	bs, err := newBlobSync(cs, vb, &blobSyncConfig{retentionStart: blobRetentionStart, nbv: w.nbv, store: w.bfs})
 	if err != nil {
 		return b.withRetryableError(err)
@@ -92,9 +113,11 @@ func (w *p2pWorker) handleBlobs(ctx context.Context, b batch) batch {
 	}
 	dlt := time.Now()
 	backfillBatchTimeDownloadingBlobs.Observe(float64(dlt.Sub(start).Milliseconds()))
+	b.dlTime = dlt.Sub(start)
 	if len(blobs) > 0 {
 		// All blobs are the same size, so we can compute 1 and use it for all in the batch.
 		sz := blobs[0].SizeSSZ() * len(blobs)
+		b.dlBytes = sz
 		backfillBlobsApproximateBytes.Add(float64(sz))
 		log.WithFields(b.logFields()).WithField("dlbytes", sz).Debug("Backfill batch blob bytes downloaded")
 	}
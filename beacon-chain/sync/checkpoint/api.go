@@ -26,20 +26,33 @@ func NewAPIInitializer(beaconNodeHost string) (*APIInitializer, error) {
 }
 
 // Initialize downloads origin state and block for checkpoint sync and initializes database records to
-// prepare the node to begin syncing from that point.
+// prepare the node to begin syncing from that point. The state download is staged in the database directory,
+// so that a restarted node resumes an interrupted download of the same state.
 func (dl *APIInitializer) Initialize(ctx context.Context, d db.Database) error {
-	origin, err := d.OriginCheckpointBlockRoot(ctx)
-	if err == nil && origin != params.BeaconConfig().ZeroHash {
-		log.Warnf("Origin checkpoint root %#x found in db, ignoring checkpoint sync flags", origin)
+	exists, err := originExists(ctx, d)
+	if err != nil {
+		return err
+	}
+	if exists {
 		return nil
-	} else {
-		if !errors.Is(err, db.ErrNotFound) {
-			return errors.Wrap(err, "error while checking database for origin root")
-		}
 	}
-	od, err := beacon.DownloadFinalizedData(ctx, dl.c)
+	od, err := beacon.DownloadOriginDataToDir(ctx, dl.c, beacon.IdFinalized, d.DatabasePath())
 	if err != nil {
 		return errors.Wrap(err, "Error retrieving checkpoint origin state and block")
 	}
 	return d.SaveOrigin(ctx, od.StateBytes(), od.BlockBytes())
 }
+
+// originExists checks whether the database was already initialized from a checkpoint, in which case the
+// checkpoint sync flags are ignored.
+func originExists(ctx context.Context, d db.Database) (bool, error) {
+	origin, err := d.OriginCheckpointBlockRoot(ctx)
+	if err == nil && origin != params.BeaconConfig().ZeroHash {
+		log.Warnf("Origin checkpoint root %#x found in db, ignoring checkpoint sync flags", origin)
+		return true, nil
+	}
+	if !errors.Is(err, db.ErrNotFound) {
+		return false, errors.Wrap(err, "error while checking database for origin root")
+	}
+	return false, nil
+}
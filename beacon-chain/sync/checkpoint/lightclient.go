@@ -0,0 +1,152 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	"github.com/sirupsen/logrus"
+)
+
+var errLightClientStateMismatch = errors.New("checkpoint state and block do not match the light client finalized header")
+
+// LightClientInitializer manages initializing the beacon node using checkpoint sync, following the light client
+// sync protocol from a trusted block root to the latest finalized header, and only trusting the remote beacon node
+// api for the origin state and block which match that header.
+type LightClientInitializer struct {
+	c           *beacon.Client
+	trustedRoot [32]byte
+}
+
+// NewLightClientInitializer creates a LightClientInitializer, handling the set up of a beacon node api client
+// using the provided host string.
+func NewLightClientInitializer(beaconNodeHost string, trustedRoot [32]byte) (*LightClientInitializer, error) {
+	c, err := beacon.NewClient(beaconNodeHost)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", beaconNodeHost)
+	}
+	return &LightClientInitializer{c: c, trustedRoot: trustedRoot}, nil
+}
+
+// Initialize verifies the light client chain from the trusted root to the latest finality update, downloads the
+// origin state and block of the finalized header it reached and initializes database records to prepare the node
+// to begin syncing from that point.
+func (li *LightClientInitializer) Initialize(ctx context.Context, d db.Database) error {
+	exists, err := originExists(ctx, d)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	gvr, err := li.genesisValidatorsRoot(ctx)
+	if err != nil {
+		return err
+	}
+	store, err := li.bootstrap(ctx)
+	if err != nil {
+		return err
+	}
+	if err := li.followUpdates(ctx, store, gvr); err != nil {
+		return err
+	}
+
+	header := store.FinalizedHeader
+	blockRoot, err := header.HashTreeRoot()
+	if err != nil {
+		return errors.Wrap(err, "could not compute finalized header root")
+	}
+	stateRoot := bytesutil.ToBytes32(header.StateRoot)
+	log.WithFields(logrus.Fields{
+		"slot":      header.Slot,
+		"blockRoot": fmt.Sprintf("%#x", blockRoot),
+		"stateRoot": fmt.Sprintf("%#x", stateRoot),
+	}).Info("Light client reached finalized header, downloading checkpoint state")
+	od, err := beacon.DownloadOriginDataToDir(ctx, li.c, beacon.IdFromRoot(stateRoot), d.DatabasePath())
+	if err != nil {
+		return errors.Wrap(err, "error retrieving checkpoint origin state and block")
+	}
+	if od.StateRoot() != stateRoot || od.BlockRoot() != blockRoot {
+		return errors.Wrapf(errLightClientStateMismatch, "state root %#x, block root %#x", od.StateRoot(), od.BlockRoot())
+	}
+	return d.SaveOrigin(ctx, od.StateBytes(), od.BlockBytes())
+}
+
+func (li *LightClientInitializer) genesisValidatorsRoot(ctx context.Context) ([]byte, error) {
+	gvr := params.BeaconConfig().GenesisValidatorsRoot
+	if gvr != params.BeaconConfig().ZeroHash {
+		return gvr[:], nil
+	}
+	// The signatures are verified against the sync committee of the trusted root, so a remote genesis validators
+	// root can not be used to forge updates.
+	g, err := li.c.GetGenesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := hexutil.Decode(g.GenesisValidatorsRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode genesis validators root")
+	}
+	return r, nil
+}
+
+func (li *LightClientInitializer) bootstrap(ctx context.Context) (*lightclient.Store, error) {
+	resp, err := li.c.GetLightClientBootstrap(ctx, li.trustedRoot)
+	if err != nil {
+		return nil, err
+	}
+	return beacon.LightClientStoreFromBootstrap(li.trustedRoot, resp.Data)
+}
+
+// followUpdates applies the light client updates of every sync committee period since the store's finalized
+// header, followed by the latest finality update.
+func (li *LightClientInitializer) followUpdates(ctx context.Context, store *lightclient.Store, gvr []byte) error {
+	for {
+		period := store.Period()
+		updates, err := li.c.GetLightClientUpdatesByRange(ctx, period, params.BeaconConfig().MaxRequestLightClientUpdates)
+		if err != nil {
+			return err
+		}
+		progressed := false
+		for _, resp := range updates {
+			u, err := beacon.LightClientUpdateFromJSON(resp.Data)
+			if err != nil {
+				return errors.Wrap(err, "could not decode light client update")
+			}
+			changed, err := store.ProcessUpdate(u, gvr)
+			if err != nil {
+				return errors.Wrapf(err, "could not process light client update signed at slot %d", u.SignatureSlot)
+			}
+			progressed = progressed || changed
+		}
+		log.WithFields(logrus.Fields{
+			"period":        store.Period(),
+			"finalizedSlot": store.FinalizedHeader.Slot,
+		}).Info("Processed light client updates")
+		if !progressed || store.Period() == period {
+			break
+		}
+	}
+
+	resp, err := li.c.GetLightClientFinalityUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	u, err := beacon.LightClientFinalityUpdateFromJSON(resp.Data)
+	if err != nil {
+		return errors.Wrap(err, "could not decode light client finality update")
+	}
+	if u.FinalizedHeader == nil || u.FinalizedHeader.Slot <= store.FinalizedHeader.Slot {
+		return nil
+	}
+	if _, err := store.ProcessUpdate(u, gvr); err != nil {
+		return errors.Wrap(err, "could not process light client finality update")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errNoQuorum          = errors.New("checkpoint sync providers did not reach quorum on the finalized checkpoint")
+	errInvalidQuorum     = errors.New("invalid checkpoint sync quorum")
+	errConflictingQuorum = errors.New("checkpoint sync providers reached quorum on different finalized checkpoints")
+	errNoProviderServed  = errors.New("no agreeing checkpoint sync provider served a matching state and block")
+)
+
+// ProviderReport is the finalized checkpoint a single checkpoint sync provider reported.
+type ProviderReport struct {
+	Host      string
+	BlockRoot [32]byte
+	StateRoot [32]byte
+	Err       error
+}
+
+// String renders the report in a human-readable form.
+func (r *ProviderReport) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: error=%v", r.Host, r.Err)
+	}
+	return fmt.Sprintf("%s: block_root=%#x state_root=%#x", r.Host, r.BlockRoot, r.StateRoot)
+}
+
+type checkpointProvider struct {
+	host string
+	c    *beacon.Client
+}
+
+// QuorumInitializer manages initializing the beacon node using checkpoint sync from several remote beacon node
+// apis. The finalized block and state roots are requested from every provider, and the node only starts from a
+// checkpoint that the configured quorum of providers agrees on.
+type QuorumInitializer struct {
+	providers []*checkpointProvider
+	quorum    int
+}
+
+// NewQuorumInitializer creates a QuorumInitializer for the provided beacon node hosts. A quorum of 0 requires
+// a simple majority of the providers to agree.
+func NewQuorumInitializer(hosts []string, quorum int) (*QuorumInitializer, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no checkpoint sync providers specified")
+	}
+	if quorum == 0 {
+		quorum = len(hosts)/2 + 1
+	}
+	if quorum < 0 || quorum > len(hosts) {
+		return nil, errors.Wrapf(errInvalidQuorum, "quorum of %d with %d providers", quorum, len(hosts))
+	}
+	providers := make([]*checkpointProvider, 0, len(hosts))
+	for _, h := range hosts {
+		c, err := beacon.NewClient(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", h)
+		}
+		providers = append(providers, &checkpointProvider{host: h, c: c})
+	}
+	return &QuorumInitializer{providers: providers, quorum: quorum}, nil
+}
+
+// Initialize asks every provider for its finalized checkpoint, downloads the origin state and block agreed on by
+// the quorum and initializes database records to prepare the node to begin syncing from that point.
+func (qi *QuorumInitializer) Initialize(ctx context.Context, d db.Database) error {
+	exists, err := originExists(ctx, d)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	reports := qi.collectReports(ctx)
+	for _, r := range reports {
+		log.Info(r.String())
+	}
+	agreed, err := tallyReports(reports, qi.quorum)
+	if err != nil {
+		return err
+	}
+	log.WithFields(logrus.Fields{
+		"blockRoot": fmt.Sprintf("%#x", agreed.BlockRoot),
+		"stateRoot": fmt.Sprintf("%#x", agreed.StateRoot),
+		"quorum":    qi.quorum,
+		"providers": len(qi.providers),
+	}).Info("Checkpoint sync providers reached quorum on the finalized checkpoint")
+
+	for i, r := range reports {
+		if r.Err != nil || r.BlockRoot != agreed.BlockRoot || r.StateRoot != agreed.StateRoot {
+			continue
+		}
+		od, err := beacon.DownloadOriginDataToDir(ctx, qi.providers[i].c, beacon.IdFromRoot(agreed.StateRoot), d.DatabasePath())
+		if err != nil {
+			log.WithError(err).WithField("provider", r.Host).Error("Could not download checkpoint state and block")
+			continue
+		}
+		if od.StateRoot() != agreed.StateRoot || od.BlockRoot() != agreed.BlockRoot {
+			log.WithFields(logrus.Fields{
+				"provider":  r.Host,
+				"blockRoot": fmt.Sprintf("%#x", od.BlockRoot()),
+				"stateRoot": fmt.Sprintf("%#x", od.StateRoot()),
+			}).Error("Downloaded checkpoint state and block do not match the agreed roots")
+			continue
+		}
+		return d.SaveOrigin(ctx, od.StateBytes(), od.BlockBytes())
+	}
+	return errNoProviderServed
+}
+
+// collectReports concurrently requests the finalized block and state roots from every provider.
+func (qi *QuorumInitializer) collectReports(ctx context.Context) []*ProviderReport {
+	reports := make([]*ProviderReport, len(qi.providers))
+	var wg sync.WaitGroup
+	for i, p := range qi.providers {
+		wg.Add(1)
+		go func(i int, p *checkpointProvider) {
+			defer wg.Done()
+			r := &ProviderReport{Host: p.host}
+			r.BlockRoot, r.Err = p.c.GetBlockRoot(ctx, beacon.IdFinalized)
+			if r.Err == nil {
+				r.StateRoot, r.Err = p.c.GetStateRoot(ctx, beacon.IdFinalized)
+			}
+			reports[i] = r
+		}(i, p)
+	}
+	wg.Wait()
+	return reports
+}
+
+// tallyReports returns the checkpoint reported by at least quorum providers. An error listing what every
+// provider reported is returned if no checkpoint reaches the quorum, or if several do, which a quorum of at most
+// half of the providers allows.
+func tallyReports(reports []*ProviderReport, quorum int) (*ProviderReport, error) {
+	type checkpointKey struct {
+		blockRoot [32]byte
+		stateRoot [32]byte
+	}
+	votes := make(map[checkpointKey]int)
+	var best *ProviderReport
+	bestVotes := 0
+	var reached []*ProviderReport
+	for _, r := range reports {
+		if r.Err != nil {
+			continue
+		}
+		k := checkpointKey{blockRoot: r.BlockRoot, stateRoot: r.StateRoot}
+		votes[k]++
+		if votes[k] > bestVotes {
+			best, bestVotes = r, votes[k]
+		}
+		if votes[k] == quorum {
+			reached = append(reached, r)
+		}
+	}
+	if best == nil || bestVotes < quorum {
+		return nil, errors.Wrapf(errNoQuorum, "best agreement %d/%d, required %d:\n%s",
+			bestVotes, len(reports), quorum, reportLines(reports))
+	}
+	if len(reached) > 1 {
+		return nil, errors.Wrapf(errConflictingQuorum, "%d checkpoints reached the quorum of %d:\n%s",
+			len(reached), quorum, reportLines(reports))
+	}
+	return best, nil
+}
+
+func reportLines(reports []*ProviderReport) string {
+	lines := make([]string, 0, len(reports))
+	for _, r := range reports {
+		lines = append(lines, r.String())
+	}
+	return strings.Join(lines, "\n")
+}
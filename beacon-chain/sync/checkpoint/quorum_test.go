@@ -0,0 +1,188 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	dbtest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	blocktest "github.com/prysmaticlabs/prysm/v5/consensus-types/blocks/testing"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+type testCheckpoint struct {
+	stateBytes []byte
+	blockBytes []byte
+	stateRoot  [32]byte
+	blockRoot  [32]byte
+	blockSlot  string
+}
+
+func newTestCheckpoint(t *testing.T) *testCheckpoint {
+	ctx := context.Background()
+	cfg := params.BeaconConfig()
+	slot, err := slots.EpochStart(cfg.AltairForkEpoch - 1)
+	require.NoError(t, err)
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	require.NoError(t, st.SetFork(&ethpb.Fork{
+		PreviousVersion: cfg.GenesisForkVersion,
+		CurrentVersion:  cfg.GenesisForkVersion,
+	}))
+	require.NoError(t, st.SetSlot(slot))
+
+	b, err := blocks.NewSignedBeaconBlock(util.NewBeaconBlock())
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockSlot(b, slot)
+	require.NoError(t, err)
+	header, err := b.Header()
+	require.NoError(t, err)
+	require.NoError(t, st.SetLatestBlockHeader(header.Header))
+	sr, err := st.HashTreeRoot(ctx)
+	require.NoError(t, err)
+	b, err = blocktest.SetBlockStateRoot(b, sr)
+	require.NoError(t, err)
+	br, err := b.Block().HashTreeRoot()
+	require.NoError(t, err)
+
+	sb, err := st.MarshalSSZ()
+	require.NoError(t, err)
+	bb, err := b.MarshalSSZ()
+	require.NoError(t, err)
+	return &testCheckpoint{
+		stateBytes: sb,
+		blockBytes: bb,
+		stateRoot:  sr,
+		blockRoot:  br,
+		blockSlot:  fmt.Sprintf("%d", slot),
+	}
+}
+
+// newTestProvider serves the finalized roots reported by the provider and the checkpoint data.
+func newTestProvider(t *testing.T, cp *testCheckpoint, blockRoot, stateRoot [32]byte) *httptest.Server {
+	writeRoot := func(w http.ResponseWriter, root [32]byte) {
+		resp := &structs.BlockRootResponse{Data: &structs.BlockRoot{Root: fmt.Sprintf("%#x", root)}}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/beacon/blocks/finalized/root", func(w http.ResponseWriter, _ *http.Request) {
+		writeRoot(w, blockRoot)
+	})
+	mux.HandleFunc("/eth/v1/beacon/states/finalized/root", func(w http.ResponseWriter, _ *http.Request) {
+		writeRoot(w, stateRoot)
+	})
+	mux.HandleFunc(fmt.Sprintf("/eth/v2/debug/beacon/states/%#x", cp.stateRoot), func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write(cp.stateBytes)
+		require.NoError(t, err)
+	})
+	mux.HandleFunc("/eth/v2/beacon/blocks/"+cp.blockSlot, func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write(cp.blockBytes)
+		require.NoError(t, err)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewQuorumInitializer(t *testing.T) {
+	hosts := []string{"http://localhost:3500", "http://localhost:3501", "http://localhost:3502"}
+
+	qi, err := NewQuorumInitializer(hosts, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, qi.quorum)
+	assert.Equal(t, 3, len(qi.providers))
+
+	qi, err = NewQuorumInitializer(hosts, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, qi.quorum)
+
+	_, err = NewQuorumInitializer(hosts, 4)
+	require.ErrorIs(t, err, errInvalidQuorum)
+	_, err = NewQuorumInitializer(hosts, -1)
+	require.ErrorIs(t, err, errInvalidQuorum)
+	_, err = NewQuorumInitializer(nil, 0)
+	require.ErrorContains(t, "no checkpoint sync providers", err)
+}
+
+func TestTallyReports(t *testing.T) {
+	a := &ProviderReport{Host: "a", BlockRoot: [32]byte{1}, StateRoot: [32]byte{2}}
+	b := &ProviderReport{Host: "b", BlockRoot: [32]byte{1}, StateRoot: [32]byte{2}}
+	c := &ProviderReport{Host: "c", BlockRoot: [32]byte{3}, StateRoot: [32]byte{4}}
+	d := &ProviderReport{Host: "d", Err: errors.New("connection refused")}
+
+	agreed, err := tallyReports([]*ProviderReport{c, a, b, d}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, a.BlockRoot, agreed.BlockRoot)
+	assert.Equal(t, a.StateRoot, agreed.StateRoot)
+
+	// Same block root with a different state root is a disagreement.
+	e := &ProviderReport{Host: "e", BlockRoot: [32]byte{1}, StateRoot: [32]byte{5}}
+	_, err = tallyReports([]*ProviderReport{a, e, d}, 2)
+	require.ErrorIs(t, err, errNoQuorum)
+	// The report names what every provider said.
+	require.ErrorContains(t, "a: block_root=0x01", err)
+	require.ErrorContains(t, "e: block_root=0x01", err)
+	require.ErrorContains(t, "d: error=connection refused", err)
+
+	_, err = tallyReports([]*ProviderReport{d}, 1)
+	require.ErrorIs(t, err, errNoQuorum)
+
+	// With a quorum of at most half of the providers, two checkpoints with as many votes can both reach it.
+	f := &ProviderReport{Host: "f", BlockRoot: [32]byte{3}, StateRoot: [32]byte{4}}
+	_, err = tallyReports([]*ProviderReport{a, b, c, f}, 2)
+	require.ErrorIs(t, err, errConflictingQuorum)
+	require.ErrorContains(t, "a: block_root=0x01", err)
+	require.ErrorContains(t, "f: block_root=0x03", err)
+	// The checkpoint with the most votes is not trusted either when another one reached the quorum.
+	g := &ProviderReport{Host: "g", BlockRoot: [32]byte{1}, StateRoot: [32]byte{2}}
+	_, err = tallyReports([]*ProviderReport{a, b, g, c, f}, 2)
+	require.ErrorIs(t, err, errConflictingQuorum)
+	agreed, err = tallyReports([]*ProviderReport{a, b, g, c, f}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, a.BlockRoot, agreed.BlockRoot)
+}
+
+func TestQuorumInitializer_Initialize(t *testing.T) {
+	ctx := context.Background()
+	cp := newTestCheckpoint(t)
+	honest1 := newTestProvider(t, cp, cp.blockRoot, cp.stateRoot)
+	honest2 := newTestProvider(t, cp, cp.blockRoot, cp.stateRoot)
+	dishonest := newTestProvider(t, cp, [32]byte{'a'}, [32]byte{'b'})
+
+	t.Run("no quorum", func(t *testing.T) {
+		d := dbtest.SetupDB(t)
+		qi, err := NewQuorumInitializer([]string{honest1.URL, honest2.URL, dishonest.URL}, 3)
+		require.NoError(t, err)
+		require.ErrorIs(t, qi.Initialize(ctx, d), errNoQuorum)
+		_, err = d.OriginCheckpointBlockRoot(ctx)
+		require.NotNil(t, err)
+	})
+	t.Run("quorum reached", func(t *testing.T) {
+		d := dbtest.SetupDB(t)
+		qi, err := NewQuorumInitializer([]string{dishonest.URL, honest1.URL, honest2.URL}, 2)
+		require.NoError(t, err)
+		require.NoError(t, qi.Initialize(ctx, d))
+		origin, err := d.OriginCheckpointBlockRoot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, cp.blockRoot, origin)
+	})
+	t.Run("agreed state not served", func(t *testing.T) {
+		d := dbtest.SetupDB(t)
+		lying1 := newTestProvider(t, cp, cp.blockRoot, [32]byte{'c'})
+		lying2 := newTestProvider(t, cp, cp.blockRoot, [32]byte{'c'})
+		qi, err := NewQuorumInitializer([]string{lying1.URL, lying2.URL}, 2)
+		require.NoError(t, err)
+		require.ErrorIs(t, qi.Initialize(ctx, d), errNoProviderServed)
+	})
+}
@@ -78,6 +78,7 @@ type blocksFetcherConfig struct {
 	peerFilterCapacityWeight float64
 	mode                     syncMode
 	bs                       filesystem.BlobStorageSummarizer
+	peerWeight               peerWeightFunc
 }
 
 // blocksFetcher is a service to fetch chain data from peers.
@@ -99,9 +100,10 @@ type blocksFetcher struct {
 	peerLocks       map[peer.ID]*peerLock
 	fetchRequests   chan *fetchRequestParams
 	fetchResponses  chan *fetchRequestResponse
-	capacityWeight  float64       // how remaining capacity affects peer selection
-	mode            syncMode      // allows to use fetcher in different sync scenarios
-	quit            chan struct{} // termination notifier
+	capacityWeight  float64           // how remaining capacity affects peer selection
+	mode            syncMode          // allows to use fetcher in different sync scenarios
+	candidates      *candidateTracker // chains followed by peers above the finalized checkpoint
+	quit            chan struct{}     // termination notifier
 }
 
 // peerLock restricts fetcher actions on per peer basis. Currently, used for rate limiting.
@@ -125,6 +127,8 @@ type fetchRequestResponse struct {
 	count uint64
 	bwb   []blocks2.BlockWithROBlobs
 	err   error
+	// switches is the number of candidate chain switches when the peer was selected.
+	switches uint64
 }
 
 // newBlocksFetcher creates ready to use fetcher.
@@ -143,7 +147,7 @@ func newBlocksFetcher(ctx context.Context, cfg *blocksFetcherConfig) *blocksFetc
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
-	return &blocksFetcher{
+	f := &blocksFetcher{
 		ctx:             ctx,
 		cancel:          cancel,
 		rand:            rand.NewGenerator(),
@@ -162,6 +166,14 @@ func newBlocksFetcher(ctx context.Context, cfg *blocksFetcherConfig) *blocksFetc
 		mode:            cfg.mode,
 		quit:            make(chan struct{}),
 	}
+	peerWeight := cfg.peerWeight
+	if peerWeight == nil {
+		peerWeight = defaultPeerWeight(func(pid peer.ID) float64 {
+			return f.p2p.Peers().Scorers().Score(pid)
+		})
+	}
+	f.candidates = newCandidateTracker(peerWeight)
+	return f
 }
 
 // This specifies the block batch limit the initial sync fetcher will use. In the event the user has provided
@@ -314,8 +326,11 @@ func (f *blocksFetcher) handleRequest(ctx context.Context, start primitives.Slot
 		}
 	}
 
+	peers = f.candidatePeers(start, peers)
+	response.switches = f.candidates.switchCount()
 	response.bwb, response.pid, response.err = f.fetchBlocksFromPeer(ctx, start, count, peers)
 	if response.err == nil {
+		f.candidates.observe(response.pid, response.bwb)
 		bwb, err := f.fetchBlobsFromPeer(ctx, response.bwb, response.pid, peers)
 		if err != nil {
 			response.err = err
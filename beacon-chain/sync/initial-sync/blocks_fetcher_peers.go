@@ -10,8 +10,10 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers/scorers"
 	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	mathutil "github.com/prysmaticlabs/prysm/v5/math"
 	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	prysmTime "github.com/prysmaticlabs/prysm/v5/time"
 	"github.com/prysmaticlabs/prysm/v5/time/slots"
 	"github.com/sirupsen/logrus"
@@ -136,3 +138,48 @@ func trimPeers(peers []peer.ID, peersPercentage float64) []peer.ID {
 	limit = mathutil.Min(limit, uint64(len(peers)))
 	return peers[:limit]
 }
+
+// updateCandidates refreshes the chains followed by connected peers above the finalized checkpoint, and returns
+// the chain selected for syncing, if any.
+func (f *blocksFetcher) updateCandidates() *candidateChain {
+	statuses := make(map[peer.ID]*ethpb.Status)
+	for _, pid := range f.p2p.Peers().Connected() {
+		if f.p2p.Peers().IsBad(pid) {
+			continue
+		}
+		st, err := f.p2p.Peers().ChainState(pid)
+		if err != nil || st == nil {
+			continue
+		}
+		statuses[pid] = st
+	}
+	_, selected := f.candidates.update(statuses, f.chain.FinalizedCheckpt().Epoch, f.chain.HeadSlot())
+	return selected
+}
+
+// candidatePeers replaces the peers used to fetch blocks starting at the given slot with the peers following the
+// selected candidate chain, which may advertise a lower head than the best peers. Blocks up to the finalized
+// checkpoint are the same on every chain, so requests for them use the given peers.
+func (f *blocksFetcher) candidatePeers(start primitives.Slot, peers []peer.ID) []peer.ID {
+	if f.mode != modeNonConstrained {
+		return peers
+	}
+	finalizedSlot, err := slots.EpochStart(f.chain.FinalizedCheckpt().Epoch)
+	if err != nil || start <= finalizedSlot {
+		return peers
+	}
+	selected := f.updateCandidates()
+	if selected == nil || len(selected.peers) == 0 {
+		return peers
+	}
+	return append([]peer.ID{}, selected.peers...)
+}
+
+// isOffSelectedChain checks whether the peer follows another candidate chain than the selected one.
+func (f *blocksFetcher) isOffSelectedChain(pid peer.ID) bool {
+	selected := f.candidates.selectedChain()
+	if selected == nil || selected.hasPeer(pid) {
+		return false
+	}
+	return f.candidates.isCandidatePeer(pid)
+}
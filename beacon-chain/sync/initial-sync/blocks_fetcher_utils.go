@@ -3,6 +3,7 @@ package initialsync
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
@@ -180,6 +181,12 @@ func (f *blocksFetcher) findFork(ctx context.Context, slot primitives.Slot) (*fo
 	f.rand.Shuffle(len(peers), func(i, j int) {
 		peers[i], peers[j] = peers[j], peers[i]
 	})
+	// Query peers of the selected candidate chain first, so that backtracking favours the heaviest chain.
+	if selected := f.updateCandidates(); selected != nil {
+		sort.SliceStable(peers, func(i, j int) bool {
+			return selected.hasPeer(peers[i]) && !selected.hasPeer(peers[j])
+		})
+	}
 
 	// Query all found peers, stop on peer with alternative blocks, and try backtracking.
 	for i, pid := range peers {
@@ -344,6 +344,7 @@ func (q *blocksQueue) onDataReceivedEvent(ctx context.Context) eventHandlerFn {
 		}
 		m.pid = response.pid
 		m.bwb = response.bwb
+		m.switches = response.switches
 		return stateDataParsed, nil
 	}
 }
@@ -362,6 +363,17 @@ func (q *blocksQueue) onReadyToSendEvent(ctx context.Context) eventHandlerFn {
 			return stateSkipped, nil
 		}
 
+		// Initial sync may have switched to a heavier chain since the data was fetched, re-fetch from its peers.
+		if q.mode == modeNonConstrained && m.switches != q.blocksFetcher.candidates.switchCount() &&
+			q.blocksFetcher.isOffSelectedChain(m.pid) {
+			log.WithFields(logrus.Fields{
+				"start": m.start,
+				"peer":  m.pid,
+			}).Debug("Dropping blocks fetched from a peer off the selected chain")
+			m.bwb = nil
+			return stateNew, nil
+		}
+
 		send := func() (stateID, error) {
 			data := &blocksQueueFetchedData{
 				pid: m.pid,
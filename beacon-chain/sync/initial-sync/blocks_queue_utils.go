@@ -26,6 +26,7 @@ func (q *blocksQueue) resetFromFork(fork *forkData) error {
 	fsm := q.smm.addStateMachine(firstBlock.Slot())
 	fsm.pid = fork.peer
 	fsm.bwb = fork.bwb
+	fsm.switches = q.blocksFetcher.candidates.switchCount()
 	fsm.state = stateDataParsed
 
 	// The rest of machines are in skipped state.
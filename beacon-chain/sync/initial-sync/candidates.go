@@ -0,0 +1,241 @@
+package initialsync
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// candidateSwitchThreshold is how much heavier another candidate chain must be, relative to the
+	// selected one, before initial sync switches to it. Prevents thrashing between chains of similar weight.
+	candidateSwitchThreshold = 1.25
+	// peerWeightUnit is the stake weight of a single peer with a neutral score.
+	peerWeightUnit = 100
+)
+
+// peerWeightFunc returns the stake weight a peer lends to the chain it advertises.
+type peerWeightFunc func(pid peer.ID) uint64
+
+// candidateChain is a chain above the finalized checkpoint, followed by a set of peers.
+type candidateChain struct {
+	headRoot [32]byte
+	headSlot primitives.Slot
+	peers    []peer.ID
+	weight   uint64
+}
+
+func (c *candidateChain) String() string {
+	return fmt.Sprintf("%#x@%d", c.headRoot, c.headSlot)
+}
+
+// hasPeer checks whether the peer follows the chain.
+func (c *candidateChain) hasPeer(pid peer.ID) bool {
+	for _, p := range c.peers {
+		if p == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateTracker follows the chains advertised by peers above the finalized checkpoint, and selects the one
+// initial sync should follow, by the stake weight of the peers on each chain. Peers on the same chain may advertise
+// different heads, so heads found to be ancestors of another head, either because a peer moved from one to the
+// other or because the head was among the blocks a peer served, are merged into the descendant chain.
+type candidateTracker struct {
+	sync.Mutex
+	weight    peerWeightFunc
+	finalized primitives.Epoch
+	lastHead  map[peer.ID][32]byte
+	ancestors map[[32]byte][32]byte
+	selected  *candidateChain
+	switches  uint64
+}
+
+func newCandidateTracker(weight peerWeightFunc) *candidateTracker {
+	return &candidateTracker{
+		weight:    weight,
+		lastHead:  make(map[peer.ID][32]byte),
+		ancestors: make(map[[32]byte][32]byte),
+	}
+}
+
+// resolve returns the most recent known descendant head of the given head root.
+func (t *candidateTracker) resolve(root [32]byte) [32]byte {
+	// Bound the walk in case descendant links form a cycle after conflicting observations.
+	for i := 0; i <= len(t.ancestors); i++ {
+		next, ok := t.ancestors[root]
+		if !ok || next == root {
+			return root
+		}
+		root = next
+	}
+	return root
+}
+
+// link records that the head ancestor is on the chain of the head descendant.
+func (t *candidateTracker) link(ancestor, descendant [32]byte) {
+	descendant = t.resolve(descendant)
+	if t.resolve(ancestor) == descendant {
+		return
+	}
+	t.ancestors[ancestor] = descendant
+}
+
+// update groups the given peers by the chain they follow, and selects the chain to sync. Peers which are behind
+// the finalized epoch or do not advertise a head above headSlot are ignored. It returns the candidate chains,
+// heaviest first, and the selected chain, which is nil if there are no candidates.
+func (t *candidateTracker) update(
+	statuses map[peer.ID]*ethpb.Status, finalized primitives.Epoch, headSlot primitives.Slot,
+) ([]*candidateChain, *candidateChain) {
+	t.Lock()
+	defer t.Unlock()
+
+	if finalized > t.finalized {
+		// Chains diverging before the new finalized checkpoint have been pruned, start over.
+		t.finalized = finalized
+		t.ancestors = make(map[[32]byte][32]byte)
+	}
+
+	byHead := make(map[[32]byte]*candidateChain)
+	pids := make([]peer.ID, 0, len(statuses))
+	for pid := range statuses {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	lastHead := make(map[peer.ID][32]byte, len(statuses))
+	for _, pid := range pids {
+		st := statuses[pid]
+		if st == nil || st.FinalizedEpoch < finalized || st.HeadSlot <= headSlot {
+			continue
+		}
+		head := bytesutil.ToBytes32(st.HeadRoot)
+		if last, ok := t.lastHead[pid]; ok && last != head {
+			// Assume the peer extended the chain it was following.
+			t.link(last, head)
+		}
+		lastHead[pid] = head
+	}
+	t.lastHead = lastHead
+	for _, pid := range pids {
+		head, ok := lastHead[pid]
+		if !ok {
+			continue
+		}
+		head = t.resolve(head)
+		c, ok := byHead[head]
+		if !ok {
+			c = &candidateChain{headRoot: head}
+			byHead[head] = c
+		}
+		if headSlot := statuses[pid].HeadSlot; headSlot > c.headSlot {
+			c.headSlot = headSlot
+		}
+		c.peers = append(c.peers, pid)
+		c.weight += t.weight(pid)
+	}
+
+	candidates := make([]*candidateChain, 0, len(byHead))
+	for _, c := range byHead {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].weight != candidates[j].weight {
+			return candidates[i].weight > candidates[j].weight
+		}
+		if candidates[i].headSlot != candidates[j].headSlot {
+			return candidates[i].headSlot > candidates[j].headSlot
+		}
+		return bytes.Compare(candidates[i].headRoot[:], candidates[j].headRoot[:]) < 0
+	})
+	if len(candidates) == 0 {
+		t.selected = nil
+		return candidates, nil
+	}
+
+	best := candidates[0]
+	var current *candidateChain
+	if t.selected != nil {
+		current = byHead[t.resolve(t.selected.headRoot)]
+	}
+	switch {
+	case current == nil:
+		if t.selected != nil {
+			t.switches++
+		}
+		t.selected = best
+	case current != best && float64(best.weight) > float64(current.weight)*candidateSwitchThreshold:
+		log.WithFields(logrus.Fields{
+			"from":       current.String(),
+			"fromWeight": current.weight,
+			"to":         best.String(),
+			"toWeight":   best.weight,
+		}).Info("Switching initial sync to a heavier chain")
+		t.switches++
+		t.selected = best
+	default:
+		t.selected = current
+	}
+	return candidates, t.selected
+}
+
+// observe merges the chains advertised by other peers into the chain of the peer which served the given blocks,
+// when their heads are among those blocks.
+func (t *candidateTracker) observe(pid peer.ID, bwb []blocks.BlockWithROBlobs) {
+	t.Lock()
+	defer t.Unlock()
+	head, ok := t.lastHead[pid]
+	if !ok {
+		return
+	}
+	heads := make(map[[32]byte]bool, len(t.lastHead))
+	for _, h := range t.lastHead {
+		heads[h] = true
+	}
+	for _, b := range bwb {
+		if r := b.Block.Root(); r != head && heads[r] {
+			t.link(r, head)
+		}
+	}
+}
+
+// isCandidatePeer checks whether the peer advertised a head above ours in the last update.
+func (t *candidateTracker) isCandidatePeer(pid peer.ID) bool {
+	t.Lock()
+	defer t.Unlock()
+	_, ok := t.lastHead[pid]
+	return ok
+}
+
+// selectedChain returns the chain selected by the last update, or nil.
+func (t *candidateTracker) selectedChain() *candidateChain {
+	t.Lock()
+	defer t.Unlock()
+	return t.selected
+}
+
+// switchCount returns how many times the selected chain changed.
+func (t *candidateTracker) switchCount() uint64 {
+	t.Lock()
+	defer t.Unlock()
+	return t.switches
+}
+
+// defaultPeerWeight weighs peers by their score. Peers do not reveal the stake behind them, so every peer carries
+// the same base weight, which good behaviour increases and bad behaviour decreases.
+func defaultPeerWeight(score func(peer.ID) float64) peerWeightFunc {
+	return func(pid peer.ID) uint64 {
+		s := math.Max(-1, math.Min(1, score(pid)))
+		return uint64(math.Round(peerWeightUnit * (1 + s)))
+	}
+}
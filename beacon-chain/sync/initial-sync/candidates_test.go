@@ -0,0 +1,207 @@
+package initialsync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	dbtest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	p2pt "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	leakybucket "github.com/prysmaticlabs/prysm/v5/container/leaky-bucket"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	eth "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func testStatus(head string, headSlot primitives.Slot) *eth.Status {
+	return &eth.Status{
+		HeadRoot: bytesutil.PadTo([]byte(head), 32),
+		HeadSlot: headSlot,
+	}
+}
+
+func TestCandidateTracker_Update(t *testing.T) {
+	stakes := map[peer.ID]uint64{"a1": 10, "a2": 10, "b1": 32, "behind": 100}
+	tracker := newCandidateTracker(func(pid peer.ID) uint64 { return stakes[pid] })
+	statuses := map[peer.ID]*eth.Status{
+		"a1":     testStatus("a", 300),
+		"a2":     testStatus("a", 300),
+		"b1":     testStatus("b", 280),
+		"behind": testStatus("c", 100),
+	}
+
+	candidates, selected := tracker.update(statuses, 0, 160)
+	require.Equal(t, 2, len(candidates))
+	assert.Equal(t, bytesutil.ToBytes32(bytesutil.PadTo([]byte("b"), 32)), selected.headRoot)
+	assert.Equal(t, uint64(32), selected.weight)
+	assert.Equal(t, uint64(20), candidates[1].weight)
+	assert.Equal(t, false, tracker.isCandidatePeer("behind"))
+
+	// A chain slightly heavier than the selected one does not trigger a switch.
+	statuses["a3"] = testStatus("a", 300)
+	stakes["a3"] = 15
+	_, selected = tracker.update(statuses, 0, 160)
+	assert.Equal(t, bytesutil.ToBytes32(bytesutil.PadTo([]byte("b"), 32)), selected.headRoot)
+	assert.Equal(t, uint64(0), tracker.switchCount())
+
+	// A clearly heavier chain does.
+	stakes["a3"] = 25
+	_, selected = tracker.update(statuses, 0, 160)
+	assert.Equal(t, bytesutil.ToBytes32(bytesutil.PadTo([]byte("a"), 32)), selected.headRoot)
+	assert.Equal(t, uint64(45), selected.weight)
+	assert.Equal(t, uint64(1), tracker.switchCount())
+
+	// Peers moving to a new head of the selected chain stay on it.
+	statuses["a1"] = testStatus("a'", 301)
+	candidates, selected = tracker.update(statuses, 0, 160)
+	require.Equal(t, 2, len(candidates))
+	assert.Equal(t, uint64(45), selected.weight)
+	assert.Equal(t, primitives.Slot(301), selected.headSlot)
+	assert.Equal(t, true, selected.hasPeer("a1"))
+	assert.Equal(t, uint64(1), tracker.switchCount())
+
+	// Once past every advertised head, there is nothing left to select.
+	candidates, selected = tracker.update(statuses, 0, 301)
+	assert.Equal(t, 0, len(candidates))
+	assert.Equal(t, true, selected == nil)
+}
+
+func TestCandidateTracker_Observe(t *testing.T) {
+	chain := extendBlockSequence(t, []*eth.SignedBeaconBlock{}, 10)
+	roots := make([][32]byte, len(chain))
+	bwb := make([]blocks.BlockWithROBlobs, len(chain))
+	for i, blk := range chain {
+		wsb, err := blocks.NewSignedBeaconBlock(blk)
+		require.NoError(t, err)
+		rob, err := blocks.NewROBlock(wsb)
+		require.NoError(t, err)
+		roots[i] = rob.Root()
+		bwb[i] = blocks.BlockWithROBlobs{Block: rob}
+	}
+
+	tracker := newCandidateTracker(func(peer.ID) uint64 { return 1 })
+	statuses := map[peer.ID]*eth.Status{
+		"tip":    {HeadRoot: roots[10][:], HeadSlot: 10},
+		"lagger": {HeadRoot: roots[7][:], HeadSlot: 7},
+	}
+	candidates, _ := tracker.update(statuses, 0, 1)
+	require.Equal(t, 2, len(candidates))
+
+	// The lagging peer's head is among the blocks served by the other peer, so both follow the same chain.
+	tracker.observe("tip", bwb[5:])
+	candidates, selected := tracker.update(statuses, 0, 1)
+	require.Equal(t, 1, len(candidates))
+	assert.Equal(t, roots[10], selected.headRoot)
+	assert.Equal(t, uint64(2), selected.weight)
+}
+
+// TestBlocksFetcher_LongNonFinality simulates a long period without finality, where peers are split between two
+// chains forking well above the finalized checkpoint, and the stake behind them shifts over time.
+func TestBlocksFetcher_LongNonFinality(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	beaconDB := dbtest.SetupDB(t)
+	p2p := p2pt.NewTestP2P(t)
+
+	// Nothing has been finalized since genesis, and the chains fork 5 epochs in.
+	chain1 := extendBlockSequence(t, []*eth.SignedBeaconBlock{}, 320)
+	forkedSlot := primitives.Slot(161)
+	chain2 := extendBlockSequence(t, chain1[:forkedSlot], 150)
+
+	st, err := util.NewBeaconState()
+	require.NoError(t, err)
+	genesisRoot, err := chain1[0].Block.HashTreeRoot()
+	require.NoError(t, err)
+	util.SaveBlock(t, ctx, beaconDB, chain1[0])
+	for _, blk := range chain1[1:forkedSlot] {
+		util.SaveBlock(t, ctx, beaconDB, blk)
+	}
+	require.NoError(t, st.SetSlot(forkedSlot-1))
+	mc := &mock.ChainService{
+		State:               st,
+		Root:                genesisRoot[:],
+		DB:                  beaconDB,
+		FinalizedCheckPoint: &eth.Checkpoint{Epoch: 0},
+		Genesis:             time.Now(),
+		ValidatorsRoot:      [32]byte{},
+	}
+
+	var lock sync.Mutex
+	stakes := make(map[peer.ID]uint64)
+	setStake := func(pid peer.ID, stake uint64) {
+		lock.Lock()
+		defer lock.Unlock()
+		stakes[pid] = stake
+	}
+	fetcher := newBlocksFetcher(ctx, &blocksFetcherConfig{
+		chain: mc,
+		p2p:   p2p,
+		db:    beaconDB,
+		clock: startup.NewClock(mc.Genesis, mc.ValidatorsRoot),
+		peerWeight: func(pid peer.ID) uint64 {
+			lock.Lock()
+			defer lock.Unlock()
+			return stakes[pid]
+		},
+	})
+	fetcher.rateLimiter = leakybucket.NewCollector(6400, 6400, 1*time.Second, false)
+	queue := newBlocksQueue(ctx, &blocksQueueConfig{
+		blocksFetcher:       fetcher,
+		chain:               mc,
+		highestExpectedSlot: primitives.Slot(len(chain2) - 1),
+		mode:                modeNonConstrained,
+	})
+
+	setStake(connectPeerHavingBlocks(t, p2p, chain1, 0, p2p.Peers()), 10)
+	setStake(connectPeerHavingBlocks(t, p2p, chain1, 0, p2p.Peers()), 10)
+	heavyPeer := connectPeerHavingBlocks(t, p2p, chain2, 0, p2p.Peers())
+	setStake(heavyPeer, 32)
+
+	requireChain := func(chain []*eth.SignedBeaconBlock, bwb []blocks.BlockWithROBlobs) {
+		require.NotEqual(t, 0, len(bwb))
+		for _, b := range bwb {
+			r, err := chain[b.Block.Block().Slot()].Block.HashTreeRoot()
+			require.NoError(t, err)
+			require.Equal(t, r, b.Block.Root())
+		}
+	}
+
+	// The heavier chain is followed above the fork.
+	start := forkedSlot
+	res := fetcher.handleRequest(ctx, start, 32)
+	require.NoError(t, res.err)
+	assert.Equal(t, heavyPeer, res.pid)
+	requireChain(chain2, res.bwb)
+	fsm := queue.smm.addStateMachine(start)
+	fsm.setState(stateScheduled)
+	state, err := queue.onDataReceivedEvent(ctx)(fsm, res)
+	require.NoError(t, err)
+	fsm.setState(state)
+
+	// Blocks below the fork are served by everyone.
+	res = fetcher.handleRequest(ctx, 64, 32)
+	require.NoError(t, res.err)
+	requireChain(chain1, res.bwb)
+
+	// Stake moves to the other chain, which is picked without restarting the queue.
+	setStake(connectPeerHavingBlocks(t, p2p, chain1, 0, p2p.Peers()), 25)
+	res = fetcher.handleRequest(ctx, start+32, 32)
+	require.NoError(t, res.err)
+	assert.NotEqual(t, heavyPeer, res.pid)
+	requireChain(chain1, res.bwb)
+	assert.Equal(t, uint64(1), fetcher.candidates.switchCount())
+
+	// Blocks fetched from the previous chain are dropped and re-fetched.
+	state, err = queue.onReadyToSendEvent(ctx)(fsm, nil)
+	require.NoError(t, err)
+	assert.Equal(t, stateNew, state)
+	assert.Equal(t, 0, len(fsm.bwb))
+}
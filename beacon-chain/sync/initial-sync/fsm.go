@@ -48,6 +48,8 @@ type stateMachine struct {
 	pid     peer.ID
 	bwb     []blocks.BlockWithROBlobs
 	updated time.Time
+	// switches is the number of candidate chain switches when the machine's blocks were fetched.
+	switches uint64
 }
 
 // eventHandlerFn is an event handler function's signature.
@@ -154,6 +154,14 @@ var (
 		Name: "gossip_pending_attestations_total",
 		Help: "increased when receiving a new pending attestation",
 	})
+	pendingBlocksBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sync_pending_blocks_bytes",
+		Help: "Total size of the blocks waiting in the pending block queue",
+	})
+	pendingBlocksEvicted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sync_pending_blocks_evicted_total",
+		Help: "Number of pending blocks dropped to stay within the pending queue memory limit",
+	})
 
 	// Sync committee verification performance.
 	syncMessagesForUnknownBlocks = promauto.NewCounter(
@@ -69,9 +69,6 @@ func (s *Service) processPendingBlocks(ctx context.Context) error {
 
 	span.SetAttributes(prysmTrace.Int64Attribute("numSlots", int64(len(sortedSlots))), prysmTrace.Int64Attribute("numPeers", int64(len(s.cfg.p2p.Peers().Connected()))))
 
-	randGen := rand.NewGenerator()
-	var parentRoots [][32]byte
-
 	// Iterate through sorted slots.
 	for _, slot := range sortedSlots {
 		// Skip processing if slot is in the future.
@@ -112,9 +109,6 @@ func (s *Service) processPendingBlocks(ctx context.Context) error {
 				continue
 			}
 
-			parentRoot := b.Block().ParentRoot()
-			inPendingQueue := s.isBlockInQueue(parentRoot)
-
 			// Check if block is bad.
 			keepProcessing, err := s.checkIfBlockIsBad(ctx, span, slot, b, blkRoot)
 			if err != nil {
@@ -124,13 +118,8 @@ func (s *Service) processPendingBlocks(ctx context.Context) error {
 				continue
 			}
 
-			// Request parent block if not in the pending queue and not in the database.
-			isParentBlockInDB := s.cfg.beaconDB.HasBlock(ctx, parentRoot)
-			if !inPendingQueue && !isParentBlockInDB && s.hasPeer() {
-				parentRoots = append(parentRoots, parentRoot)
-				continue
-			}
-			if !isParentBlockInDB {
+			// Wait for the parent, which is either pending as well or requested below.
+			if !s.cfg.beaconDB.HasBlock(ctx, b.Block().ParentRoot()) {
 				continue
 			}
 
@@ -154,7 +143,10 @@ func (s *Service) processPendingBlocks(ctx context.Context) error {
 		}
 		span.End()
 	}
-	return s.sendBatchRootRequest(ctx, parentRoots, randGen)
+	if !s.hasPeer() {
+		return nil
+	}
+	return s.requestMissingParents(ctx, rand.NewGenerator())
 }
 
 // startInnerSpan starts a new tracing span for an inner loop and returns the new context and span.
@@ -181,14 +173,6 @@ func (s *Service) removeBlockFromQueue(b interfaces.ReadOnlySignedBeaconBlock, b
 	return nil
 }
 
-// isBlockInQueue checks if a block's parent root is in the pending queue.
-func (s *Service) isBlockInQueue(parentRoot [32]byte) bool {
-	s.pendingQueueLock.RLock()
-	defer s.pendingQueueLock.RUnlock()
-
-	return s.seenPendingBlocks[parentRoot]
-}
-
 func (s *Service) hasPeer() bool {
 	return len(s.cfg.p2p.Peers().Connected()) > 0
 }
@@ -407,6 +391,9 @@ func (s *Service) clearPendingSlots() {
 	defer s.pendingQueueLock.Unlock()
 	s.slotToPendingBlocks.Flush()
 	s.seenPendingBlocks = make(map[[32]byte]bool)
+	s.pendingBlockMeta = make(map[[32]byte]pendingBlockMeta)
+	s.pendingBlocksSize = 0
+	pendingBlocksBytes.Set(0)
 }
 
 // Delete block from the list from the pending queue using the slot as key.
@@ -441,7 +428,7 @@ func (s *Service) deleteBlockFromPendingQueue(slot primitives.Slot, b interfaces
 	}
 	if len(newBlks) == 0 {
 		s.slotToPendingBlocks.Delete(slotToCacheKey(slot))
-		delete(s.seenPendingBlocks, r)
+		s.forgetPendingBlock(r)
 		return nil
 	}
 
@@ -450,7 +437,7 @@ func (s *Service) deleteBlockFromPendingQueue(slot primitives.Slot, b interfaces
 	if err := s.slotToPendingBlocks.Replace(slotToCacheKey(slot), newBlks, d); err != nil {
 		return err
 	}
-	delete(s.seenPendingBlocks, r)
+	s.forgetPendingBlock(r)
 	return nil
 }
 
@@ -472,12 +459,12 @@ func (s *Service) insertBlockToPendingQueue(_ primitives.Slot, b interfaces.Read
 		return nil
 	}
 
-	if err := s.addPendingBlockToCache(b); err != nil {
+	if err := s.addPendingBlockToCache(b, r); err != nil {
 		return err
 	}
 
 	s.seenPendingBlocks[r] = true
-	return nil
+	return s.evictPendingBlocks()
 }
 
 // This returns signed beacon blocks given input key from slotToPendingBlocks.
@@ -495,7 +482,7 @@ func (s *Service) pendingBlocksInCache(slot primitives.Slot) []interfaces.ReadOn
 }
 
 // This adds input signed beacon block to slotToPendingBlocks cache.
-func (s *Service) addPendingBlockToCache(b interfaces.ReadOnlySignedBeaconBlock) error {
+func (s *Service) addPendingBlockToCache(b interfaces.ReadOnlySignedBeaconBlock, r [32]byte) error {
 	if err := blocks.BeaconBlockIsNil(b); err != nil {
 		return err
 	}
@@ -509,6 +496,7 @@ func (s *Service) addPendingBlockToCache(b interfaces.ReadOnlySignedBeaconBlock)
 	blks = append(blks, b)
 	k := slotToCacheKey(b.Block().Slot())
 	s.slotToPendingBlocks.Set(k, blks, pendingBlockExpTime)
+	s.trackPendingBlock(b, r)
 	return nil
 }
 
@@ -0,0 +1,298 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/crypto/rand"
+	prysmTrace "github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/trailofbits/go-mutexasserts"
+)
+
+// maxPendingBlocksBytes caps the total size of the blocks held in the pending queue. When the queue grows past it,
+// the blocks furthest ahead are dropped first, since the queue cannot make progress without the ancestors.
+var maxPendingBlocksBytes = 256 * 1024 * 1024
+
+// maxParentWalkRounds bounds how many generations of missing ancestors are requested in one pass over the queue.
+const maxParentWalkRounds = 8
+
+// pendingBlockMeta is what the pending queue keeps about a block besides the block itself. It is keyed by the root
+// the block was queued with, so that the pending chains are computed without hashing the blocks again.
+type pendingBlockMeta struct {
+	slot   primitives.Slot
+	parent [32]byte
+	size   int
+	peer   peer.ID
+}
+
+// PendingChain describes the blocks of the pending queue which descend from the same missing block.
+type PendingChain struct {
+	// MissingRoot is the parent root of the lowest block of the chain.
+	MissingRoot [32]byte
+	BaseSlot    primitives.Slot
+	HeadRoot    [32]byte
+	HeadSlot    primitives.Slot
+	Blocks      int
+	Bytes       int
+	// Peers are the peers the blocks of the chain were received from.
+	Peers []peer.ID
+}
+
+// PendingBlocksFetcher provides a view of the chains waiting in the pending block queue.
+type PendingBlocksFetcher interface {
+	PendingChains() []*PendingChain
+}
+
+// PendingChains returns the chains of the pending block queue, ordered by the slot of their lowest block.
+func (s *Service) PendingChains() []*PendingChain {
+	s.pendingQueueLock.RLock()
+	defer s.pendingQueueLock.RUnlock()
+	return s.pendingChains()
+}
+
+// Note: this helper is not thread safe.
+func (s *Service) pendingChains() []*PendingChain {
+	nodes := s.pendingBlockMeta
+
+	// Map every block to the lowest of its ancestors in the queue, remembering the result for the blocks in between.
+	bases := make(map[[32]byte][32]byte, len(nodes))
+	baseOf := func(r [32]byte) [32]byte {
+		var path [][32]byte
+		base := r
+		for {
+			if b, ok := bases[base]; ok {
+				base = b
+				break
+			}
+			path = append(path, base)
+			n := nodes[base]
+			if _, ok := nodes[n.parent]; !ok {
+				break
+			}
+			base = n.parent
+		}
+		for _, p := range path {
+			bases[p] = base
+		}
+		return base
+	}
+
+	chains := make(map[[32]byte]*PendingChain)
+	for r, n := range nodes {
+		base := baseOf(r)
+		c, ok := chains[base]
+		if !ok {
+			c = &PendingChain{MissingRoot: nodes[base].parent, BaseSlot: nodes[base].slot, HeadRoot: r, HeadSlot: n.slot}
+			chains[base] = c
+		}
+		if n.slot > c.HeadSlot {
+			c.HeadRoot, c.HeadSlot = r, n.slot
+		}
+		c.Blocks++
+		c.Bytes += n.size
+		if n.peer != "" && !containsPeer(c.Peers, n.peer) {
+			c.Peers = append(c.Peers, n.peer)
+		}
+	}
+	result := make([]*PendingChain, 0, len(chains))
+	for _, c := range chains {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BaseSlot != result[j].BaseSlot {
+			return result[i].BaseSlot < result[j].BaseSlot
+		}
+		return bytes.Compare(result[i].MissingRoot[:], result[j].MissingRoot[:]) < 0
+	})
+	return result
+}
+
+// requestMissingParents walks back the pending chains whose ancestors are unknown. Each round requests the missing
+// parent of every such chain, batched per peer and preferring the peers which are likely to have it. The next round
+// continues from the parents that were received, until no progress is made.
+func (s *Service) requestMissingParents(ctx context.Context, randGen *rand.Rand) error {
+	ctx, span := prysmTrace.StartSpan(ctx, "requestMissingParents")
+	defer span.End()
+
+	for i := 0; i < maxParentWalkRounds; i++ {
+		chains := s.chainsMissingParent(ctx)
+		if len(chains) == 0 {
+			return nil
+		}
+		roots := make([][32]byte, 0, len(chains))
+		batches := make(map[peer.ID][][32]byte)
+		for _, c := range chains {
+			roots = append(roots, c.MissingRoot)
+			ps := s.chainPeers(c)
+			if len(ps) == 0 {
+				continue
+			}
+			pid := ps[randGen.Int()%len(ps)]
+			batches[pid] = append(batches[pid], c.MissingRoot)
+		}
+		maxReqBlock := params.MaxRequestBlock(slots.ToEpoch(s.cfg.clock.CurrentSlot()))
+		for pid, batch := range batches {
+			if uint64(len(batch)) > maxReqBlock {
+				batch = batch[:maxReqBlock]
+			}
+			req := p2ptypes.BeaconBlockByRootsReq(batch)
+			if err := s.sendRecentBeaconBlocksRequest(ctx, &req, pid); err != nil {
+				log.WithError(err).WithField("peer", pid).Debug("Could not request missing parents of pending blocks")
+			}
+		}
+		// Whatever the preferred peers could not serve is requested from the best peers.
+		if err := s.sendBatchRootRequest(ctx, roots, randGen); err != nil {
+			return err
+		}
+
+		received := 0
+		s.pendingQueueLock.RLock()
+		for _, r := range roots {
+			if s.seenPendingBlocks[r] {
+				received++
+			}
+		}
+		s.pendingQueueLock.RUnlock()
+		if received == 0 {
+			return nil
+		}
+		log.WithField("round", i).WithField("received", received).WithField("missing", len(roots)).
+			Debug("Received missing parents of pending blocks")
+	}
+	return nil
+}
+
+// chainsMissingParent returns the pending chains whose missing parent is neither known nor being processed.
+func (s *Service) chainsMissingParent(ctx context.Context) []*PendingChain {
+	s.pendingQueueLock.RLock()
+	chains := s.pendingChains()
+	s.pendingQueueLock.RUnlock()
+	missing := make([]*PendingChain, 0, len(chains))
+	for _, c := range chains {
+		if s.cfg.beaconDB.HasBlock(ctx, c.MissingRoot) || s.cfg.chain.BlockBeingSynced(c.MissingRoot) || s.hasBadBlock(c.MissingRoot) {
+			continue
+		}
+		missing = append(missing, c)
+	}
+	return missing
+}
+
+// chainPeers returns the connected peers which should have the ancestors of a chain: the peers that sent blocks of
+// the chain, and the peers whose status advertises the head of the chain.
+func (s *Service) chainPeers(c *PendingChain) []peer.ID {
+	connected := s.cfg.p2p.Peers().Connected()
+	ps := make([]peer.ID, 0, len(c.Peers))
+	for _, pid := range c.Peers {
+		if containsPeer(connected, pid) {
+			ps = append(ps, pid)
+		}
+	}
+	for _, pid := range connected {
+		if containsPeer(ps, pid) {
+			continue
+		}
+		st, err := s.cfg.p2p.Peers().ChainState(pid)
+		if err != nil || st == nil {
+			continue
+		}
+		if bytes.Equal(st.HeadRoot, c.HeadRoot[:]) {
+			ps = append(ps, pid)
+		}
+	}
+	return ps
+}
+
+// Note: this helper is not thread safe.
+func (s *Service) trackPendingBlock(b interfaces.ReadOnlySignedBeaconBlock, r [32]byte) {
+	mutexasserts.AssertRWMutexLocked(&s.pendingQueueLock)
+
+	if s.pendingBlockMeta == nil {
+		s.pendingBlockMeta = make(map[[32]byte]pendingBlockMeta)
+	}
+	if _, ok := s.pendingBlockMeta[r]; ok {
+		return
+	}
+	size := b.SizeSSZ()
+	s.pendingBlockMeta[r] = pendingBlockMeta{slot: b.Block().Slot(), parent: b.Block().ParentRoot(), size: size}
+	s.pendingBlocksSize += size
+	pendingBlocksBytes.Set(float64(s.pendingBlocksSize))
+}
+
+// Note: this helper is not thread safe.
+func (s *Service) forgetPendingBlock(r [32]byte) {
+	delete(s.seenPendingBlocks, r)
+	meta, ok := s.pendingBlockMeta[r]
+	if !ok {
+		return
+	}
+	delete(s.pendingBlockMeta, r)
+	s.pendingBlocksSize -= meta.size
+	pendingBlocksBytes.Set(float64(s.pendingBlocksSize))
+}
+
+// setPendingBlockPeer records the peer a pending block was received from, so that its ancestors can be requested
+// from the same peer.
+// Note: this helper is not thread safe.
+func (s *Service) setPendingBlockPeer(r [32]byte, pid peer.ID) {
+	mutexasserts.AssertRWMutexLocked(&s.pendingQueueLock)
+
+	meta, ok := s.pendingBlockMeta[r]
+	if !ok {
+		return
+	}
+	meta.peer = pid
+	s.pendingBlockMeta[r] = meta
+}
+
+// evictPendingBlocks drops the blocks with the highest slots until the queue fits within maxPendingBlocksBytes.
+// Note: this helper is not thread safe.
+func (s *Service) evictPendingBlocks() error {
+	mutexasserts.AssertRWMutexLocked(&s.pendingQueueLock)
+
+	for s.pendingBlocksSize > maxPendingBlocksBytes {
+		var highest primitives.Slot
+		found := false
+		for k := range s.slotToPendingBlocks.Items() {
+			if slot := cacheKeyToSlot(k); !found || slot > highest {
+				highest, found = slot, true
+			}
+		}
+		if !found {
+			return nil
+		}
+		blks := s.pendingBlocksInCache(highest)
+		if len(blks) == 0 {
+			s.slotToPendingBlocks.Delete(slotToCacheKey(highest))
+			continue
+		}
+		b := blks[len(blks)-1]
+		r, err := b.Block().HashTreeRoot()
+		if err != nil {
+			return err
+		}
+		if err := s.deleteBlockFromPendingQueue(highest, b, r); err != nil {
+			return err
+		}
+		pendingBlocksEvicted.Inc()
+		log.WithField("slot", highest).WithField("blockRoot", fmt.Sprintf("%#x", r)).
+			Debug("Dropped pending block to stay within the pending queue memory limit")
+	}
+	return nil
+}
+
+func containsPeer(ps []peer.ID, pid peer.ID) bool {
+	for _, p := range ps {
+		if p == pid {
+			return true
+		}
+	}
+	return false
+}
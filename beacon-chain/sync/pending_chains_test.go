@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	gcache "github.com/patrickmn/go-cache"
+	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	dbtest "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
+	p2ptest "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/crypto/rand"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func pendingTestBlock(t *testing.T, slot primitives.Slot, parent [32]byte) (*ethpb.SignedBeaconBlock, [32]byte) {
+	b := util.NewBeaconBlock()
+	b.Block.Slot = slot
+	b.Block.ParentRoot = parent[:]
+	r, err := b.Block.HashTreeRoot()
+	require.NoError(t, err)
+	return b, r
+}
+
+func insertPendingTestBlock(t *testing.T, s *Service, b *ethpb.SignedBeaconBlock, r [32]byte, pid peer.ID) {
+	wsb, err := blocks.NewSignedBeaconBlock(b)
+	require.NoError(t, err)
+	s.pendingQueueLock.Lock()
+	defer s.pendingQueueLock.Unlock()
+	require.NoError(t, s.insertBlockToPendingQueue(b.Block.Slot, wsb, r))
+	s.setPendingBlockPeer(r, pid)
+}
+
+func TestPendingChains(t *testing.T) {
+	s := &Service{
+		slotToPendingBlocks: gcache.New(time.Second, 2*time.Second),
+		seenPendingBlocks:   make(map[[32]byte]bool),
+	}
+	b1, r1 := pendingTestBlock(t, 1, [32]byte{'a'})
+	b2, r2 := pendingTestBlock(t, 2, r1)
+	b3, r3 := pendingTestBlock(t, 3, r2)
+	// A fork of the same chain.
+	b4, r4 := pendingTestBlock(t, 4, r1)
+	b6, r6 := pendingTestBlock(t, 6, [32]byte{'b'})
+	insertPendingTestBlock(t, s, b3, r3, "peer1")
+	insertPendingTestBlock(t, s, b6, r6, "peer2")
+	insertPendingTestBlock(t, s, b2, r2, "peer1")
+	insertPendingTestBlock(t, s, b1, r1, "peer2")
+	insertPendingTestBlock(t, s, b4, r4, "")
+
+	chains := s.PendingChains()
+	require.Equal(t, 2, len(chains))
+	c := chains[0]
+	assert.Equal(t, [32]byte{'a'}, c.MissingRoot)
+	assert.Equal(t, primitives.Slot(1), c.BaseSlot)
+	assert.Equal(t, r4, c.HeadRoot)
+	assert.Equal(t, primitives.Slot(4), c.HeadSlot)
+	assert.Equal(t, 4, c.Blocks)
+	assert.Equal(t, b1.SizeSSZ()+b2.SizeSSZ()+b3.SizeSSZ()+b4.SizeSSZ(), c.Bytes)
+	assert.Equal(t, 2, len(c.Peers))
+	c = chains[1]
+	assert.Equal(t, [32]byte{'b'}, c.MissingRoot)
+	assert.Equal(t, r6, c.HeadRoot)
+	assert.Equal(t, 1, c.Blocks)
+	assert.DeepEqual(t, []peer.ID{"peer2"}, c.Peers)
+	assert.Equal(t, c.Bytes+chains[0].Bytes, s.pendingBlocksSize)
+
+	s.pendingQueueLock.Lock()
+	wsb, err := blocks.NewSignedBeaconBlock(b6)
+	require.NoError(t, err)
+	require.NoError(t, s.deleteBlockFromPendingQueue(6, wsb, r6))
+	s.pendingQueueLock.Unlock()
+	assert.Equal(t, chains[0].Bytes, s.pendingBlocksSize)
+	s.clearPendingSlots()
+	assert.Equal(t, 0, s.pendingBlocksSize)
+}
+
+func TestEvictPendingBlocks(t *testing.T) {
+	s := &Service{
+		slotToPendingBlocks: gcache.New(time.Second, 2*time.Second),
+		seenPendingBlocks:   make(map[[32]byte]bool),
+	}
+	b1, r1 := pendingTestBlock(t, 1, [32]byte{'a'})
+	b2, r2 := pendingTestBlock(t, 2, r1)
+	b3, r3 := pendingTestBlock(t, 3, r2)
+	b0, r0 := pendingTestBlock(t, 0, [32]byte{'b'})
+	b5, r5 := pendingTestBlock(t, 5, r3)
+
+	defer func(limit int) {
+		maxPendingBlocksBytes = limit
+	}(maxPendingBlocksBytes)
+	maxPendingBlocksBytes = b2.SizeSSZ() + b3.SizeSSZ()
+
+	insertPendingTestBlock(t, s, b2, r2, "")
+	insertPendingTestBlock(t, s, b3, r3, "")
+	assert.Equal(t, true, s.seenPendingBlocks[r2])
+	assert.Equal(t, true, s.seenPendingBlocks[r3])
+	// Ancestors make room for themselves by dropping the highest blocks.
+	insertPendingTestBlock(t, s, b1, r1, "")
+	assert.Equal(t, true, s.seenPendingBlocks[r1])
+	assert.Equal(t, true, s.seenPendingBlocks[r2])
+	assert.Equal(t, false, s.seenPendingBlocks[r3])
+	insertPendingTestBlock(t, s, b0, r0, "")
+	assert.Equal(t, true, s.seenPendingBlocks[r0])
+	assert.Equal(t, true, s.seenPendingBlocks[r1])
+	assert.Equal(t, false, s.seenPendingBlocks[r2])
+	// Blocks ahead of the queue do not fit.
+	insertPendingTestBlock(t, s, b5, r5, "")
+	assert.Equal(t, false, s.seenPendingBlocks[r5])
+	assert.Equal(t, 2, len(s.slotToPendingBlocks.Items()))
+	assert.Equal(t, b0.SizeSSZ()+b1.SizeSSZ(), s.pendingBlocksSize)
+}
+
+func TestRequestMissingParents(t *testing.T) {
+	db := dbtest.SetupDB(t)
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p3 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Connect(p3)
+
+	chain := &mock.ChainService{
+		FinalizedCheckPoint: &ethpb.Checkpoint{Epoch: 0, Root: make([]byte, 32)},
+		Genesis:             time.Now(),
+	}
+	s := &Service{
+		cfg: &config{
+			p2p:      p1,
+			beaconDB: db,
+			chain:    chain,
+			clock:    startup.NewClock(chain.Genesis, chain.ValidatorsRoot),
+		},
+		slotToPendingBlocks: gcache.New(time.Second, 2*time.Second),
+		seenPendingBlocks:   make(map[[32]byte]bool),
+	}
+	s.initCaches()
+
+	b0 := util.NewBeaconBlock()
+	util.SaveBlock(t, context.Background(), db, b0)
+	r0, err := b0.Block.HashTreeRoot()
+	require.NoError(t, err)
+	b1, r1 := pendingTestBlock(t, 1, r0)
+	b2, r2 := pendingTestBlock(t, 2, r1)
+	b3, r3 := pendingTestBlock(t, 3, r2)
+	insertPendingTestBlock(t, s, b3, r3, "")
+
+	// Only the peer advertising the head of the pending chain has its ancestors.
+	for _, p := range []*p2ptest.TestP2P{p2, p3} {
+		p1.Peers().Add(new(enr.Record), p.PeerID(), nil, network.DirOutbound)
+		p1.Peers().SetConnectionState(p.PeerID(), peers.PeerConnected)
+	}
+	p1.Peers().SetChainState(p2.PeerID(), &ethpb.Status{HeadRoot: r3[:], HeadSlot: 3})
+	p1.Peers().SetChainState(p3.PeerID(), &ethpb.Status{})
+
+	served := map[[32]byte]*ethpb.SignedBeaconBlock{r1: b1, r2: b2}
+	pcl := protocol.ID("/eth2/beacon_chain/req/beacon_blocks_by_root/1/ssz_snappy")
+	var requests [][][32]byte
+	var mu sync.Mutex
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		var req p2ptypes.BeaconBlockByRootsReq
+		assert.NoError(t, p2.Encoding().DecodeWithMaxLength(stream, &req))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		for _, r := range req {
+			_, err := stream.Write([]byte{responseCodeSuccess})
+			assert.NoError(t, err)
+			_, err = p2.Encoding().EncodeWithMaxLength(stream, served[r])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, stream.Close())
+	})
+	p3.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		t.Error("Unexpected request to a peer which does not have the pending chain")
+		assert.NoError(t, stream.Close())
+	})
+
+	require.NoError(t, s.requestMissingParents(context.Background(), rand.NewGenerator()))
+	mu.Lock()
+	defer mu.Unlock()
+	require.DeepEqual(t, [][][32]byte{{r2}, {r1}}, requests)
+	assert.Equal(t, true, s.seenPendingBlocks[r1])
+	assert.Equal(t, true, s.seenPendingBlocks[r2])
+	chains := s.PendingChains()
+	require.Equal(t, 1, len(chains))
+	assert.Equal(t, r0, chains[0].MissingRoot)
+	assert.Equal(t, 3, chains[0].Blocks)
+	assert.DeepEqual(t, []peer.ID{p2.PeerID()}, chains[0].Peers)
+}
@@ -2,10 +2,13 @@ package sync
 
 import (
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
 	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
@@ -25,12 +28,39 @@ const blockBucketPeriod = 30 * time.Second
 // Dummy topic to validate all incoming rpc requests.
 const rpcLimiterTopic = "rpc-limiter-topic"
 
+// minQuotaFactor is the smallest share of the configured quotas granted to a peer,
+// regardless of its score and of the current load of the node.
+const minQuotaFactor = 0.25
+
+// busyRequestThreshold is the number of rpc requests served concurrently above which
+// the node is considered busy and peer quotas start shrinking proportionally.
+const busyRequestThreshold = 32
+
 type limiter struct {
-	limiterMap map[string]*leakybucket.Collector
-	p2p        p2p.P2P
+	limiterMap      map[string]*leakybucket.Collector
+	bytesLimiterMap map[string]*leakybucket.Collector
+	p2p             p2p.P2P
+	activeRequests  atomic.Int64
 	sync.RWMutex
 }
 
+// PeerQuotaUsage describes how much of its req/resp quotas a peer has consumed.
+type PeerQuotaUsage struct {
+	PeerID      peer.ID
+	QuotaFactor float64
+	Topics      []*TopicQuotaUsage
+}
+
+// TopicQuotaUsage describes the quota usage of a peer for a single rpc topic. Byte usage
+// is only tracked for topics serving blocks and blobs.
+type TopicQuotaUsage struct {
+	Topic         string
+	Used          int64
+	Capacity      int64
+	BytesUsed     int64
+	BytesCapacity int64
+}
+
 // Instantiates a multi-rpc protocol rate limiter, providing
 // separate collectors for each topic.
 func newRateLimiter(p2pProvider p2p.P2P) *limiter {
@@ -79,10 +109,31 @@ func newRateLimiter(p2pProvider p2p.P2P) *limiter {
 	// BlobSidecarsByRangeV1
 	topicMap[addEncoding(p2p.RPCBlobSidecarsByRangeTopicV1)] = blobCollector
 
+	// Light client requests
+	topicMap[addEncoding(p2p.RPCLightClientBootstrapTopicV1)] = leakybucket.NewCollector(1, defaultBurstLimit, leakyBucketPeriod, false /* deleteEmptyBuckets */)
+	topicMap[addEncoding(p2p.RPCLightClientUpdatesByRangeTopicV1)] = leakybucket.NewCollector(1, defaultBurstLimit, leakyBucketPeriod, false /* deleteEmptyBuckets */)
+	topicMap[addEncoding(p2p.RPCLightClientFinalityUpdateTopicV1)] = leakybucket.NewCollector(1, defaultBurstLimit, leakyBucketPeriod, false /* deleteEmptyBuckets */)
+	topicMap[addEncoding(p2p.RPCLightClientOptimisticUpdateTopicV1)] = leakybucket.NewCollector(1, defaultBurstLimit, leakyBucketPeriod, false /* deleteEmptyBuckets */)
+
 	// General topic for all rpc requests.
 	topicMap[rpcLimiterTopic] = leakybucket.NewCollector(5, defaultBurstLimit*2, leakyBucketPeriod, false /* deleteEmptyBuckets */)
 
-	return &limiter{limiterMap: topicMap, p2p: p2pProvider}
+	// Byte budgets for block and blob responses, a zero limit disables them.
+	bytesTopicMap := make(map[string]*leakybucket.Collector)
+	if limit := flags.Get().BlockBytesLimit; limit > 0 {
+		blockBytesCollector := leakybucket.NewCollector(float64(limit), int64(flags.Get().BlockBatchLimitBurstFactor*limit), leakyBucketPeriod, false /* deleteEmptyBuckets */)
+		bytesTopicMap[addEncoding(p2p.RPCBlocksByRootTopicV1)] = blockBytesCollector
+		bytesTopicMap[addEncoding(p2p.RPCBlocksByRootTopicV2)] = blockBytesCollector
+		bytesTopicMap[addEncoding(p2p.RPCBlocksByRangeTopicV1)] = blockBytesCollector
+		bytesTopicMap[addEncoding(p2p.RPCBlocksByRangeTopicV2)] = blockBytesCollector
+	}
+	if limit := flags.Get().BlobBytesLimit; limit > 0 {
+		blobBytesCollector := leakybucket.NewCollector(float64(limit), int64(flags.Get().BlobBatchLimitBurstFactor*limit), leakyBucketPeriod, false /* deleteEmptyBuckets */)
+		bytesTopicMap[addEncoding(p2p.RPCBlobSidecarsByRootTopicV1)] = blobBytesCollector
+		bytesTopicMap[addEncoding(p2p.RPCBlobSidecarsByRangeTopicV1)] = blobBytesCollector
+	}
+
+	return &limiter{limiterMap: topicMap, bytesLimiterMap: bytesTopicMap, p2p: p2pProvider}
 }
 
 // Returns the current topic collector for the provided topic.
@@ -103,20 +154,124 @@ func (l *limiter) validateRequest(stream network.Stream, amt uint64) error {
 	if err != nil {
 		return err
 	}
-	key := stream.Conn().RemotePeer().String()
-	remaining := collector.Remaining(key)
+	pid := stream.Conn().RemotePeer()
+	remaining := l.allowance(collector, pid)
 	// Treat each request as a minimum of 1.
 	if amt == 0 {
 		amt = 1
 	}
-	if amt > uint64(remaining) {
-		l.p2p.Peers().Scorers().BadResponsesScorer().Increment(stream.Conn().RemotePeer())
+	if remaining < 0 || amt > uint64(remaining) {
+		l.p2p.Peers().Scorers().BadResponsesScorer().Increment(pid)
+		writeErrorResponseToStream(responseCodeInvalidRequest, p2ptypes.ErrRateLimited.Error(), stream, l.p2p)
+		return p2ptypes.ErrRateLimited
+	}
+	return nil
+}
+
+// validates that a response of the given size fits in the byte budget of the peer for the topic.
+// Unlike request validation, running out of byte budget does not penalize the peer, as it
+// cannot know the size of the responses in advance.
+func (l *limiter) validateResponseBytes(stream network.Stream, size uint64) error {
+	l.RLock()
+	defer l.RUnlock()
+
+	collector, ok := l.bytesLimiterMap[string(stream.Protocol())]
+	if !ok {
+		return nil
+	}
+	remaining := l.allowance(collector, stream.Conn().RemotePeer())
+	if remaining < 0 || size > uint64(remaining) {
 		writeErrorResponseToStream(responseCodeInvalidRequest, p2ptypes.ErrRateLimited.Error(), stream, l.p2p)
 		return p2ptypes.ErrRateLimited
 	}
 	return nil
 }
 
+// adds the size of a response to the byte budget of the peer for the topic.
+func (l *limiter) addResponseBytes(stream network.Stream, size int64) {
+	l.Lock()
+	defer l.Unlock()
+
+	collector, ok := l.bytesLimiterMap[string(stream.Protocol())]
+	if !ok {
+		return
+	}
+	collector.Add(stream.Conn().RemotePeer().String(), size)
+}
+
+// marks the start of an rpc request being served, used to determine how busy the node is.
+func (l *limiter) beginRequest() {
+	l.activeRequests.Add(1)
+}
+
+// marks the end of an rpc request being served.
+func (l *limiter) endRequest() {
+	l.activeRequests.Add(-1)
+}
+
+// quotaFactor returns the share of the configured quotas granted to the peer. Peers with
+// a negative score are granted a smaller share, and all shares shrink while the node is busy
+// serving more than busyRequestThreshold requests.
+func (l *limiter) quotaFactor(pid peer.ID) float64 {
+	factor := float64(1)
+	if score := l.p2p.Peers().Scorers().Score(pid); score < 0 {
+		factor = 1 / (1 - score)
+	}
+	if active := l.activeRequests.Load(); active > busyRequestThreshold {
+		factor *= float64(busyRequestThreshold) / float64(active)
+	}
+	if factor < minQuotaFactor {
+		return minQuotaFactor
+	}
+	return factor
+}
+
+// allowance returns how much the peer is still allowed to consume from the collector, once its
+// quota is adjusted by the quota factor. The result is negative if the peer is above its quota.
+func (l *limiter) allowance(collector *leakybucket.Collector, pid peer.ID) int64 {
+	capacity := collector.Capacity()
+	used := capacity - collector.Remaining(pid.String())
+	return int64(float64(capacity)*l.quotaFactor(pid)) - used
+}
+
+// usage returns the current quota usage of all connected peers.
+func (l *limiter) usage() []*PeerQuotaUsage {
+	l.RLock()
+	defer l.RUnlock()
+
+	topics := make([]string, 0, len(l.limiterMap))
+	for topic := range l.limiterMap {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	connected := l.p2p.Peers().Connected()
+	usage := make([]*PeerQuotaUsage, 0, len(connected))
+	for _, pid := range connected {
+		key := pid.String()
+		peerUsage := &PeerQuotaUsage{
+			PeerID:      pid,
+			QuotaFactor: l.quotaFactor(pid),
+			Topics:      make([]*TopicQuotaUsage, 0, len(topics)),
+		}
+		for _, topic := range topics {
+			collector := l.limiterMap[topic]
+			topicUsage := &TopicQuotaUsage{
+				Topic:    topic,
+				Used:     collector.Count(key),
+				Capacity: collector.Capacity(),
+			}
+			if bytesCollector, ok := l.bytesLimiterMap[topic]; ok {
+				topicUsage.BytesUsed = bytesCollector.Count(key)
+				topicUsage.BytesCapacity = bytesCollector.Capacity()
+			}
+			peerUsage.Topics = append(peerUsage.Topics, topicUsage)
+		}
+		usage = append(usage, peerUsage)
+	}
+	return usage
+}
+
 // This is used to validate all incoming rpc streams from external peers.
 func (l *limiter) validateRawRpcRequest(stream network.Stream) error {
 	l.RLock()
@@ -128,8 +283,7 @@ func (l *limiter) validateRawRpcRequest(stream network.Stream) error {
 	if err != nil {
 		return err
 	}
-	key := stream.Conn().RemotePeer().String()
-	remaining := collector.Remaining(key)
+	remaining := l.allowance(collector, stream.Conn().RemotePeer())
 	// Treat each request as a minimum of 1.
 	amt := int64(1)
 	if amt > remaining {
@@ -194,6 +348,14 @@ func (l *limiter) free() {
 		delete(l.limiterMap, t)
 		tempMap[ptr] = true
 	}
+	for t, collector := range l.bytesLimiterMap {
+		ptr := reflect.ValueOf(collector).Pointer()
+		if !tempMap[ptr] {
+			collector.Free()
+			tempMap[ptr] = true
+		}
+		delete(l.bytesLimiterMap, t)
+	}
 }
 
 // not to be used outside the rate limiter file as it is unsafe for concurrent usage
@@ -9,8 +9,10 @@ import (
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/peers"
 	mockp2p "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
 	p2ptypes "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	leakybucket "github.com/prysmaticlabs/prysm/v5/container/leaky-bucket"
 	"github.com/prysmaticlabs/prysm/v5/testing/assert"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"github.com/prysmaticlabs/prysm/v5/testing/util"
@@ -18,7 +20,7 @@ import (
 
 func TestNewRateLimiter(t *testing.T) {
 	rlimiter := newRateLimiter(mockp2p.NewTestP2P(t))
-	assert.Equal(t, len(rlimiter.limiterMap), 12, "correct number of topics not registered")
+	assert.Equal(t, len(rlimiter.limiterMap), 16, "correct number of topics not registered")
 }
 
 func TestNewRateLimiter_FreeCorrectly(t *testing.T) {
@@ -110,3 +112,98 @@ func Test_limiter_retrieveCollector_requiresLock(t *testing.T) {
 	_, err := l.retrieveCollector("")
 	require.ErrorContains(t, "caller must hold read/write lock", err)
 }
+
+func TestRateLimiter_QuotaFactor(t *testing.T) {
+	p1 := mockp2p.NewTestP2P(t)
+	p2 := mockp2p.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(nil, p2.PeerID(), p2.BHost.Addrs()[0], network.DirOutbound)
+
+	rlimiter := newRateLimiter(p1)
+	assert.Equal(t, float64(1), rlimiter.quotaFactor(p2.PeerID()))
+
+	// Peers with a negative score are granted a smaller share.
+	p1.Peers().Scorers().BadResponsesScorer().Increment(p2.PeerID())
+	factor := rlimiter.quotaFactor(p2.PeerID())
+	assert.Equal(t, true, factor < 1, "quota factor was not reduced for a negatively scored peer")
+	assert.Equal(t, true, factor > minQuotaFactor, "quota factor was reduced too much")
+
+	// Quotas shrink while the node is busy, but never below the floor.
+	rlimiter.activeRequests.Store(2 * busyRequestThreshold)
+	assert.Equal(t, factor/2, rlimiter.quotaFactor(p2.PeerID()))
+	rlimiter.activeRequests.Store(100 * busyRequestThreshold)
+	assert.Equal(t, minQuotaFactor, rlimiter.quotaFactor(p2.PeerID()))
+}
+
+func TestRateLimiter_ExceedBytesCapacity(t *testing.T) {
+	p1 := mockp2p.NewTestP2P(t)
+	p2 := mockp2p.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(nil, p2.PeerID(), p2.BHost.Addrs()[0], network.DirOutbound)
+
+	rlimiter := newRateLimiter(p1)
+	topic := p2p.RPCBlocksByRangeTopicV2 + p1.Encoding().ProtocolSuffix()
+	rlimiter.bytesLimiterMap[topic] = leakybucket.NewCollector(1000, 1000, time.Second, false)
+
+	wg := sync.WaitGroup{}
+	p2.BHost.SetStreamHandler(protocol.ID(topic), func(stream network.Stream) {
+		defer wg.Done()
+		code, errMsg, err := readStatusCodeNoDeadline(stream, p2.Encoding())
+		require.NoError(t, err, "could not read incoming stream")
+		assert.Equal(t, responseCodeInvalidRequest, code, "not equal response codes")
+		assert.Equal(t, p2ptypes.ErrRateLimited.Error(), errMsg, "not equal errors")
+	})
+	wg.Add(1)
+	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), protocol.ID(topic))
+	require.NoError(t, err, "could not create stream")
+
+	require.NoError(t, rlimiter.validateResponseBytes(stream, 800))
+	rlimiter.addResponseBytes(stream, 800)
+	assert.ErrorContains(t, p2ptypes.ErrRateLimited.Error(), rlimiter.validateResponseBytes(stream, 800))
+
+	// Running out of byte budget does not penalize the peer.
+	count, err := p1.Peers().Scorers().BadResponsesScorer().Count(p2.PeerID())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	require.NoError(t, stream.Close(), "could not close stream")
+
+	if util.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+func TestRateLimiter_Usage(t *testing.T) {
+	p1 := mockp2p.NewTestP2P(t)
+	p2 := mockp2p.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(nil, p2.PeerID(), p2.BHost.Addrs()[0], network.DirOutbound)
+	p1.Peers().SetConnectionState(p2.PeerID(), peers.PeerConnected)
+
+	rlimiter := newRateLimiter(p1)
+	topic := p2p.RPCBlocksByRangeTopicV2 + p1.Encoding().ProtocolSuffix()
+	rlimiter.bytesLimiterMap[topic] = leakybucket.NewCollector(1000, 1000, time.Second, false)
+
+	p2.BHost.SetStreamHandler(protocol.ID(topic), func(stream network.Stream) {})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), protocol.ID(topic))
+	require.NoError(t, err, "could not create stream")
+	rlimiter.add(stream, 10)
+	rlimiter.addResponseBytes(stream, 300)
+	require.NoError(t, stream.Close(), "could not close stream")
+
+	usage := rlimiter.usage()
+	require.Equal(t, 1, len(usage))
+	assert.Equal(t, p2.PeerID(), usage[0].PeerID)
+	assert.Equal(t, float64(1), usage[0].QuotaFactor)
+	require.Equal(t, len(rlimiter.limiterMap), len(usage[0].Topics))
+	found := false
+	for _, u := range usage[0].Topics {
+		if u.Topic != topic {
+			continue
+		}
+		found = true
+		assert.Equal(t, int64(10), u.Used)
+		assert.Equal(t, int64(300), u.BytesUsed)
+		assert.Equal(t, int64(1000), u.BytesCapacity)
+	}
+	assert.Equal(t, true, found, "topic usage not reported")
+}
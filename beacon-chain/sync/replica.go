@@ -0,0 +1,34 @@
+package sync
+
+import "github.com/pkg/errors"
+
+var _ Checker = ReplicaChecker{}
+
+// ReplicaChecker is the Checker of a read-only replica, which does not sync but follows the head of the node
+// writing to its database. It reports being synced, as it serves whatever that node synced.
+type ReplicaChecker struct{}
+
+// Initialized returns true, a replica starts from the database of a synced node.
+func (ReplicaChecker) Initialized() bool {
+	return true
+}
+
+// Syncing returns false, a replica never syncs.
+func (ReplicaChecker) Syncing() bool {
+	return false
+}
+
+// Synced returns true, a replica is as synced as the node writing to its database.
+func (ReplicaChecker) Synced() bool {
+	return true
+}
+
+// Status returns nil.
+func (ReplicaChecker) Status() error {
+	return nil
+}
+
+// Resync returns an error, a replica can not sync.
+func (ReplicaChecker) Resync() error {
+	return errors.New("a read-only replica can not resync")
+}
@@ -77,7 +77,10 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	for batch, more = batcher.next(ctx, stream); more; batch, more = batcher.next(ctx, stream) {
 		batchStart := time.Now()
 		if err := s.writeBlockBatchToStream(ctx, batch, stream); err != nil {
-			s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+			// The rate limiter already notified the peer when its byte budget was exceeded.
+			if !errors.Is(err, p2ptypes.ErrRateLimited) {
+				s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+			}
 			return err
 		}
 		rpcBlocksByRangeResponseLatency.Observe(float64(time.Since(batchStart).Milliseconds()))
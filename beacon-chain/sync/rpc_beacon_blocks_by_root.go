@@ -44,6 +44,7 @@ func (s *Service) sendRecentBeaconBlocksRequest(ctx context.Context, requests *t
 		if err := s.insertBlockToPendingQueue(blk.Block().Slot(), blk, blkRoot); err != nil {
 			return err
 		}
+		s.setPendingBlockPeer(blkRoot, id)
 		return nil
 	})
 	for _, blk := range blks {
@@ -44,6 +44,10 @@ func (s *Service) streamBlobBatch(ctx context.Context, batch blockBatch, wQuota
 				s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
 				return wQuota, errors.Wrapf(err, "could not retrieve sidecar: index %d, block root %#x", i, root)
 			}
+			size := sc.SizeSSZ()
+			if err := s.rateLimiter.validateResponseBytes(stream, uint64(size)); err != nil {
+				return wQuota, err
+			}
 			SetStreamWriteDeadline(stream, defaultWriteDuration)
 			if chunkErr := WriteBlobSidecarChunk(stream, s.cfg.chain, s.cfg.p2p.Encoding(), sc); chunkErr != nil {
 				log.WithError(chunkErr).Debug("Could not send a chunked response")
@@ -52,6 +56,7 @@ func (s *Service) streamBlobBatch(ctx context.Context, batch blockBatch, wQuota
 				return wQuota, chunkErr
 			}
 			s.rateLimiter.add(stream, 1)
+			s.rateLimiter.addResponseBytes(stream, int64(size))
 			wQuota -= 1
 			// Stop streaming results once the quota of writes for the request is consumed.
 			if wQuota == 0 {
@@ -92,6 +92,10 @@ func (s *Service) blobSidecarByRootRPCHandler(ctx context.Context, msg interface
 			return types.ErrBlobLTMinRequest
 		}
 
+		size := sc.SizeSSZ()
+		if err := s.rateLimiter.validateResponseBytes(stream, uint64(size)); err != nil {
+			return err
+		}
 		SetStreamWriteDeadline(stream, defaultWriteDuration)
 		if chunkErr := WriteBlobSidecarChunk(stream, s.cfg.chain, s.cfg.p2p.Encoding(), sc); chunkErr != nil {
 			log.WithError(chunkErr).Debug("Could not send a chunked response")
@@ -99,6 +103,7 @@ func (s *Service) blobSidecarByRootRPCHandler(ctx context.Context, msg interface
 			tracing.AnnotateError(span, chunkErr)
 			return chunkErr
 		}
+		s.rateLimiter.addResponseBytes(stream, int64(size))
 	}
 	closeStream(stream, log)
 	return nil
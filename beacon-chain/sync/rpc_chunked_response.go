@@ -18,9 +18,18 @@ import (
 // chunkBlockWriter writes the given message as a chunked response to the given network
 // stream.
 // response_chunk  ::= <result> | <context-bytes> | <encoding-dependent-header> | <encoded-payload>
+// The block is only written if it fits in the byte budget of the peer.
 func (s *Service) chunkBlockWriter(stream libp2pcore.Stream, blk interfaces.ReadOnlySignedBeaconBlock) error {
+	size := blk.SizeSSZ()
+	if err := s.rateLimiter.validateResponseBytes(stream, uint64(size)); err != nil {
+		return err
+	}
 	SetStreamWriteDeadline(stream, defaultWriteDuration)
-	return WriteBlockChunk(stream, s.cfg.clock, s.cfg.p2p.Encoding(), blk)
+	if err := WriteBlockChunk(stream, s.cfg.clock, s.cfg.p2p.Encoding(), blk); err != nil {
+		return err
+	}
+	s.rateLimiter.addResponseBytes(stream, int64(size))
+	return nil
 }
 
 // WriteBlockChunk writes block chunk object to stream.
@@ -0,0 +1,189 @@
+package sync
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	mock "github.com/prysmaticlabs/prysm/v5/beacon-chain/blockchain/testing"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	db "github.com/prysmaticlabs/prysm/v5/beacon-chain/db/testing"
+	doublylinkedtree "github.com/prysmaticlabs/prysm/v5/beacon-chain/forkchoice/doubly-linked-tree"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	p2ptest "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/types"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stategen"
+	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+)
+
+func setupLightClientRPCTest(t *testing.T, topic string) (*Service, *p2ptest.TestP2P, *p2ptest.TestP2P, protocol.ID) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	beaconDB := db.SetupDB(t)
+	s := &Service{
+		cfg: &config{
+			beaconDB: beaconDB,
+			p2p:      p1,
+			chain:    &mock.ChainService{ValidatorsRoot: [32]byte{'A'}},
+			stateGen: stategen.New(beaconDB, doublylinkedtree.New()),
+		},
+		rateLimiter: newRateLimiter(p1),
+	}
+	return s, p1, p2, protocol.ID(topic + p1.Encoding().ProtocolSuffix())
+}
+
+func TestLightClientUpdatesByRangeRPCHandler(t *testing.T) {
+	s, p1, p2, pcl := setupLightClientRPCTest(t, p2p.RPCLightClientUpdatesByRangeTopicV1)
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	update, err := lightclient.NewLightClientUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock, l.FinalizedBlock)
+	require.NoError(t, err)
+	period := slots.SyncCommitteePeriod(slots.ToEpoch(l.AttestedBlock.Block().Slot()))
+	// The periods after the first gap are not served.
+	for _, p := range []uint64{period, period + 1, period + 3} {
+		require.NoError(t, s.cfg.beaconDB.SaveLightClientUpdate(l.Ctx, p, &ethpbv2.LightClientUpdateWithVersion{
+			Version: ethpbv2.Version(l.AttestedBlock.Version()),
+			Data:    update,
+		}))
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		for i := 0; i < 2; i++ {
+			expectSuccess(t, stream)
+			_, err := readContextFromStream(stream)
+			require.NoError(t, err)
+			out := &ethpb.LightClientUpdateAltair{}
+			require.NoError(t, p2.Encoding().DecodeWithMaxLength(stream, out))
+			assert.Equal(t, l.AttestedBlock.Block().Slot(), out.AttestedHeader.Beacon.Slot)
+			assert.Equal(t, l.Block.Block().Slot(), out.SignatureSlot)
+		}
+		// The stream is closed after the last period with an update.
+		_, err := stream.Read(make([]byte, 1))
+		require.ErrorIs(t, err, io.EOF)
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	req := &types.LightClientUpdatesByRangeReq{StartPeriod: period, Count: 5}
+	require.NoError(t, s.lightClientUpdatesByRangeRPCHandler(context.Background(), req, stream))
+	if util.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+func TestLightClientUpdatesByRangeRPCHandler_ZeroCount(t *testing.T) {
+	s, p1, p2, pcl := setupLightClientRPCTest(t, p2p.RPCLightClientUpdatesByRangeTopicV1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		expectFailure(t, responseCodeInvalidRequest, types.ErrInvalidRequest.Error(), stream)
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	req := &types.LightClientUpdatesByRangeReq{StartPeriod: 1}
+	require.ErrorIs(t, s.lightClientUpdatesByRangeRPCHandler(context.Background(), req, stream), types.ErrInvalidRequest)
+	if util.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+func TestLightClientBootstrapRPCHandler(t *testing.T) {
+	s, p1, p2, pcl := setupLightClientRPCTest(t, p2p.RPCLightClientBootstrapTopicV1)
+	l := util.NewTestLightClient(t).SetupTestCapella(false)
+	require.NoError(t, s.cfg.beaconDB.SaveBlock(l.Ctx, l.AttestedBlock))
+	root, err := l.AttestedBlock.Block().HashTreeRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.cfg.stateGen.SaveState(l.Ctx, root, l.AttestedState))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		expectSuccess(t, stream)
+		_, err := readContextFromStream(stream)
+		require.NoError(t, err)
+		out := &ethpb.LightClientBootstrapCapella{}
+		require.NoError(t, p2.Encoding().DecodeWithMaxLength(stream, out))
+		assert.Equal(t, l.AttestedBlock.Block().Slot(), out.Header.Beacon.Slot)
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	req := types.LightClientBootstrapReq(root)
+	require.NoError(t, s.lightClientBootstrapRPCHandler(context.Background(), &req, stream))
+	if util.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+func TestLightClientBootstrapRPCHandler_UnknownBlock(t *testing.T) {
+	s, p1, p2, pcl := setupLightClientRPCTest(t, p2p.RPCLightClientBootstrapTopicV1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		expectFailure(t, responseCodeResourceUnavailable, types.ErrResourceUnavailable.Error(), stream)
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	req := types.LightClientBootstrapReq{'a'}
+	require.NoError(t, s.lightClientBootstrapRPCHandler(context.Background(), &req, stream))
+	if util.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
+
+func TestLightClientOptimisticUpdateRPCHandler(t *testing.T) {
+	s, p1, p2, pcl := setupLightClientRPCTest(t, p2p.RPCLightClientOptimisticUpdateTopicV1)
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	update, err := lightclient.NewLightClientOptimisticUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock)
+	require.NoError(t, err)
+
+	// Nothing is served before the first update is produced.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		expectFailure(t, responseCodeResourceUnavailable, types.ErrResourceUnavailable.Error(), stream)
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	require.NoError(t, s.lightClientOptimisticUpdateRPCHandler(context.Background(), new(interface{}), stream))
+	if util.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+
+	s.lightClientOptimisticUpdate = &ethpbv2.LightClientOptimisticUpdateWithVersion{
+		Version: ethpbv2.Version(l.AttestedBlock.Version()),
+		Data:    update,
+	}
+	wg.Add(1)
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		expectSuccess(t, stream)
+		_, err := readContextFromStream(stream)
+		require.NoError(t, err)
+		out := &ethpb.LightClientOptimisticUpdateAltair{}
+		require.NoError(t, p2.Encoding().DecodeWithMaxLength(stream, out))
+		assert.Equal(t, l.AttestedBlock.Block().Slot(), out.AttestedHeader.Beacon.Slot)
+	})
+	stream, err = p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+	require.NoError(t, err)
+	require.NoError(t, s.lightClientOptimisticUpdateRPCHandler(context.Background(), new(interface{}), stream))
+	if util.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
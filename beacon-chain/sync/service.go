@@ -36,10 +36,13 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill/coverage"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/verification"
 	lruwrpr "github.com/prysmaticlabs/prysm/v5/cache/lru"
+	"github.com/prysmaticlabs/prysm/v5/config/features"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/blocks"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/interfaces"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	leakybucket "github.com/prysmaticlabs/prysm/v5/container/leaky-bucket"
+	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/runtime"
 	prysmTime "github.com/prysmaticlabs/prysm/v5/time"
@@ -117,47 +120,55 @@ type blockchainService interface {
 // Service is responsible for handling all run time p2p related operations as the
 // main entry point for network messages.
 type Service struct {
-	cfg                              *config
-	ctx                              context.Context
-	cancel                           context.CancelFunc
-	slotToPendingBlocks              *gcache.Cache
-	seenPendingBlocks                map[[32]byte]bool
-	blkRootToPendingAtts             map[[32]byte][]ethpb.SignedAggregateAttAndProof
-	subHandler                       *subTopicHandler
-	pendingAttsLock                  sync.RWMutex
-	pendingQueueLock                 sync.RWMutex
-	chainStarted                     *abool.AtomicBool
-	validateBlockLock                sync.RWMutex
-	rateLimiter                      *limiter
-	seenBlockLock                    sync.RWMutex
-	seenBlockCache                   *lru.Cache
-	seenBlobLock                     sync.RWMutex
-	seenBlobCache                    *lru.Cache
-	seenAggregatedAttestationLock    sync.RWMutex
-	seenAggregatedAttestationCache   *lru.Cache
-	seenUnAggregatedAttestationLock  sync.RWMutex
-	seenUnAggregatedAttestationCache *lru.Cache
-	seenExitLock                     sync.RWMutex
-	seenExitCache                    *lru.Cache
-	seenProposerSlashingLock         sync.RWMutex
-	seenProposerSlashingCache        *lru.Cache
-	seenAttesterSlashingLock         sync.RWMutex
-	seenAttesterSlashingCache        map[uint64]bool
-	seenSyncMessageLock              sync.RWMutex
-	seenSyncMessageCache             *lru.Cache
-	seenSyncContributionLock         sync.RWMutex
-	seenSyncContributionCache        *lru.Cache
-	badBlockCache                    *lru.Cache
-	badBlockLock                     sync.RWMutex
-	syncContributionBitsOverlapLock  sync.RWMutex
-	syncContributionBitsOverlapCache *lru.Cache
-	signatureChan                    chan *signatureVerifier
-	clockWaiter                      startup.ClockWaiter
-	initialSyncComplete              chan struct{}
-	verifierWaiter                   *verification.InitializerWaiter
-	newBlobVerifier                  verification.NewBlobVerifier
-	availableBlocker                 coverage.AvailableBlocker
-	ctxMap                           ContextByteVersions
+	cfg                                       *config
+	ctx                                       context.Context
+	cancel                                    context.CancelFunc
+	slotToPendingBlocks                       *gcache.Cache
+	seenPendingBlocks                         map[[32]byte]bool
+	pendingBlockMeta                          map[[32]byte]pendingBlockMeta
+	pendingBlocksSize                         int
+	blkRootToPendingAtts                      map[[32]byte][]ethpb.SignedAggregateAttAndProof
+	subHandler                                *subTopicHandler
+	pendingAttsLock                           sync.RWMutex
+	pendingQueueLock                          sync.RWMutex
+	chainStarted                              *abool.AtomicBool
+	validateBlockLock                         sync.RWMutex
+	rateLimiter                               *limiter
+	seenBlockLock                             sync.RWMutex
+	seenBlockCache                            *lru.Cache
+	seenBlobLock                              sync.RWMutex
+	seenBlobCache                             *lru.Cache
+	seenAggregatedAttestationLock             sync.RWMutex
+	seenAggregatedAttestationCache            *lru.Cache
+	seenUnAggregatedAttestationLock           sync.RWMutex
+	seenUnAggregatedAttestationCache          *lru.Cache
+	seenExitLock                              sync.RWMutex
+	seenExitCache                             *lru.Cache
+	seenProposerSlashingLock                  sync.RWMutex
+	seenProposerSlashingCache                 *lru.Cache
+	seenAttesterSlashingLock                  sync.RWMutex
+	seenAttesterSlashingCache                 map[uint64]bool
+	seenSyncMessageLock                       sync.RWMutex
+	seenSyncMessageCache                      *lru.Cache
+	seenSyncContributionLock                  sync.RWMutex
+	seenSyncContributionCache                 *lru.Cache
+	badBlockCache                             *lru.Cache
+	badBlockLock                              sync.RWMutex
+	syncContributionBitsOverlapLock           sync.RWMutex
+	syncContributionBitsOverlapCache          *lru.Cache
+	signatureChan                             chan *signatureVerifier
+	clockWaiter                               startup.ClockWaiter
+	initialSyncComplete                       chan struct{}
+	verifierWaiter                            *verification.InitializerWaiter
+	newBlobVerifier                           verification.NewBlobVerifier
+	availableBlocker                          coverage.AvailableBlocker
+	ctxMap                                    ContextByteVersions
+	lightClientUpdateLock                     sync.RWMutex
+	lightClientFinalityUpdate                 *ethpbv2.LightClientFinalityUpdateWithVersion
+	lightClientOptimisticUpdate               *ethpbv2.LightClientOptimisticUpdateWithVersion
+	lightClientFinalityForwardedSlot          primitives.Slot
+	lightClientFinalityForwardedSupermajority bool
+	lightClientOptimisticForwardedSlot        primitives.Slot
 }
 
 // NewService initializes new regular sync service.
@@ -171,6 +182,7 @@ func NewService(ctx context.Context, opts ...Option) *Service {
 		cfg:                  &config{clock: startup.NewClock(time.Unix(0, 0), [32]byte{})},
 		slotToPendingBlocks:  c,
 		seenPendingBlocks:    make(map[[32]byte]bool),
+		pendingBlockMeta:     make(map[[32]byte]pendingBlockMeta),
 		blkRootToPendingAtts: make(map[[32]byte][]ethpb.SignedAggregateAttAndProof),
 		signatureChan:        make(chan *signatureVerifier, verifierLimit),
 	}
@@ -198,7 +210,7 @@ func NewService(ctx context.Context, opts ...Option) *Service {
 				log.WithError(err).Error("Could not calculate htr of block")
 				continue
 			}
-			delete(r.seenPendingBlocks, root)
+			r.forgetPendingBlock(root)
 		}
 	})
 	r.subHandler = newSubTopicHandler()
@@ -225,6 +237,9 @@ func (s *Service) Start() {
 
 	go s.verifierRoutine()
 	go s.registerHandlers()
+	if features.Get().EnableLightClient && s.cfg.stateNotifier != nil {
+		go s.subscribeToLightClientUpdates()
+	}
 
 	s.cfg.p2p.AddConnectionHandler(s.reValidatePeer, s.sendGoodbye)
 	s.cfg.p2p.AddDisconnectionHandler(func(_ context.Context, _ peer.ID) error {
@@ -352,6 +367,19 @@ func (s *Service) chainIsStarted() bool {
 	return s.chainStarted.IsSet()
 }
 
+// RateLimitUsage returns the current req/resp quota usage of all connected peers.
+func (s *Service) RateLimitUsage() []*PeerQuotaUsage {
+	if s.rateLimiter == nil {
+		return []*PeerQuotaUsage{}
+	}
+	return s.rateLimiter.usage()
+}
+
+// RateLimitUsageFetcher provides the req/resp quota usage of connected peers.
+type RateLimitUsageFetcher interface {
+	RateLimitUsage() []*PeerQuotaUsage
+}
+
 // Checker defines a struct which can verify whether a node is currently
 // synchronizing a chain with the rest of peers in the network.
 type Checker interface {
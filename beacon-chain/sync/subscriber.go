@@ -125,6 +125,22 @@ func (s *Service) registerSubscribers(epoch primitives.Epoch, digest [4]byte) {
 		}
 	}
 
+	// Light client updates, served to light clients by the nodes which enable them.
+	if epoch >= params.BeaconConfig().AltairForkEpoch && features.Get().EnableLightClient {
+		s.subscribe(
+			p2p.LightClientFinalityUpdateTopicFormat,
+			s.validateLightClientFinalityUpdate,
+			s.lightClientUpdateSubscriber,
+			digest,
+		)
+		s.subscribe(
+			p2p.LightClientOptimisticUpdateTopicFormat,
+			s.validateLightClientOptimisticUpdate,
+			s.lightClientUpdateSubscriber,
+			digest,
+		)
+	}
+
 	// New Gossip Topic in Capella
 	if epoch >= params.BeaconConfig().CapellaForkEpoch {
 		s.subscribe(
@@ -641,6 +657,14 @@ func (s *Service) subscribeDynamicWithSyncSubnets(
 				for _, idx := range wantedSubs {
 					s.subscribeSyncSubnet(subscriptions, idx, digest, validate, handle)
 				}
+
+				// search for peers on the subnets of upcoming sync committees once per epoch,
+				// so that they are already in place by the time we join those subnets.
+				if slots.IsEpochStart(currentSlot) {
+					for _, idx := range s.retrieveUpcomingSyncSubnets(slots.ToEpoch(currentSlot), wantedSubs) {
+						s.lookupSyncSubnets(digest, idx)
+					}
+				}
 			}
 		}
 	}()
@@ -661,6 +685,20 @@ func (s *Service) lookupAttesterSubnets(digest [4]byte, idx uint64) {
 	}
 }
 
+// lookup peers for sync committee subnets that we are going to join.
+func (s *Service) lookupSyncSubnets(digest [4]byte, idx uint64) {
+	topic := p2p.GossipTypeMapping[reflect.TypeOf(&ethpb.SyncCommitteeMessage{})]
+	subnetTopic := fmt.Sprintf(topic, digest, idx)
+	if !s.validPeersExist(subnetTopic) {
+		log.Debugf("No peers found subscribed to upcoming sync gossip subnet with "+
+			"committee index %d. Searching network for peers subscribed to the subnet.", idx)
+		_, err := s.cfg.p2p.FindPeersWithSubnet(s.ctx, subnetTopic, idx, flags.Get().MinimumPeersPerSubnet)
+		if err != nil {
+			log.WithError(err).Debug("Could not search for peers")
+		}
+	}
+}
+
 func (s *Service) unSubscribeFromTopic(topic string) {
 	log.WithField("topic", topic).Debug("Unsubscribing from topic")
 	if err := s.cfg.p2p.PubSub().UnregisterTopicValidator(topic); err != nil {
@@ -697,6 +735,13 @@ func (*Service) retrieveActiveSyncSubnets(currEpoch primitives.Epoch) []uint64 {
 	return slice.SetUint64(subs)
 }
 
+// retrieveUpcomingSyncSubnets returns the sync committee subnets our validators will join
+// within the next sync committee period which we are not yet subscribed to.
+func (*Service) retrieveUpcomingSyncSubnets(currEpoch primitives.Epoch, activeSubs []uint64) []uint64 {
+	subs := cache.SyncSubnetIDs.GetAllSubnets(currEpoch + params.BeaconConfig().EpochsPerSyncCommitteePeriod)
+	return slice.NotUint64(activeSubs, slice.SetUint64(subs))
+}
+
 // filters out required peers for the node to function, not
 // pruning peers who are in our attestation subnets.
 func (s *Service) filterNeededPeers(pids []peer.ID) []peer.ID {
@@ -0,0 +1,13 @@
+package sync
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// lightClientUpdateSubscriber handles the light client updates received over gossip. Only the updates matching the
+// ones computed by the node are accepted, so there is nothing left to process once they are forwarded.
+func (*Service) lightClientUpdateSubscriber(_ context.Context, _ proto.Message) error {
+	return nil
+}
@@ -615,6 +615,24 @@ func TestSubscribeWithSyncSubnets_DynamicOK(t *testing.T) {
 	cancel()
 }
 
+func TestRetrieveUpcomingSyncSubnets(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	defer cache.SyncSubnetIDs.EmptyAllCaches()
+	s := &Service{}
+	currEpoch := primitives.Epoch(100)
+	period := params.BeaconConfig().EpochsPerSyncCommitteePeriod
+	// Active subnets of the current period.
+	cache.SyncSubnetIDs.AddSyncCommitteeSubnets([]byte("pubkey1"), currEpoch, []uint64{0, 1}, 10*time.Second)
+	// Subnets of the next period.
+	cache.SyncSubnetIDs.AddSyncCommitteeSubnets([]byte("pubkey2"), currEpoch+period, []uint64{1, 3}, 10*time.Second)
+	// Subnets too far in the future.
+	cache.SyncSubnetIDs.AddSyncCommitteeSubnets([]byte("pubkey3"), currEpoch+3*period, []uint64{2}, 10*time.Second)
+
+	active := s.retrieveActiveSyncSubnets(currEpoch)
+	assert.DeepEqual(t, []uint64{0, 1}, active)
+	assert.DeepEqual(t, []uint64{3}, s.retrieveUpcomingSyncSubnets(currEpoch, active))
+}
+
 func TestSubscribeWithSyncSubnets_StaticSwitchFork(t *testing.T) {
 	p := p2ptest.NewTestP2P(t)
 	params.SetupTestConfigCleanup(t)
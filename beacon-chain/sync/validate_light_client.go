@@ -0,0 +1,171 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	ssz "github.com/prysmaticlabs/fastssz"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing"
+	"github.com/prysmaticlabs/prysm/v5/monitoring/tracing/trace"
+	"github.com/prysmaticlabs/prysm/v5/proto/migration"
+)
+
+// validateLightClientFinalityUpdate validates a light client finality update received over gossip.
+// spec: https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/light-client/p2p-interface.md#light_client_finality_update
+func (s *Service) validateLightClientFinalityUpdate(ctx context.Context, pid peer.ID, msg *pubsub.Message) (pubsub.ValidationResult, error) {
+	// Validation runs on publish (not just subscriptions), so we should approve any message from
+	// ourselves.
+	if pid == s.cfg.p2p.PeerID() {
+		return pubsub.ValidationAccept, nil
+	}
+
+	// The node cannot compute the updates to compare with while syncing.
+	if s.cfg.initialSync.Syncing() {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	_, span := trace.StartSpan(ctx, "sync.validateLightClientFinalityUpdate")
+	defer span.End()
+
+	m, err := s.decodePubsubMessage(msg)
+	if err != nil {
+		tracing.AnnotateError(span, err)
+		return pubsub.ValidationReject, err
+	}
+
+	// [IGNORE] The received finality_update matches the locally computed one exactly.
+	local := s.latestLightClientFinalityUpdate()
+	if local == nil || local.Data == nil {
+		return pubsub.ValidationIgnore, nil
+	}
+	expected, err := migration.V2LightClientFinalityUpdateToV1Alpha1(local.Data)
+	if err != nil {
+		return pubsub.ValidationIgnore, err
+	}
+	if equal, err := sszEqual(m, expected); err != nil || !equal {
+		return pubsub.ValidationIgnore, err
+	}
+
+	// [IGNORE] The finality_update is received after the block at signature_slot was given enough time to
+	// propagate through the network.
+	if !s.lightClientUpdatePropagated(local.Data.SignatureSlot) {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	// [IGNORE] The finalized_header.beacon.slot is greater than that of all previously forwarded
+	// finality_updates, or it matches the highest previously forwarded slot and also has a supermajority of
+	// sync committee participants while the previously forwarded finality_update for that slot did not.
+	finalizedHeader, err := local.Data.FinalizedHeader.GetBeacon()
+	if err != nil {
+		return pubsub.ValidationIgnore, err
+	}
+	if local.Data.SyncAggregate == nil {
+		return pubsub.ValidationIgnore, errors.New("nil sync aggregate")
+	}
+	supermajority := local.Data.SyncAggregate.SyncCommitteeBits.Count()*3 > params.BeaconConfig().SyncCommitteeSize*2
+
+	s.lightClientUpdateLock.Lock()
+	defer s.lightClientUpdateLock.Unlock()
+	forwarded := s.lightClientFinalityForwardedSlot
+	if finalizedHeader.Slot < forwarded ||
+		(finalizedHeader.Slot == forwarded && (!supermajority || s.lightClientFinalityForwardedSupermajority)) {
+		return pubsub.ValidationIgnore, nil
+	}
+	s.lightClientFinalityForwardedSlot = finalizedHeader.Slot
+	s.lightClientFinalityForwardedSupermajority = supermajority
+
+	msg.ValidatorData = m // Used in downstream subscriber
+	return pubsub.ValidationAccept, nil
+}
+
+// validateLightClientOptimisticUpdate validates a light client optimistic update received over gossip.
+// spec: https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/light-client/p2p-interface.md#light_client_optimistic_update
+func (s *Service) validateLightClientOptimisticUpdate(ctx context.Context, pid peer.ID, msg *pubsub.Message) (pubsub.ValidationResult, error) {
+	// Validation runs on publish (not just subscriptions), so we should approve any message from
+	// ourselves.
+	if pid == s.cfg.p2p.PeerID() {
+		return pubsub.ValidationAccept, nil
+	}
+
+	// The node cannot compute the updates to compare with while syncing.
+	if s.cfg.initialSync.Syncing() {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	_, span := trace.StartSpan(ctx, "sync.validateLightClientOptimisticUpdate")
+	defer span.End()
+
+	m, err := s.decodePubsubMessage(msg)
+	if err != nil {
+		tracing.AnnotateError(span, err)
+		return pubsub.ValidationReject, err
+	}
+
+	// [IGNORE] The received optimistic_update matches the locally computed one exactly.
+	local := s.latestLightClientOptimisticUpdate()
+	if local == nil || local.Data == nil {
+		return pubsub.ValidationIgnore, nil
+	}
+	expected, err := migration.V2LightClientOptimisticUpdateToV1Alpha1(local.Data)
+	if err != nil {
+		return pubsub.ValidationIgnore, err
+	}
+	if equal, err := sszEqual(m, expected); err != nil || !equal {
+		return pubsub.ValidationIgnore, err
+	}
+
+	// [IGNORE] The optimistic_update is received after the block at signature_slot was given enough time to
+	// propagate through the network.
+	if !s.lightClientUpdatePropagated(local.Data.SignatureSlot) {
+		return pubsub.ValidationIgnore, nil
+	}
+
+	// [IGNORE] The attested_header.beacon.slot is greater than that of all previously forwarded
+	// optimistic_updates.
+	attestedHeader, err := local.Data.AttestedHeader.GetBeacon()
+	if err != nil {
+		return pubsub.ValidationIgnore, err
+	}
+
+	s.lightClientUpdateLock.Lock()
+	defer s.lightClientUpdateLock.Unlock()
+	if attestedHeader.Slot <= s.lightClientOptimisticForwardedSlot {
+		return pubsub.ValidationIgnore, nil
+	}
+	s.lightClientOptimisticForwardedSlot = attestedHeader.Slot
+
+	msg.ValidatorData = m // Used in downstream subscriber
+	return pubsub.ValidationAccept, nil
+}
+
+// lightClientUpdatePropagated returns whether a third of the signature slot has passed, allowing for the
+// gossip clock disparity.
+func (s *Service) lightClientUpdatePropagated(signatureSlot primitives.Slot) bool {
+	cfg := params.BeaconConfig()
+	due := s.cfg.clock.SlotStart(signatureSlot).
+		Add(time.Duration(cfg.SecondsPerSlot) * time.Second / time.Duration(cfg.IntervalsPerSlot)).
+		Add(-cfg.MaximumGossipClockDisparityDuration())
+	return !s.cfg.clock.Now().Before(due)
+}
+
+func sszEqual(a interface{}, b ssz.Marshaler) (bool, error) {
+	am, ok := a.(ssz.Marshaler)
+	if !ok {
+		return false, errWrongMessage
+	}
+	aBytes, err := am.MarshalSSZ()
+	if err != nil {
+		return false, err
+	}
+	bBytes, err := b.MarshalSSZ()
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aBytes, bBytes), nil
+}
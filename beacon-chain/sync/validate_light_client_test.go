@@ -0,0 +1,165 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	ssz "github.com/prysmaticlabs/fastssz"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	p2ptest "github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/startup"
+	mockSync "github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/initial-sync/testing"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
+	"github.com/prysmaticlabs/prysm/v5/proto/migration"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+	"google.golang.org/protobuf/proto"
+)
+
+func lightClientGossipMessage(t *testing.T, p *p2ptest.TestP2P, topicFormat string, vRoot [32]byte, obj ssz.Marshaler) *pubsub.Message {
+	digest, err := signing.ComputeForkDigest(params.BeaconConfig().AltairForkVersion, vRoot[:])
+	require.NoError(t, err)
+	buf := new(bytes.Buffer)
+	_, err = p.Encoding().EncodeGossip(buf, obj)
+	require.NoError(t, err)
+	topic := fmt.Sprintf(topicFormat, digest) + p.Encoding().ProtocolSuffix()
+	return &pubsub.Message{
+		Message: &pubsubpb.Message{
+			Data:  buf.Bytes(),
+			Topic: &topic,
+		},
+	}
+}
+
+func TestValidateLightClientOptimisticUpdate(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.AltairForkEpoch = 0
+	params.OverrideBeaconConfig(cfg)
+
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	update, err := lightclient.NewLightClientOptimisticUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock)
+	require.NoError(t, err)
+	gossiped, err := migration.V2LightClientOptimisticUpdateToV1Alpha1(update)
+	require.NoError(t, err)
+
+	vRoot := [32]byte{'A'}
+	p := p2ptest.NewTestP2P(t)
+	s := &Service{
+		cfg: &config{
+			p2p:         p,
+			initialSync: &mockSync.Sync{IsSyncing: false},
+			clock:       startup.NewClock(time.Unix(0, 0), vRoot),
+		},
+	}
+	ctx := context.Background()
+	msg := func() *pubsub.Message {
+		return lightClientGossipMessage(t, p, p2p.LightClientOptimisticUpdateTopicFormat, vRoot, gossiped)
+	}
+
+	// No update has been computed locally yet.
+	res, err := s.validateLightClientOptimisticUpdate(ctx, "foo", msg())
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationIgnore, res)
+
+	s.lightClientOptimisticUpdate = &ethpbv2.LightClientOptimisticUpdateWithVersion{
+		Version: ethpbv2.Version(l.AttestedBlock.Version()),
+		Data:    update,
+	}
+	m := msg()
+	res, err = s.validateLightClientOptimisticUpdate(ctx, "foo", m)
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationAccept, res)
+	require.NotNil(t, m.ValidatorData)
+
+	// The same update is not forwarded twice.
+	res, err = s.validateLightClientOptimisticUpdate(ctx, "foo", msg())
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationIgnore, res)
+}
+
+func TestValidateLightClientOptimisticUpdate_Mismatch(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.AltairForkEpoch = 0
+	params.OverrideBeaconConfig(cfg)
+
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	update, err := lightclient.NewLightClientOptimisticUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock)
+	require.NoError(t, err)
+	gossiped, err := migration.V2LightClientOptimisticUpdateToV1Alpha1(update)
+	require.NoError(t, err)
+
+	vRoot := [32]byte{'A'}
+	p := p2ptest.NewTestP2P(t)
+	s := &Service{
+		cfg: &config{
+			p2p:         p,
+			initialSync: &mockSync.Sync{IsSyncing: false},
+			clock:       startup.NewClock(time.Unix(0, 0), vRoot),
+		},
+	}
+	local, ok := proto.Clone(update).(*ethpbv2.LightClientOptimisticUpdate)
+	require.Equal(t, true, ok)
+	local.SignatureSlot++
+	s.lightClientOptimisticUpdate = &ethpbv2.LightClientOptimisticUpdateWithVersion{
+		Version: ethpbv2.Version(l.AttestedBlock.Version()),
+		Data:    local,
+	}
+	msg := lightClientGossipMessage(t, p, p2p.LightClientOptimisticUpdateTopicFormat, vRoot, gossiped)
+	res, err := s.validateLightClientOptimisticUpdate(context.Background(), "foo", msg)
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationIgnore, res)
+}
+
+func TestValidateLightClientFinalityUpdate(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.AltairForkEpoch = 0
+	params.OverrideBeaconConfig(cfg)
+
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	update, err := lightclient.NewLightClientFinalityUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock, l.FinalizedBlock)
+	require.NoError(t, err)
+	gossiped, err := migration.V2LightClientFinalityUpdateToV1Alpha1(update)
+	require.NoError(t, err)
+
+	vRoot := [32]byte{'A'}
+	p := p2ptest.NewTestP2P(t)
+	s := &Service{
+		cfg: &config{
+			p2p:         p,
+			initialSync: &mockSync.Sync{IsSyncing: false},
+			clock:       startup.NewClock(time.Unix(0, 0), vRoot),
+		},
+	}
+	s.lightClientFinalityUpdate = &ethpbv2.LightClientFinalityUpdateWithVersion{
+		Version: ethpbv2.Version(l.AttestedBlock.Version()),
+		Data:    update,
+	}
+	ctx := context.Background()
+
+	// Nothing is forwarded while syncing.
+	s.cfg.initialSync = &mockSync.Sync{IsSyncing: true}
+	res, err := s.validateLightClientFinalityUpdate(ctx, "foo", lightClientGossipMessage(t, p, p2p.LightClientFinalityUpdateTopicFormat, vRoot, gossiped))
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationIgnore, res)
+
+	s.cfg.initialSync = &mockSync.Sync{IsSyncing: false}
+	res, err = s.validateLightClientFinalityUpdate(ctx, "foo", lightClientGossipMessage(t, p, p2p.LightClientFinalityUpdateTopicFormat, vRoot, gossiped))
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationAccept, res)
+
+	// The same finalized slot without an improvement in participation is not forwarded again.
+	res, err = s.validateLightClientFinalityUpdate(ctx, "foo", lightClientGossipMessage(t, p, p2p.LightClientFinalityUpdateTopicFormat, vRoot, gossiped))
+	require.NoError(t, err)
+	require.Equal(t, pubsub.ValidationIgnore, res)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	beacondb "github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/storage"
 	"github.com/prysmaticlabs/prysm/v5/runtime/tos"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -18,10 +19,11 @@ var Commands = &cli.Command{
 	Subcommands: []*cli.Command{
 		{
 			Name:        "restore",
-			Description: `restores a database from a backup file`,
+			Description: `restores a database from a backup file or a snapshot archive`,
 			Flags: cmd.WrapFlags([]cli.Flag{
 				cmd.RestoreSourceFileFlag,
 				cmd.RestoreTargetDirFlag,
+				cmd.RestoreBlobTargetDirFlag,
 			}),
 			Before: tos.VerifyTosAcceptedOrPrompt,
 			Action: func(cliCtx *cli.Context) error {
@@ -31,5 +33,36 @@ var Commands = &cli.Command{
 				return nil
 			},
 		},
+		{
+			Name:        "export-snapshot",
+			Description: `writes a snapshot archive of the database and blobs of a stopped beacon node`,
+			Flags: cmd.WrapFlags([]cli.Flag{
+				cmd.DataDirFlag,
+				storage.BlobStoragePathFlag,
+				SnapshotOutputDirFlag,
+			}),
+			Before: tos.VerifyTosAcceptedOrPrompt,
+			Action: func(cliCtx *cli.Context) error {
+				if err := exportSnapshot(cliCtx); err != nil {
+					log.WithError(err).Fatal("Could not export snapshot")
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "import-era",
+			Description: `imports the blocks and states of a directory of era files into a database`,
+			Flags: cmd.WrapFlags([]cli.Flag{
+				cmd.DataDirFlag,
+				EraDirFlag,
+			}),
+			Before: tos.VerifyTosAcceptedOrPrompt,
+			Action: func(cliCtx *cli.Context) error {
+				if err := importEra(cliCtx); err != nil {
+					log.WithError(err).Fatal("Could not import era files")
+				}
+				return nil
+			},
+		},
 	},
 }
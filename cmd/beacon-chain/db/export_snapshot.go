@@ -0,0 +1,43 @@
+package db
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	beacondb "github.com/prysmaticlabs/prysm/v5/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// SnapshotOutputDirFlag is the directory the snapshot archive is written to.
+var SnapshotOutputDirFlag = &cli.StringFlag{
+	Name:  "snapshot-output-dir",
+	Usage: "Directory the snapshot archive is written to. Defaults to the backups directory of the database.",
+}
+
+func exportSnapshot(cliCtx *cli.Context) error {
+	ctx := cliCtx.Context
+	dir := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), kv.BeaconNodeDbDirName)
+	d, err := kv.NewKVStore(ctx, dir)
+	if err != nil {
+		return errors.Wrapf(err, "could not open database in %s", dir)
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+	blobs, err := filesystem.NewBlobStorage(filesystem.WithBasePath(storage.BlobStoragePath(cliCtx)))
+	if err != nil {
+		return err
+	}
+	outputDir := cliCtx.String(SnapshotOutputDirFlag.Name)
+	if outputDir == "" {
+		outputDir = filepath.Join(dir, kv.BackupsDirName)
+	}
+	_, _, err = beacondb.WriteSnapshot(ctx, d, blobs, outputDir)
+	return err
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/era"
+	"github.com/prysmaticlabs/prysm/v5/cmd"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/urfave/cli/v2"
+)
+
+// EraDirFlag is the directory holding the era files to import.
+var EraDirFlag = &cli.StringFlag{
+	Name:     "era-dir",
+	Usage:    "Directory of era files to import into the database.",
+	Required: true,
+}
+
+func importEra(cliCtx *cli.Context) error {
+	ctx := cliCtx.Context
+	a, err := era.OpenArchive(cliCtx.String(EraDirFlag.Name))
+	if err != nil {
+		return err
+	}
+	// The database encodes and verifies data according to the active config, which is the network of the files.
+	if err := params.SetActive(a.Config().Copy()); err != nil {
+		return err
+	}
+	dir := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), kv.BeaconNodeDbDirName)
+	d, err := kv.NewKVStore(ctx, dir)
+	if err != nil {
+		return errors.Wrapf(err, "could not open database in %s", dir)
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+	if err := era.Import(ctx, d, a); err != nil {
+		return err
+	}
+	log.WithField("eras", len(a.Eras())).Info("Era import completed successfully")
+	return nil
+}
@@ -4,6 +4,7 @@ package flags
 
 import (
 	"strings"
+	"time"
 
 	"github.com/prysmaticlabs/prysm/v5/cmd"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
@@ -180,6 +181,75 @@ var (
 		Usage: "The slot durations of when an archived state gets saved in the beaconDB.",
 		Value: 2048,
 	}
+	// StateDiffExponents stores finalized states as a hierarchy of snapshots and diffs instead of archive points.
+	StateDiffExponents = &cli.StringFlag{
+		Name: "state-diff-exponents",
+		Usage: "Stores finalized states as full snapshots and state diffs at slots which are multiples of powers of two, " +
+			"given as comma separated exponents from the coarsest to the finest, e.g. 21,18,16,13,11,9,5. " +
+			"Any finalized state can then be rebuilt without replaying blocks. Can not be changed once the database stores state diffs.",
+	}
+	// DBBackend selects the storage engine of a new beacon node database.
+	DBBackend = &cli.StringFlag{
+		Name: "db-backend",
+		Usage: "Storage engine of the beacon node database, bolt or pebble. Only used when creating a new database, " +
+			"an existing database keeps its backend and can be converted with prysmctl db convert.",
+		DefaultText: "bolt",
+	}
+	// DBCompactionFreeRatio enables the online compaction of the beacon node database.
+	DBCompactionFreeRatio = &cli.Float64Flag{
+		Name: "db-compaction-free-ratio",
+		Usage: "Compacts the beacon node database in the background while the node runs, whenever the share of its disk space " +
+			"which does not hold live data reaches the given ratio, e.g. 0.3. The database is copied next to itself, so the disk " +
+			"needs enough free space for its live data. Disabled by default.",
+	}
+	// HistoryRetentionEpochs enables the pruning of historical blocks and states.
+	HistoryRetentionEpochs = &cli.Uint64Flag{
+		Name: "history-retention-epochs",
+		Usage: "Deletes finalized blocks and states older than the given number of epochs from the beacon node database. " +
+			"The weak subjectivity period and the MIN_EPOCHS_FOR_BLOCK_REQUESTS epochs of blocks peers may request are always kept, " +
+			"whatever the value. Disabled by default, keeping the whole history.",
+	}
+	// BoundaryStateCacheSize enables the persistent cache of finalized epoch boundary states.
+	BoundaryStateCacheSize = &cli.Uint64Flag{
+		Name: "boundary-state-cache-size-mb",
+		Usage: "Size in MiB of the on-disk cache of finalized epoch boundary states, which historical states are regenerated from " +
+			"instead of replaying the blocks since the last archived state. The states queried the most are kept when it is full. " +
+			"Disabled by default.",
+	}
+	// BoundaryStateCacheDays sets how far back the boundary state cache keeps states.
+	BoundaryStateCacheDays = &cli.Uint64Flag{
+		Name:  "boundary-state-cache-days",
+		Usage: "Number of days before the finalized checkpoint the boundary state cache keeps states of.",
+		Value: 7,
+	}
+	// ReadOnlyReplica runs the node as a read-only replica of the database of another node.
+	ReadOnlyReplica = &cli.BoolFlag{
+		Name: "read-only-replica",
+		Usage: "Serves the REST API from the database and blobs of the data directory, which another beacon node writes to, " +
+			"without p2p, sync or writing to them. The replica opens the newest checkpoint of the database that node writes with " +
+			"--replica-checkpoint-interval, and follows the head saved in it. Needs a pebble database, created with " +
+			"--db-backend=pebble or converted with prysmctl db convert, and the writing node must set --replica-checkpoint-interval. " +
+			"Replicas of bolt databases are not supported.",
+	}
+	// ReplicaRefreshInterval sets how often a read-only replica looks for a newer checkpoint of its database.
+	ReplicaRefreshInterval = &cli.DurationFlag{
+		Name:  "replica-refresh-interval",
+		Usage: "How often a read-only replica looks for a newer checkpoint of the database of the node writing to it.",
+		Value: 2 * time.Second,
+	}
+	// ReplicaCheckpointInterval enables the checkpoints of the database which read-only replicas open.
+	ReplicaCheckpointInterval = &cli.DurationFlag{
+		Name: "replica-checkpoint-interval",
+		Usage: "Writes a checkpoint of the beacon node database for read-only replicas every interval, e.g. 12s. Checkpoints link the " +
+			"files of the database rather than copying them, and only the newest ones are kept. Needs the pebble backend. Disabled by default.",
+	}
+	// SnapshotAuthTokenFile enables the snapshot endpoint of the Prysm API.
+	SnapshotAuthTokenFile = &cli.StringFlag{
+		Name: "snapshot-auth-token-file",
+		Usage: "Path to a file holding the bearer token which authorizes requests to the /prysm/v1/node/snapshot endpoint, " +
+			"which writes a snapshot archive of the database and blobs to the --db-backup-output-dir directory. " +
+			"The endpoint is disabled without it.",
+	}
 	// BlockBatchLimit specifies the requested block batch size.
 	BlockBatchLimit = &cli.IntFlag{
 		Name:  "block-batch-limit",
@@ -204,6 +274,18 @@ var (
 		Usage: "The factor by which blob batch limit may increase on burst.",
 		Value: 2,
 	}
+	// BlockBytesLimit specifies the amount of block bytes served to a single peer per second.
+	BlockBytesLimit = &cli.IntFlag{
+		Name:  "block-bytes-limit",
+		Usage: "The amount of block bytes per second the local peer is bounded to respond with to a single peer. A value of 0 disables the limit.",
+		Value: 16 * 1024 * 1024,
+	}
+	// BlobBytesLimit specifies the amount of blob sidecar bytes served to a single peer per second.
+	BlobBytesLimit = &cli.IntFlag{
+		Name:  "blob-bytes-limit",
+		Usage: "The amount of blob sidecar bytes per second the local peer is bounded to respond with to a single peer. A value of 0 disables the limit.",
+		Value: 16 * 1024 * 1024,
+	}
 	// DisableDebugRPCEndpoints disables the debug Beacon API namespace.
 	DisableDebugRPCEndpoints = &cli.BoolFlag{
 		Name:  "disable-debug-rpc-endpoints",
@@ -59,10 +59,22 @@ var appFlags = []cli.Flag{
 	flags.BlockBatchLimitBurstFactor,
 	flags.BlobBatchLimit,
 	flags.BlobBatchLimitBurstFactor,
+	flags.BlockBytesLimit,
+	flags.BlobBytesLimit,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropNumValidatorsFlag,
 	flags.InteropGenesisTimeFlag,
 	flags.SlotsPerArchivedPoint,
+	flags.StateDiffExponents,
+	flags.DBBackend,
+	flags.DBCompactionFreeRatio,
+	flags.HistoryRetentionEpochs,
+	flags.SnapshotAuthTokenFile,
+	flags.BoundaryStateCacheSize,
+	flags.BoundaryStateCacheDays,
+	flags.ReadOnlyReplica,
+	flags.ReplicaRefreshInterval,
+	flags.ReplicaCheckpointInterval,
 	flags.DisableDebugRPCEndpoints,
 	flags.SubscribeToAllSubnets,
 	flags.HistoricalSlasherNode,
@@ -104,6 +116,9 @@ var appFlags = []cli.Flag{
 	cmd.P2PAllowList,
 	cmd.P2PDenyList,
 	cmd.PubsubQueueSize,
+	cmd.P2PGossipTraceFile,
+	cmd.P2PGossipTraceSampleRate,
+	cmd.P2PGossipTraceMaxSize,
 	cmd.DataDirFlag,
 	cmd.VerbosityFlag,
 	cmd.EnableTracingFlag,
@@ -138,6 +153,9 @@ var appFlags = []cli.Flag{
 	checkpoint.BlockPath,
 	checkpoint.StatePath,
 	checkpoint.RemoteURL,
+	checkpoint.LightClientRoot,
+	checkpoint.ProviderURLs,
+	checkpoint.Quorum,
 	genesis.StatePath,
 	genesis.BeaconAPIURL,
 	flags.SlasherDirFlag,
@@ -148,6 +166,9 @@ var appFlags = []cli.Flag{
 	bflags.BackfillBatchSize,
 	bflags.BackfillWorkerCount,
 	bflags.BackfillOldestSlot,
+	bflags.BackfillBandwidthLimit,
+	bflags.BackfillActiveHours,
+	bflags.BackfillEraDir,
 }
 
 func init() {
@@ -36,12 +36,14 @@ func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 		return nil, err
 	}
 	opts := []node.Option{node.WithBlobStorageOptions(
-		filesystem.WithBlobRetentionEpochs(e), filesystem.WithBasePath(blobStoragePath(c)),
+		filesystem.WithBlobRetentionEpochs(e), filesystem.WithBasePath(BlobStoragePath(c)),
 	)}
 	return opts, nil
 }
 
-func blobStoragePath(c *cli.Context) string {
+// BlobStoragePath returns the directory of blob storage, which is the blobs directory of the data directory unless
+// another one is given.
+func BlobStoragePath(c *cli.Context) string {
 	blobsPath := c.Path(BlobStoragePathFlag.Name)
 	if blobsPath == "" {
 		// append a "blobs" subdir to the end of the data dir path
@@ -18,7 +18,7 @@ func TestBlobStoragePath_NoFlagSpecified(t *testing.T) {
 	set := flag.NewFlagSet("test", 0)
 	set.String(cmd.DataDirFlag.Name, cmd.DataDirFlag.Value, cmd.DataDirFlag.Usage)
 	cliCtx := cli.NewContext(&app, set, nil)
-	storagePath := blobStoragePath(cliCtx)
+	storagePath := BlobStoragePath(cliCtx)
 
 	assert.Equal(t, cmd.DefaultDataDir()+"/blobs", storagePath)
 }
@@ -28,7 +28,7 @@ func TestBlobStoragePath_FlagSpecified(t *testing.T) {
 	set := flag.NewFlagSet("test", 0)
 	set.String(BlobStoragePathFlag.Name, "/blah/blah", BlobStoragePathFlag.Usage)
 	cliCtx := cli.NewContext(&app, set, nil)
-	storagePath := blobStoragePath(cliCtx)
+	storagePath := BlobStoragePath(cliCtx)
 
 	assert.Equal(t, "/blah/blah", storagePath)
 }
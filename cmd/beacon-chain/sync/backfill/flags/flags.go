@@ -40,4 +40,22 @@ var (
 		Usage: "Specifies the oldest slot that backfill should download. " +
 			"If this value is greater than current_slot - MIN_EPOCHS_FOR_BLOCK_REQUESTS, it will be ignored with a warning log.",
 	}
+	// BackfillBandwidthLimit caps the average download rate of backfill, to leave room for other uses of the network.
+	BackfillBandwidthLimit = &cli.Uint64Flag{
+		Name: "backfill-bandwidth-limit",
+		Usage: "Maximum average download rate of backfill, in bytes per second. " +
+			"Zero means no limit. The limit can also be changed at runtime through the /prysm/v1/node/backfill/config endpoint.",
+	}
+	// BackfillActiveHours restricts backfill to a daily window, so that it can run outside of peak hours.
+	BackfillActiveHours = &cli.StringFlag{
+		Name: "backfill-active-hours",
+		Usage: "Daily window of UTC hours during which backfill downloads blocks, written as start-end, e.g. 22-6. " +
+			"Backfill runs at any time when not set.",
+	}
+	// BackfillEraDir points backfill at a directory of era files, which are read in place of downloading from peers.
+	BackfillEraDir = &cli.StringFlag{
+		Name: "backfill-era-dir",
+		Usage: "Directory of era files for the network. Backfill reads the blocks of the eras found there " +
+			"instead of requesting them from peers, and downloads the rest as usual.",
+	}
 )
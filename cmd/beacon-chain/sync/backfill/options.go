@@ -1,9 +1,14 @@
 package backfill
 
 import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/era"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/node"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/backfill"
 	"github.com/prysmaticlabs/prysm/v5/cmd/beacon-chain/sync/backfill/flags"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
 	"github.com/urfave/cli/v2"
 )
@@ -22,6 +27,27 @@ func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 			uv := c.Uint64(flags.BackfillOldestSlot.Name)
 			bno = append(bno, backfill.WithMinimumSlot(primitives.Slot(uv)))
 		}
+		if c.IsSet(flags.BackfillBandwidthLimit.Name) {
+			bno = append(bno, backfill.WithBandwidthLimit(c.Uint64(flags.BackfillBandwidthLimit.Name)))
+		}
+		if c.IsSet(flags.BackfillActiveHours.Name) {
+			hours, err := backfill.ParseActiveHours(c.String(flags.BackfillActiveHours.Name))
+			if err != nil {
+				return errors.Wrapf(err, "invalid value for --%s", flags.BackfillActiveHours.Name)
+			}
+			bno = append(bno, backfill.WithActiveHours(hours))
+		}
+		if c.IsSet(flags.BackfillEraDir.Name) {
+			a, err := era.OpenArchive(c.String(flags.BackfillEraDir.Name))
+			if err != nil {
+				return errors.Wrapf(err, "invalid value for --%s", flags.BackfillEraDir.Name)
+			}
+			if a.Config().ConfigName != params.BeaconConfig().ConfigName {
+				return fmt.Errorf("era files in --%s are for network %s, node is running %s",
+					flags.BackfillEraDir.Name, a.Config().ConfigName, params.BeaconConfig().ConfigName)
+			}
+			bno = append(bno, backfill.WithBlockArchive(a))
+		}
 		node.BackfillOpts = bno
 		return nil
 	}
@@ -3,9 +3,11 @@ package checkpoint
 import (
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/node"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/sync/checkpoint"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	"github.com/urfave/cli/v2"
 )
 
@@ -28,6 +30,25 @@ var (
 			"As an additional safety measure, it is strongly recommended to only use this option in conjunction with " +
 			"--weak-subjectivity-checkpoint flag",
 	}
+	// LightClientRoot defines a trusted block root to start following the light client sync protocol from.
+	LightClientRoot = &cli.StringFlag{
+		Name: "checkpoint-sync-light-client-root",
+		Usage: "Hex-encoded trusted block root. When used with --checkpoint-sync-url, the node follows the light client " +
+			"sync protocol from this root to the latest finalized header and only accepts a checkpoint state matching it.",
+	}
+	// ProviderURLs defines several beacon nodes which have to agree on the finalized checkpoint used for checkpoint sync.
+	ProviderURLs = &cli.StringSliceFlag{
+		Name: "checkpoint-sync-provider",
+		Usage: "URL of a synced beacon node to use as a checkpoint sync provider. May be specified multiple times. " +
+			"The finalized checkpoint is only trusted if --checkpoint-sync-quorum of the providers agree on it.",
+	}
+	// Quorum defines the amount of checkpoint sync providers which have to agree on the finalized checkpoint.
+	Quorum = &cli.IntFlag{
+		Name: "checkpoint-sync-quorum",
+		Usage: "Number of --checkpoint-sync-provider nodes that have to report the same finalized checkpoint " +
+			"before it is used. Defaults to a simple majority of the providers. Checkpoint sync fails if several " +
+			"checkpoints reach a quorum of at most half of the providers.",
+	}
 )
 
 // BeaconNodeOptions is responsible for determining if the checkpoint sync options have been used, and if so,
@@ -37,6 +58,41 @@ func BeaconNodeOptions(c *cli.Context) ([]node.Option, error) {
 	blockPath := c.Path(BlockPath.Name)
 	statePath := c.Path(StatePath.Name)
 	remoteURL := c.String(RemoteURL.Name)
+	providers := c.StringSlice(ProviderURLs.Name)
+	lightClientRoot := c.String(LightClientRoot.Name)
+	if len(providers) > 0 {
+		if remoteURL != "" || lightClientRoot != "" {
+			return nil, fmt.Errorf("--%s can not be combined with --%s or --%s", ProviderURLs.Name, RemoteURL.Name, LightClientRoot.Name)
+		}
+		quorum := c.Int(Quorum.Name)
+		opt := func(node *node.BeaconNode) error {
+			var err error
+			node.CheckpointInitializer, err = checkpoint.NewQuorumInitializer(providers, quorum)
+			if err != nil {
+				return errors.Wrap(err, "error while constructing beacon node api clients for checkpoint sync")
+			}
+			return nil
+		}
+		return []node.Option{opt}, nil
+	}
+	if lightClientRoot != "" {
+		if remoteURL == "" {
+			return nil, fmt.Errorf("--%s requires --%s", LightClientRoot.Name, RemoteURL.Name)
+		}
+		root, err := hexutil.Decode(lightClientRoot)
+		if err != nil || len(root) != 32 {
+			return nil, fmt.Errorf("--%s must be a hex-encoded 32 byte block root", LightClientRoot.Name)
+		}
+		opt := func(node *node.BeaconNode) error {
+			var err error
+			node.CheckpointInitializer, err = checkpoint.NewLightClientInitializer(remoteURL, bytesutil.ToBytes32(root))
+			if err != nil {
+				return errors.Wrap(err, "error while constructing beacon node api client for checkpoint sync")
+			}
+			return nil
+		}
+		return []node.Option{opt}, nil
+	}
 	if remoteURL != "" {
 		opt := func(node *node.BeaconNode) error {
 			var err error
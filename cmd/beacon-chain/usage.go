@@ -111,10 +111,22 @@ var appHelpFlagGroups = []flagGroup{
 			flags.ExecutionJWTSecretFlag,
 			flags.SetGCPercent,
 			flags.SlotsPerArchivedPoint,
+			flags.StateDiffExponents,
+			flags.DBBackend,
+			flags.DBCompactionFreeRatio,
+			flags.HistoryRetentionEpochs,
+			flags.SnapshotAuthTokenFile,
+			flags.BoundaryStateCacheSize,
+			flags.BoundaryStateCacheDays,
+			flags.ReadOnlyReplica,
+			flags.ReplicaRefreshInterval,
+			flags.ReplicaCheckpointInterval,
 			flags.BlockBatchLimit,
 			flags.BlockBatchLimitBurstFactor,
 			flags.BlobBatchLimit,
 			flags.BlobBatchLimitBurstFactor,
+			flags.BlockBytesLimit,
+			flags.BlobBytesLimit,
 			flags.DisableDebugRPCEndpoints,
 			flags.SubscribeToAllSubnets,
 			flags.HistoricalSlasherNode,
@@ -136,6 +148,9 @@ var appHelpFlagGroups = []flagGroup{
 			checkpoint.BlockPath,
 			checkpoint.StatePath,
 			checkpoint.RemoteURL,
+			checkpoint.LightClientRoot,
+			checkpoint.ProviderURLs,
+			checkpoint.Quorum,
 			genesis.StatePath,
 			genesis.BeaconAPIURL,
 			storage.BlobStoragePathFlag,
@@ -144,6 +159,9 @@ var appHelpFlagGroups = []flagGroup{
 			backfill.BackfillWorkerCount,
 			backfill.BackfillBatchSize,
 			backfill.BackfillOldestSlot,
+			backfill.BackfillBandwidthLimit,
+			backfill.BackfillActiveHours,
+			backfill.BackfillEraDir,
 		},
 	},
 	{
@@ -168,6 +186,9 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.P2PAllowList,
 			cmd.P2PDenyList,
 			cmd.PubsubQueueSize,
+			cmd.P2PGossipTraceFile,
+			cmd.P2PGossipTraceSampleRate,
+			cmd.P2PGossipTraceMaxSize,
 			cmd.StaticPeers,
 			cmd.EnableUPnPFlag,
 			flags.MinSyncPeers,
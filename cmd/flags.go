@@ -191,6 +191,25 @@ var (
 		Usage: "The size of the pubsub validation and outbound queue for the node.",
 		Value: 1000,
 	}
+	// P2PGossipTraceFile defines a flag to specify the file gossip message events are traced to.
+	P2PGossipTraceFile = &cli.StringFlag{
+		Name: "p2p-gossip-trace-file",
+		Usage: "Writes receive, validate, reject, duplicate and deliver events of gossip messages to the given file, " +
+			"one JSON object per line. The file is rotated once it reaches --p2p-gossip-trace-max-size. Disabled by default.",
+	}
+	// P2PGossipTraceSampleRate defines a flag to specify the fraction of gossip messages traced.
+	P2PGossipTraceSampleRate = &cli.Float64Flag{
+		Name: "p2p-gossip-trace-sample-rate",
+		Usage: "The fraction of gossip messages to trace, between 0 and 1. Messages are sampled by message ID, " +
+			"so nodes using the same rate trace the same messages.",
+		Value: 1,
+	}
+	// P2PGossipTraceMaxSize defines a flag to specify the size at which the gossip trace file is rotated.
+	P2PGossipTraceMaxSize = &cli.IntFlag{
+		Name:  "p2p-gossip-trace-max-size",
+		Usage: "The size in megabytes at which the gossip trace file is rotated. The last 10 rotated files are kept.",
+		Value: 100,
+	}
 	// ForceClearDB removes any previously stored data at the data directory.
 	ForceClearDB = &cli.BoolFlag{
 		Name:  "force-clear-db",
@@ -267,6 +286,11 @@ var (
 		Usage: "Target directory of the restored database",
 		Value: DefaultDataDir(),
 	}
+	// RestoreBlobTargetDirFlag specifies the target directory of the blobs restored from a snapshot archive.
+	RestoreBlobTargetDirFlag = &cli.StringFlag{
+		Name:  "restore-blob-target-dir",
+		Usage: "Target directory of the blobs restored from a snapshot archive. Defaults to the blobs directory of the restore target directory",
+	}
 	// ApiTimeoutFlag specifies the timeout value for API requests in seconds. A timeout of zero means no timeout.
 	ApiTimeoutFlag = &cli.DurationFlag{
 		Name:  "api-timeout",
@@ -2,11 +2,14 @@ package checkpointsync
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/prysmaticlabs/prysm/v5/api/client"
 	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
@@ -14,6 +17,7 @@ import (
 var downloadFlags = struct {
 	BeaconNodeHost string
 	Timeout        time.Duration
+	StateRoot      string
 }{}
 
 var downloadCmd = &cli.Command{
@@ -39,6 +43,12 @@ var downloadCmd = &cli.Command{
 			Destination: &downloadFlags.Timeout,
 			Value:       time.Minute * 4,
 		},
+		&cli.StringFlag{
+			Name: "state-root",
+			Usage: "hex encoded root of the state to download instead of the latest finalized state. " +
+				"An interrupted download of the state with this root in the working directory is resumed",
+			Destination: &downloadFlags.StateRoot,
+		},
 	},
 }
 
@@ -57,7 +67,25 @@ func cliActionDownload(_ *cli.Context) error {
 		return err
 	}
 
-	od, err := beacon.DownloadFinalizedData(ctx, client)
+	// A partial download is only resumed for the root the state id resolves to, so the root of an interrupted
+	// download that is no longer finalized has to be passed explicitly.
+	stateId := beacon.IdFinalized
+	if f.StateRoot != "" {
+		root, err := hexutil.Decode(f.StateRoot)
+		if err != nil || len(root) != 32 {
+			return fmt.Errorf("invalid state root %s, expected a 0x prefixed 32 byte hex string", f.StateRoot)
+		}
+		stateId = beacon.IdFromRoot(bytesutil.ToBytes32(root))
+	} else {
+		roots, err := beacon.PartialStateRoots(cwd)
+		if err != nil {
+			return err
+		}
+		for _, r := range roots {
+			log.Printf("found interrupted download of state with root %#x, use --state-root=%#x to resume it", r, r)
+		}
+	}
+	od, err := beacon.DownloadOriginDataToDir(ctx, client, stateId, cwd)
 	if err != nil {
 		return err
 	}
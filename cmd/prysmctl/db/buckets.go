@@ -3,7 +3,10 @@ package db
 import (
 	"fmt"
 
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
@@ -12,9 +15,14 @@ var bucketsFlags = struct {
 }{}
 
 var bucketsCmd = &cli.Command{
-	Name:   "buckets",
-	Usage:  "list db buckets",
-	Action: bucketsAction,
+	Name:  "buckets",
+	Usage: "list db buckets, with their size when a db path is given",
+	Action: func(cliCtx *cli.Context) error {
+		if err := bucketsAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not report db size")
+		}
+		return nil
+	},
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:        "path",
@@ -25,8 +33,42 @@ var bucketsCmd = &cli.Command{
 }
 
 func bucketsAction(_ *cli.Context) error {
-	for _, b := range kv.Buckets {
-		fmt.Printf("%s\n", string(b))
+	if bucketsFlags.Path == "" {
+		for _, b := range kv.Buckets {
+			fmt.Printf("%s\n", string(b))
+		}
+		return nil
+	}
+	db, err := getDB(bucketsFlags.Path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.WithError(err).Error("Could not close db")
+		}
+	}()
+	r, err := engine.Sizes(db)
+	if err != nil {
+		return err
+	}
+
+	tw := table.NewWriter()
+	tw.AppendHeader(table.Row{"Bucket", "Keys", "Bytes", "Allocated bytes"})
+	keys, bytes, allocated := 0, int64(0), int64(0)
+	for _, b := range r.Buckets {
+		tw.AppendRow(table.Row{string(b.Name), b.Keys, b.Bytes, b.AllocatedBytes})
+		keys += b.Keys
+		bytes += b.Bytes
+		allocated += b.AllocatedBytes
+	}
+	tw.AppendFooter(table.Row{"Total", keys, bytes, allocated})
+	displayTable(tw)
+	fmt.Printf("Backend: %s\n", r.Backend)
+	fmt.Printf("Disk bytes: %d\n", r.DiskBytes)
+	if r.Backend == engine.Bolt {
+		fmt.Printf("Free pages: %d\n", r.FreePages)
 	}
+	fmt.Printf("Free bytes: %d\n", r.FreeBytes)
 	return nil
 }
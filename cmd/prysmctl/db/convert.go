@@ -0,0 +1,108 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var convertFlags = struct {
+	Path    string
+	Out     string
+	Backend string
+}{}
+
+var convertCmd = &cli.Command{
+	Name:  "convert",
+	Usage: "copy a beacon db into a new db using another storage backend",
+	Action: func(cliCtx *cli.Context) error {
+		if err := convertAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not convert db")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "path",
+			Usage:       "path to directory containing the db to convert",
+			Destination: &convertFlags.Path,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "out",
+			Usage:       "directory where the converted db is written",
+			Destination: &convertFlags.Out,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "backend",
+			Usage:       fmt.Sprintf("storage backend of the converted db, one of %v", engine.Backends),
+			Destination: &convertFlags.Backend,
+			Required:    true,
+		},
+	},
+}
+
+func convertAction(cliCtx *cli.Context) error {
+	flags := convertFlags
+	to, err := engine.ParseBackend(flags.Backend)
+	if err != nil {
+		return err
+	}
+	from, ok, err := kv.DetectBackend(flags.Path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no db found in %s", flags.Path)
+	}
+	_, exists, err := kv.DetectBackend(flags.Out)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("a db already exists in %s", flags.Out)
+	}
+	if err := file.MkdirAll(flags.Out); err != nil {
+		return err
+	}
+
+	src, err := kv.OpenEngine(flags.Path, from, true)
+	if err != nil {
+		return errors.Wrapf(err, "could not open db at path %s", flags.Path)
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			log.WithError(err).Error("Could not close db")
+		}
+	}()
+	dst, err := kv.OpenEngine(flags.Out, to, false)
+	if err != nil {
+		return errors.Wrapf(err, "could not create db at path %s", flags.Out)
+	}
+	defer func() {
+		if err := dst.Close(); err != nil {
+			log.WithError(err).Error("Could not close converted db")
+		}
+	}()
+
+	log.WithFields(log.Fields{
+		"from": from,
+		"to":   to,
+	}).Info("Converting db")
+	if err := engine.Copy(cliCtx.Context, src, dst, func(bucket []byte, keys int) {
+		log.WithFields(log.Fields{
+			"bucket": string(bucket),
+			"keys":   keys,
+		}).Info("Copied keys")
+	}); err != nil {
+		return err
+	}
+	log.WithField("path", kv.StoreDataPath(flags.Out, to)).Info("Converted db")
+	return nil
+}
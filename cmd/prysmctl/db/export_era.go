@@ -0,0 +1,94 @@
+package db
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/era"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/io/file"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var exportEraFlags = struct {
+	Path string
+	Out  string
+	From uint64
+	To   uint64
+}{}
+
+var exportEraCmd = &cli.Command{
+	Name:  "export-era",
+	Usage: "export the finalized history of a beacon db as era files",
+	Action: func(cliCtx *cli.Context) error {
+		if err := exportEraAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not export era files")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "path",
+			Usage:       "path to directory containing beaconchain.db",
+			Destination: &exportEraFlags.Path,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "out",
+			Usage:       "directory where the era files are written",
+			Destination: &exportEraFlags.Out,
+			Required:    true,
+		},
+		&cli.Uint64Flag{
+			Name:        "from",
+			Usage:       "first era to export",
+			Destination: &exportEraFlags.From,
+		},
+		&cli.Uint64Flag{
+			Name:        "to",
+			Usage:       "last era to export, defaults to the last finalized era",
+			Destination: &exportEraFlags.To,
+			DefaultText: "last finalized era",
+		},
+	},
+}
+
+func exportEraAction(cliCtx *cli.Context) error {
+	flags := exportEraFlags
+	ctx := cliCtx.Context
+	d, err := kv.NewKVStore(ctx, flags.Path)
+	if err != nil {
+		return errors.Wrapf(err, "could not open db at path %s", flags.Path)
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.WithError(err).Error("Could not close db")
+		}
+	}()
+	e, err := era.NewExporter(ctx, d)
+	if err != nil {
+		return err
+	}
+	// State replay uses the active config, which has to match the network of the database.
+	if err := params.SetActive(e.Config().Copy()); err != nil {
+		return err
+	}
+	to := e.LastEra
+	if cliCtx.IsSet("to") {
+		to = flags.To
+	}
+	if flags.From > to {
+		return errors.Errorf("--from %d is after --to %d", flags.From, to)
+	}
+	if err := file.MkdirAll(flags.Out); err != nil {
+		return err
+	}
+	for i := flags.From; i <= to; i++ {
+		p, err := e.Export(ctx, flags.Out, i)
+		if err != nil {
+			return errors.Wrapf(err, "could not export era %d", i)
+		}
+		log.WithField("era", i).WithField("path", p).Info("Exported era file")
+	}
+	return nil
+}
@@ -3,14 +3,13 @@ package db
 import (
 	"bytes"
 	"fmt"
-	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
-	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/engine"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	bolt "go.etcd.io/bbolt"
 )
 
 var queryFlags = struct {
@@ -32,7 +31,7 @@ var queryCmd = &cli.Command{
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:        "bucket",
-			Usage:       "boltdb bucket to search",
+			Usage:       "db bucket to search",
 			Destination: &queryFlags.Bucket,
 		},
 		&cli.StringFlag{
@@ -59,13 +58,18 @@ func queryAction(_ *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.WithError(err).Error("Could not close db")
+		}
+	}()
 	if flags.Prefix != "" {
 		return prefixScan(db, flags.Bucket, flags.Prefix, flags.KeysOnly)
 	}
 	return nil
 }
 
-func prefixScan(db *bolt.DB, bucket, prefix string, keysOnly bool) error {
+func prefixScan(db engine.DB, bucket, prefix string, keysOnly bool) error {
 	if !keysOnly {
 		return errors.New("prefix scan with value display not implemented")
 	}
@@ -74,8 +78,11 @@ func prefixScan(db *bolt.DB, bucket, prefix string, keysOnly bool) error {
 		return err
 	}
 	log.Infof("scanning for prefix=%#x", pb)
-	return db.View(func(tx *bolt.Tx) error {
+	return db.View(func(tx engine.Tx) error {
 		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", bucket)
+		}
 		c := b.Cursor()
 		for k, _ := c.Seek(pb); k != nil && bytes.HasPrefix(k, pb); k, _ = c.Next() {
 			fmt.Printf("%#x\n", k)
@@ -84,19 +91,13 @@ func prefixScan(db *bolt.DB, bucket, prefix string, keysOnly bool) error {
 	})
 }
 
-func getDB(path string) (*bolt.DB, error) {
-	bdb, err := bolt.Open(
-		path,
-		params.BeaconIoConfig().ReadWritePermissions,
-		&bolt.Options{
-			Timeout: 1 * time.Second,
-		},
-	)
+func getDB(path string) (engine.DB, error) {
+	backend, ok, err := kv.DetectBackend(path)
 	if err != nil {
-		if errors.Is(err, bolt.ErrTimeout) {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
-		}
 		return nil, err
 	}
-	return bdb, nil
+	if !ok {
+		return nil, fmt.Errorf("no db found in %s", path)
+	}
+	return kv.OpenEngine(path, backend, true)
 }
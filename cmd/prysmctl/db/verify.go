@@ -0,0 +1,114 @@
+package db
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/filesystem"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var verifyFlags = struct {
+	Path     string
+	BlobPath string
+	Out      string
+	Repair   bool
+}{}
+
+var verifyCmd = &cli.Command{
+	Name:  "verify",
+	Usage: "check the integrity of the finalized chain, state summaries, archived point index and blobs of a beacon db",
+	Action: func(cliCtx *cli.Context) error {
+		if err := verifyAction(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not verify db")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "path",
+			Usage:       "path to directory containing beaconchain.db",
+			Destination: &verifyFlags.Path,
+			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "blob-path",
+			Usage:       "path to the blob storage directory, blobs are not checked when unset",
+			Destination: &verifyFlags.BlobPath,
+		},
+		&cli.StringFlag{
+			Name:        "out",
+			Usage:       "file the JSON report is written to, instead of stdout",
+			Destination: &verifyFlags.Out,
+		},
+		&cli.BoolFlag{
+			Name:        "repair",
+			Usage:       "fix the inconsistencies of the block, finalized and archived point indices",
+			Destination: &verifyFlags.Repair,
+		},
+	},
+}
+
+func verifyAction(cliCtx *cli.Context) error {
+	flags := verifyFlags
+	ctx := cliCtx.Context
+	if _, ok, err := kv.DetectBackend(flags.Path); err != nil {
+		return err
+	} else if !ok {
+		return errors.Errorf("no database found in %s", flags.Path)
+	}
+	// The database is only written to when repairing it. Opening it read only also skips the bucket creation and
+	// the state summary flush a writable store does.
+	var opts []kv.KVStoreOption
+	if !flags.Repair {
+		opts = append(opts, kv.WithReadOnly())
+	}
+	d, err := kv.NewKVStore(ctx, flags.Path, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "could not open db at path %s", flags.Path)
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.WithError(err).Error("Could not close db")
+		}
+	}()
+	var blobs kv.BlobIndexer
+	if flags.BlobPath != "" {
+		bs, err := filesystem.NewBlobStorage(filesystem.WithBasePath(flags.BlobPath))
+		if err != nil {
+			return errors.Wrapf(err, "could not open blob storage at path %s", flags.BlobPath)
+		}
+		blobs = bs
+	}
+
+	report, err := d.Verify(ctx, blobs, flags.Repair)
+	if err != nil {
+		return err
+	}
+	enc, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if flags.Out == "" {
+		if _, err := os.Stdout.Write(append(enc, '\n')); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(flags.Out, enc, params.BeaconIoConfig().ReadWritePermissions); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"finalizedBlocks": report.FinalizedBlocks,
+		"stateSummaries":  report.StateSummaries,
+		"states":          report.States,
+		"findings":        len(report.Findings),
+	}).Info("Verified db")
+	if n := report.Unrepaired(); n > 0 {
+		return errors.Errorf("found %d inconsistencies which were not repaired", n)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package p2p
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	"github.com/urfave/cli/v2"
+)
+
+var gossipLatencyFlags = struct {
+	Traces cli.StringSlice
+}{}
+
+var gossipLatencyCmd = &cli.Command{
+	Name: "gossip-latency",
+	Usage: "Compute per-topic gossip propagation and validation latency distributions from gossip traces " +
+		"written with --p2p-gossip-trace-file",
+	Action: func(cliCtx *cli.Context) error {
+		if err := cliActionGossipLatency(cliCtx); err != nil {
+			log.WithError(err).Fatal("Could not compute gossip latencies")
+		}
+		return nil
+	},
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name: "traces",
+			Usage: "glob pattern matching the gossip trace files of a single node, including its rotated files. " +
+				"Repeat the flag once per node. Propagation latencies require the traces of at least two nodes",
+			Destination: &gossipLatencyFlags.Traces,
+			Required:    true,
+		},
+	},
+}
+
+// latencyDistribution summarizes a set of latency samples.
+type latencyDistribution struct {
+	p50 time.Duration
+	p90 time.Duration
+	p99 time.Duration
+	max time.Duration
+}
+
+// topicLatency holds the latency distributions of a single gossip topic.
+type topicLatency struct {
+	topic       string
+	messages    int
+	propagation latencyDistribution
+	validation  latencyDistribution
+}
+
+// nodeMessage is what a single node observed about a gossip message.
+type nodeMessage struct {
+	topic     string
+	firstSeen int64
+	validate  int64
+	validated int64
+}
+
+func cliActionGossipLatency(_ *cli.Context) error {
+	patterns := gossipLatencyFlags.Traces.Value()
+	nodes := make([]map[string]*nodeMessage, 0, len(patterns))
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "invalid trace pattern %s", pattern)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no trace files match %s", pattern)
+		}
+		msgs := make(map[string]*nodeMessage)
+		for _, f := range files {
+			if err := readGossipTrace(f, msgs); err != nil {
+				return errors.Wrapf(err, "could not read trace file %s", f)
+			}
+		}
+		nodes = append(nodes, msgs)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOPIC\tMESSAGES\tPROPAGATION P50\tP90\tP99\tMAX\tVALIDATION P50\tP90\tP99\tMAX")
+	for _, l := range gossipLatencies(nodes) {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", l.topic, l.messages,
+			l.propagation.p50, l.propagation.p90, l.propagation.p99, l.propagation.max,
+			l.validation.p50, l.validation.p90, l.validation.p99, l.validation.max)
+	}
+	return w.Flush()
+}
+
+// readGossipTrace adds the messages observed in the trace file to msgs.
+func readGossipTrace(path string, msgs map[string]*nodeMessage) error {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close trace file")
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		evt := &p2p.GossipTraceEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), evt); err != nil {
+			// The last line of a trace may be truncated if the node was killed.
+			log.WithError(err).WithField("file", path).Warn("Skipping malformed trace event")
+			continue
+		}
+		m, ok := msgs[evt.MsgID]
+		if !ok {
+			m = &nodeMessage{topic: evt.Topic, firstSeen: evt.Time}
+			msgs[evt.MsgID] = m
+		}
+		if evt.Time < m.firstSeen {
+			m.firstSeen = evt.Time
+		}
+		switch evt.Type {
+		case p2p.GossipTraceValidate:
+			m.validate = evt.Time
+		case p2p.GossipTraceDeliver, p2p.GossipTraceReject:
+			// Locally published messages are delivered without being validated from the network.
+			if !evt.Local {
+				m.validated = evt.Time
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// gossipLatencies computes per topic latency distributions from the messages observed by every node.
+// The propagation latency of a message to a node is the time it was first seen by that node, relative
+// to the earliest time it was seen by any node. The validation latency is the time a node took to
+// validate a message after first receiving it.
+func gossipLatencies(nodes []map[string]*nodeMessage) []*topicLatency {
+	type samples struct {
+		messages    int
+		propagation []time.Duration
+		validation  []time.Duration
+	}
+	byTopic := make(map[string]*samples)
+	topicSamples := func(topic string) *samples {
+		s, ok := byTopic[topic]
+		if !ok {
+			s = &samples{}
+			byTopic[topic] = s
+		}
+		return s
+	}
+
+	seen := make(map[string]bool)
+	for i, msgs := range nodes {
+		for id, m := range msgs {
+			s := topicSamples(m.topic)
+			if m.validate != 0 && m.validated >= m.validate {
+				s.validation = append(s.validation, time.Duration(m.validated-m.validate))
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			s.messages++
+
+			// Collect the first observation of the message by every node.
+			observed := make([]int64, 0, len(nodes))
+			for _, other := range nodes[i:] {
+				if om, ok := other[id]; ok {
+					observed = append(observed, om.firstSeen)
+				}
+			}
+			if len(observed) < 2 {
+				continue
+			}
+			sort.Slice(observed, func(a, b int) bool { return observed[a] < observed[b] })
+			for _, t := range observed[1:] {
+				s.propagation = append(s.propagation, time.Duration(t-observed[0]))
+			}
+		}
+	}
+
+	latencies := make([]*topicLatency, 0, len(byTopic))
+	for topic, s := range byTopic {
+		latencies = append(latencies, &topicLatency{
+			topic:       topic,
+			messages:    s.messages,
+			propagation: distribution(s.propagation),
+			validation:  distribution(s.validation),
+		})
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].topic < latencies[j].topic })
+	return latencies
+}
+
+func distribution(samples []time.Duration) latencyDistribution {
+	if len(samples) == 0 {
+		return latencyDistribution{}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	percentile := func(p float64) time.Duration {
+		idx := int(p * float64(len(samples)-1))
+		return samples[idx].Round(time.Microsecond)
+	}
+	return latencyDistribution{
+		p50: percentile(0.5),
+		p90: percentile(0.9),
+		p99: percentile(0.99),
+		max: samples[len(samples)-1].Round(time.Microsecond),
+	}
+}
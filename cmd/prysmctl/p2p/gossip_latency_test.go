@@ -0,0 +1,117 @@
+package p2p
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+func writeGossipTrace(t *testing.T, events []*p2p.GossipTraceEvent, trailer string) string {
+	path := filepath.Join(t.TempDir(), "gossip.jsonl")
+	var data []byte
+	for _, evt := range events {
+		line, err := json.Marshal(evt)
+		require.NoError(t, err)
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	data = append(data, trailer...)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestReadGossipTrace(t *testing.T) {
+	path := writeGossipTrace(t, []*p2p.GossipTraceEvent{
+		{Type: p2p.GossipTraceReceive, Time: 95, Topic: "blocks", MsgID: "a"},
+		{Type: p2p.GossipTraceValidate, Time: 100, Topic: "blocks", MsgID: "a"},
+		{Type: p2p.GossipTraceReceive, Time: 85, Topic: "blocks", MsgID: "a"},
+		{Type: p2p.GossipTraceDuplicate, Time: 90, Topic: "blocks", MsgID: "a"},
+		{Type: p2p.GossipTraceDeliver, Time: 150, Topic: "blocks", MsgID: "a"},
+		{Type: p2p.GossipTraceValidate, Time: 200, Topic: "attestations", MsgID: "b"},
+		{Type: p2p.GossipTraceReject, Time: 260, Topic: "attestations", MsgID: "b", Reason: "invalid"},
+		{Type: p2p.GossipTraceDeliver, Time: 300, Topic: "blocks", MsgID: "c", Local: true},
+	}, `{"type":"validate","ti`)
+
+	msgs := make(map[string]*nodeMessage)
+	require.NoError(t, readGossipTrace(path, msgs))
+	require.Equal(t, 3, len(msgs))
+
+	a := msgs["a"]
+	assert.Equal(t, "blocks", a.topic)
+	// The message was first seen when a copy was received, before the copy was found to be a duplicate.
+	assert.Equal(t, int64(85), a.firstSeen)
+	assert.Equal(t, int64(100), a.validate)
+	assert.Equal(t, int64(150), a.validated)
+
+	b := msgs["b"]
+	assert.Equal(t, "attestations", b.topic)
+	assert.Equal(t, int64(200), b.validate)
+	assert.Equal(t, int64(260), b.validated)
+
+	// Locally published messages are not validated.
+	c := msgs["c"]
+	assert.Equal(t, int64(300), c.firstSeen)
+	assert.Equal(t, int64(0), c.validated)
+}
+
+func TestReadGossipTrace_MissingFile(t *testing.T) {
+	err := readGossipTrace(filepath.Join(t.TempDir(), "missing.jsonl"), make(map[string]*nodeMessage))
+	require.NotNil(t, err)
+}
+
+func TestGossipLatencies(t *testing.T) {
+	us := int64(time.Microsecond)
+	nodes := []map[string]*nodeMessage{
+		{
+			"a": {topic: "blocks", firstSeen: 100 * us, validate: 100 * us, validated: 110 * us},
+			"b": {topic: "attestations", firstSeen: 500 * us, validate: 500 * us, validated: 540 * us},
+		},
+		{
+			"a": {topic: "blocks", firstSeen: 130 * us, validate: 130 * us, validated: 150 * us},
+		},
+		{
+			"a": {topic: "blocks", firstSeen: 90 * us, validate: 90 * us, validated: 95 * us},
+			// Only seen by a single node, so there is no propagation sample.
+			"c": {topic: "blocks", firstSeen: 1000 * us},
+		},
+	}
+
+	latencies := gossipLatencies(nodes)
+	require.Equal(t, 2, len(latencies))
+
+	att := latencies[0]
+	assert.Equal(t, "attestations", att.topic)
+	assert.Equal(t, 1, att.messages)
+	assert.Equal(t, latencyDistribution{}, att.propagation)
+	assert.Equal(t, 40*time.Microsecond, att.validation.max)
+
+	blocks := latencies[1]
+	assert.Equal(t, "blocks", blocks.topic)
+	assert.Equal(t, 2, blocks.messages)
+	// Message a was first seen at 90us, so the other nodes saw it 10us and 40us later.
+	assert.Equal(t, 10*time.Microsecond, blocks.propagation.p50)
+	assert.Equal(t, 40*time.Microsecond, blocks.propagation.max)
+	// Validation took 5us, 10us and 20us on the three nodes.
+	assert.Equal(t, 10*time.Microsecond, blocks.validation.p50)
+	assert.Equal(t, 20*time.Microsecond, blocks.validation.max)
+}
+
+func TestDistribution(t *testing.T) {
+	assert.Equal(t, latencyDistribution{}, distribution(nil))
+
+	samples := make([]time.Duration, 0, 100)
+	for i := 100; i > 0; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	d := distribution(samples)
+	assert.Equal(t, 50*time.Millisecond, d.p50)
+	assert.Equal(t, 90*time.Millisecond, d.p90)
+	assert.Equal(t, 99*time.Millisecond, d.p99)
+	assert.Equal(t, 100*time.Millisecond, d.max)
+}
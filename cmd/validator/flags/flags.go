@@ -389,6 +389,18 @@ var (
 		Usage: "To enable the use of prysm validator client in Distributed Validator Cluster",
 		Value: false,
 	}
+	// LightClientTrustedRootFlag enables verifying the chain view of the beacon node with an embedded light client.
+	LightClientTrustedRootFlag = &cli.StringFlag{
+		Name: "light-client-trusted-root",
+		Usage: "Hex encoded block root of a trusted checkpoint. When set, the validator client follows the light client " +
+			"updates of its beacon node and verifies block roots and proposer duties against headers signed by the sync committee.",
+	}
+	// LightClientRefuseFlag refuses to sign data which the embedded light client could not verify.
+	LightClientRefuseFlag = &cli.BoolFlag{
+		Name:  "light-client-refuse-unverified",
+		Usage: "Refuses to sign data which can not be tied to headers signed by the sync committee, instead of only logging a warning. Requires --light-client-trusted-root.",
+		Value: false,
+	}
 )
 
 // DefaultValidatorDir returns OS-specific default validator directory.
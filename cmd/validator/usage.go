@@ -143,6 +143,8 @@ var appHelpFlagGroups = []flagGroup{
 			flags.DisablePenaltyRewardLogFlag,
 			flags.DisableAccountMetricsFlag,
 			flags.EnableDistributed,
+			flags.LightClientTrustedRootFlag,
+			flags.LightClientRefuseFlag,
 			flags.AuthTokenPathFlag,
 		},
 	},
@@ -40,6 +40,8 @@ const disabledFeatureFlag = "Disabled feature flag"
 type Flags struct {
 	// Feature related flags.
 	EnableExperimentalState             bool // EnableExperimentalState turns on the latest and greatest (but potentially unstable) changes to the beacon state.
+	EnablePersistentStateTree           bool // EnablePersistentStateTree shares the Merkle trees of beacon state fields between state copies.
+	EnableParallelEpochProcessing       bool // EnableParallelEpochProcessing splits the per-validator passes of epoch processing across goroutines.
 	WriteSSZStateTransitions            bool // WriteSSZStateTransitions to tmp directory.
 	EnablePeerScorer                    bool // EnablePeerScorer enables experimental peer scoring in p2p.
 	EnableLightClient                   bool // EnableLightClient enables light client APIs.
@@ -180,6 +182,16 @@ func ConfigureBeaconChain(ctx *cli.Context) error {
 		cfg.EnableExperimentalState = false
 	}
 
+	if ctx.Bool(enablePersistentStateTree.Name) {
+		logEnabled(enablePersistentStateTree)
+		cfg.EnablePersistentStateTree = true
+	}
+
+	if ctx.Bool(enableParallelEpochProcessing.Name) {
+		logEnabled(enableParallelEpochProcessing)
+		cfg.EnableParallelEpochProcessing = true
+	}
+
 	if ctx.Bool(writeSSZStateTransitionsFlag.Name) {
 		logEnabled(writeSSZStateTransitionsFlag)
 		cfg.WriteSSZStateTransitions = true
@@ -32,6 +32,15 @@ var (
 		Name:  "disable-experimental-state",
 		Usage: "Turns off the latest and greatest changes to the beacon state. Disabling this is safe to do after the feature has been enabled.",
 	}
+	enablePersistentStateTree = &cli.BoolFlag{
+		Name: "enable-persistent-state-tree",
+		Usage: "Keeps the Merkle trees of the large beacon state fields as persistent trees, which state copies share " +
+			"so that hashing a copy only rehashes the paths changed since.",
+	}
+	enableParallelEpochProcessing = &cli.BoolFlag{
+		Name:  "enable-parallel-epoch-processing",
+		Usage: "Splits the per-validator passes of epoch processing into chunks processed in parallel.",
+	}
 	writeSSZStateTransitionsFlag = &cli.BoolFlag{
 		Name:  "interop-write-ssz-state-transitions",
 		Usage: "Writes SSZ states to disk after attempted state transitio.",
@@ -205,6 +214,8 @@ var E2EValidatorFlags = []string{
 var BeaconChainFlags = append(deprecatedBeaconFlags, append(deprecatedFlags, []cli.Flag{
 	devModeFlag,
 	disableExperimentalState,
+	enablePersistentStateTree,
+	enableParallelEpochProcessing,
 	writeSSZStateTransitionsFlag,
 	saveInvalidBlockTempFlag,
 	saveInvalidBlobTempFlag,
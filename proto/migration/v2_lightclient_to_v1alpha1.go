@@ -0,0 +1,295 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	ssz "github.com/prysmaticlabs/fastssz"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	enginev1 "github.com/prysmaticlabs/prysm/v5/proto/engine/v1"
+	ethpbv1 "github.com/prysmaticlabs/prysm/v5/proto/eth/v1"
+	ethpbv2 "github.com/prysmaticlabs/prysm/v5/proto/eth/v2"
+	ethpbalpha "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+// The light client objects are stored and served over the REST API in their v2 form, while the p2p network carries
+// the v1alpha1 SSZ containers of the fork of the attested header. The functions below convert between the two. A
+// finalized header of an earlier fork than the attested header is upgraded to the attested header's fork, and a
+// missing one is replaced with an empty header, as done by the light client sync protocol.
+
+// V2LightClientBootstrapToV1Alpha1 converts a v2 LightClientBootstrap to the v1alpha1 SSZ container of its fork.
+func V2LightClientBootstrapToV1Alpha1(bootstrap *ethpbv2.LightClientBootstrap) (ssz.Marshaler, error) {
+	if bootstrap == nil || bootstrap.Header == nil {
+		return nil, errors.New("nil light client bootstrap")
+	}
+	committee := V2SyncCommitteeToV1Alpha1(bootstrap.CurrentSyncCommittee)
+	branch := bytesutil.SafeCopy2dBytes(bootstrap.CurrentSyncCommitteeBranch)
+	switch h := bootstrap.Header.Header.(type) {
+	case *ethpbv2.LightClientHeaderContainer_HeaderAltair:
+		return &ethpbalpha.LightClientBootstrapAltair{
+			Header:                     lightClientHeaderAltair(bootstrap.Header),
+			CurrentSyncCommittee:       committee,
+			CurrentSyncCommitteeBranch: branch,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderCapella:
+		return &ethpbalpha.LightClientBootstrapCapella{
+			Header:                     lightClientHeaderCapella(bootstrap.Header),
+			CurrentSyncCommittee:       committee,
+			CurrentSyncCommitteeBranch: branch,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderDeneb:
+		return &ethpbalpha.LightClientBootstrapDeneb{
+			Header:                     lightClientHeaderDeneb(bootstrap.Header),
+			CurrentSyncCommittee:       committee,
+			CurrentSyncCommitteeBranch: branch,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported light client header type %T", h)
+	}
+}
+
+// V2LightClientUpdateToV1Alpha1 converts a v2 LightClientUpdate to the v1alpha1 SSZ container of the fork of its
+// attested header.
+func V2LightClientUpdateToV1Alpha1(update *ethpbv2.LightClientUpdate) (ssz.Marshaler, error) {
+	if update == nil || update.AttestedHeader == nil {
+		return nil, errors.New("nil light client update")
+	}
+	nextSyncCommittee := V2SyncCommitteeToV1Alpha1(update.NextSyncCommittee)
+	if nextSyncCommittee == nil {
+		nextSyncCommittee = emptySyncCommittee()
+	}
+	nextSyncCommitteeBranch := branchOrEmpty(update.NextSyncCommitteeBranch, fieldparams.SyncCommitteeBranchDepth)
+	finalityBranch := branchOrEmpty(update.FinalityBranch, fieldparams.FinalityBranchDepth)
+	syncAggregate := v1SyncAggregateToV1Alpha1(update.SyncAggregate)
+	switch h := update.AttestedHeader.Header.(type) {
+	case *ethpbv2.LightClientHeaderContainer_HeaderAltair:
+		return &ethpbalpha.LightClientUpdateAltair{
+			AttestedHeader:          lightClientHeaderAltair(update.AttestedHeader),
+			NextSyncCommittee:       nextSyncCommittee,
+			NextSyncCommitteeBranch: nextSyncCommitteeBranch,
+			FinalizedHeader:         lightClientHeaderAltair(update.FinalizedHeader),
+			FinalityBranch:          finalityBranch,
+			SyncAggregate:           syncAggregate,
+			SignatureSlot:           update.SignatureSlot,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderCapella:
+		return &ethpbalpha.LightClientUpdateCapella{
+			AttestedHeader:          lightClientHeaderCapella(update.AttestedHeader),
+			NextSyncCommittee:       nextSyncCommittee,
+			NextSyncCommitteeBranch: nextSyncCommitteeBranch,
+			FinalizedHeader:         lightClientHeaderCapella(update.FinalizedHeader),
+			FinalityBranch:          finalityBranch,
+			SyncAggregate:           syncAggregate,
+			SignatureSlot:           update.SignatureSlot,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderDeneb:
+		return &ethpbalpha.LightClientUpdateDeneb{
+			AttestedHeader:          lightClientHeaderDeneb(update.AttestedHeader),
+			NextSyncCommittee:       nextSyncCommittee,
+			NextSyncCommitteeBranch: nextSyncCommitteeBranch,
+			FinalizedHeader:         lightClientHeaderDeneb(update.FinalizedHeader),
+			FinalityBranch:          finalityBranch,
+			SyncAggregate:           syncAggregate,
+			SignatureSlot:           update.SignatureSlot,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported light client header type %T", h)
+	}
+}
+
+// V2LightClientFinalityUpdateToV1Alpha1 converts a v2 LightClientFinalityUpdate to the v1alpha1 SSZ container of
+// the fork of its attested header.
+func V2LightClientFinalityUpdateToV1Alpha1(update *ethpbv2.LightClientFinalityUpdate) (ssz.Marshaler, error) {
+	if update == nil || update.AttestedHeader == nil {
+		return nil, errors.New("nil light client finality update")
+	}
+	finalityBranch := branchOrEmpty(update.FinalityBranch, fieldparams.FinalityBranchDepth)
+	syncAggregate := v1SyncAggregateToV1Alpha1(update.SyncAggregate)
+	switch h := update.AttestedHeader.Header.(type) {
+	case *ethpbv2.LightClientHeaderContainer_HeaderAltair:
+		return &ethpbalpha.LightClientFinalityUpdateAltair{
+			AttestedHeader:  lightClientHeaderAltair(update.AttestedHeader),
+			FinalizedHeader: lightClientHeaderAltair(update.FinalizedHeader),
+			FinalityBranch:  finalityBranch,
+			SyncAggregate:   syncAggregate,
+			SignatureSlot:   update.SignatureSlot,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderCapella:
+		return &ethpbalpha.LightClientFinalityUpdateCapella{
+			AttestedHeader:  lightClientHeaderCapella(update.AttestedHeader),
+			FinalizedHeader: lightClientHeaderCapella(update.FinalizedHeader),
+			FinalityBranch:  finalityBranch,
+			SyncAggregate:   syncAggregate,
+			SignatureSlot:   update.SignatureSlot,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderDeneb:
+		return &ethpbalpha.LightClientFinalityUpdateDeneb{
+			AttestedHeader:  lightClientHeaderDeneb(update.AttestedHeader),
+			FinalizedHeader: lightClientHeaderDeneb(update.FinalizedHeader),
+			FinalityBranch:  finalityBranch,
+			SyncAggregate:   syncAggregate,
+			SignatureSlot:   update.SignatureSlot,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported light client header type %T", h)
+	}
+}
+
+// V2LightClientOptimisticUpdateToV1Alpha1 converts a v2 LightClientOptimisticUpdate to the v1alpha1 SSZ container
+// of the fork of its attested header.
+func V2LightClientOptimisticUpdateToV1Alpha1(update *ethpbv2.LightClientOptimisticUpdate) (ssz.Marshaler, error) {
+	if update == nil || update.AttestedHeader == nil {
+		return nil, errors.New("nil light client optimistic update")
+	}
+	syncAggregate := v1SyncAggregateToV1Alpha1(update.SyncAggregate)
+	switch h := update.AttestedHeader.Header.(type) {
+	case *ethpbv2.LightClientHeaderContainer_HeaderAltair:
+		return &ethpbalpha.LightClientOptimisticUpdateAltair{
+			AttestedHeader: lightClientHeaderAltair(update.AttestedHeader),
+			SyncAggregate:  syncAggregate,
+			SignatureSlot:  update.SignatureSlot,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderCapella:
+		return &ethpbalpha.LightClientOptimisticUpdateCapella{
+			AttestedHeader: lightClientHeaderCapella(update.AttestedHeader),
+			SyncAggregate:  syncAggregate,
+			SignatureSlot:  update.SignatureSlot,
+		}, nil
+	case *ethpbv2.LightClientHeaderContainer_HeaderDeneb:
+		return &ethpbalpha.LightClientOptimisticUpdateDeneb{
+			AttestedHeader: lightClientHeaderDeneb(update.AttestedHeader),
+			SyncAggregate:  syncAggregate,
+			SignatureSlot:  update.SignatureSlot,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported light client header type %T", h)
+	}
+}
+
+func lightClientBeaconHeader(container *ethpbv2.LightClientHeaderContainer) *ethpbalpha.BeaconBlockHeader {
+	if container != nil {
+		if beacon, err := container.GetBeacon(); err == nil && beacon != nil {
+			return V1HeaderToV1Alpha1(beacon)
+		}
+	}
+	return &ethpbalpha.BeaconBlockHeader{
+		ParentRoot: make([]byte, fieldparams.RootLength),
+		StateRoot:  make([]byte, fieldparams.RootLength),
+		BodyRoot:   make([]byte, fieldparams.RootLength),
+	}
+}
+
+func lightClientHeaderAltair(container *ethpbv2.LightClientHeaderContainer) *ethpbalpha.LightClientHeaderAltair {
+	return &ethpbalpha.LightClientHeaderAltair{Beacon: lightClientBeaconHeader(container)}
+}
+
+func lightClientHeaderCapella(container *ethpbv2.LightClientHeaderContainer) *ethpbalpha.LightClientHeaderCapella {
+	if container != nil {
+		if h := container.GetHeaderCapella(); h != nil {
+			return &ethpbalpha.LightClientHeaderCapella{
+				Beacon:          V1HeaderToV1Alpha1(h.Beacon),
+				Execution:       h.Execution,
+				ExecutionBranch: executionBranch(h.ExecutionBranch),
+			}
+		}
+	}
+	return &ethpbalpha.LightClientHeaderCapella{
+		Beacon:          lightClientBeaconHeader(container),
+		Execution:       emptyExecutionHeaderCapella(),
+		ExecutionBranch: branchOrEmpty(nil, fieldparams.ExecutionBranchDepth),
+	}
+}
+
+func lightClientHeaderDeneb(container *ethpbv2.LightClientHeaderContainer) *ethpbalpha.LightClientHeaderDeneb {
+	if container != nil {
+		if h := container.GetHeaderDeneb(); h != nil {
+			return &ethpbalpha.LightClientHeaderDeneb{
+				Beacon:          V1HeaderToV1Alpha1(h.Beacon),
+				Execution:       h.Execution,
+				ExecutionBranch: executionBranch(h.ExecutionBranch),
+			}
+		}
+	}
+	capella := lightClientHeaderCapella(container)
+	e := capella.Execution
+	return &ethpbalpha.LightClientHeaderDeneb{
+		Beacon: capella.Beacon,
+		Execution: &enginev1.ExecutionPayloadHeaderDeneb{
+			ParentHash:       e.ParentHash,
+			FeeRecipient:     e.FeeRecipient,
+			StateRoot:        e.StateRoot,
+			ReceiptsRoot:     e.ReceiptsRoot,
+			LogsBloom:        e.LogsBloom,
+			PrevRandao:       e.PrevRandao,
+			BlockNumber:      e.BlockNumber,
+			GasLimit:         e.GasLimit,
+			GasUsed:          e.GasUsed,
+			Timestamp:        e.Timestamp,
+			ExtraData:        e.ExtraData,
+			BaseFeePerGas:    e.BaseFeePerGas,
+			BlockHash:        e.BlockHash,
+			TransactionsRoot: e.TransactionsRoot,
+			WithdrawalsRoot:  e.WithdrawalsRoot,
+		},
+		ExecutionBranch: capella.ExecutionBranch,
+	}
+}
+
+// executionBranch returns the proof of the execution payload header against the block body root. The v2 headers
+// extend it up to the block root, which is not part of the SSZ container.
+func executionBranch(branch [][]byte) [][]byte {
+	if len(branch) > fieldparams.ExecutionBranchDepth {
+		branch = branch[:fieldparams.ExecutionBranchDepth]
+	}
+	return branchOrEmpty(branch, fieldparams.ExecutionBranchDepth)
+}
+
+func emptyExecutionHeaderCapella() *enginev1.ExecutionPayloadHeaderCapella {
+	return &enginev1.ExecutionPayloadHeaderCapella{
+		ParentHash:       make([]byte, fieldparams.RootLength),
+		FeeRecipient:     make([]byte, fieldparams.FeeRecipientLength),
+		StateRoot:        make([]byte, fieldparams.RootLength),
+		ReceiptsRoot:     make([]byte, fieldparams.RootLength),
+		LogsBloom:        make([]byte, fieldparams.LogsBloomLength),
+		PrevRandao:       make([]byte, fieldparams.RootLength),
+		ExtraData:        []byte{},
+		BaseFeePerGas:    make([]byte, fieldparams.RootLength),
+		BlockHash:        make([]byte, fieldparams.RootLength),
+		TransactionsRoot: make([]byte, fieldparams.RootLength),
+		WithdrawalsRoot:  make([]byte, fieldparams.RootLength),
+	}
+}
+
+func emptySyncCommittee() *ethpbalpha.SyncCommittee {
+	pubkeys := make([][]byte, fieldparams.SyncCommitteeLength)
+	for i := range pubkeys {
+		pubkeys[i] = make([]byte, fieldparams.BLSPubkeyLength)
+	}
+	return &ethpbalpha.SyncCommittee{
+		Pubkeys:         pubkeys,
+		AggregatePubkey: make([]byte, fieldparams.BLSPubkeyLength),
+	}
+}
+
+// branchOrEmpty copies the merkle branch, or returns a branch of zero roots of the given depth if it is missing.
+func branchOrEmpty(branch [][]byte, depth int) [][]byte {
+	if len(branch) != 0 {
+		return bytesutil.SafeCopy2dBytes(branch)
+	}
+	empty := make([][]byte, depth)
+	for i := range empty {
+		empty[i] = make([]byte, fieldparams.RootLength)
+	}
+	return empty
+}
+
+func v1SyncAggregateToV1Alpha1(aggregate *ethpbv1.SyncAggregate) *ethpbalpha.SyncAggregate {
+	if aggregate == nil {
+		return nil
+	}
+	return &ethpbalpha.SyncAggregate{
+		SyncCommitteeBits:      bytesutil.SafeCopyBytes(aggregate.SyncCommitteeBits),
+		SyncCommitteeSignature: bytesutil.SafeCopyBytes(aggregate.SyncCommitteeSignature),
+	}
+}
@@ -0,0 +1,89 @@
+package migration
+
+import (
+	"testing"
+
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	ethpbalpha "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/assert"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	"github.com/prysmaticlabs/prysm/v5/testing/util"
+)
+
+func TestV2LightClientUpdateToV1Alpha1(t *testing.T) {
+	t.Run("Altair", func(t *testing.T) {
+		l := util.NewTestLightClient(t).SetupTestAltair()
+		update, err := lightclient.NewLightClientUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock, l.FinalizedBlock)
+		require.NoError(t, err)
+
+		converted, err := V2LightClientUpdateToV1Alpha1(update)
+		require.NoError(t, err)
+		altair, ok := converted.(*ethpbalpha.LightClientUpdateAltair)
+		require.Equal(t, true, ok)
+		assert.Equal(t, l.AttestedBlock.Block().Slot(), altair.AttestedHeader.Beacon.Slot)
+		assert.Equal(t, update.SignatureSlot, altair.SignatureSlot)
+		_, err = converted.MarshalSSZ()
+		require.NoError(t, err)
+	})
+
+	t.Run("Deneb", func(t *testing.T) {
+		l := util.NewTestLightClient(t).SetupTestDeneb(false)
+		update, err := lightclient.NewLightClientUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock, l.FinalizedBlock)
+		require.NoError(t, err)
+
+		converted, err := V2LightClientUpdateToV1Alpha1(update)
+		require.NoError(t, err)
+		deneb, ok := converted.(*ethpbalpha.LightClientUpdateDeneb)
+		require.Equal(t, true, ok)
+		execution, err := update.AttestedHeader.GetExecutionHeaderDeneb()
+		require.NoError(t, err)
+		assert.DeepEqual(t, execution.BlockHash, deneb.AttestedHeader.Execution.BlockHash)
+		_, err = converted.MarshalSSZ()
+		require.NoError(t, err)
+	})
+
+	t.Run("finalized header of an earlier fork", func(t *testing.T) {
+		l := util.NewTestLightClient(t).SetupTestCapellaFinalizedBlockAltair(false)
+		update, err := lightclient.NewLightClientUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock, l.FinalizedBlock)
+		require.NoError(t, err)
+
+		converted, err := V2LightClientUpdateToV1Alpha1(update)
+		require.NoError(t, err)
+		capella, ok := converted.(*ethpbalpha.LightClientUpdateCapella)
+		require.Equal(t, true, ok)
+		assert.Equal(t, l.FinalizedBlock.Block().Slot(), capella.FinalizedHeader.Beacon.Slot)
+		assert.DeepEqual(t, make([]byte, 32), capella.FinalizedHeader.Execution.BlockHash)
+		_, err = converted.MarshalSSZ()
+		require.NoError(t, err)
+	})
+}
+
+func TestV2LightClientBootstrapToV1Alpha1(t *testing.T) {
+	l := util.NewTestLightClient(t).SetupTestCapella(false)
+	bootstrap, err := lightclient.NewLightClientBootstrapFromBeaconState(l.Ctx, l.AttestedState, l.AttestedBlock)
+	require.NoError(t, err)
+
+	converted, err := V2LightClientBootstrapToV1Alpha1(bootstrap)
+	require.NoError(t, err)
+	capella, ok := converted.(*ethpbalpha.LightClientBootstrapCapella)
+	require.Equal(t, true, ok)
+	assert.Equal(t, l.AttestedBlock.Block().Slot(), capella.Header.Beacon.Slot)
+	_, err = converted.MarshalSSZ()
+	require.NoError(t, err)
+}
+
+func TestV2LightClientOptimisticUpdateToV1Alpha1(t *testing.T) {
+	l := util.NewTestLightClient(t).SetupTestAltair()
+	update, err := lightclient.NewLightClientOptimisticUpdateFromBeaconState(l.Ctx, l.State, l.Block, l.AttestedState, l.AttestedBlock)
+	require.NoError(t, err)
+
+	converted, err := V2LightClientOptimisticUpdateToV1Alpha1(update)
+	require.NoError(t, err)
+	_, ok := converted.(*ethpbalpha.LightClientOptimisticUpdateAltair)
+	require.Equal(t, true, ok)
+	_, err = converted.MarshalSSZ()
+	require.NoError(t, err)
+
+	_, err = V2LightClientOptimisticUpdateToV1Alpha1(nil)
+	require.ErrorContains(t, "nil light client optimistic update", err)
+}
@@ -0,0 +1,44 @@
+// Package epochprocessing has test helpers to run tests with each epoch processing mode.
+package epochprocessing
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/config/features"
+)
+
+// RunInModes runs the test as a subtest with sequential and with parallel epoch processing. The other feature
+// flags are kept as they are.
+func RunInModes(t *testing.T, test func(t *testing.T)) {
+	for _, parallel := range []bool{false, true} {
+		name := "sequential"
+		if parallel {
+			name = "parallel"
+		}
+		t.Run(name, func(t *testing.T) {
+			cfg := *features.Get()
+			cfg.EnableParallelEpochProcessing = parallel
+			resetCfg := features.InitWithReset(&cfg)
+			defer resetCfg()
+			test(t)
+		})
+	}
+}
+
+// BenchmarkInModes runs the benchmark as a sub-benchmark with sequential and with parallel epoch processing. The
+// other feature flags are kept as they are.
+func BenchmarkInModes(b *testing.B, bench func(b *testing.B)) {
+	for _, parallel := range []bool{false, true} {
+		name := "sequential"
+		if parallel {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			cfg := *features.Get()
+			cfg.EnableParallelEpochProcessing = parallel
+			resetCfg := features.InitWithReset(&cfg)
+			defer resetCfg()
+			bench(b)
+		})
+	}
+}
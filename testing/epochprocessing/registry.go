@@ -0,0 +1,84 @@
+package epochprocessing
+
+import (
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+// FuzzRegistry returns a copy of st with fuzzed validators, balances, participation and inactivity scores. Balances
+// and epochs are kept in range so that most states can be processed, and effective balances are at most
+// maxEffectiveBalance. Compounding withdrawal credentials are set only if compounding is true.
+func FuzzRegistry(t *testing.T, fuzzer *fuzz.Fuzzer, st state.BeaconState, maxEffectiveBalance uint64, compounding bool) state.BeaconState {
+	cfg := params.BeaconConfig()
+	epoch := func() primitives.Epoch {
+		var e uint64
+		fuzzer.Fuzz(&e)
+		if e%4 == 0 {
+			return cfg.FarFutureEpoch
+		}
+		return primitives.Epoch(e % 16)
+	}
+	balance := func() uint64 {
+		var b uint64
+		fuzzer.Fuzz(&b)
+		return b % (2 * maxEffectiveBalance)
+	}
+	n := st.NumValidators()
+	vals := make([]*ethpb.Validator, n)
+	balances, scores := make([]uint64, n), make([]uint64, n)
+	prev, curr := make([]byte, n), make([]byte, n)
+	for i := range vals {
+		vals[i] = &ethpb.Validator{
+			PublicKey:                  make([]byte, fieldparams.BLSPubkeyLength),
+			WithdrawalCredentials:      make([]byte, 32),
+			EffectiveBalance:           balance() / 2 / cfg.EffectiveBalanceIncrement * cfg.EffectiveBalanceIncrement,
+			ActivationEligibilityEpoch: epoch(),
+			ActivationEpoch:            epoch(),
+			ExitEpoch:                  epoch(),
+			WithdrawableEpoch:          epoch(),
+		}
+		fuzzer.Fuzz(&vals[i].Slashed)
+		var compounded bool
+		fuzzer.Fuzz(&compounded)
+		if compounding && compounded {
+			vals[i].WithdrawalCredentials[0] = cfg.CompoundingWithdrawalPrefixByte
+		}
+		balances[i] = balance()
+		fuzzer.Fuzz(&scores[i])
+		scores[i] %= 100
+		fuzzer.Fuzz(&prev[i])
+		fuzzer.Fuzz(&curr[i])
+	}
+	copied := st.Copy()
+	require.NoError(t, copied.SetValidators(vals))
+	require.NoError(t, copied.SetBalances(balances))
+	require.NoError(t, copied.SetInactivityScores(scores))
+	require.NoError(t, copied.SetPreviousParticipationBits(prev))
+	require.NoError(t, copied.SetCurrentParticipationBits(curr))
+	return copied
+}
+
+// RequireSameRegistry requires the validators, balances, participation and inactivity scores of the states to be
+// equal. These are the fields written by the parallel epoch processing passes.
+func RequireSameRegistry(t *testing.T, sequential, parallel state.BeaconState) {
+	require.DeepSSZEqual(t, sequential.Validators(), parallel.Validators(), "Parallel epoch processing differs from sequential")
+	require.DeepEqual(t, sequential.Balances(), parallel.Balances(), "Parallel epoch processing differs from sequential")
+	for _, field := range []func(state.BeaconState) (interface{}, error){
+		func(st state.BeaconState) (interface{}, error) { return st.InactivityScores() },
+		func(st state.BeaconState) (interface{}, error) { return st.PreviousEpochParticipation() },
+		func(st state.BeaconState) (interface{}, error) { return st.CurrentEpochParticipation() },
+	} {
+		want, err := field(sequential)
+		require.NoError(t, err)
+		got, err := field(parallel)
+		require.NoError(t, err)
+		require.DeepEqual(t, want, got, "Parallel epoch processing differs from sequential")
+	}
+}
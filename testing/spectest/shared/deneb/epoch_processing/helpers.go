@@ -11,6 +11,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
 	state_native "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/epochprocessing"
 	"github.com/prysmaticlabs/prysm/v5/testing/require"
 	"github.com/prysmaticlabs/prysm/v5/testing/util"
 	"google.golang.org/protobuf/proto"
@@ -20,10 +21,21 @@ type epochOperation func(*testing.T, state.BeaconState) (state.BeaconState, erro
 
 // RunEpochOperationTest takes in the prestate and processes it through the
 // passed in epoch operation function and checks the post state with the expected post state.
+// The test runs with both sequential and parallel epoch processing.
 func RunEpochOperationTest(
 	t *testing.T,
 	testFolderPath string,
 	operationFn epochOperation,
+) {
+	epochprocessing.RunInModes(t, func(t *testing.T) {
+		runEpochOperationTest(t, testFolderPath, operationFn)
+	})
+}
+
+func runEpochOperationTest(
+	t *testing.T,
+	testFolderPath string,
+	operationFn epochOperation,
 ) {
 	preBeaconStateFile, err := util.BazelFileBytes(path.Join(testFolderPath, "pre.ssz_snappy"))
 	require.NoError(t, err)
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+// randomEpochRegistry holds the validator fields of a state set by RandomEpochStateAltair and
+// RandomEpochStateElectra.
+type randomEpochRegistry struct {
+	validators                 []*ethpb.Validator
+	balances                   []uint64
+	currentEpochParticipation  []byte
+	previousEpochParticipation []byte
+	inactivityScores           []uint64
+}
+
+// RandomEpochStateAltair returns an Altair state at the last slot of an epoch with n validators of varied statuses,
+// balances, participation and inactivity scores. The same n always returns the same state.
+func RandomEpochStateAltair(t testing.TB, n int, finalizedEpoch primitives.Epoch) state.BeaconState {
+	reg := randomEpochValidators(n, false)
+	st, err := NewBeaconStateAltair(func(s *ethpb.BeaconStateAltair) error {
+		s.Slot = 11*params.BeaconConfig().SlotsPerEpoch - 1
+		s.Validators = reg.validators
+		s.Balances = reg.balances
+		s.CurrentEpochParticipation = reg.currentEpochParticipation
+		s.PreviousEpochParticipation = reg.previousEpochParticipation
+		s.InactivityScores = reg.inactivityScores
+		s.FinalizedCheckpoint.Epoch = finalizedEpoch
+		return nil
+	})
+	require.NoError(t, err)
+	return st
+}
+
+// RandomEpochStateElectra returns an Electra state at the last slot of an epoch with n validators of varied
+// statuses, credentials, balances, participation and inactivity scores. The same n always returns the same state.
+func RandomEpochStateElectra(t testing.TB, n int, finalizedEpoch primitives.Epoch) state.BeaconState {
+	reg := randomEpochValidators(n, true)
+	st, err := NewBeaconStateElectra(func(s *ethpb.BeaconStateElectra) error {
+		s.Slot = 11*params.BeaconConfig().SlotsPerEpoch - 1
+		s.Validators = reg.validators
+		s.Balances = reg.balances
+		s.CurrentEpochParticipation = reg.currentEpochParticipation
+		s.PreviousEpochParticipation = reg.previousEpochParticipation
+		s.InactivityScores = reg.inactivityScores
+		s.FinalizedCheckpoint.Epoch = finalizedEpoch
+		return nil
+	})
+	require.NoError(t, err)
+	return st
+}
+
+// randomEpochValidators returns n validators seeded by n. Electra validators may also be pending activation or
+// have compounding credentials.
+func randomEpochValidators(n int, electra bool) *randomEpochRegistry {
+	cfg := params.BeaconConfig()
+	r := rand.New(rand.NewSource(int64(n)))
+	reg := &randomEpochRegistry{
+		validators:                 make([]*ethpb.Validator, n),
+		balances:                   make([]uint64, n),
+		currentEpochParticipation:  make([]byte, n),
+		previousEpochParticipation: make([]byte, n),
+		inactivityScores:           make([]uint64, n),
+	}
+	maxBalance, balanceNoise := cfg.MaxEffectiveBalance, cfg.EffectiveBalanceIncrement
+	if electra {
+		maxBalance, balanceNoise = cfg.MinActivationBalance, 2*cfg.EffectiveBalanceIncrement
+	}
+	for i := range reg.validators {
+		v := &ethpb.Validator{
+			PublicKey:                  make([]byte, fieldparams.BLSPubkeyLength),
+			WithdrawalCredentials:      make([]byte, 32),
+			EffectiveBalance:           uint64(17+r.Intn(16)) * cfg.EffectiveBalanceIncrement,
+			ActivationEligibilityEpoch: 0,
+			ActivationEpoch:            0,
+			ExitEpoch:                  cfg.FarFutureEpoch,
+			WithdrawableEpoch:          cfg.FarFutureEpoch,
+		}
+		switch c := r.Intn(20); {
+		case c == 0:
+			v.Slashed = true
+			v.ExitEpoch = 8
+			v.WithdrawableEpoch = 100
+		case c == 1:
+			v.ExitEpoch = 2
+			v.WithdrawableEpoch = 4
+		case c == 2:
+			v.ActivationEligibilityEpoch = cfg.FarFutureEpoch
+			v.ActivationEpoch = cfg.FarFutureEpoch
+			v.EffectiveBalance = maxBalance
+		case c == 3 && electra:
+			v.ActivationEligibilityEpoch = 1
+			v.ActivationEpoch = cfg.FarFutureEpoch
+			v.EffectiveBalance = cfg.MinActivationBalance
+		case c == 4 && electra:
+			v.WithdrawalCredentials[0] = cfg.CompoundingWithdrawalPrefixByte
+			v.EffectiveBalance = uint64(32+r.Intn(100)) * cfg.EffectiveBalanceIncrement
+		}
+		if i < 16 {
+			// Ejections are costly, so only a few validators are ejected.
+			v.EffectiveBalance = cfg.EjectionBalance
+		}
+		reg.validators[i] = v
+		reg.balances[i] = v.EffectiveBalance + uint64(r.Int63n(int64(2*balanceNoise))) - balanceNoise
+		reg.currentEpochParticipation[i] = randomParticipation(r)
+		reg.previousEpochParticipation[i] = randomParticipation(r)
+		reg.inactivityScores[i] = uint64(r.Intn(50))
+	}
+	return reg
+}
+
+// randomParticipation returns participation flags with each flag set for most validators, so that the state can be
+// justified.
+func randomParticipation(r *rand.Rand) byte {
+	var b byte
+	for i := 0; i < 3; i++ {
+		if r.Intn(5) > 0 {
+			b |= 1 << i
+		}
+	}
+	return b
+}
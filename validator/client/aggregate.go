@@ -105,6 +105,14 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot primitives
 		agg = res.AggregateAndProof
 	}
 
+	if err := v.verifyAttestationDataWithLightClient(ctx, agg.AggregateVal().GetData()); err != nil {
+		log.WithError(err).Error("Refusing to sign aggregate which could not be verified by the light client")
+		if v.emitAccountMetrics {
+			ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
+
 	sig, err := v.aggregateAndProofSig(ctx, pubKey, agg, slot)
 	if err != nil {
 		log.WithError(err).Error("Could not sign aggregate and proof")
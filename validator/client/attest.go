@@ -85,6 +85,15 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot primitives.Slot,
 		return
 	}
 
+	if err := v.verifyAttestationDataWithLightClient(ctx, data); err != nil {
+		log.WithError(err).Error("Refusing to sign attestation data which could not be verified by the light client")
+		if v.emitAccountMetrics {
+			ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		tracing.AnnotateError(span, err)
+		return
+	}
+
 	sig, _, err := v.signAtt(ctx, pubKey, data, slot)
 	if err != nil {
 		log.WithError(err).Error("Could not sign attestation")
@@ -0,0 +1,370 @@
+package light_client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/time/slots"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxUnverifiedBlocks is the number of blocks the head presented by the beacon node may be ahead of the
+	// latest header signed by the sync committee. The sync committee signs a block in the next one, so the head
+	// block is usually one block ahead of the light client.
+	maxUnverifiedBlocks = 2
+	// minRefreshInterval limits how often the light client updates are requested from the beacon node.
+	minRefreshInterval = time.Second
+)
+
+var (
+	// ErrUnverifiedRoot is returned when a block root can not be tied to the headers signed by the sync committee.
+	ErrUnverifiedRoot = errors.New("block root is not verified by the light client")
+	errHeaderMismatch = errors.New("beacon node returned a header for a different block root")
+)
+
+// beaconAPI is the subset of the Beacon API used by the Verifier.
+type beaconAPI interface {
+	GetGenesis(ctx context.Context) (*structs.Genesis, error)
+	GetLightClientBootstrap(ctx context.Context, blockRoot [32]byte) (*structs.LightClientBootstrapResponse, error)
+	GetLightClientUpdatesByRange(ctx context.Context, startPeriod, count uint64) ([]*structs.LightClientUpdateResponse, error)
+	GetLightClientFinalityUpdate(ctx context.Context) (*structs.LightClientFinalityUpdateResponse, error)
+	GetLightClientOptimisticUpdate(ctx context.Context) (*structs.LightClientOptimisticUpdateResponse, error)
+	GetBlockHeader(ctx context.Context, blockId beacon.StateOrBlockId) (*ethpb.SignedBeaconBlockHeader, error)
+	GetProposerDuties(ctx context.Context, epoch primitives.Epoch) (*structs.GetProposerDutiesResponse, error)
+}
+
+// Verifier follows the light client updates served by the beacon node from a trusted block root, and checks
+// the block roots the validator client is asked to sign against the headers signed by the sync committee.
+// A block root is verified when it is a recent ancestor of the light client head, or when it descends from a
+// signed header through at most maxUnverifiedBlocks blocks.
+type Verifier struct {
+	api         beaconAPI
+	trustedRoot [32]byte
+	refuse      bool
+
+	lock        sync.Mutex
+	store       *lightclient.Store
+	gvr         []byte
+	genesisTime time.Time
+	headRoot    [32]byte
+	headers     map[[32]byte]*ethpb.BeaconBlockHeader
+	lastRefresh time.Time
+}
+
+// NewVerifier creates a Verifier following the light client updates of the beacon node api at the given host.
+// When refuse is set, the validator client refuses to sign data which can not be verified instead of only
+// logging a warning.
+func NewVerifier(beaconNodeHost string, trustedRoot [32]byte, refuse bool) (*Verifier, error) {
+	c, err := beacon.NewClient(beaconNodeHost)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse beacon node url or hostname - %s", beaconNodeHost)
+	}
+	return newVerifier(c, trustedRoot, refuse), nil
+}
+
+func newVerifier(api beaconAPI, trustedRoot [32]byte, refuse bool) *Verifier {
+	return &Verifier{
+		api:         api,
+		trustedRoot: trustedRoot,
+		refuse:      refuse,
+		headers:     make(map[[32]byte]*ethpb.BeaconBlockHeader),
+	}
+}
+
+// Refuse returns whether the validator client should refuse to sign data which can not be verified.
+func (v *Verifier) Refuse() bool {
+	return v.refuse
+}
+
+// VerifyAttestationData checks the head and target block roots of the attestation data.
+func (v *Verifier) VerifyAttestationData(ctx context.Context, data *ethpb.AttestationData) error {
+	if data == nil {
+		return errors.New("nil attestation data")
+	}
+	if err := v.VerifyRoot(ctx, bytesutil.ToBytes32(data.BeaconBlockRoot)); err != nil {
+		return errors.Wrap(err, "could not verify beacon block root")
+	}
+	if data.Target != nil {
+		if err := v.VerifyRoot(ctx, bytesutil.ToBytes32(data.Target.Root)); err != nil {
+			return errors.Wrap(err, "could not verify target root")
+		}
+	}
+	return nil
+}
+
+// VerifyProposerDuties checks that the proposer duties of the epoch depend on a verified block root, and that
+// the proposer slots of the given duties match them.
+func (v *Verifier) VerifyProposerDuties(ctx context.Context, epoch primitives.Epoch, duties []*ethpb.DutiesResponse_Duty) error {
+	resp, err := v.api.GetProposerDuties(ctx, epoch)
+	if err != nil {
+		return err
+	}
+	dependentRoot, err := hexutil.Decode(resp.DependentRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not decode dependent root")
+	}
+	if err := v.VerifyRoot(ctx, bytesutil.ToBytes32(dependentRoot)); err != nil {
+		return errors.Wrap(err, "could not verify proposer duties dependent root")
+	}
+	proposers := make(map[primitives.Slot]primitives.ValidatorIndex, len(resp.Data))
+	for _, d := range resp.Data {
+		slot, err := strconv.ParseUint(d.Slot, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "could not decode proposer duty slot")
+		}
+		index, err := strconv.ParseUint(d.ValidatorIndex, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "could not decode proposer duty validator index")
+		}
+		proposers[primitives.Slot(slot)] = primitives.ValidatorIndex(index)
+	}
+	for _, d := range duties {
+		for _, slot := range d.ProposerSlots {
+			if index, ok := proposers[slot]; !ok || index != d.ValidatorIndex {
+				return fmt.Errorf("validator %d is not the proposer of slot %d in the duties depending on %#x",
+					d.ValidatorIndex, slot, dependentRoot)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyRoot checks that the block root is tied to the headers signed by the sync committee, following the
+// latest light client updates of the beacon node when it is not.
+func (v *Verifier) VerifyRoot(ctx context.Context, root [32]byte) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	err := v.verifyRoot(ctx, root)
+	if !errors.Is(err, ErrUnverifiedRoot) {
+		return err
+	}
+	if time.Since(v.lastRefresh) < minRefreshInterval {
+		return err
+	}
+	if err := v.refresh(ctx); err != nil {
+		return errors.Wrap(err, "could not follow light client updates")
+	}
+	return v.verifyRoot(ctx, root)
+}
+
+func (v *Verifier) verifyRoot(ctx context.Context, root [32]byte) error {
+	if v.store == nil {
+		return errors.Wrap(ErrUnverifiedRoot, "light client is not initialized")
+	}
+	if _, ok := v.headers[root]; ok {
+		return nil
+	}
+	header, err := v.header(ctx, root)
+	if err != nil {
+		return err
+	}
+	head := v.headers[v.headRoot]
+	if header.Slot > head.Slot {
+		// The block has to build on the light client head. The other verified headers are ancestors of the head,
+		// and a block descending from one of them is on a fork which skips the head.
+		cur := header
+		for i := 0; i < maxUnverifiedBlocks; i++ {
+			parent := bytesutil.ToBytes32(cur.ParentRoot)
+			if h, ok := v.headers[parent]; ok && (parent == v.headRoot || h.Slot >= head.Slot) {
+				return nil
+			}
+			if i+1 < maxUnverifiedBlocks {
+				if cur, err = v.header(ctx, parent); err != nil {
+					return err
+				}
+			}
+		}
+		return errors.Wrapf(ErrUnverifiedRoot, "block %#x at slot %d does not descend from a signed header", root, header.Slot)
+	}
+	if err := v.walkBackTo(ctx, header.Slot); err != nil {
+		return err
+	}
+	if _, ok := v.headers[root]; !ok {
+		return errors.Wrapf(ErrUnverifiedRoot, "block %#x at slot %d is not an ancestor of the light client head", root, header.Slot)
+	}
+	return nil
+}
+
+// walkBackTo adds the ancestors of the light client head down to the given slot to the verified headers.
+func (v *Verifier) walkBackTo(ctx context.Context, slot primitives.Slot) error {
+	cur := v.headers[v.headRoot]
+	if cur.Slot > slot+maxAncestorDistance() {
+		return errors.Wrapf(ErrUnverifiedRoot, "slot %d is too far behind the light client head at slot %d", slot, cur.Slot)
+	}
+	for cur.Slot > slot {
+		parent := bytesutil.ToBytes32(cur.ParentRoot)
+		h, ok := v.headers[parent]
+		if !ok {
+			var err error
+			if h, err = v.header(ctx, parent); err != nil {
+				return err
+			}
+			v.headers[parent] = h
+		}
+		cur = h
+	}
+	return nil
+}
+
+// header requests the header of the block root from the beacon node, and checks that it hashes to the root.
+func (v *Verifier) header(ctx context.Context, root [32]byte) (*ethpb.BeaconBlockHeader, error) {
+	signed, err := v.api.GetBlockHeader(ctx, beacon.IdFromRoot(root))
+	if err != nil {
+		return nil, err
+	}
+	if signed == nil || signed.Header == nil {
+		return nil, errors.Errorf("empty header for block %#x", root)
+	}
+	r, err := signed.Header.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute header root")
+	}
+	if r != root {
+		return nil, errors.Wrapf(errHeaderMismatch, "requested %#x, received %#x", root, r)
+	}
+	return signed.Header, nil
+}
+
+// refresh bootstraps the light client from the trusted root when needed, then applies the updates of the sync
+// committee periods it has not followed yet, and the latest finality and optimistic updates.
+func (v *Verifier) refresh(ctx context.Context) error {
+	v.lastRefresh = time.Now()
+	if v.store == nil {
+		if err := v.bootstrap(ctx); err != nil {
+			return err
+		}
+	}
+	if err := v.followPeriods(ctx); err != nil {
+		return err
+	}
+	finality, err := v.api.GetLightClientFinalityUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	u, err := beacon.LightClientFinalityUpdateFromJSON(finality.Data)
+	if err != nil {
+		return errors.Wrap(err, "could not decode light client finality update")
+	}
+	v.processUpdate(u)
+	optimistic, err := v.api.GetLightClientOptimisticUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	if u, err = beacon.LightClientOptimisticUpdateFromJSON(optimistic.Data); err != nil {
+		return errors.Wrap(err, "could not decode light client optimistic update")
+	}
+	v.processUpdate(u)
+	return v.updateHead()
+}
+
+func (v *Verifier) bootstrap(ctx context.Context) error {
+	g, err := v.api.GetGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	genesisTime, err := strconv.ParseInt(g.GenesisTime, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "could not decode genesis time")
+	}
+	v.genesisTime = time.Unix(genesisTime, 0)
+	gvr := params.BeaconConfig().GenesisValidatorsRoot
+	v.gvr = gvr[:]
+	if gvr == params.BeaconConfig().ZeroHash {
+		// The signatures are verified against the sync committee of the trusted root, so a remote genesis
+		// validators root can not be used to forge updates.
+		if v.gvr, err = hexutil.Decode(g.GenesisValidatorsRoot); err != nil {
+			return errors.Wrap(err, "could not decode genesis validators root")
+		}
+	}
+	resp, err := v.api.GetLightClientBootstrap(ctx, v.trustedRoot)
+	if err != nil {
+		return err
+	}
+	store, err := beacon.LightClientStoreFromBootstrap(v.trustedRoot, resp.Data)
+	if err != nil {
+		return err
+	}
+	v.store = store
+	log.WithField("slot", store.FinalizedHeader.Slot).Info("Initialized light client from the trusted block root")
+	return v.updateHead()
+}
+
+// followPeriods applies the light client updates from the sync committee period of the store's finalized header,
+// until the store knows the next sync committee of the current period or does not progress anymore.
+func (v *Verifier) followPeriods(ctx context.Context) error {
+	current := slots.SyncCommitteePeriod(slots.ToEpoch(slots.CurrentSlot(uint64(v.genesisTime.Unix()))))
+	for {
+		period := v.store.Period()
+		if v.store.NextSyncCommittee != nil && period >= current {
+			return nil
+		}
+		updates, err := v.api.GetLightClientUpdatesByRange(ctx, period, params.BeaconConfig().MaxRequestLightClientUpdates)
+		if err != nil {
+			return err
+		}
+		progressed := false
+		for _, resp := range updates {
+			u, err := beacon.LightClientUpdateFromJSON(resp.Data)
+			if err != nil {
+				return errors.Wrap(err, "could not decode light client update")
+			}
+			progressed = v.processUpdate(u) || progressed
+		}
+		if !progressed || v.store.Period() == period {
+			return nil
+		}
+	}
+}
+
+func (v *Verifier) processUpdate(u *lightclient.Update) bool {
+	changed, err := v.store.ProcessUpdate(u, v.gvr)
+	if err != nil {
+		log.WithError(err).WithField("signatureSlot", u.SignatureSlot).Warn("Beacon node served an invalid light client update")
+		return false
+	}
+	return changed
+}
+
+// updateHead adds the optimistic and finalized headers of the store to the verified headers, and drops the
+// headers which are too old to be asked for.
+func (v *Verifier) updateHead() error {
+	for _, h := range []*ethpb.BeaconBlockHeader{v.store.FinalizedHeader, v.store.OptimisticHeader} {
+		r, err := h.HashTreeRoot()
+		if err != nil {
+			return errors.Wrap(err, "could not compute header root")
+		}
+		v.headers[r] = h
+		v.headRoot = r
+	}
+	head := v.store.OptimisticHeader
+	for r, h := range v.headers {
+		if h.Slot+maxAncestorDistance() < head.Slot {
+			delete(v.headers, r)
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"slot":          head.Slot,
+		"root":          fmt.Sprintf("%#x", bytesutil.Trunc(v.headRoot[:])),
+		"finalizedSlot": v.store.FinalizedHeader.Slot,
+	}).Debug("Updated light client head")
+	return nil
+}
+
+// maxAncestorDistance is the number of slots the light client walks back from its head, enough for the target
+// checkpoints of the current and previous epochs.
+func maxAncestorDistance() primitives.Slot {
+	return 2 * params.BeaconConfig().SlotsPerEpoch
+}
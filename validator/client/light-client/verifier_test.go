@@ -0,0 +1,157 @@
+package light_client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
+	lightclient "github.com/prysmaticlabs/prysm/v5/beacon-chain/core/light-client"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+)
+
+var errNotFound = errors.New("not found")
+
+type fakeBeaconAPI struct {
+	beaconAPI
+	headers map[[32]byte]*ethpb.BeaconBlockHeader
+	duties  *structs.GetProposerDutiesResponse
+}
+
+func (f *fakeBeaconAPI) GetBlockHeader(_ context.Context, blockId beacon.StateOrBlockId) (*ethpb.SignedBeaconBlockHeader, error) {
+	for r, h := range f.headers {
+		if beacon.IdFromRoot(r) == blockId {
+			return &ethpb.SignedBeaconBlockHeader{Header: h}, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (f *fakeBeaconAPI) GetProposerDuties(_ context.Context, _ primitives.Epoch) (*structs.GetProposerDutiesResponse, error) {
+	return f.duties, nil
+}
+
+// testChain adds a chain of headers descending from parent, one per slot in [from, to].
+func testChain(t *testing.T, api *fakeBeaconAPI, parent [32]byte, from, to primitives.Slot, tag string) [][32]byte {
+	var roots [][32]byte
+	for slot := from; slot <= to; slot++ {
+		h := &ethpb.BeaconBlockHeader{
+			Slot:       slot,
+			ParentRoot: bytesutil.SafeCopyBytes(parent[:]),
+			StateRoot:  bytesutil.PadTo([]byte(tag), 32),
+			BodyRoot:   make([]byte, 32),
+		}
+		r, err := h.HashTreeRoot()
+		require.NoError(t, err)
+		api.headers[r] = h
+		roots = append(roots, r)
+		parent = r
+	}
+	return roots
+}
+
+// setupVerifier returns a verifier whose light client head is at slot 10 of a chain of 14 blocks.
+func setupVerifier(t *testing.T) (*Verifier, *fakeBeaconAPI, [][32]byte) {
+	api := &fakeBeaconAPI{headers: make(map[[32]byte]*ethpb.BeaconBlockHeader)}
+	roots := testChain(t, api, [32]byte{}, 0, 13, "canonical")
+	v := newVerifier(api, roots[0], false)
+	v.store = &lightclient.Store{
+		FinalizedHeader:  api.headers[roots[2]],
+		OptimisticHeader: api.headers[roots[10]],
+	}
+	require.NoError(t, v.updateHead())
+	// Do not follow the light client updates of the fake api.
+	v.lastRefresh = time.Now().Add(time.Hour)
+	return v, api, roots
+}
+
+func TestVerifier_VerifyRoot(t *testing.T) {
+	ctx := context.Background()
+	v, api, roots := setupVerifier(t)
+
+	for i, tt := range []struct {
+		root     [32]byte
+		verified bool
+	}{
+		{root: roots[10], verified: true},
+		{root: roots[2], verified: true},
+		{root: roots[11], verified: true},
+		{root: roots[12], verified: true},
+		{root: roots[13], verified: false},
+		{root: roots[5], verified: true},
+		{root: roots[0], verified: true},
+	} {
+		t.Run(fmt.Sprintf("slot %d", api.headers[tt.root].Slot), func(t *testing.T) {
+			err := v.VerifyRoot(ctx, tt.root)
+			if tt.verified {
+				require.NoError(t, err, "case %d", i)
+			} else {
+				require.ErrorIs(t, err, ErrUnverifiedRoot)
+			}
+		})
+	}
+
+	t.Run("fork", func(t *testing.T) {
+		fork := testChain(t, api, roots[6], 7, 11, "fork")
+		require.ErrorIs(t, v.VerifyRoot(ctx, fork[0]), ErrUnverifiedRoot)
+		require.ErrorIs(t, v.VerifyRoot(ctx, fork[4]), ErrUnverifiedRoot)
+		// A block building on a verified ancestor of the head skips the head.
+		require.NoError(t, v.VerifyRoot(ctx, roots[9]))
+		skip := testChain(t, api, roots[9], 11, 11, "skip")
+		require.ErrorIs(t, v.VerifyRoot(ctx, skip[0]), ErrUnverifiedRoot)
+	})
+
+	t.Run("header of a different block", func(t *testing.T) {
+		forged := [32]byte{'f'}
+		api.headers[forged] = api.headers[roots[11]]
+		require.ErrorIs(t, v.VerifyRoot(ctx, forged), errHeaderMismatch)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		require.ErrorIs(t, v.VerifyRoot(ctx, [32]byte{'u'}), errNotFound)
+	})
+}
+
+func TestVerifier_VerifyAttestationData(t *testing.T) {
+	ctx := context.Background()
+	v, _, roots := setupVerifier(t)
+
+	data := &ethpb.AttestationData{
+		Slot:            11,
+		BeaconBlockRoot: roots[11][:],
+		Target:          &ethpb.Checkpoint{Root: roots[8][:]},
+	}
+	require.NoError(t, v.VerifyAttestationData(ctx, data))
+
+	data.BeaconBlockRoot = roots[13][:]
+	require.ErrorContains(t, "could not verify beacon block root", v.VerifyAttestationData(ctx, data))
+}
+
+func TestVerifier_VerifyProposerDuties(t *testing.T) {
+	ctx := context.Background()
+	v, api, roots := setupVerifier(t)
+	api.duties = &structs.GetProposerDutiesResponse{
+		DependentRoot: fmt.Sprintf("%#x", roots[7]),
+		Data: []*structs.ProposerDuty{
+			{ValidatorIndex: "3", Slot: "16"},
+			{ValidatorIndex: "4", Slot: "17"},
+		},
+	}
+	duties := []*ethpb.DutiesResponse_Duty{
+		{ValidatorIndex: 3, ProposerSlots: []primitives.Slot{16}},
+		{ValidatorIndex: 5},
+	}
+	require.NoError(t, v.VerifyProposerDuties(ctx, 2, duties))
+
+	duties[1].ProposerSlots = []primitives.Slot{17}
+	require.ErrorContains(t, "validator 5 is not the proposer of slot 17", v.VerifyProposerDuties(ctx, 2, duties))
+
+	api.duties.DependentRoot = fmt.Sprintf("%#x", [32]byte{'u'})
+	require.ErrorContains(t, "could not verify proposer duties dependent root", v.VerifyProposerDuties(ctx, 2, duties))
+}
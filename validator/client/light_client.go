@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+)
+
+// verifyRootWithLightClient checks the block root presented by the beacon node against the headers signed by the
+// sync committee. It returns an error only when the validator client refuses to sign unverified data, otherwise
+// a failed verification is logged as a warning.
+func (v *validator) verifyRootWithLightClient(ctx context.Context, root []byte) error {
+	if v.lightClient == nil {
+		return nil
+	}
+	return v.lightClientResult(v.lightClient.VerifyRoot(ctx, bytesutil.ToBytes32(root)))
+}
+
+// verifyAttestationDataWithLightClient checks the head and target roots of the attestation data presented by the
+// beacon node against the headers signed by the sync committee.
+func (v *validator) verifyAttestationDataWithLightClient(ctx context.Context, data *ethpb.AttestationData) error {
+	if v.lightClient == nil {
+		return nil
+	}
+	return v.lightClientResult(v.lightClient.VerifyAttestationData(ctx, data))
+}
+
+// verifyDutiesWithLightClient checks that the proposer duties of the epoch depend on a block root signed by the
+// sync committee.
+func (v *validator) verifyDutiesWithLightClient(ctx context.Context, epoch primitives.Epoch, duties []*ethpb.DutiesResponse_Duty) error {
+	if v.lightClient == nil {
+		return nil
+	}
+	return v.lightClientResult(v.lightClient.VerifyProposerDuties(ctx, epoch, duties))
+}
+
+func (v *validator) lightClientResult(err error) error {
+	if err == nil || v.lightClient.Refuse() {
+		return err
+	}
+	log.WithError(err).Warn("Beacon node presented data which could not be verified by the light client")
+	return nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/testing/require"
+	lightclient "github.com/prysmaticlabs/prysm/v5/validator/client/light-client"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLightClientResult(t *testing.T) {
+	errUnverified := errors.Wrap(lightclient.ErrUnverifiedRoot, "could not verify beacon block root")
+
+	t.Run("disabled", func(t *testing.T) {
+		v := &validator{}
+		require.NoError(t, v.verifyRootWithLightClient(context.Background(), make([]byte, 32)))
+	})
+	t.Run("warn", func(t *testing.T) {
+		hook := logTest.NewGlobal()
+		lc, err := lightclient.NewVerifier("http://localhost:3500", [32]byte{}, false)
+		require.NoError(t, err)
+		v := &validator{lightClient: lc}
+		require.NoError(t, v.lightClientResult(errUnverified))
+		require.LogsContain(t, hook, "could not be verified by the light client")
+	})
+	t.Run("refuse", func(t *testing.T) {
+		lc, err := lightclient.NewVerifier("http://localhost:3500", [32]byte{}, true)
+		require.NoError(t, err)
+		v := &validator{lightClient: lc}
+		require.ErrorIs(t, v.lightClientResult(errUnverified), lightclient.ErrUnverifiedRoot)
+		require.NoError(t, v.lightClientResult(nil))
+	})
+}
@@ -102,6 +102,15 @@ func (v *validator) ProposeBlock(ctx context.Context, slot primitives.Slot, pubK
 		return
 	}
 
+	parentRoot := wb.ParentRoot()
+	if err := v.verifyRootWithLightClient(ctx, parentRoot[:]); err != nil {
+		log.WithError(err).Error("Refusing to sign block whose parent could not be verified by the light client")
+		if v.emitAccountMetrics {
+			ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
+	}
+
 	sig, signingRoot, err := v.signBlock(ctx, pubKey, epoch, slot, wb)
 	if err != nil {
 		log.WithError(err).Error("Failed to sign block")
@@ -19,11 +19,13 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/config/proposer"
 	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/encoding/bytesutil"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/validator/accounts/wallet"
 	beaconApi "github.com/prysmaticlabs/prysm/v5/validator/client/beacon-api"
 	beaconChainClientFactory "github.com/prysmaticlabs/prysm/v5/validator/client/beacon-chain-client-factory"
 	"github.com/prysmaticlabs/prysm/v5/validator/client/iface"
+	lightclient "github.com/prysmaticlabs/prysm/v5/validator/client/light-client"
 	nodeclientfactory "github.com/prysmaticlabs/prysm/v5/validator/client/node-client-factory"
 	validatorclientfactory "github.com/prysmaticlabs/prysm/v5/validator/client/validator-client-factory"
 	"github.com/prysmaticlabs/prysm/v5/validator/db"
@@ -57,6 +59,8 @@ type ValidatorService struct {
 	emitAccountMetrics      bool
 	logValidatorPerformance bool
 	distributed             bool
+	lightClientTrustedRoot  []byte
+	lightClientRefuse       bool
 }
 
 // Config for the validator service.
@@ -83,6 +87,8 @@ type Config struct {
 	LogValidatorPerformance bool
 	EmitAccountMetrics      bool
 	Distributed             bool
+	LightClientTrustedRoot  []byte
+	LightClientRefuse       bool
 }
 
 // NewValidatorService creates a new validator service for the service
@@ -106,6 +112,8 @@ func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, e
 		emitAccountMetrics:      cfg.EmitAccountMetrics,
 		logValidatorPerformance: cfg.LogValidatorPerformance,
 		distributed:             cfg.Distributed,
+		lightClientTrustedRoot:  cfg.LightClientTrustedRoot,
+		lightClientRefuse:       cfg.LightClientRefuse,
 	}
 
 	dialOpts := ConstructDialOptions(
@@ -179,6 +187,15 @@ func (v *ValidatorService) Start() {
 
 	validatorClient := validatorclientfactory.NewValidatorClient(v.conn, restHandler)
 
+	var lightClient *lightclient.Verifier
+	if len(v.lightClientTrustedRoot) != 0 {
+		lightClient, err = lightclient.NewVerifier(hosts[0], bytesutil.ToBytes32(v.lightClientTrustedRoot), v.lightClientRefuse)
+		if err != nil {
+			log.WithError(err).Error("Could not initialize light client verifier")
+			return
+		}
+	}
+
 	valStruct := &validator{
 		slotFeed:                       new(event.Feed),
 		startBalances:                  make(map[[fieldparams.BLSPubkeyLength]byte]uint64),
@@ -215,6 +232,7 @@ func (v *ValidatorService) Start() {
 		emitAccountMetrics:             v.emitAccountMetrics,
 		useWeb:                         v.useWeb,
 		distributed:                    v.distributed,
+		lightClient:                    lightClient,
 	}
 
 	v.validator = valStruct
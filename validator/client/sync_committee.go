@@ -38,6 +38,11 @@ func (v *validator) SubmitSyncCommitteeMessage(ctx context.Context, slot primiti
 		tracing.AnnotateError(span, err)
 		return
 	}
+	if err := v.verifyRootWithLightClient(ctx, res.Root); err != nil {
+		log.WithError(err).Error("Refusing to sign sync message block root which could not be verified by the light client")
+		tracing.AnnotateError(span, err)
+		return
+	}
 
 	duty, err := v.duty(pubKey)
 	if err != nil {
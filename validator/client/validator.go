@@ -41,6 +41,7 @@ import (
 	accountsiface "github.com/prysmaticlabs/prysm/v5/validator/accounts/iface"
 	"github.com/prysmaticlabs/prysm/v5/validator/accounts/wallet"
 	"github.com/prysmaticlabs/prysm/v5/validator/client/iface"
+	lightclient "github.com/prysmaticlabs/prysm/v5/validator/client/light-client"
 	"github.com/prysmaticlabs/prysm/v5/validator/db"
 	dbCommon "github.com/prysmaticlabs/prysm/v5/validator/db/common"
 	"github.com/prysmaticlabs/prysm/v5/validator/graffiti"
@@ -105,6 +106,7 @@ type validator struct {
 	emitAccountMetrics                 bool
 	useWeb                             bool
 	distributed                        bool
+	lightClient                        *lightclient.Verifier
 	domainDataLock                     sync.RWMutex
 	attLogsLock                        sync.Mutex
 	aggregatedSlotCommitteeIDCacheLock sync.Mutex
@@ -581,6 +583,14 @@ func (v *validator) UpdateDuties(ctx context.Context, slot primitives.Slot) erro
 		return err
 	}
 
+	if err := v.verifyDutiesWithLightClient(ctx, req.Epoch, resp.CurrentEpochDuties); err != nil {
+		v.dutiesLock.Lock()
+		v.duties = nil // Clear assignments so we know to retry the request.
+		v.dutiesLock.Unlock()
+		log.WithError(err).Error("Refusing duties which could not be verified by the light client")
+		return err
+	}
+
 	v.dutiesLock.Lock()
 	v.duties = resp
 	v.logDuties(slot, v.duties.CurrentEpochDuties, v.duties.NextEpochDuties)
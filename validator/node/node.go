@@ -17,6 +17,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/v5/api"
 	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
@@ -24,6 +25,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/cmd"
 	"github.com/prysmaticlabs/prysm/v5/cmd/validator/flags"
 	"github.com/prysmaticlabs/prysm/v5/config/features"
+	fieldparams "github.com/prysmaticlabs/prysm/v5/config/fieldparams"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
 	"github.com/prysmaticlabs/prysm/v5/config/proposer"
 	"github.com/prysmaticlabs/prysm/v5/config/proposer/loader"
@@ -487,6 +489,19 @@ func (c *ValidatorClient) registerValidatorService(cliCtx *cli.Context) error {
 		return err
 	}
 
+	var lightClientTrustedRoot []byte
+	if c.cliCtx.IsSet(flags.LightClientTrustedRootFlag.Name) {
+		lightClientTrustedRoot, err = hexutil.Decode(c.cliCtx.String(flags.LightClientTrustedRootFlag.Name))
+		if err != nil {
+			return errors.Wrapf(err, "could not decode --%s", flags.LightClientTrustedRootFlag.Name)
+		}
+		if len(lightClientTrustedRoot) != fieldparams.RootLength {
+			return fmt.Errorf("--%s must be a %d byte block root", flags.LightClientTrustedRootFlag.Name, fieldparams.RootLength)
+		}
+	} else if c.cliCtx.Bool(flags.LightClientRefuseFlag.Name) {
+		return fmt.Errorf("--%s requires --%s", flags.LightClientRefuseFlag.Name, flags.LightClientTrustedRootFlag.Name)
+	}
+
 	validatorService, err := client.NewValidatorService(c.cliCtx.Context, &client.Config{
 		DB:                      c.db,
 		Wallet:                  c.wallet,
@@ -509,6 +524,8 @@ func (c *ValidatorClient) registerValidatorService(cliCtx *cli.Context) error {
 		LogValidatorPerformance: !c.cliCtx.Bool(flags.DisablePenaltyRewardLogFlag.Name),
 		EmitAccountMetrics:      !c.cliCtx.Bool(flags.DisableAccountMetricsFlag.Name),
 		Distributed:             c.cliCtx.Bool(flags.EnableDistributed.Name),
+		LightClientTrustedRoot:  lightClientTrustedRoot,
+		LightClientRefuse:       c.cliCtx.Bool(flags.LightClientRefuseFlag.Name),
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not initialize validator service")